@@ -3,6 +3,8 @@ package provider
 import (
     "fmt"
 
+    "gorm.io/gorm"
+
     "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/provider"
 
@@ -62,37 +64,37 @@ func (this *Demo) loadExtInfo() {
         },
         Version: "1.0.1",
         Adaptation: ">= 1.2.1",
-        Install: func() error {
+        Install: func(tx *gorm.DB) error {
             facade.Logger.Error("demo Install")
 
             rules := getRules(slug)
-            extension.NewRule().Create(rules, "0")
+            extension.NewRuleWithDB(tx).Create(rules, "0")
 
             return nil
         },
-        Uninstall: func() error {
+        Uninstall: func(tx *gorm.DB) error {
             facade.Logger.Error("demo Uninstall")
 
-            extension.NewRule().Delete(slug)
+            extension.NewRuleWithDB(tx).Delete(slug)
 
             return nil
         },
-        Upgrade: func() error {
+        Upgrade: func(tx *gorm.DB) error {
             facade.Logger.Error("demo Upgrade")
 
             return nil
         },
-        Enable: func() error {
+        Enable: func(tx *gorm.DB) error {
             facade.Logger.Error("demo Enable")
 
-            extension.NewRule().Enable(slug)
+            extension.NewRuleWithDB(tx).Enable(slug)
 
             return nil
         },
-        Disable: func() error {
+        Disable: func(tx *gorm.DB) error {
             facade.Logger.Error("demo Disable")
 
-            extension.NewRule().Disable(slug)
+            extension.NewRuleWithDB(tx).Disable(slug)
 
             return nil
         },
@@ -0,0 +1,147 @@
+package audit
+
+import (
+    "sync"
+    "reflect"
+    "context"
+)
+
+// 上下文名称
+const ContextKey = "action_log_audit"
+
+// 操作类型
+const (
+    ActionCreate = "create"
+    ActionUpdate = "update"
+    ActionDelete = "delete"
+)
+
+var (
+    // 锁定
+    mu sync.RWMutex
+
+    // 已注册模型，模型类型 => 实体名称
+    entities = make(map[reflect.Type]string)
+)
+
+// 注册需要审计的模型，比如 Register("admin", &model.Admin{})
+func Register(entity string, model any) {
+    typ := reflect.TypeOf(model)
+    for typ.Kind() == reflect.Ptr {
+        typ = typ.Elem()
+    }
+
+    mu.Lock()
+    defer mu.Unlock()
+
+    entities[typ] = entity
+}
+
+// 获取模型的实体名称，没有注册时返回空
+func GetEntity(typ reflect.Type) string {
+    mu.RLock()
+    defer mu.RUnlock()
+
+    return entities[typ]
+}
+
+/**
+ * 字段变动
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Diff struct {
+    // 修改前
+    Before any `json:"before"`
+
+    // 修改后
+    After any `json:"after"`
+}
+
+/**
+ * 实体变动
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Change struct {
+    // 实体名称
+    Entity string `json:"entity"`
+
+    // 实体 ID
+    EntityId string `json:"entity_id"`
+
+    // 操作类型
+    Action string `json:"action"`
+
+    // 变动的字段
+    Fields map[string]Diff `json:"fields"`
+}
+
+/**
+ * 请求内的变动记录
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Recorder struct {
+    // 锁定
+    mu sync.Mutex
+
+    // 变动列表
+    changes []Change
+}
+
+// 构造函数
+func NewRecorder() *Recorder {
+    return &Recorder{
+        changes: make([]Change, 0),
+    }
+}
+
+// 添加变动，字段会按脱敏规则处理
+func (this *Recorder) Add(change Change) {
+    for field, diff := range change.Fields {
+        if MatchField(field) {
+            change.Fields[field] = Diff{
+                Before: RedactValue(diff.Before),
+                After:  RedactValue(diff.After),
+            }
+        }
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.changes = append(this.changes, change)
+}
+
+// 变动列表
+func (this *Recorder) Changes() []Change {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    changes := make([]Change, len(this.changes))
+    copy(changes, this.changes)
+
+    return changes
+}
+
+// 上下文添加变动记录
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+    return context.WithValue(ctx, ContextKey, recorder)
+}
+
+// 从上下文获取变动记录，gin 的上下文使用 ctx.Set 设置
+func RecorderFromContext(ctx context.Context) *Recorder {
+    if ctx == nil {
+        return nil
+    }
+
+    if recorder, ok := ctx.Value(ContextKey).(*Recorder); ok {
+        return recorder
+    }
+
+    return nil
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+    "context"
+    "testing"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+type auditUser struct {
+    ID       uint
+    Name     string
+    Password string
+    Status   int
+}
+
+// 没有注册的模型
+type auditOther struct {
+    ID   uint
+    Name string
+}
+
+func newAuditDB(t *testing.T) *gorm.DB {
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/audit.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = RegisterCallbacks(db); err != nil {
+        t.Fatal(err)
+    }
+
+    db.AutoMigrate(&auditUser{}, &auditOther{})
+
+    Register("user", &auditUser{})
+
+    return db
+}
+
+func Test_Callbacks(t *testing.T) {
+    eq := assertT(t)
+
+    db := newAuditDB(t)
+
+    recorder := NewRecorder()
+    ctx := WithRecorder(context.Background(), recorder)
+
+    user := &auditUser{
+        Name:     "lakego",
+        Password: "123456",
+        Status:   1,
+    }
+    db.WithContext(ctx).Create(user)
+
+    db.WithContext(ctx).Model(&auditUser{}).
+        Where("id = ?", user.ID).
+        Updates(map[string]any{
+            "name":     "lakego-admin",
+            "password": "654321",
+        })
+
+    // 没有变动的字段不记录
+    db.WithContext(ctx).Model(&auditUser{}).
+        Where("id = ?", user.ID).
+        Update("status", 1)
+
+    db.WithContext(ctx).Delete(&auditUser{}, user.ID)
+
+    // 没有注册的模型和没有上下文的写入不记录
+    db.WithContext(ctx).Create(&auditOther{Name: "other"})
+    db.Create(&auditUser{Name: "no-context"})
+
+    changes := recorder.Changes()
+    eq(len(changes), 3, "Changes len")
+    if len(changes) != 3 {
+        return
+    }
+
+    id := "1"
+
+    create := changes[0]
+    eq(create.Entity, "user", "Create Entity")
+    eq(create.EntityId, id, "Create EntityId")
+    eq(create.Action, ActionCreate, "Create Action")
+    eq(create.Fields["name"], Diff{After: "lakego"}, "Create name")
+    eq(create.Fields["password"], Diff{After: "******"}, "Create password redact")
+    eq(create.Fields["status"], Diff{After: 1}, "Create status")
+
+    update := changes[1]
+    eq(update.Action, ActionUpdate, "Update Action")
+    eq(update.EntityId, id, "Update EntityId")
+    eq(len(update.Fields), 2, "Update Fields len")
+    eq(update.Fields["name"], Diff{Before: "lakego", After: "lakego-admin"}, "Update name")
+    eq(update.Fields["password"], Diff{Before: "******", After: "******"}, "Update password redact")
+
+    del := changes[2]
+    eq(del.Action, ActionDelete, "Delete Action")
+    eq(del.EntityId, id, "Delete EntityId")
+    eq(del.Fields["name"], Diff{Before: "lakego-admin"}, "Delete name")
+    eq(del.Fields["password"], Diff{Before: "******"}, "Delete password redact")
+}
+
+func Test_Callbacks_Save(t *testing.T) {
+    eq := assertT(t)
+
+    db := newAuditDB(t)
+
+    user := &auditUser{
+        Name:   "lakego",
+        Status: 1,
+    }
+    db.Create(user)
+
+    recorder := NewRecorder()
+    ctx := WithRecorder(context.Background(), recorder)
+
+    // 结构体的主键作为条件
+    user.Status = 0
+    db.WithContext(ctx).Save(user)
+
+    changes := recorder.Changes()
+    eq(len(changes), 1, "Save Changes len")
+    if len(changes) != 1 {
+        return
+    }
+
+    eq(changes[0].Action, ActionUpdate, "Save Action")
+    eq(changes[0].Fields, map[string]Diff{
+        "status": {Before: 1, After: 0},
+    }, "Save Fields")
+}
+
+func Test_Redact(t *testing.T) {
+    eq := assertT(t)
+
+    eq(MatchField("password"), true, "MatchField password")
+    eq(MatchField("NewPassword_Confirm"), true, "MatchField wildcard")
+    eq(MatchField("client_secret"), true, "MatchField suffix")
+    eq(MatchField("token"), true, "MatchField token")
+    eq(MatchField("refresh_token"), false, "MatchField exact")
+    eq(MatchField("name"), false, "MatchField name")
+
+    eq(RedactValue(nil), nil, "RedactValue nil")
+    eq(RedactValue(""), "", "RedactValue empty")
+    eq(RedactValue(123), "******", "RedactValue int")
+
+    data := Redact(map[string]any{
+        "name":     "lakego",
+        "password": "123456",
+        "list": []any{
+            map[string]any{
+                "totp_secret": "secret",
+                "status":      1,
+            },
+        },
+    })
+    eq(data, map[string]any{
+        "name":     "lakego",
+        "password": "******",
+        "list": []any{
+            map[string]any{
+                "totp_secret": "******",
+                "status":      1,
+            },
+        },
+    }, "Redact")
+}
@@ -0,0 +1,324 @@
+package audit
+
+import (
+    "fmt"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+    "gorm.io/gorm/schema"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+// 修改前数据
+const beforeKey = "lakego:audit_before"
+
+// 单次修改或者删除最多记录的数据条数
+func MaxRows() int {
+    maxRows := config.New("admin").GetInt("action-log.audit.max-rows")
+    if maxRows <= 0 {
+        maxRows = 100
+    }
+
+    return maxRows
+}
+
+// 注册审计回调，只记录已注册模型在带有变动记录的上下文中的写入
+// 写入需要带上请求上下文，比如 model.NewAdmin(ctx) 或者 model.NewDB(ctx)，
+// 没有上下文的写入不会记录
+func RegisterCallbacks(db *gorm.DB) error {
+    var err error
+
+    callback := db.Callback()
+
+    err = callback.Create().After("gorm:create").Register("lakego:audit_create", afterCreate)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Update().Before("gorm:update").Register("lakego:audit_before_update", beforeWrite)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Update().After("gorm:update").Register("lakego:audit_update", afterUpdate)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Delete().Before("gorm:delete").Register("lakego:audit_before_delete", beforeWrite)
+    if err != nil {
+        return err
+    }
+
+    return callback.Delete().After("gorm:delete").Register("lakego:audit_delete", afterDelete)
+}
+
+// 当前语句的实体名称和变动记录
+func auditable(tx *gorm.DB) (string, *Recorder) {
+    if tx.Statement.Schema == nil {
+        return "", nil
+    }
+
+    recorder := RecorderFromContext(tx.Statement.Context)
+    if recorder == nil {
+        return "", nil
+    }
+
+    entity := GetEntity(tx.Statement.Schema.ModelType)
+    if entity == "" {
+        return "", nil
+    }
+
+    return entity, recorder
+}
+
+// 添加数据后记录全部字段
+func afterCreate(tx *gorm.DB) {
+    if tx.Error != nil {
+        return
+    }
+
+    entity, recorder := auditable(tx)
+    if recorder == nil {
+        return
+    }
+
+    stmt := tx.Statement
+
+    values := make([]reflect.Value, 0)
+    switch stmt.ReflectValue.Kind() {
+        case reflect.Struct:
+            if stmt.ReflectValue.Type() == stmt.Schema.ModelType {
+                values = append(values, stmt.ReflectValue)
+            }
+        case reflect.Slice, reflect.Array:
+            for i := 0; i < stmt.ReflectValue.Len(); i++ {
+                value := reflect.Indirect(stmt.ReflectValue.Index(i))
+                if value.Kind() == reflect.Struct && value.Type() == stmt.Schema.ModelType {
+                    values = append(values, value)
+                }
+            }
+    }
+
+    for _, value := range values {
+        fields := make(map[string]Diff)
+        for _, field := range stmt.Schema.Fields {
+            if field.DBName == "" {
+                continue
+            }
+
+            data, _ := field.ValueOf(stmt.Context, value)
+            fields[field.DBName] = Diff{
+                After: formatValue(data),
+            }
+        }
+
+        entityId := ""
+        if field := stmt.Schema.PrioritizedPrimaryField; field != nil {
+            data, _ := field.ValueOf(stmt.Context, value)
+            entityId = goch.ToString(data)
+        }
+
+        recorder.Add(Change{
+            Entity:   entity,
+            EntityId: entityId,
+            Action:   ActionCreate,
+            Fields:   fields,
+        })
+    }
+}
+
+// 修改或者删除前查询将受影响的数据
+func beforeWrite(tx *gorm.DB) {
+    if tx.Error != nil {
+        return
+    }
+
+    _, recorder := auditable(tx)
+    if recorder == nil || tx.Statement.Schema.PrioritizedPrimaryField == nil {
+        return
+    }
+
+    exprs := conditions(tx)
+    if len(exprs) == 0 {
+        return
+    }
+
+    rows := make([]map[string]any, 0)
+    err := newQuery(tx).
+        Clauses(clause.Where{Exprs: exprs}).
+        Limit(MaxRows()).
+        Find(&rows).
+        Error
+    if err != nil || len(rows) == 0 {
+        return
+    }
+
+    tx.InstanceSet(beforeKey, rows)
+}
+
+// 修改后对比字段
+func afterUpdate(tx *gorm.DB) {
+    entity, recorder, before := beforeRows(tx)
+    if len(before) == 0 {
+        return
+    }
+
+    primaryKey := tx.Statement.Schema.PrioritizedPrimaryField.DBName
+
+    ids := make([]any, 0, len(before))
+    for _, row := range before {
+        ids = append(ids, row[primaryKey])
+    }
+
+    rows := make([]map[string]any, 0)
+    err := newQuery(tx).
+        Where(clause.IN{Column: clause.Column{Name: primaryKey}, Values: ids}).
+        Find(&rows).
+        Error
+    if err != nil {
+        return
+    }
+
+    after := make(map[string]map[string]any, len(rows))
+    for _, row := range rows {
+        after[goch.ToString(row[primaryKey])] = row
+    }
+
+    for _, row := range before {
+        entityId := goch.ToString(row[primaryKey])
+
+        newRow, ok := after[entityId]
+        if !ok {
+            continue
+        }
+
+        fields := make(map[string]Diff)
+        for field, value := range newRow {
+            oldValue := formatValue(row[field])
+            newValue := formatValue(value)
+
+            if fmt.Sprint(oldValue) != fmt.Sprint(newValue) {
+                fields[field] = Diff{
+                    Before: oldValue,
+                    After:  newValue,
+                }
+            }
+        }
+
+        if len(fields) == 0 {
+            continue
+        }
+
+        recorder.Add(Change{
+            Entity:   entity,
+            EntityId: entityId,
+            Action:   ActionUpdate,
+            Fields:   fields,
+        })
+    }
+}
+
+// 删除后记录删除前的全部字段
+func afterDelete(tx *gorm.DB) {
+    entity, recorder, before := beforeRows(tx)
+    if len(before) == 0 {
+        return
+    }
+
+    primaryKey := tx.Statement.Schema.PrioritizedPrimaryField.DBName
+
+    for _, row := range before {
+        fields := make(map[string]Diff)
+        for field, value := range row {
+            fields[field] = Diff{
+                Before: formatValue(value),
+            }
+        }
+
+        recorder.Add(Change{
+            Entity:   entity,
+            EntityId: goch.ToString(row[primaryKey]),
+            Action:   ActionDelete,
+            Fields:   fields,
+        })
+    }
+}
+
+// 写入成功时获取修改前数据
+func beforeRows(tx *gorm.DB) (string, *Recorder, []map[string]any) {
+    if tx.Error != nil || tx.RowsAffected == 0 {
+        return "", nil, nil
+    }
+
+    entity, recorder := auditable(tx)
+    if recorder == nil {
+        return "", nil, nil
+    }
+
+    data, ok := tx.InstanceGet(beforeKey)
+    if !ok {
+        return "", nil, nil
+    }
+
+    rows, _ := data.([]map[string]any)
+
+    return entity, recorder, rows
+}
+
+// 新的查询，使用相同的连接和上下文
+// 带上模型，条件中的主键占位符比如 Delete(&Admin{}, id) 需要模型解析
+func newQuery(tx *gorm.DB) *gorm.DB {
+    model := reflect.New(tx.Statement.Schema.ModelType).Interface()
+
+    return tx.Session(&gorm.Session{NewDB: true}).
+        Model(model).
+        Table(tx.Statement.Table)
+}
+
+// 语句的查询条件，包括结构体的主键条件
+func conditions(tx *gorm.DB) []clause.Expression {
+    stmt := tx.Statement
+
+    exprs := make([]clause.Expression, 0)
+    if c, ok := stmt.Clauses["WHERE"]; ok {
+        if where, ok := c.Expression.(clause.Where); ok {
+            exprs = append(exprs, where.Exprs...)
+        }
+    }
+
+    values := []reflect.Value{stmt.ReflectValue}
+    if stmt.Model != nil && stmt.Dest != stmt.Model {
+        values = append(values, reflect.Indirect(reflect.ValueOf(stmt.Model)))
+    }
+
+    for _, value := range values {
+        // 只使用模型结构体，map 数据没有主键条件
+        if !value.IsValid() ||
+            value.Kind() != reflect.Struct ||
+            value.Type() != stmt.Schema.ModelType {
+            continue
+        }
+
+        _, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, value, stmt.Schema.PrimaryFields)
+        column, columnValues := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+        if len(columnValues) > 0 {
+            exprs = append(exprs, clause.IN{Column: column, Values: columnValues})
+        }
+    }
+
+    return exprs
+}
+
+// 格式化数据库数据
+func formatValue(value any) any {
+    if data, ok := value.([]byte); ok {
+        return string(data)
+    }
+
+    return value
+}
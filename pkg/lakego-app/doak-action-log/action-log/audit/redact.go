@@ -0,0 +1,101 @@
+package audit
+
+import (
+    "path"
+    "strings"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+// 默认脱敏字段
+var defaultRedactFields = []string{
+    "*password*",
+    "password_salt",
+    "totp_secret",
+    "totp_recovery",
+    "token",
+    "*secret",
+}
+
+// 默认替换内容
+const defaultRedactMask = "******"
+
+// 配置
+func conf(key string) string {
+    return "action-log.redact." + key
+}
+
+// 脱敏字段，不区分大小写，支持 * 通配
+func RedactFields() []string {
+    key := conf("fields")
+
+    cfg := config.New("admin")
+    if !cfg.IsSet(key) {
+        return defaultRedactFields
+    }
+
+    return cfg.GetStringSlice(key)
+}
+
+// 替换内容
+func RedactMask() string {
+    mask := config.New("admin").GetString(conf("mask"))
+    if mask == "" {
+        mask = defaultRedactMask
+    }
+
+    return mask
+}
+
+// 字段是否需要脱敏
+func MatchField(field string) bool {
+    field = strings.ToLower(field)
+
+    for _, pattern := range RedactFields() {
+        if ok, _ := path.Match(strings.ToLower(pattern), field); ok {
+            return true
+        }
+    }
+
+    return false
+}
+
+// 替换值，空值保持不变以便区分是否有修改
+func RedactValue(value any) any {
+    if value == nil {
+        return nil
+    }
+
+    if s, ok := value.(string); ok && s == "" {
+        return s
+    }
+
+    return RedactMask()
+}
+
+// 递归脱敏数据，用于请求数据
+func Redact(data any) any {
+    switch v := data.(type) {
+        case map[string]any:
+            newData := make(map[string]any, len(v))
+            for key, value := range v {
+                if MatchField(key) {
+                    newData[key] = RedactValue(value)
+                } else {
+                    newData[key] = Redact(value)
+                }
+            }
+
+            return newData
+
+        case []any:
+            newData := make([]any, len(v))
+            for i, value := range v {
+                newData[i] = Redact(value)
+            }
+
+            return newData
+    }
+
+    return data
+}
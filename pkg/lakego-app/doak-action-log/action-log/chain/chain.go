@@ -0,0 +1,80 @@
+package chain
+
+import (
+    "strconv"
+    "errors"
+
+    "github.com/deatil/go-hash/hash"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+var (
+    // 哈希算法不支持
+    ErrHashNotSupport = errors.New("chain: hash not support")
+)
+
+// 哈希算法
+var hashes = map[string]func(hash.Hash) hash.Hash{
+    "sha256": func(h hash.Hash) hash.Hash {
+        return h.SHA256()
+    },
+    "sha384": func(h hash.Hash) hash.Hash {
+        return h.SHA384()
+    },
+    "sha512": func(h hash.Hash) hash.Hash {
+        return h.SHA512()
+    },
+    "sm3": func(h hash.Hash) hash.Hash {
+        return h.SM3()
+    },
+}
+
+// 配置
+func conf(key string) string {
+    return "action-log.chain." + key
+}
+
+// 哈希算法名称
+func HashName() string {
+    name := config.New("admin").GetString(conf("hash"))
+    if name == "" {
+        name = "sha256"
+    }
+
+    return name
+}
+
+// 每多少条记录生成一个检查点
+func CheckpointEvery() int64 {
+    every := config.New("admin").GetInt64(conf("checkpoint-every"))
+    if every <= 0 {
+        every = 100
+    }
+
+    return every
+}
+
+// 计算记录哈希，包含上一条记录的哈希
+func Hash(prev string, content string) (string, error) {
+    return HashWith(HashName(), prev, content)
+}
+
+// 使用指定算法计算记录哈希，校验时使用记录保存的算法，为空时使用配置的算法
+func HashWith(name string, prev string, content string) (string, error) {
+    if name == "" {
+        name = HashName()
+    }
+
+    fn, ok := hashes[name]
+    if !ok {
+        return "", ErrHashNotSupport
+    }
+
+    return fn(hash.FromString(prev + "\n" + content)).ToHexString(), nil
+}
+
+// 检查点签名数据
+func CheckpointData(seq int64, hash string, kind string) []byte {
+    return []byte(strconv.FormatInt(seq, 10) + ":" + hash + ":" + kind)
+}
@@ -0,0 +1,134 @@
+package chain
+
+import (
+    "os"
+    "testing"
+    "crypto/rand"
+    "encoding/pem"
+    "path/filepath"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+// 写入 PEM 文件
+func writePEM(t *testing.T, file string, typ string, data []byte) string {
+    file = filepath.Join(t.TempDir(), file)
+
+    err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{
+        Type:  typ,
+        Bytes: data,
+    }), 0644)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return file
+}
+
+func Test_Hash(t *testing.T) {
+    eq := assertT(t)
+
+    eq(HashName(), "sha256", "HashName")
+    eq(CheckpointEvery(), int64(3), "CheckpointEvery")
+
+    hash, err := Hash("", "content")
+    eq(err, nil, "Hash error")
+    eq(len(hash), 64, "Hash len")
+
+    // 相同内容哈希相同，上一条哈希或者内容变化时哈希变化
+    again, _ := Hash("", "content")
+    eq(again, hash, "Hash same")
+
+    prev, _ := Hash("prev", "content")
+    eq(prev != hash, true, "Hash prev changed")
+
+    changed, _ := Hash("", "content2")
+    eq(changed != hash, true, "Hash content changed")
+
+    eq(string(CheckpointData(3, "abc", "periodic")), "3:abc:periodic", "CheckpointData")
+}
+
+func Test_HmacSigner(t *testing.T) {
+    eq := assertT(t)
+
+    signer, err := GetSigner()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(signer.Name(), "hmac", "Name")
+
+    data := CheckpointData(3, "abc", "periodic")
+
+    signature, err := signer.Sign(data)
+    eq(err, nil, "Sign error")
+    eq(signer.Verify(data, signature), true, "Verify")
+
+    eq(signer.Verify(CheckpointData(3, "abd", "periodic"), signature), false, "Verify changed hash")
+    eq(signer.Verify(CheckpointData(3, "abc", "archive"), signature), false, "Verify changed kind")
+    eq(signer.Verify(data, signature[:len(signature)-1] + "0"), false, "Verify tampered signature")
+
+    // 不同密钥
+    other := &HmacSigner{
+        hash: hmacHashes["sha256"],
+        key:  []byte("other-key"),
+    }
+    eq(other.Verify(data, signature), false, "Verify other key")
+}
+
+func Test_SM2Signer(t *testing.T) {
+    eq := assertT(t)
+
+    key, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    privateDer, err := sm2.MarshalPrivateKey(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    publicDer, err := sm2.MarshalPublicKey(&key.PublicKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    privateFile := writePEM(t, "sm2", "PRIVATE KEY", privateDer)
+    publicFile := writePEM(t, "sm2.pub", "PUBLIC KEY", publicDer)
+
+    signer, err := NewSM2Signer(privateFile, "", "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    data := CheckpointData(3, "abc", "periodic")
+
+    signature, err := signer.Sign(data)
+    eq(err, nil, "Sign error")
+    eq(signer.Verify(data, signature), true, "Verify")
+    eq(signer.Verify(CheckpointData(4, "abc", "periodic"), signature), false, "Verify changed seq")
+    eq(signer.Verify(data, "zz"), false, "Verify bad signature")
+
+    // 只有公钥时只能验证
+    verifier, err := NewSM2Signer("", publicFile, "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(verifier.Verify(data, signature), true, "Verify public key")
+
+    _, err = verifier.Sign(data)
+    eq(err, ErrSignerNotConfigured, "Sign without private key")
+
+    _, err = NewSM2Signer("", "", "")
+    eq(err, ErrSignerNotConfigured, "NewSM2Signer without key")
+}
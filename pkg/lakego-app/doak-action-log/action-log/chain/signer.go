@@ -0,0 +1,220 @@
+package chain
+
+import (
+    "os"
+    "errors"
+    "crypto/rand"
+    "crypto/hmac"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/hex"
+    "encoding/pem"
+    "encoding/base64"
+    stdhash "hash"
+
+    "github.com/deatil/go-hash/sm3"
+    "github.com/deatil/go-hash/hash"
+    "github.com/deatil/go-cryptobin/pkcs8"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+var (
+    // 签名方式不支持
+    ErrSignerNotSupport = errors.New("chain: signer not support")
+
+    // 签名密钥没有设置
+    ErrSignerNotConfigured = errors.New("chain: signer key not configured")
+
+    // 密钥格式错误
+    ErrKeyMustBePEMEncoded = errors.New("chain: key must be pem encoded")
+)
+
+// hmac 使用的哈希算法
+var hmacHashes = map[string]func() stdhash.Hash{
+    "sha256": sha256.New,
+    "sha384": sha512.New384,
+    "sha512": sha512.New,
+    "sm3":    sm3.New,
+}
+
+/**
+ * 检查点签名
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Signer interface {
+    // 名称
+    Name() string
+
+    // 签名
+    Sign(data []byte) (string, error)
+
+    // 验证签名
+    Verify(data []byte, signature string) bool
+}
+
+// 根据配置获取签名方式
+func GetSigner() (Signer, error) {
+    cfg := config.New("admin")
+
+    switch cfg.GetString(conf("signer")) {
+        case "", "hmac":
+            key, err := base64.StdEncoding.DecodeString(cfg.GetString(conf("hmac-key")))
+            if err != nil || len(key) == 0 {
+                return nil, ErrSignerNotConfigured
+            }
+
+            h, ok := hmacHashes[HashName()]
+            if !ok {
+                return nil, ErrHashNotSupport
+            }
+
+            return &HmacSigner{
+                hash: h,
+                key:  key,
+            }, nil
+
+        case "sm2":
+            return NewSM2Signer(
+                cfg.GetString(conf("sm2-private-key")),
+                cfg.GetString(conf("sm2-public-key")),
+                cfg.GetString(conf("sm2-private-key-password")),
+            )
+    }
+
+    return nil, ErrSignerNotSupport
+}
+
+/**
+ * HMAC 签名
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type HmacSigner struct {
+    hash func() stdhash.Hash
+    key  []byte
+}
+
+// 名称
+func (this *HmacSigner) Name() string {
+    return "hmac"
+}
+
+// 签名
+func (this *HmacSigner) Sign(data []byte) (string, error) {
+    return hash.FromBytes(data).Hmac(this.hash, this.key).ToHexString(), nil
+}
+
+// 验证签名
+func (this *HmacSigner) Verify(data []byte, signature string) bool {
+    expected, _ := this.Sign(data)
+
+    return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+/**
+ * SM2 签名，只配置公钥时只能验证
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SM2Signer struct {
+    privateKey *sm2.PrivateKey
+    publicKey  *sm2.PublicKey
+}
+
+// 构造函数，密钥为 PKCS8 格式的 PEM 文件路径
+func NewSM2Signer(privateKeyFile string, publicKeyFile string, password string) (*SM2Signer, error) {
+    signer := &SM2Signer{}
+
+    if privateKeyFile != "" {
+        block, err := readPEM(privateKeyFile)
+        if err != nil {
+            return nil, err
+        }
+
+        der := block.Bytes
+        if password != "" {
+            pass, err := base64.StdEncoding.DecodeString(password)
+            if err != nil {
+                return nil, err
+            }
+
+            if der, err = pkcs8.DecryptPEMBlock(block, pass); err != nil {
+                return nil, err
+            }
+        }
+
+        if signer.privateKey, err = sm2.ParsePrivateKey(der); err != nil {
+            return nil, err
+        }
+
+        signer.publicKey = &signer.privateKey.PublicKey
+    }
+
+    if publicKeyFile != "" {
+        block, err := readPEM(publicKeyFile)
+        if err != nil {
+            return nil, err
+        }
+
+        if signer.publicKey, err = sm2.ParsePublicKey(block.Bytes); err != nil {
+            return nil, err
+        }
+    }
+
+    if signer.publicKey == nil {
+        return nil, ErrSignerNotConfigured
+    }
+
+    return signer, nil
+}
+
+// 名称
+func (this *SM2Signer) Name() string {
+    return "sm2"
+}
+
+// 签名
+func (this *SM2Signer) Sign(data []byte) (string, error) {
+    if this.privateKey == nil {
+        return "", ErrSignerNotConfigured
+    }
+
+    signed, err := sm2.Sign(rand.Reader, this.privateKey, data, sm2.SignerOpts{})
+    if err != nil {
+        return "", err
+    }
+
+    return hex.EncodeToString(signed), nil
+}
+
+// 验证签名
+func (this *SM2Signer) Verify(data []byte, signature string) bool {
+    signed, err := hex.DecodeString(signature)
+    if err != nil {
+        return false
+    }
+
+    return sm2.Verify(this.publicKey, data, signed, sm2.SignerOpts{})
+}
+
+// 读取 PEM 文件
+func readPEM(file string) (*pem.Block, error) {
+    data, err := os.ReadFile(path.FormatPath(file))
+    if err != nil {
+        return nil, err
+    }
+
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, ErrKeyMustBePEMEncoded
+    }
+
+    return block, nil
+}
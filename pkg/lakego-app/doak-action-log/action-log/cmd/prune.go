@@ -0,0 +1,54 @@
+package cmd
+
+import (
+    "github.com/deatil/lakego-doak/lakego/color"
+    "github.com/deatil/lakego-doak/lakego/command"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/service"
+)
+
+/**
+ * 归档并清除过期的操作日志
+ *
+ * > ./main action-log:prune [--days=180]
+ * > main.exe action-log:prune [--days=180]
+ * > go run main.go action-log:prune [--days=180]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var PruneCmd = &command.Command{
+    Use: "action-log:prune",
+    Short: "action-log archive and prune expired logs.",
+    Example: "{execfile} action-log:prune",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Prune()
+    },
+}
+
+// 保留天数
+var pruneDays int
+
+func init() {
+    pf := PruneCmd.Flags()
+    pf.IntVarP(&pruneDays, "days", "d", 0, "保留天数，默认使用配置")
+}
+
+// 归档并清除
+func Prune() {
+    result, err := service.Prune(pruneDays)
+    if err != nil {
+        color.Redln("归档操作日志失败：" + err.Error())
+        return
+    }
+
+    for _, file := range result.Files {
+        color.Greenln("归档文件：" + file)
+    }
+
+    color.Greenln("归档操作日志成功，共清除 %d 条记录", result.Total)
+}
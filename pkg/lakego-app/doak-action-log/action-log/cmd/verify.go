@@ -0,0 +1,49 @@
+package cmd
+
+import (
+    "github.com/deatil/lakego-doak/lakego/color"
+    "github.com/deatil/lakego-doak/lakego/command"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/service"
+)
+
+/**
+ * 校验操作日志哈希链
+ *
+ * > ./main action-log:verify
+ * > main.exe action-log:verify
+ * > go run main.go action-log:verify
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var VerifyCmd = &command.Command{
+    Use: "action-log:verify",
+    Short: "action-log verify hash chain.",
+    Example: "{execfile} action-log:verify",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Verify()
+    },
+}
+
+// 校验
+func Verify() {
+    result, err := service.Verify()
+    if err != nil {
+        color.Redln("校验操作日志失败：" + err.Error())
+        return
+    }
+
+    if result.Broken != nil {
+        color.Redln("操作日志哈希链断开，序号：%d，日志ID：%s，原因：%s",
+            result.Broken.Seq, result.Broken.Id, result.Broken.Reason)
+        return
+    }
+
+    color.Greenln("操作日志校验通过，共 %d 条记录 [%d - %d]，%d 个检查点",
+        result.Total, result.First, result.Last, result.Checkpoints)
+}
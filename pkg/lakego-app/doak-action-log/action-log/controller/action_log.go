@@ -1,16 +1,31 @@
 package controller
 
 import (
+    "bytes"
+    "strings"
+    "net/http"
+    "encoding/csv"
+    "encoding/json"
+
+    "gorm.io/gorm"
+
     "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-datebin/datebin"
 
     "github.com/deatil/lakego-doak/lakego/router"
+    httpResponse "github.com/deatil/lakego-doak/lakego/http/response"
 
+    adminScope "github.com/deatil/lakego-doak-admin/admin/model/scope"
+    "github.com/deatil/lakego-doak-admin/admin/auth/datascope"
     adminController "github.com/deatil/lakego-doak-admin/admin/controller"
 
     "github.com/deatil/lakego-doak-action-log/action-log/model"
+    "github.com/deatil/lakego-doak-action-log/action-log/service"
 )
 
+// 单次最多导出数量
+const exportLimit = 10000
+
 /**
  * 操作日志
  *
@@ -33,6 +48,12 @@ type ActionLog struct {
 // @Param end_time   query string false "结束时间"
 // @Param method     query string false "请求方法"
 // @Param status     query string false "状态"
+// @Param admin_id   query string false "操作账号ID"
+// @Param slug       query string false "权限标识"
+// @Param request_id query string false "请求ID"
+// @Param outcome    query string false "操作结果，success 或者 failure"
+// @Param entity     query string false "实体名称，比如 admin"
+// @Param entity_id  query string false "实体ID"
 // @Param start      query string false "开始数据量"
 // @Param limit      query string false "每页数量"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
@@ -41,7 +62,187 @@ type ActionLog struct {
 // @x-lakego {"slug": "lakego-admin.action-log.index"}
 func (this *ActionLog) Index(ctx *router.Context) {
     // 模型
-    logModel := model.NewActionLog()
+    logModel := this.search(ctx)
+
+    // 分页相关
+    start := ctx.DefaultQuery("start", "0")
+    limit := ctx.DefaultQuery("limit", "10")
+
+    newStart := goch.ToInt(start)
+    newLimit := goch.ToInt(limit)
+
+    logModel = logModel.
+        Offset(newStart).
+        Limit(newLimit)
+
+    list := make([]map[string]any, 0)
+
+    // 列表
+    logModel = logModel.Find(&list)
+
+    var total int64
+
+    // 总数
+    err := logModel.
+        Offset(-1).
+        Limit(-1).
+        Count(&total).
+        Error
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "start": start,
+        "limit": limit,
+        "total": total,
+        "list": list,
+    })
+}
+
+// 操作日志详情
+// @Summary 操作日志详情
+// @Description 操作日志详情，包括实体字段变动
+// @Tags 操作日志
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "日志ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /action-log/{id} [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.action-log.detail"}
+func (this *ActionLog) Detail(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "日志ID不能为空")
+        return
+    }
+
+    info := make(map[string]any)
+    err := model.NewActionLog().
+        Scopes(adminScope.WithDataScope(ctx, "admin_id")).
+        Where("id = ?", id).
+        First(&info).
+        Error
+    if err != nil || len(info) == 0 {
+        this.Error(ctx, "日志信息不存在")
+        return
+    }
+
+    changes := make([]map[string]any, 0)
+    model.NewActionLogChange().
+        Where("log_id = ?", id).
+        Order("time ASC").
+        Find(&changes)
+
+    for _, change := range changes {
+        fields := make(map[string]any)
+        json.Unmarshal([]byte(goch.ToString(change["fields"])), &fields)
+
+        change["fields"] = fields
+    }
+
+    info["changes"] = changes
+
+    this.SuccessWithData(ctx, "获取成功", info)
+}
+
+// 导出操作日志
+// @Summary 导出操作日志
+// @Description 按筛选条件导出操作日志为 csv 文件
+// @Tags 操作日志
+// @Accept  application/json
+// @Produce text/csv
+// @Param searchword query string false "搜索关键字"
+// @Param start_time query string false "开始时间"
+// @Param end_time   query string false "结束时间"
+// @Param method     query string false "请求方法"
+// @Param status     query string false "状态"
+// @Param admin_id   query string false "操作账号ID"
+// @Param slug       query string false "权限标识"
+// @Param request_id query string false "请求ID"
+// @Param outcome    query string false "操作结果，success 或者 failure"
+// @Param entity     query string false "实体名称，比如 admin"
+// @Param entity_id  query string false "实体ID"
+// @Success 200 {string} string "csv"
+// @Router /action-log/export [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.action-log.export"}
+func (this *ActionLog) Export(ctx *router.Context) {
+    list := make([]model.ActionLog, 0)
+    err := this.search(ctx).
+        Limit(exportLimit).
+        Find(&list).
+        Error
+    if err != nil {
+        this.Error(ctx, "导出失败")
+        return
+    }
+
+    // 实体变动
+    ids := make([]string, 0, len(list))
+    for _, item := range list {
+        ids = append(ids, item.ID)
+    }
+
+    changes := make([]model.ActionLogChange, 0)
+    if len(ids) > 0 {
+        model.NewActionLogChange().
+            Select([]string{"log_id", "entity", "entity_id", "action"}).
+            Where("log_id in ?", ids).
+            Order("time ASC").
+            Find(&changes)
+    }
+
+    entities := make(map[string][]string)
+    for _, change := range changes {
+        entities[change.LogId] = append(entities[change.LogId], change.Action + ":" + change.Entity + ":" + change.EntityId)
+    }
+
+    buf := new(bytes.Buffer)
+
+    // 表格软件识别 utf-8
+    buf.WriteString("\xEF\xBB\xBF")
+
+    w := csv.NewWriter(buf)
+    w.Write([]string{
+        "id", "time", "admin_id", "name", "slug", "method", "url",
+        "ip", "status", "outcome", "request_id", "entities",
+    })
+
+    for _, item := range list {
+        w.Write([]string{
+            item.ID,
+            datebin.FromTimestamp(int64(item.Time)).ToDatetimeString(),
+            item.AdminId,
+            item.Name,
+            item.Slug,
+            item.Method,
+            item.Url,
+            item.Ip,
+            item.Status,
+            item.Outcome,
+            item.RequestId,
+            strings.Join(entities[item.ID], ";"),
+        })
+    }
+
+    w.Flush()
+
+    filename := "action-log-" + datebin.Now().Format("YmdHis") + ".csv"
+
+    httpResponse.New().
+        WithContext(ctx).
+        WithHeader("Content-Disposition", "attachment; filename=" + filename).
+        Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// 筛选条件
+func (this *ActionLog) search(ctx *router.Context) *gorm.DB {
+    // 模型
+    logModel := model.NewActionLog().
+        Scopes(adminScope.WithDataScope(ctx, "admin_id"))
 
     // 排序
     order := ctx.DefaultQuery("order", "time__DESC")
@@ -88,46 +289,53 @@ func (this *ActionLog) Index(ctx *router.Context) {
         logModel = logModel.Where("status = ?", status)
     }
 
-    // 分页相关
-    start := ctx.DefaultQuery("start", "0")
-    limit := ctx.DefaultQuery("limit", "10")
+    // 操作账号
+    adminId := ctx.DefaultQuery("admin_id", "")
+    if adminId != "" {
+        logModel = logModel.Where("admin_id = ?", adminId)
+    }
 
-    newStart := goch.ToInt(start)
-    newLimit := goch.ToInt(limit)
+    // 权限标识
+    slug := ctx.DefaultQuery("slug", "")
+    if slug != "" {
+        logModel = logModel.Where("slug = ?", slug)
+    }
 
-    logModel = logModel.
-        Offset(newStart).
-        Limit(newLimit)
+    // 请求 ID
+    requestId := ctx.DefaultQuery("request_id", "")
+    if requestId != "" {
+        logModel = logModel.Where("request_id = ?", requestId)
+    }
 
-    list := make([]map[string]any, 0)
+    // 操作结果
+    outcome := ctx.DefaultQuery("outcome", "")
+    if outcome == model.OutcomeSuccess || outcome == model.OutcomeFailure {
+        logModel = logModel.Where("outcome = ?", outcome)
+    }
 
-    // 列表
-    logModel = logModel.Find(&list)
+    // 实体
+    entity := ctx.DefaultQuery("entity", "")
+    entityId := ctx.DefaultQuery("entity_id", "")
+    if entity != "" || entityId != "" {
+        changeModel := model.NewActionLogChange().Select("log_id")
 
-    var total int64
+        if entity != "" {
+            changeModel = changeModel.Where("entity = ?", entity)
+        }
 
-    // 总数
-    err := logModel.
-        Offset(-1).
-        Limit(-1).
-        Count(&total).
-        Error
-    if err != nil {
-        this.Error(ctx, "获取失败")
-        return
+        if entityId != "" {
+            changeModel = changeModel.Where("entity_id = ?", entityId)
+        }
+
+        logModel = logModel.Where("id IN (?)", changeModel)
     }
 
-    this.SuccessWithData(ctx, "获取成功", router.H{
-        "start": start,
-        "limit": limit,
-        "total": total,
-        "list": list,
-    })
+    return logModel
 }
 
-// 清除 30 天前的数据
-// @Summary 清除 30 天前的日志数据
-// @Description 清除 30 天前的日志数据
+// 归档并清除过期的数据
+// @Summary 归档并清除过期的日志数据
+// @Description 归档超过保留天数的日志数据到存储后清除，需要全部数据范围
 // @Tags 操作日志
 // @Accept  application/json
 // @Produce application/json
@@ -136,15 +344,20 @@ func (this *ActionLog) Index(ctx *router.Context) {
 // @Security Bearer
 // @x-lakego {"slug": "lakego-admin.action-log.clear"}
 func (this *ActionLog) Clear(ctx *router.Context) {
-    // 清除
-    err := model.NewActionLog().
-        Where("time <= ?", int(datebin.Now().SubDays(30).Timestamp())).
-        Delete(&model.ActionLog{}).
-        Error
+    // 清除会影响全部日志的哈希链
+    if !datascope.FromContext(ctx).All {
+        this.Error(ctx, "没有清除全部日志的权限")
+        return
+    }
+
+    result, err := service.Prune(0)
     if err != nil {
-        this.Error(ctx, "30天前日志清除失败")
+        this.Error(ctx, "过期日志归档清除失败")
         return
     }
 
-    this.Success(ctx, "30天前日志清除成功")
+    this.SuccessWithData(ctx, "过期日志归档清除成功", router.H{
+        "total": result.Total,
+        "files": result.Files,
+    })
 }
@@ -0,0 +1,57 @@
+package listener
+
+import (
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+    "github.com/deatil/lakego-doak-action-log/action-log/service"
+)
+
+// 账号锁定记录
+type PassportLock struct{}
+
+func (this *PassportLock) Handle(data map[string]any) {
+    record("账号锁定[" + goch.ToString(data["name"]) + "]", data)
+}
+
+// 账号解锁记录
+type PassportUnlock struct{}
+
+func (this *PassportUnlock) Handle(data map[string]any) {
+    record("账号解锁[" + goch.ToString(data["name"]) + "]", data)
+}
+
+// 刷新 token 重复使用记录
+type PassportTokenReused struct{}
+
+func (this *PassportTokenReused) Handle(data map[string]any) {
+    record("刷新token重复使用[" + goch.ToString(data["admin_id"]) + "]", data)
+}
+
+// 记录日志
+func record(name string, data map[string]any) {
+    info, _ := json.Marshal(data)
+
+    // 操作账号，没有时为被操作的账号
+    adminId := goch.ToString(data["operator_id"])
+    if adminId == "" {
+        adminId = goch.ToString(data["admin_id"])
+    }
+
+    err := service.Record(&model.ActionLog{
+        AdminId: adminId,
+        Name: name,
+        Info: string(info),
+        Time: int(datebin.NowTimestamp()),
+        Ip: goch.ToString(data["ip"]),
+        Status: "200",
+    })
+    if err != nil {
+        facade.Logger.Error("[action-log] " + err.Error())
+    }
+}
@@ -4,28 +4,23 @@ import (
     "strconv"
     "encoding/json"
 
+    "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-datebin/datebin"
-    "github.com/deatil/lakego-doak/lakego/gmq"
+    "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/http/request"
+    "github.com/deatil/lakego-doak/lakego/middleware/requestid"
+
+    admin_model "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/support/url"
+    "github.com/deatil/lakego-doak-admin/admin/support/response"
+    authruleRepository "github.com/deatil/lakego-doak-admin/admin/repository/authrule"
 
     "github.com/deatil/lakego-doak-action-log/action-log/model"
+    "github.com/deatil/lakego-doak-action-log/action-log/audit"
+    "github.com/deatil/lakego-doak-action-log/action-log/service"
 )
 
-var MQ *gmq.GMQ
-
-func init() {
-    MQ = gmq.NewGMQ()
-
-    // 执行记录
-    MQ.Subscribe("action-log", func(value interface{}) {
-        f := value.(func())
-        f()
-    })
-
-    go MQ.Run()
-}
-
 /**
  * 操作日志
  *
@@ -34,27 +29,18 @@ func init() {
  */
 func Handler() router.HandlerFunc {
     return func(ctx *router.Context) {
-        ctx.Next()
-
-        // 协程使用 ctx
-        newCtx := ctx.Copy()
+        // 记录请求内已注册模型的变动
+        recorder := audit.NewRecorder()
+        ctx.Set(audit.ContextKey, recorder)
 
-        go MQ.Publish("action-log", func() {
-            recordLog(newCtx)
-        })
-    }
-}
+        ctx.Next()
 
-func filterPassword(data map[string]any, key string) map[string]any {
-    if _, ok := data[key]; ok {
-        data[key] = ""
+        recordLog(ctx, recorder.Changes())
     }
-
-    return data
 }
 
 // 记录日志
-func recordLog(ctx *router.Context) {
+func recordLog(ctx *router.Context, changes []audit.Change) {
     path := ctx.Request.URL.Path
     raw := ctx.Request.URL.RawQuery
 
@@ -68,13 +54,8 @@ func recordLog(ctx *router.Context) {
         path = path + "?" + raw
     }
 
-    post = filterPassword(post, "password")
-    post = filterPassword(post, "oldpassword")
-    post = filterPassword(post, "newpassword")
-    post = filterPassword(post, "newpassword_confirm")
-
     // 请求数据
-    info, _ := json.Marshal(&post)
+    info, _ := json.Marshal(audit.Redact(post))
     useragent := ctx.Request.Header.Get("User-Agent")
 
     // 请求 IP
@@ -83,22 +64,72 @@ func recordLog(ctx *router.Context) {
     // 响应输出状态
     status := strconv.Itoa(ctx.Writer.Status())
 
-    adminId, _ := ctx.Get("admin_id")
+    adminId := ctx.GetString("admin_id")
 
-    name := "操作账号[-]"
-    if adminId != nil {
-        name = "操作账号[" + adminId.(string) + "]"
+    // 当前路由对应的权限
+    name := ctx.Request.URL.Path
+    slug := ""
+    if rule := routeRule(ctx); rule != nil {
+        name = rule.Title
+        slug = rule.Slug
     }
 
-    // 记录数据
-    model.NewDB().Create(&model.ActionLog{
+    now := int(datebin.NowTimestamp())
+    requestId := ctx.GetString(requestid.ContextKey)
+
+    log := &model.ActionLog{
+        AdminId: adminId,
         Name: name,
         Url: path,
         Method: method,
         Info: string(info),
         Useragent: useragent,
-        Time: int(datebin.NowTimestamp()),
+        Time: now,
         Ip: ip,
         Status: status,
-    })
+        RequestId: requestId,
+        Slug: slug,
+        Outcome: outcome(ctx),
+    }
+
+    for _, change := range changes {
+        fields, _ := json.Marshal(change.Fields)
+
+        log.Changes = append(log.Changes, model.ActionLogChange{
+            RequestId: requestId,
+            Entity: change.Entity,
+            EntityId: change.EntityId,
+            Action: change.Action,
+            Fields: string(fields),
+            Time: now,
+        })
+    }
+
+    // 记录数据，开启异步写入时写入缓冲区
+    if err := service.Record(log); err != nil {
+        facade.Logger.Error("[action-log] " + err.Error())
+    }
+}
+
+// 请求结果，优先使用响应的 success 字段
+func outcome(ctx *router.Context) string {
+    if ctx.Writer.Status() >= 400 {
+        return model.OutcomeFailure
+    }
+
+    if success, ok := ctx.Get(response.SuccessKey); ok && !goch.ToBool(success) {
+        return model.OutcomeFailure
+    }
+
+    return model.OutcomeSuccess
+}
+
+// 当前路由对应的权限规则，规则列表使用缓存
+func routeRule(ctx *router.Context) *admin_model.AuthRule {
+    rules := authruleRepository.MatchRouteRules(ctx.Request.Method, url.RoutePath(ctx))
+    if len(rules) == 0 {
+        return nil
+    }
+
+    return &rules[0]
 }
@@ -1,14 +1,26 @@
 package model
 
 import (
+    "sort"
+    "encoding/json"
+
     "gorm.io/gorm"
 
     "github.com/deatil/lakego-doak/lakego/uuid"
     "github.com/deatil/lakego-doak/lakego/facade"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+)
+
+// 操作结果
+const (
+    OutcomeSuccess = "success"
+    OutcomeFailure = "failure"
 )
 
 type ActionLog struct {
     ID        string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    AdminId   string `gorm:"column:admin_id;type:char(36);not null;default:'';index;" json:"admin_id"`
     Name      string `gorm:"column:name;not null;type:varchar(250);" json:"name"`
     Url       string `gorm:"column:url;type:text;" json:"url"`
     Method    string `gorm:"column:method;type:varchar(10);" json:"method"`
@@ -17,6 +29,15 @@ type ActionLog struct {
     Time      int    `gorm:"column:time;type:int(10);" json:"time"`
     Ip        string `gorm:"column:ip;type:varchar(50);" json:"ip"`
     Status    string `gorm:"column:status;type:char(3);" json:"status"`
+    RequestId string `gorm:"column:request_id;type:varchar(64);not null;default:'';index;" json:"request_id"`
+    Slug      string `gorm:"column:slug;type:varchar(150);not null;default:'';index;" json:"slug"`
+    Outcome   string `gorm:"column:outcome;type:varchar(10);not null;default:'';index;" json:"outcome"`
+    Seq       int64  `gorm:"column:seq;type:bigint(20);not null;default:0;uniqueIndex;" json:"seq"`
+    PrevHash  string `gorm:"column:prev_hash;type:varchar(128);not null;default:'';" json:"prev_hash"`
+    Hash      string `gorm:"column:hash;type:varchar(128);not null;default:'';" json:"hash"`
+    HashAlgo  string `gorm:"column:hash_algo;type:varchar(20);not null;default:'';" json:"hash_algo"`
+
+    Changes []ActionLogChange `gorm:"foreignKey:LogId;references:ID" json:"changes,omitempty"`
 }
 
 /*
@@ -32,6 +53,59 @@ func (this *ActionLog) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
+// 参与哈希计算的内容，不包括 ID 和哈希字段
+func (this *ActionLog) ChainContent(changes []ActionLogChange) string {
+    items := make([]string, 0, len(changes))
+    for _, change := range changes {
+        item, _ := json.Marshal([]string{
+            change.Entity,
+            change.EntityId,
+            change.Action,
+            change.Fields,
+        })
+
+        items = append(items, string(item))
+    }
+
+    // 变动记录没有顺序，排序后计算
+    sort.Strings(items)
+
+    data, _ := json.Marshal([]any{
+        this.Seq,
+        this.AdminId,
+        this.Name,
+        this.Url,
+        this.Method,
+        this.Info,
+        this.Useragent,
+        this.Time,
+        this.Ip,
+        this.Status,
+        this.RequestId,
+        this.Slug,
+        this.Outcome,
+        items,
+    })
+
+    return string(data)
+}
+
+// 接到上一条记录之后，设置序号和哈希，记录使用的哈希算法
+func (this *ActionLog) Link(prevSeq int64, prevHash string) error {
+    this.Seq = prevSeq + 1
+    this.PrevHash = prevHash
+    this.HashAlgo = chain.HashName()
+
+    hash, err := chain.HashWith(this.HashAlgo, prevHash, this.ChainContent(this.Changes))
+    if err != nil {
+        return err
+    }
+
+    this.Hash = hash
+
+    return nil
+}
+
 func NewActionLog() *gorm.DB {
     return facade.DB.Model(&ActionLog{})
 }
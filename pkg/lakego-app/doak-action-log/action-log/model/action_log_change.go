@@ -0,0 +1,30 @@
+package model
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/facade"
+)
+
+// 实体变动
+type ActionLogChange struct {
+    ID        string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    LogId     string `gorm:"column:log_id;type:char(36);not null;index;" json:"log_id"`
+    RequestId string `gorm:"column:request_id;type:varchar(64);not null;default:'';" json:"request_id"`
+    Entity    string `gorm:"column:entity;type:varchar(50);not null;index:idx_entity;" json:"entity"`
+    EntityId  string `gorm:"column:entity_id;type:varchar(64);not null;default:'';index:idx_entity;" json:"entity_id"`
+    Action    string `gorm:"column:action;type:varchar(10);not null;" json:"action"`
+    Fields    string `gorm:"column:fields;type:longtext;" json:"fields"`
+    Time      int    `gorm:"column:time;type:int(10);" json:"time"`
+}
+
+func (this *ActionLogChange) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewActionLogChange() *gorm.DB {
+    return facade.DB.Model(&ActionLogChange{})
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/facade"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+)
+
+// 检查点类型
+const (
+    // 定期生成
+    CheckpointPeriodic = "periodic"
+
+    // 归档清除时生成，作为剩余记录的起点
+    CheckpointArchive = "archive"
+)
+
+// 哈希链检查点
+type ActionLogCheckpoint struct {
+    ID        string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    Seq       int64  `gorm:"column:seq;type:bigint(20);not null;uniqueIndex;" json:"seq"`
+    Hash      string `gorm:"column:hash;type:varchar(128);not null;" json:"hash"`
+    Kind      string `gorm:"column:kind;type:varchar(10);not null;" json:"kind"`
+    Algo      string `gorm:"column:algo;type:varchar(20);not null;" json:"algo"`
+    Signer    string `gorm:"column:signer;type:varchar(20);not null;" json:"signer"`
+    Signature string `gorm:"column:signature;type:text;" json:"signature"`
+    Time      int    `gorm:"column:time;type:int(10);" json:"time"`
+}
+
+func (this *ActionLogCheckpoint) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+// 验证签名
+func (this *ActionLogCheckpoint) Verify(signer chain.Signer) bool {
+    return this.Signer == signer.Name() &&
+        signer.Verify(chain.CheckpointData(this.Seq, this.Hash, this.Kind), this.Signature)
+}
+
+// 生成签名的检查点
+func MakeActionLogCheckpoint(seq int64, hash string, kind string) (*ActionLogCheckpoint, error) {
+    signer, err := chain.GetSigner()
+    if err != nil {
+        return nil, err
+    }
+
+    signature, err := signer.Sign(chain.CheckpointData(seq, hash, kind))
+    if err != nil {
+        return nil, err
+    }
+
+    return &ActionLogCheckpoint{
+        Seq:       seq,
+        Hash:      hash,
+        Kind:      kind,
+        Algo:      chain.HashName(),
+        Signer:    signer.Name(),
+        Signature: signature,
+        Time:      int(datebin.NowTimestamp()),
+    }, nil
+}
+
+func NewActionLogCheckpoint() *gorm.DB {
+    return facade.DB.Model(&ActionLogCheckpoint{})
+}
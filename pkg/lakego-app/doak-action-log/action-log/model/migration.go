@@ -0,0 +1,268 @@
+package model
+
+import (
+    "errors"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+)
+
+// 数据库迁移
+var Migrations = []migration.Migration{
+    {
+        // 旧版本由安装 sql 创建，已存在时跳过
+        Name: "2026_10_18_000000_create_action_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasTable(&ActionLog{}) {
+                return m.CreateTable(&ActionLog{})
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            return db.Migrator().DropTable(&ActionLog{})
+        },
+    },
+    {
+        Name: "2026_10_18_000001_add_admin_id_to_action_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasColumn(&ActionLog{}, "AdminId") {
+                if err := m.AddColumn(&ActionLog{}, "AdminId"); err != nil {
+                    return err
+                }
+            }
+
+            if !m.HasIndex(&ActionLog{}, "AdminId") {
+                if err := m.CreateIndex(&ActionLog{}, "AdminId"); err != nil {
+                    return err
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasIndex(&ActionLog{}, "AdminId") {
+                if err := m.DropIndex(&ActionLog{}, "AdminId"); err != nil {
+                    return err
+                }
+            }
+
+            if m.HasColumn(&ActionLog{}, "AdminId") {
+                return m.DropColumn(&ActionLog{}, "AdminId")
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000002_add_audit_to_action_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, field := range []string{"RequestId", "Slug", "Outcome"} {
+                if !m.HasColumn(&ActionLog{}, field) {
+                    if err := m.AddColumn(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+
+                if !m.HasIndex(&ActionLog{}, field) {
+                    if err := m.CreateIndex(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            if !m.HasTable(&ActionLogChange{}) {
+                return m.CreateTable(&ActionLogChange{})
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&ActionLogChange{}) {
+                if err := m.DropTable(&ActionLogChange{}); err != nil {
+                    return err
+                }
+            }
+
+            for _, field := range []string{"RequestId", "Slug", "Outcome"} {
+                if m.HasIndex(&ActionLog{}, field) {
+                    if err := m.DropIndex(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+
+                if m.HasColumn(&ActionLog{}, field) {
+                    if err := m.DropColumn(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000003_add_hash_chain_to_action_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, field := range []string{"Seq", "PrevHash", "Hash"} {
+                if !m.HasColumn(&ActionLog{}, field) {
+                    if err := m.AddColumn(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            if !m.HasTable(&ActionLogCheckpoint{}) {
+                if err := m.CreateTable(&ActionLogCheckpoint{}); err != nil {
+                    return err
+                }
+            }
+
+            // 已有记录按时间接入哈希链
+            if err := linkActionLogs(db); err != nil {
+                return err
+            }
+
+            if !m.HasIndex(&ActionLog{}, "Seq") {
+                return m.CreateIndex(&ActionLog{}, "Seq")
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&ActionLogCheckpoint{}) {
+                if err := m.DropTable(&ActionLogCheckpoint{}); err != nil {
+                    return err
+                }
+            }
+
+            if m.HasIndex(&ActionLog{}, "Seq") {
+                if err := m.DropIndex(&ActionLog{}, "Seq"); err != nil {
+                    return err
+                }
+            }
+
+            for _, field := range []string{"Seq", "PrevHash", "Hash"} {
+                if m.HasColumn(&ActionLog{}, field) {
+                    if err := m.DropColumn(&ActionLog{}, field); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000004_add_hash_algo_to_action_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasColumn(&ActionLog{}, "HashAlgo") {
+                if err := m.AddColumn(&ActionLog{}, "HashAlgo"); err != nil {
+                    return err
+                }
+            }
+
+            // 已有记录使用当前配置的算法生成
+            return db.Model(&ActionLog{}).
+                Where("hash_algo = ?", "").
+                UpdateColumn("hash_algo", chain.HashName()).
+                Error
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasColumn(&ActionLog{}, "HashAlgo") {
+                return m.DropColumn(&ActionLog{}, "HashAlgo")
+            }
+
+            return nil
+        },
+    },
+}
+
+// 没有序号的记录接入哈希链
+func linkActionLogs(db *gorm.DB) error {
+    last := ActionLog{}
+    err := db.Model(&ActionLog{}).
+        Select([]string{"seq", "hash"}).
+        Order("seq DESC").
+        Limit(1).
+        Find(&last).
+        Error
+    if err != nil {
+        return err
+    }
+
+    linked := false
+    for {
+        logs := make([]ActionLog, 0)
+        err := db.Model(&ActionLog{}).
+            Preload("Changes").
+            Where("seq = ?", 0).
+            Order("time ASC, id ASC").
+            Limit(500).
+            Find(&logs).
+            Error
+        if err != nil {
+            return err
+        }
+
+        if len(logs) == 0 {
+            break
+        }
+
+        for _, log := range logs {
+            if err := log.Link(last.Seq, last.Hash); err != nil {
+                return err
+            }
+
+            err := db.Model(&ActionLog{}).
+                Where("id = ?", log.ID).
+                UpdateColumns(map[string]any{
+                    "seq": log.Seq,
+                    "prev_hash": log.PrevHash,
+                    "hash": log.Hash,
+                }).
+                Error
+            if err != nil {
+                return err
+            }
+
+            last = log
+            linked = true
+        }
+    }
+
+    if !linked {
+        return nil
+    }
+
+    // 没有配置签名时不生成检查点
+    checkpoint, err := MakeActionLogCheckpoint(last.Seq, last.Hash, CheckpointPeriodic)
+    if errors.Is(err, chain.ErrSignerNotConfigured) {
+        return nil
+    } else if err != nil {
+        return err
+    }
+
+    return db.Create(checkpoint).Error
+}
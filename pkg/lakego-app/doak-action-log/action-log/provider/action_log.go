@@ -1,12 +1,23 @@
 package provider
 
 import (
+    "log"
+
+    "github.com/deatil/go-events/events"
+
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/provider"
+    "github.com/deatil/lakego-doak/lakego/schedule"
 
+    admin_model "github.com/deatil/lakego-doak-admin/admin/model"
     admin_route "github.com/deatil/lakego-doak-admin/admin/support/route"
 
+    log_cmd "github.com/deatil/lakego-doak-action-log/action-log/cmd"
+    log_audit "github.com/deatil/lakego-doak-action-log/action-log/audit"
+    log_model "github.com/deatil/lakego-doak-action-log/action-log/model"
     log_router "github.com/deatil/lakego-doak-action-log/action-log/route"
+    log_service "github.com/deatil/lakego-doak-action-log/action-log/service"
+    log_listener "github.com/deatil/lakego-doak-action-log/action-log/listener"
     log_middleware "github.com/deatil/lakego-doak-action-log/action-log/middleware/actionlog"
 )
 
@@ -42,8 +53,41 @@ func (this *ActionLog) Register() {
 
 // 引导
 func (this *ActionLog) Boot() {
+    // 脚本
+    this.loadCommand()
+
     // 路由
     this.loadRoute()
+
+    // 事件
+    this.loadEvents()
+
+    // 数据库迁移
+    this.loadMigration()
+
+    // 数据审计
+    this.loadAudit()
+
+    // 异步写入
+    this.loadWriter()
+}
+
+/**
+ * 异步写入，服务关闭时写入缓冲区剩余记录
+ */
+func (this *ActionLog) loadWriter() {
+    app := this.GetApp()
+    if app == nil || app.RunningInConsole() {
+        return
+    }
+
+    log_service.StartWriter()
+
+    app.WithTerminating(func() {
+        if err := log_service.StopWriter(); err != nil {
+            log.Printf("Error to stop action-log writer: %v", err)
+        }
+    })
 }
 
 /**
@@ -65,6 +109,33 @@ func (this *ActionLog) loadMiddleware() {
     }
 }
 
+/**
+ * 导入脚本
+ */
+func (this *ActionLog) loadCommand() {
+    // 校验哈希链
+    this.AddCommand(log_cmd.VerifyCmd)
+
+    // 归档并清除过期日志
+    this.AddCommand(log_cmd.PruneCmd)
+}
+
+/**
+ * 计划任务
+ */
+func (this *ActionLog) Schedule(s *schedule.Schedule) {
+    // 归档并清除过期的操作日志
+    s.WithEntry(schedule.NewEntry().
+        WithName("action-log:prune").
+        AddErrorFunc(func() error {
+            _, err := log_service.Prune(0)
+            return err
+        }).
+        Daily().
+        OnOneServer().
+        WithoutOverlapping())
+}
+
 /**
  * 导入路由
  */
@@ -75,3 +146,36 @@ func (this *ActionLog) loadRoute() {
     })
 }
 
+/**
+ * 注册事件
+ */
+func (this *ActionLog) loadEvents() {
+    // 账号锁定
+    events.AddAction("admin.passport-lockout.lock", &log_listener.PassportLock{}, events.DefaultSort)
+    events.AddAction("admin.passport-lockout.unlock", &log_listener.PassportUnlock{}, events.DefaultSort)
+
+    // 刷新 token 重复使用
+    events.AddAction("admin.passport-session.reused", &log_listener.PassportTokenReused{}, events.DefaultSort)
+}
+
+/**
+ * 数据库迁移
+ */
+func (this *ActionLog) loadMigration() {
+    this.AddMigrations("lakego-action-log", log_model.Migrations...)
+}
+
+/**
+ * 数据审计，记录已注册模型的字段变动
+ */
+func (this *ActionLog) loadAudit() {
+    log_audit.Register("admin", &admin_model.Admin{})
+    log_audit.Register("auth_group", &admin_model.AuthGroup{})
+    log_audit.Register("auth_rule", &admin_model.AuthRule{})
+    log_audit.Register("tenant", &admin_model.Tenant{})
+    log_audit.Register("attachment", &admin_model.Attachment{})
+
+    if err := log_audit.RegisterCallbacks(admin_model.NewDB()); err != nil {
+        log.Printf("Error to register action-log audit callbacks: %v", err)
+    }
+}
@@ -13,5 +13,7 @@ func Route(engine gin.IRouter) {
     // 操作日志
     actionLogController := new(controller.ActionLog)
     engine.GET("/action-log", actionLogController.Index)
+    engine.GET("/action-log/export", actionLogController.Export)
+    engine.GET("/action-log/:id", actionLogController.Detail)
     engine.DELETE("/action-log/clear", actionLogController.Clear)
 }
@@ -0,0 +1,299 @@
+package service
+
+import (
+    "sync"
+    "errors"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/database"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+// 序号冲突时重试次数，多实例同时写入时序号可能重复
+const appendRetries = 3
+
+// 校验时每批读取数量
+const verifyBatch = 500
+
+// 同一进程内顺序写入
+var appendMu sync.Mutex
+
+// 写入日志并接入哈希链
+func Append(log *model.ActionLog) error {
+    return AppendBatch([]*model.ActionLog{log})
+}
+
+// 批量写入日志并接入哈希链
+func AppendBatch(logs []*model.ActionLog) error {
+    if len(logs) == 0 {
+        return nil
+    }
+
+    appendMu.Lock()
+    defer appendMu.Unlock()
+
+    var err error
+    for i := 0; i < appendRetries; i++ {
+        err = model.NewDB().Transaction(func(tx *gorm.DB) error {
+            return appendLogs(tx, logs)
+        })
+        if err == nil || !database.IsDuplicateKey(err) {
+            return err
+        }
+    }
+
+    return err
+}
+
+// 写入日志
+func appendLogs(tx *gorm.DB, logs []*model.ActionLog) error {
+    prevSeq, prevHash, err := lastLink(tx)
+    if err != nil {
+        return err
+    }
+
+    every := chain.CheckpointEvery()
+
+    checkpoints := make([]*model.ActionLogCheckpoint, 0)
+    for _, log := range logs {
+        if err := log.Link(prevSeq, prevHash); err != nil {
+            return err
+        }
+
+        prevSeq, prevHash = log.Seq, log.Hash
+
+        if log.Seq % every != 0 {
+            continue
+        }
+
+        // 没有配置签名时不生成检查点，签名出错时不影响日志写入
+        checkpoint, err := model.MakeActionLogCheckpoint(log.Seq, log.Hash, model.CheckpointPeriodic)
+        if err != nil {
+            if !IsSignerNotConfigured(err) {
+                facade.Logger.Error("[action-log] checkpoint: " + err.Error())
+            }
+
+            continue
+        }
+
+        checkpoints = append(checkpoints, checkpoint)
+    }
+
+    if err := tx.Create(logs).Error; err != nil {
+        return err
+    }
+
+    if len(checkpoints) == 0 {
+        return nil
+    }
+
+    return tx.Create(checkpoints).Error
+}
+
+// 链尾的序号和哈希，日志全部清除后使用最后的检查点
+// 不使用行锁，空表时锁不住任何记录。多实例同时写入时由
+// 日志和检查点的序号唯一索引拒绝重复序号，冲突后重新读取链尾重试
+func lastLink(tx *gorm.DB) (int64, string, error) {
+    last := model.ActionLog{}
+    err := tx.Model(&model.ActionLog{}).
+        Select([]string{"seq", "hash"}).
+        Order("seq DESC").
+        Limit(1).
+        Find(&last).
+        Error
+    if err != nil {
+        return 0, "", err
+    }
+
+    checkpoint := model.ActionLogCheckpoint{}
+    err = tx.Model(&model.ActionLogCheckpoint{}).
+        Order("seq DESC").
+        Limit(1).
+        Find(&checkpoint).
+        Error
+    if err != nil {
+        return 0, "", err
+    }
+
+    if checkpoint.Seq > last.Seq {
+        return checkpoint.Seq, checkpoint.Hash, nil
+    }
+
+    return last.Seq, last.Hash, nil
+}
+
+/**
+ * 断链信息
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Broken struct {
+    // 序号
+    Seq int64
+
+    // 日志 ID
+    Id string
+
+    // 原因
+    Reason string
+}
+
+/**
+ * 校验结果
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type VerifyResult struct {
+    // 校验的记录数量
+    Total int64
+
+    // 校验的检查点数量
+    Checkpoints int64
+
+    // 第一条记录序号
+    First int64
+
+    // 最后一条记录序号
+    Last int64
+
+    // 第一处断链，没有时为 nil
+    Broken *Broken
+}
+
+// 校验哈希链，返回第一处断链
+func Verify() (*VerifyResult, error) {
+    result := &VerifyResult{}
+
+    checkpoints, err := verifyCheckpoints(result)
+    if err != nil || result.Broken != nil {
+        return result, err
+    }
+
+    var prevSeq int64
+    var prevHash string
+
+    for {
+        logs := make([]model.ActionLog, 0)
+        err := model.NewActionLog().
+            Preload("Changes").
+            Where("seq > ?", prevSeq).
+            Order("seq ASC").
+            Limit(verifyBatch).
+            Find(&logs).
+            Error
+        if err != nil {
+            return result, err
+        }
+
+        if len(logs) == 0 {
+            break
+        }
+
+        for _, log := range logs {
+            if result.Total == 0 {
+                result.First = log.Seq
+
+                // 前面的记录已归档清除时，使用归档检查点作为起点
+                if log.Seq > 1 {
+                    checkpoint, ok := checkpoints[log.Seq - 1]
+                    if !ok || checkpoint.Kind != model.CheckpointArchive {
+                        result.Broken = &Broken{log.Seq, log.ID, "起点检查点不存在"}
+                        return result, nil
+                    }
+
+                    prevHash = checkpoint.Hash
+                }
+            } else if log.Seq != prevSeq + 1 {
+                result.Broken = &Broken{prevSeq + 1, "", "记录缺失"}
+                return result, nil
+            }
+
+            if log.PrevHash != prevHash {
+                result.Broken = &Broken{log.Seq, log.ID, "上一条记录哈希不一致"}
+                return result, nil
+            }
+
+            // 使用记录生成时的算法，修改配置的算法后旧记录仍可校验
+            hash, err := chain.HashWith(log.HashAlgo, log.PrevHash, log.ChainContent(log.Changes))
+            if err != nil {
+                return result, err
+            }
+
+            if log.Hash != hash {
+                result.Broken = &Broken{log.Seq, log.ID, "记录内容哈希不一致"}
+                return result, nil
+            }
+
+            if checkpoint, ok := checkpoints[log.Seq]; ok && checkpoint.Hash != log.Hash {
+                result.Broken = &Broken{log.Seq, log.ID, "与检查点哈希不一致"}
+                return result, nil
+            }
+
+            prevSeq = log.Seq
+            prevHash = log.Hash
+
+            result.Total++
+            result.Last = log.Seq
+        }
+    }
+
+    // 检查点之后的记录被删除，全部归档清除时最后的检查点为归档检查点
+    var latest model.ActionLogCheckpoint
+    for _, checkpoint := range checkpoints {
+        if checkpoint.Seq > latest.Seq {
+            latest = checkpoint
+        }
+    }
+
+    if latest.Seq > result.Last &&
+        !(result.Total == 0 && latest.Kind == model.CheckpointArchive) {
+        result.Broken = &Broken{result.Last + 1, "", "检查点之后的记录缺失"}
+    }
+
+    return result, nil
+}
+
+// 校验检查点签名
+func verifyCheckpoints(result *VerifyResult) (map[int64]model.ActionLogCheckpoint, error) {
+    list := make([]model.ActionLogCheckpoint, 0)
+    err := model.NewActionLogCheckpoint().
+        Order("seq ASC").
+        Find(&list).
+        Error
+    if err != nil {
+        return nil, err
+    }
+
+    checkpoints := make(map[int64]model.ActionLogCheckpoint, len(list))
+    if len(list) == 0 {
+        return checkpoints, nil
+    }
+
+    signer, err := chain.GetSigner()
+    if err != nil {
+        return nil, err
+    }
+
+    for _, checkpoint := range list {
+        if !checkpoint.Verify(signer) {
+            result.Broken = &Broken{checkpoint.Seq, "", "检查点签名错误"}
+            return nil, nil
+        }
+
+        checkpoints[checkpoint.Seq] = checkpoint
+        result.Checkpoints++
+    }
+
+    return checkpoints, nil
+}
+
+// 是否为没有配置签名
+func IsSignerNotConfigured(err error) bool {
+    return errors.Is(err, chain.ErrSignerNotConfigured)
+}
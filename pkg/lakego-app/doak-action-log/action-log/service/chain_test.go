@@ -0,0 +1,307 @@
+package service
+
+import (
+    "errors"
+    "testing"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    tables := []any{
+        &model.ActionLog{},
+        &model.ActionLogChange{},
+        &model.ActionLogCheckpoint{},
+    }
+
+    if err := model.NewDB().AutoMigrate(tables...); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(tables...)
+    })
+}
+
+func newLog(name string) *model.ActionLog {
+    return &model.ActionLog{
+        Name:   name,
+        Url:    "/admin-api/" + name,
+        Method: "POST",
+        Time:   1700000000,
+        Ip:     "127.0.0.1",
+        Status: "200",
+    }
+}
+
+// 写入多条日志，测试配置每 3 条生成一个检查点
+func writeLogs(t *testing.T, n int) []*model.ActionLog {
+    logs := make([]*model.ActionLog, 0, n)
+    for i := 0; i < n; i++ {
+        log := newLog("log")
+        if err := Append(log); err != nil {
+            t.Fatal(err)
+        }
+
+        logs = append(logs, log)
+    }
+
+    return logs
+}
+
+// 修改记录字段，不经过哈希链
+func updateLog(seq int64, column string, value any) {
+    model.NewActionLog().
+        Where("seq = ?", seq).
+        UpdateColumn(column, value)
+}
+
+// 注册写入日志前的回调
+func onCreateLog(t *testing.T, fn func(*gorm.DB)) {
+    name := "action-log:test"
+
+    err := model.NewDB().Callback().Create().
+        Before("gorm:create").
+        Register(name, func(db *gorm.DB) {
+            if _, ok := db.Statement.Model.([]*model.ActionLog); ok {
+                fn(db)
+            }
+        })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Callback().Create().Remove(name)
+    })
+}
+
+func Test_Append(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    logs := writeLogs(t, 4)
+
+    var prevHash string
+    for i, log := range logs {
+        eq(log.Seq, int64(i + 1), "Append seq")
+        eq(log.PrevHash, prevHash, "Append prev hash")
+
+        hash, _ := chain.Hash(prevHash, log.ChainContent(nil))
+        eq(log.Hash, hash, "Append hash")
+
+        prevHash = log.Hash
+    }
+
+    // 批量写入接在链尾
+    batch := []*model.ActionLog{newLog("a"), newLog("b")}
+    err := AppendBatch(batch)
+    eq(err, nil, "AppendBatch")
+    eq(batch[0].Seq, int64(5), "AppendBatch seq")
+    eq(batch[0].PrevHash, logs[3].Hash, "AppendBatch prev hash")
+    eq(batch[1].PrevHash, batch[0].Hash, "AppendBatch link")
+
+    var count int64
+    model.NewActionLogCheckpoint().Count(&count)
+    eq(count, int64(2), "Append checkpoints")
+
+    result, err := Verify()
+    eq(err, nil, "Verify error")
+    eq(result.Broken == nil, true, "Verify broken")
+    eq(result.Total, int64(6), "Verify total")
+    eq(result.Checkpoints, int64(2), "Verify checkpoints")
+    eq(result.First, int64(1), "Verify first")
+    eq(result.Last, int64(6), "Verify last")
+}
+
+func Test_Link(t *testing.T) {
+    eq := assertT(t)
+
+    log := newLog("log")
+    eq(log.Link(2, "prev"), nil, "Link error")
+    eq(log.Seq, int64(3), "Link seq")
+    eq(log.PrevHash, "prev", "Link prev hash")
+
+    hash := log.Hash
+
+    // 内容、上一条哈希和变动记录都参与计算
+    log.Link(2, "other")
+    eq(log.Hash != hash, true, "Link prev hash changed")
+
+    log.Link(2, "prev")
+    eq(log.Hash, hash, "Link same")
+
+    log.Name = "changed"
+    log.Link(2, "prev")
+    eq(log.Hash != hash, true, "Link content changed")
+
+    log.Name = "log"
+    log.Changes = []model.ActionLogChange{
+        {Entity: "admin", EntityId: "1", Action: "update", Fields: "{}"},
+    }
+    log.Link(2, "prev")
+    eq(log.Hash != hash, true, "Link changes")
+}
+
+func Test_Verify_Broken(t *testing.T) {
+    tests := []struct {
+        name   string
+        modify func(t *testing.T)
+        seq    int64
+        reason string
+    }{
+        {
+            name: "content",
+            modify: func(t *testing.T) {
+                updateLog(2, "name", "changed")
+            },
+            seq:    2,
+            reason: "记录内容哈希不一致",
+        },
+        {
+            name: "first of many",
+            modify: func(t *testing.T) {
+                updateLog(4, "name", "changed")
+                updateLog(2, "ip", "127.0.0.2")
+            },
+            seq:    2,
+            reason: "记录内容哈希不一致",
+        },
+        {
+            name: "prev hash",
+            modify: func(t *testing.T) {
+                updateLog(4, "prev_hash", "changed")
+            },
+            seq:    4,
+            reason: "上一条记录哈希不一致",
+        },
+        {
+            name: "missing",
+            modify: func(t *testing.T) {
+                model.NewActionLog().Where("seq = ?", 2).Delete(&model.ActionLog{})
+            },
+            seq:    2,
+            reason: "记录缺失",
+        },
+        {
+            name: "missing first",
+            modify: func(t *testing.T) {
+                model.NewActionLog().Where("seq = ?", 1).Delete(&model.ActionLog{})
+            },
+            seq:    2,
+            reason: "起点检查点不存在",
+        },
+        {
+            name: "missing after checkpoint",
+            modify: func(t *testing.T) {
+                model.NewActionLog().Where("seq > ?", 2).Delete(&model.ActionLog{})
+            },
+            seq:    3,
+            reason: "检查点之后的记录缺失",
+        },
+        {
+            name: "checkpoint signature",
+            modify: func(t *testing.T) {
+                model.NewActionLogCheckpoint().
+                    Where("seq = ?", 3).
+                    UpdateColumn("signature", "changed")
+            },
+            seq:    3,
+            reason: "检查点签名错误",
+        },
+        {
+            name: "checkpoint hash",
+            modify: func(t *testing.T) {
+                // 重新签名的检查点与记录哈希不一致
+                checkpoint, err := model.MakeActionLogCheckpoint(3, "changed", model.CheckpointPeriodic)
+                if err != nil {
+                    t.Fatal(err)
+                }
+
+                model.NewActionLogCheckpoint().
+                    Where("seq = ?", 3).
+                    UpdateColumns(map[string]any{
+                        "hash":      checkpoint.Hash,
+                        "signature": checkpoint.Signature,
+                    })
+            },
+            seq:    3,
+            reason: "与检查点哈希不一致",
+        },
+    }
+
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            eq := assertT(t)
+
+            migrate(t)
+
+            writeLogs(t, 5)
+
+            test.modify(t)
+
+            result, err := Verify()
+            eq(err, nil, "Verify error")
+            if result.Broken == nil {
+                t.Fatal("Verify broken is nil")
+            }
+
+            eq(result.Broken.Seq, test.seq, "Verify broken seq")
+            eq(result.Broken.Reason, test.reason, "Verify broken reason")
+        })
+    }
+}
+
+func Test_AppendBatch_RetryOnDuplicateSeq(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    // 第一次写入前其他实例占用了同一序号
+    attempts := 0
+    onCreateLog(t, func(db *gorm.DB) {
+        attempts++
+        if attempts > 1 {
+            return
+        }
+
+        db.Session(&gorm.Session{NewDB: true}).
+            Exec("INSERT INTO " + db.Statement.Quote(db.Statement.Table) + " (id, name, seq) VALUES (?, ?, ?)", "other", "other", 1)
+    })
+
+    log := newLog("log")
+    err := Append(log)
+    eq(err, nil, "Append")
+    eq(attempts, 2, "Append attempts")
+    eq(log.Seq, int64(1), "Append seq")
+}
+
+func Test_AppendBatch_NoRetryOnOtherError(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    testErr := errors.New("action-log: test error")
+
+    attempts := 0
+    onCreateLog(t, func(db *gorm.DB) {
+        attempts++
+        db.AddError(testErr)
+    })
+
+    err := Append(newLog("log"))
+    eq(errors.Is(err, testErr), true, "Append error")
+    eq(attempts, 1, "Append attempts")
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+    "fmt"
+    "bytes"
+    "strings"
+    "compress/gzip"
+    "encoding/json"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+    "github.com/deatil/lakego-doak/lakego/facade/storage"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+// 配置
+func retentionConf(key string) string {
+    return "action-log.retention." + key
+}
+
+// 日志保留天数
+func RetentionDays() int {
+    days := config.New("admin").GetInt(retentionConf("days"))
+    if days <= 0 {
+        days = 180
+    }
+
+    return days
+}
+
+/**
+ * 归档结果
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type PruneResult struct {
+    // 清除的记录数量
+    Total int64
+
+    // 归档文件
+    Files []string
+}
+
+// 归档并清除超过保留天数的日志，days 为 0 时使用配置
+func Prune(days int) (*PruneResult, error) {
+    if days <= 0 {
+        days = RetentionDays()
+    }
+
+    result := &PruneResult{
+        Files: make([]string, 0),
+    }
+
+    // 清除后剩余记录需要使用签名的检查点作为起点
+    if _, err := chain.GetSigner(); err != nil {
+        return result, err
+    }
+
+    cutoff := datebin.Now().SubDays(uint(days)).Timestamp()
+
+    // 只清除连续的最早记录，避免哈希链中间出现缺失
+    var maxSeq int64
+    err := model.NewActionLog().
+        Select("COALESCE(MAX(seq), 0)").
+        Where("time < ?", cutoff).
+        Scan(&maxSeq).
+        Error
+    if err != nil || maxSeq == 0 {
+        return result, err
+    }
+
+    cfg := config.New("admin")
+
+    batch := cfg.GetInt(retentionConf("batch"))
+    if batch <= 0 {
+        batch = 5000
+    }
+
+    disk := cfg.GetString(retentionConf("disk"))
+    if disk == "" {
+        disk = "local"
+    }
+
+    directory := strings.Trim(cfg.GetString(retentionConf("directory")), "/")
+    if directory == "" {
+        directory = "action-log"
+    }
+
+    for {
+        logs := make([]model.ActionLog, 0)
+        err := model.NewActionLog().
+            Preload("Changes").
+            Where("seq <= ?", maxSeq).
+            Order("seq ASC").
+            Limit(batch).
+            Find(&logs).
+            Error
+        if err != nil {
+            return result, err
+        }
+
+        if len(logs) == 0 {
+            break
+        }
+
+        // 清除的检查点和记录一起归档，归档后仍可校验签名
+        last := logs[len(logs) - 1]
+
+        checkpoints := make([]model.ActionLogCheckpoint, 0)
+        err = model.NewActionLogCheckpoint().
+            Where("seq <= ?", last.Seq).
+            Order("seq ASC").
+            Find(&checkpoints).
+            Error
+        if err != nil {
+            return result, err
+        }
+
+        files, err := archive(logs, checkpoints, disk, directory)
+        if err != nil {
+            return result, err
+        }
+
+        if err := prune(logs); err != nil {
+            return result, err
+        }
+
+        result.Total += int64(len(logs))
+        result.Files = append(result.Files, files...)
+    }
+
+    return result, nil
+}
+
+// 归档记录和检查点，检查点写入单独的文件
+func archive(
+    logs []model.ActionLog,
+    checkpoints []model.ActionLogCheckpoint,
+    disk string,
+    directory string,
+) ([]string, error) {
+    first := logs[0]
+    last := logs[len(logs) - 1]
+
+    name := fmt.Sprintf("%s/action-log-%d-%d", directory, first.Seq, last.Seq)
+
+    items := make([]any, 0, len(logs))
+    for _, log := range logs {
+        items = append(items, log)
+    }
+
+    files := make([]string, 0, 2)
+
+    file := name + ".ndjson.gz"
+    if err := writeArchive(file, items, disk); err != nil {
+        return files, err
+    }
+
+    files = append(files, file)
+
+    if len(checkpoints) == 0 {
+        return files, nil
+    }
+
+    items = make([]any, 0, len(checkpoints))
+    for _, checkpoint := range checkpoints {
+        items = append(items, checkpoint)
+    }
+
+    file = name + ".checkpoint.ndjson.gz"
+    if err := writeArchive(file, items, disk); err != nil {
+        return files, err
+    }
+
+    files = append(files, file)
+
+    return files, nil
+}
+
+// 写入压缩的 NDJSON 归档文件
+func writeArchive(file string, items []any, disk string) error {
+    buf := new(bytes.Buffer)
+
+    gz := gzip.NewWriter(buf)
+
+    encoder := json.NewEncoder(gz)
+    for _, item := range items {
+        if err := encoder.Encode(item); err != nil {
+            return err
+        }
+    }
+
+    if err := gz.Close(); err != nil {
+        return err
+    }
+
+    _, err := storage.NewWithDisk(disk).PutStream(file, buf)
+
+    return err
+}
+
+// 生成归档检查点后删除记录
+func prune(logs []model.ActionLog) error {
+    last := logs[len(logs) - 1]
+
+    checkpoint, err := model.MakeActionLogCheckpoint(last.Seq, last.Hash, model.CheckpointArchive)
+    if err != nil {
+        return err
+    }
+
+    ids := make([]string, 0, len(logs))
+    for _, log := range logs {
+        ids = append(ids, log.ID)
+    }
+
+    return model.NewDB().Transaction(func(tx *gorm.DB) error {
+        // 之前的检查点已写入归档文件
+        err := tx.Where("seq <= ?", last.Seq).
+            Delete(&model.ActionLogCheckpoint{}).
+            Error
+        if err != nil {
+            return err
+        }
+
+        if err := tx.Create(checkpoint).Error; err != nil {
+            return err
+        }
+
+        err = tx.Where("log_id IN ?", ids).
+            Delete(&model.ActionLogChange{}).
+            Error
+        if err != nil {
+            return err
+        }
+
+        return tx.Where("id IN ?", ids).
+            Delete(&model.ActionLog{}).
+            Error
+    })
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+    "bufio"
+    "strings"
+    "testing"
+    "compress/gzip"
+    "encoding/json"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+    "github.com/deatil/lakego-doak/lakego/facade/storage"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/chain"
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+// 临时修改配置
+func setConfig(t *testing.T, key string, value any) {
+    cfg := config.New("admin")
+
+    old := cfg.Get(key)
+    cfg.Set(key, value)
+
+    t.Cleanup(func() {
+        cfg.Set(key, old)
+    })
+}
+
+// 读取归档文件
+func readArchive[T any](t *testing.T, file string) []T {
+    fs := storage.NewWithDisk("public")
+
+    t.Cleanup(func() {
+        fs.Delete(file)
+    })
+
+    f, err := fs.ReadStream(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+
+    gz, err := gzip.NewReader(f)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    items := make([]T, 0)
+
+    scanner := bufio.NewScanner(gz)
+    for scanner.Scan() {
+        var item T
+        if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+            t.Fatal(err)
+        }
+
+        items = append(items, item)
+    }
+
+    return items
+}
+
+func Test_Prune_Verify(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    setConfig(t, retentionConf("disk"), "public")
+    setConfig(t, retentionConf("directory"), "action-log-test")
+    setConfig(t, retentionConf("batch"), 2)
+
+    // 前 5 条超过保留天数，检查点为 3 和 6
+    writeLogs(t, 5)
+
+    for i := 0; i < 3; i++ {
+        log := newLog("log")
+        log.Time = int(datebin.NowTimestamp())
+
+        if err := Append(log); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    result, err := Prune(30)
+    eq(err, nil, "Prune error")
+    eq(result.Total, int64(5), "Prune total")
+
+    verify, err := Verify()
+    eq(err, nil, "Verify error")
+    eq(verify.Broken == nil, true, "Verify broken")
+    eq(verify.First, int64(6), "Verify first")
+    eq(verify.Last, int64(8), "Verify last")
+
+    // 归档文件中的记录和检查点
+    logs := make([]model.ActionLog, 0)
+    checkpoints := make([]model.ActionLogCheckpoint, 0)
+    for _, file := range result.Files {
+        if strings.HasSuffix(file, ".checkpoint.ndjson.gz") {
+            checkpoints = append(checkpoints, readArchive[model.ActionLogCheckpoint](t, file)...)
+        } else {
+            logs = append(logs, readArchive[model.ActionLog](t, file)...)
+        }
+    }
+
+    eq(len(logs), 5, "archive logs")
+
+    signer, err := chain.GetSigner()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 归档记录的哈希链完整
+    var prevHash string
+    for i, log := range logs {
+        eq(log.Seq, int64(i + 1), "archive log seq")
+        eq(log.PrevHash, prevHash, "archive log prev hash")
+
+        hash, _ := chain.HashWith(log.HashAlgo, log.PrevHash, log.ChainContent(log.Changes))
+        eq(log.Hash, hash, "archive log hash")
+
+        prevHash = log.Hash
+    }
+
+    // 定期检查点已归档，签名仍然有效
+    periodic := 0
+    for _, checkpoint := range checkpoints {
+        eq(checkpoint.Verify(signer), true, "archive checkpoint signature")
+        eq(checkpoint.Hash, logs[checkpoint.Seq - 1].Hash, "archive checkpoint hash")
+
+        if checkpoint.Kind == model.CheckpointPeriodic {
+            periodic++
+        }
+    }
+    eq(periodic, 1, "archive periodic checkpoints")
+
+    // 剩余记录的起点为最后的归档检查点
+    var start model.ActionLogCheckpoint
+    model.NewActionLogCheckpoint().Where("seq = ?", 5).First(&start)
+    eq(start.Kind, model.CheckpointArchive, "archive start checkpoint")
+    eq(start.Hash, logs[4].Hash, "archive start checkpoint hash")
+}
+
+func Test_Verify_HashChanged(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    logs := writeLogs(t, 2)
+    eq(logs[0].HashAlgo, "sha256", "HashAlgo")
+
+    // 修改算法后旧记录使用记录保存的算法校验
+    setConfig(t, "action-log.chain.hash", "sm3")
+
+    more := writeLogs(t, 2)
+    eq(more[0].HashAlgo, "sm3", "HashAlgo changed")
+
+    result, err := Verify()
+    eq(err, nil, "Verify error")
+    eq(result.Broken == nil, true, "Verify broken")
+    eq(result.Total, int64(4), "Verify total")
+
+    var algo string
+    model.NewActionLog().Where("seq = ?", 1).Select("hash_algo").Scan(&algo)
+    eq(algo, "sha256", "HashAlgo stored")
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+    "os"
+    "sync"
+    "time"
+    "bufio"
+    "errors"
+    "encoding/json"
+    "path/filepath"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/metrics"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+// 缓冲区满时的处理方式
+const (
+    // 等待写入
+    OverflowBlock = "block"
+
+    // 丢弃最早的记录
+    OverflowDropOldest = "drop-oldest"
+
+    // 写入本地文件，之后再导入
+    OverflowSpill = "spill"
+)
+
+var (
+    // 关闭超时
+    ErrWriterCloseTimeout = errors.New("action-log: writer close timeout")
+)
+
+// 默认写入器
+var (
+    defaultWriter *Writer
+    defaultWriterMu sync.RWMutex
+)
+
+// 配置
+func writerConf(key string) string {
+    return "action-log.writer." + key
+}
+
+/**
+ * 日志异步写入，缓冲后批量写入数据库
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Writer struct {
+    // 锁
+    mu sync.Mutex
+
+    // 缓冲区有空位
+    notFull *sync.Cond
+
+    // 缓冲区
+    buffer []*model.ActionLog
+
+    // 缓冲区大小
+    size int
+
+    // 单次写入数量
+    batch int
+
+    // 写入间隔
+    interval time.Duration
+
+    // 缓冲区满时的处理方式
+    overflow string
+
+    // 溢出文件
+    spillFile string
+
+    // 溢出文件锁
+    spillMu sync.Mutex
+
+    // 通知写入
+    notify chan struct{}
+
+    // 退出
+    quit chan struct{}
+
+    // 退出完成
+    done chan struct{}
+
+    // 是否关闭
+    closed bool
+
+    // 关闭一次
+    closeOnce sync.Once
+
+    // 指标
+    flushed *metrics.Counter
+    dropped *metrics.Counter
+    spilled *metrics.Counter
+    failed  *metrics.Counter
+}
+
+// 构造函数
+func NewWriter(size int, batch int, interval time.Duration, overflow string, spillFile string) *Writer {
+    if size <= 0 {
+        size = 10000
+    }
+    if batch <= 0 {
+        batch = 100
+    }
+    if batch > size {
+        batch = size
+    }
+    if interval <= 0 {
+        interval = time.Second
+    }
+
+    // 没有溢出文件时不能使用 spill
+    if overflow == OverflowSpill && spillFile == "" {
+        overflow = OverflowBlock
+    }
+
+    w := &Writer{
+        buffer:    make([]*model.ActionLog, 0, size),
+        size:      size,
+        batch:     batch,
+        interval:  interval,
+        overflow:  overflow,
+        spillFile: spillFile,
+        notify:    make(chan struct{}, 1),
+        quit:      make(chan struct{}),
+        done:      make(chan struct{}),
+        flushed:   metrics.GetCounter("action-log.writer.flushed"),
+        dropped:   metrics.GetCounter("action-log.writer.dropped"),
+        spilled:   metrics.GetCounter("action-log.writer.spilled"),
+        failed:    metrics.GetCounter("action-log.writer.failed"),
+    }
+
+    w.notFull = sync.NewCond(&w.mu)
+
+    metrics.SetGauge("action-log.writer.buffered", func() int64 {
+        return int64(w.Len())
+    })
+
+    return w
+}
+
+// 运行
+func (this *Writer) Run() {
+    ticker := time.NewTicker(this.interval)
+    defer ticker.Stop()
+
+    defer close(this.done)
+
+    for {
+        select {
+            case <-this.quit:
+                this.flush()
+                return
+            case <-ticker.C:
+            case <-this.notify:
+        }
+
+        this.flush()
+        this.recoverSpill()
+    }
+}
+
+// 写入，关闭后直接写入数据库
+func (this *Writer) Write(log *model.ActionLog) error {
+    this.mu.Lock()
+
+    for !this.closed && len(this.buffer) >= this.size {
+        switch this.overflow {
+            case OverflowDropOldest:
+                this.buffer[0] = nil
+                this.buffer = this.buffer[1:]
+                this.dropped.Inc()
+
+            case OverflowSpill:
+                this.mu.Unlock()
+                return this.spill([]*model.ActionLog{log})
+
+            default:
+                this.notFull.Wait()
+        }
+    }
+
+    if this.closed {
+        this.mu.Unlock()
+        return Append(log)
+    }
+
+    this.buffer = append(this.buffer, log)
+    full := len(this.buffer) >= this.batch
+
+    this.mu.Unlock()
+
+    if full {
+        select {
+            case this.notify <- struct{}{}:
+            default:
+        }
+    }
+
+    return nil
+}
+
+// 缓冲数量
+func (this *Writer) Len() int {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    return len(this.buffer)
+}
+
+// 关闭，等待缓冲区写入完成
+func (this *Writer) Close(timeout time.Duration) error {
+    this.closeOnce.Do(func() {
+        this.mu.Lock()
+        this.closed = true
+        this.notFull.Broadcast()
+        this.mu.Unlock()
+
+        close(this.quit)
+    })
+
+    if timeout <= 0 {
+        <-this.done
+        return nil
+    }
+
+    select {
+        case <-this.done:
+            return nil
+        case <-time.After(timeout):
+            return ErrWriterCloseTimeout
+    }
+}
+
+// 写入缓冲区全部记录
+func (this *Writer) flush() {
+    for {
+        logs := this.take()
+        if len(logs) == 0 {
+            return
+        }
+
+        if err := AppendBatch(logs); err != nil {
+            facade.Logger.Error("[action-log] writer: " + err.Error())
+
+            this.failed.Add(int64(len(logs)))
+
+            // 写入失败时保存到溢出文件，之后再导入
+            if this.spillFile == "" || this.spill(logs) != nil {
+                this.dropped.Add(int64(len(logs)))
+            }
+
+            return
+        }
+
+        this.flushed.Add(int64(len(logs)))
+    }
+}
+
+// 取出一批记录
+func (this *Writer) take() []*model.ActionLog {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    n := len(this.buffer)
+    if n > this.batch {
+        n = this.batch
+    }
+
+    logs := make([]*model.ActionLog, n)
+    copy(logs, this.buffer[:n])
+
+    rest := copy(this.buffer, this.buffer[n:])
+    for i := rest; i < len(this.buffer); i++ {
+        this.buffer[i] = nil
+    }
+    this.buffer = this.buffer[:rest]
+
+    if n > 0 {
+        this.notFull.Broadcast()
+    }
+
+    return logs
+}
+
+// 写入溢出文件
+func (this *Writer) spill(logs []*model.ActionLog) error {
+    this.spillMu.Lock()
+    defer this.spillMu.Unlock()
+
+    file := path.FormatPath(this.spillFile)
+    if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+        return err
+    }
+
+    f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    encoder := json.NewEncoder(f)
+    for _, log := range logs {
+        if err := encoder.Encode(log); err != nil {
+            return err
+        }
+    }
+
+    this.spilled.Add(int64(len(logs)))
+
+    return nil
+}
+
+// 缓冲区空闲时导入溢出文件
+func (this *Writer) recoverSpill() {
+    if this.spillFile == "" || this.Len() > this.size / 2 {
+        return
+    }
+
+    this.spillMu.Lock()
+    defer this.spillMu.Unlock()
+
+    file := path.FormatPath(this.spillFile)
+
+    // 导入中的文件，上次导入失败时继续导入
+    recoverFile := file + ".recover"
+    if _, err := os.Stat(recoverFile); err != nil {
+        if _, err := os.Stat(file); err != nil {
+            return
+        }
+
+        if err := os.Rename(file, recoverFile); err != nil {
+            facade.Logger.Error("[action-log] writer recover: " + err.Error())
+            return
+        }
+    }
+
+    logs, err := readSpill(recoverFile)
+    if err != nil {
+        facade.Logger.Error("[action-log] writer recover: " + err.Error())
+        return
+    }
+
+    for len(logs) > 0 {
+        n := len(logs)
+        if n > this.batch {
+            n = this.batch
+        }
+
+        if err := AppendBatch(logs[:n]); err != nil {
+            facade.Logger.Error("[action-log] writer recover: " + err.Error())
+
+            // 保留未导入的记录
+            if err := writeSpill(recoverFile, logs); err != nil {
+                facade.Logger.Error("[action-log] writer recover: " + err.Error())
+            }
+
+            return
+        }
+
+        this.flushed.Add(int64(n))
+        logs = logs[n:]
+    }
+
+    os.Remove(recoverFile)
+}
+
+// 读取溢出文件
+func readSpill(file string) ([]*model.ActionLog, error) {
+    f, err := os.Open(file)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    logs := make([]*model.ActionLog, 0)
+
+    decoder := json.NewDecoder(bufio.NewReader(f))
+    for decoder.More() {
+        log := &model.ActionLog{}
+        if err := decoder.Decode(log); err != nil {
+            return logs, err
+        }
+
+        logs = append(logs, log)
+    }
+
+    return logs, nil
+}
+
+// 覆盖写入溢出文件
+func writeSpill(file string, logs []*model.ActionLog) error {
+    f, err := os.Create(file)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    encoder := json.NewEncoder(f)
+    for _, log := range logs {
+        if err := encoder.Encode(log); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 根据配置启动默认写入器，没有开启异步写入时不启动
+func StartWriter() {
+    cfg := config.New("admin")
+    if !cfg.GetBool(writerConf("async")) {
+        return
+    }
+
+    defaultWriterMu.Lock()
+    defer defaultWriterMu.Unlock()
+
+    if defaultWriter != nil {
+        return
+    }
+
+    defaultWriter = NewWriter(
+        cfg.GetInt(writerConf("buffer-size")),
+        cfg.GetInt(writerConf("batch-size")),
+        cfg.GetDuration(writerConf("flush-interval")),
+        cfg.GetString(writerConf("overflow")),
+        cfg.GetString(writerConf("spill-file")),
+    )
+
+    go defaultWriter.Run()
+}
+
+// 关闭默认写入器，写入缓冲区剩余记录
+func StopWriter() error {
+    defaultWriterMu.Lock()
+    w := defaultWriter
+    defaultWriter = nil
+    defaultWriterMu.Unlock()
+
+    if w == nil {
+        return nil
+    }
+
+    return w.Close(config.New("admin").GetDuration(writerConf("shutdown-timeout")))
+}
+
+// 记录日志，没有启动异步写入时直接写入
+func Record(log *model.ActionLog) error {
+    defaultWriterMu.RLock()
+    w := defaultWriter
+    defaultWriterMu.RUnlock()
+
+    if w == nil {
+        return Append(log)
+    }
+
+    return w.Write(log)
+}
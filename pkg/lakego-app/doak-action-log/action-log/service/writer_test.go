@@ -0,0 +1,252 @@
+package service
+
+import (
+    "os"
+    "time"
+    "testing"
+    "path/filepath"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-action-log/action-log/model"
+)
+
+// 数据库中的记录名称
+func logNames() []string {
+    names := make([]string, 0)
+    model.NewActionLog().
+        Order("seq ASC").
+        Pluck("name", &names)
+
+    return names
+}
+
+// 缓冲区中的记录名称
+func bufferNames(w *Writer) []string {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    names := make([]string, 0, len(w.buffer))
+    for _, log := range w.buffer {
+        names = append(names, log.Name)
+    }
+
+    return names
+}
+
+func spillFile(t *testing.T) string {
+    return filepath.Join(t.TempDir(), "spill.ndjson")
+}
+
+func Test_Writer_Flush(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    w := NewWriter(10, 2, time.Hour, OverflowBlock, "")
+
+    for _, name := range []string{"a", "b", "c"} {
+        eq(w.Write(newLog(name)), nil, "Write " + name)
+    }
+    eq(w.Len(), 3, "Len")
+
+    // 分批写入全部记录
+    w.flush()
+    eq(w.Len(), 0, "Len after flush")
+    eq(len(logNames()), 3, "flush")
+}
+
+func Test_Writer_OverflowBlock(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    w := NewWriter(2, 2, time.Hour, OverflowBlock, "")
+
+    w.Write(newLog("a"))
+    w.Write(newLog("b"))
+
+    written := make(chan error, 1)
+    go func() {
+        written <- w.Write(newLog("c"))
+    }()
+
+    // 缓冲区满时等待
+    select {
+        case <-written:
+            t.Fatal("Write not blocked")
+        case <-time.After(50 * time.Millisecond):
+    }
+
+    w.flush()
+
+    select {
+        case err := <-written:
+            eq(err, nil, "Write after flush")
+        case <-time.After(time.Second):
+            t.Fatal("Write still blocked")
+    }
+
+    w.flush()
+    eq(len(logNames()), 3, "OverflowBlock all written")
+}
+
+func Test_Writer_OverflowDropOldest(t *testing.T) {
+    eq := assertT(t)
+
+    w := NewWriter(2, 2, time.Hour, OverflowDropOldest, "")
+
+    dropped := w.dropped.Value()
+
+    for _, name := range []string{"a", "b", "c", "d"} {
+        eq(w.Write(newLog(name)), nil, "Write " + name)
+    }
+
+    names := bufferNames(w)
+    eq(len(names), 2, "OverflowDropOldest len")
+    eq(names[0], "c", "OverflowDropOldest first")
+    eq(names[1], "d", "OverflowDropOldest last")
+    eq(w.dropped.Value() - dropped, int64(2), "OverflowDropOldest dropped")
+}
+
+func Test_Writer_OverflowSpill(t *testing.T) {
+    eq := assertT(t)
+
+    file := spillFile(t)
+
+    w := NewWriter(2, 2, time.Hour, OverflowSpill, file)
+
+    for _, name := range []string{"a", "b", "c", "d"} {
+        eq(w.Write(newLog(name)), nil, "Write " + name)
+    }
+
+    eq(w.Len(), 2, "OverflowSpill buffer")
+
+    logs, err := readSpill(file)
+    eq(err, nil, "readSpill error")
+    eq(len(logs), 2, "OverflowSpill spilled")
+    eq(logs[0].Name, "c", "OverflowSpill first")
+    eq(logs[1].Name, "d", "OverflowSpill last")
+
+    // 没有溢出文件时等待写入
+    eq(NewWriter(2, 2, time.Hour, OverflowSpill, "").overflow, OverflowBlock, "OverflowSpill without file")
+}
+
+func Test_Writer_FlushFailedSpill(t *testing.T) {
+    eq := assertT(t)
+
+    file := spillFile(t)
+
+    w := NewWriter(10, 10, time.Hour, OverflowBlock, file)
+    w.Write(newLog("a"))
+    w.Write(newLog("b"))
+
+    // 没有数据表时写入失败，保存到溢出文件
+    w.flush()
+    eq(w.Len(), 0, "flush failed buffer")
+
+    logs, err := readSpill(file)
+    eq(err, nil, "readSpill error")
+    eq(len(logs), 2, "flush failed spilled")
+}
+
+func Test_Writer_RecoverSpill(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    file := spillFile(t)
+
+    w := NewWriter(10, 2, time.Hour, OverflowBlock, file)
+
+    // 上次没有导入完成的文件和新的溢出文件
+    err := writeSpill(file + ".recover", []*model.ActionLog{newLog("a"), newLog("b"), newLog("c")})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    w.spill([]*model.ActionLog{newLog("d")})
+
+    w.recoverSpill()
+
+    names := logNames()
+    eq(len(names), 3, "recoverSpill recover file")
+    eq(names[0], "a", "recoverSpill order")
+
+    _, err = os.Stat(file + ".recover")
+    eq(os.IsNotExist(err), true, "recoverSpill remove recover file")
+
+    // 下次导入新的溢出文件
+    w.recoverSpill()
+    eq(len(logNames()), 4, "recoverSpill spill file")
+
+    _, err = os.Stat(file)
+    eq(os.IsNotExist(err), true, "recoverSpill move spill file")
+
+    result, err := Verify()
+    eq(err, nil, "Verify error")
+    eq(result.Broken == nil, true, "Verify broken")
+}
+
+func Test_Writer_RecoverSpillFailed(t *testing.T) {
+    eq := assertT(t)
+
+    file := spillFile(t)
+
+    w := NewWriter(10, 2, time.Hour, OverflowBlock, file)
+    w.spill([]*model.ActionLog{newLog("a"), newLog("b"), newLog("c")})
+
+    // 没有数据表时导入失败，保留未导入的记录
+    w.recoverSpill()
+
+    logs, err := readSpill(file + ".recover")
+    eq(err, nil, "readSpill error")
+    eq(len(logs), 3, "recoverSpill failed kept")
+
+    migrate(t)
+
+    w.recoverSpill()
+    eq(len(logNames()), 3, "recoverSpill after failed")
+}
+
+func Test_StopWriter(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    cfg := config.New("admin")
+    eq(cfg.GetBool(writerConf("async")), true, "async")
+    eq(cfg.GetDuration(writerConf("flush-interval")), time.Hour, "flush-interval")
+
+    StartWriter()
+
+    // 不满一批时等待写入间隔
+    eq(Record(newLog("a")), nil, "Record")
+    eq(len(logNames()), 0, "Record buffered")
+
+    // 关闭时写入缓冲区剩余记录
+    eq(StopWriter(), nil, "StopWriter")
+    eq(len(logNames()), 1, "StopWriter flushed")
+
+    // 关闭后直接写入
+    eq(Record(newLog("b")), nil, "Record after stop")
+    eq(len(logNames()), 2, "Record after stop written")
+}
+
+func Test_Writer_WriteAfterClose(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    w := NewWriter(2, 2, time.Hour, OverflowBlock, "")
+    go w.Run()
+
+    w.Write(newLog("a"))
+    eq(w.Close(time.Second), nil, "Close")
+    eq(len(logNames()), 1, "Close flushed")
+
+    // 关闭后直接写入数据库
+    eq(w.Write(newLog("b")), nil, "Write after close")
+    eq(w.Len(), 0, "Write after close buffer")
+    eq(len(logNames()), 2, "Write after close written")
+}
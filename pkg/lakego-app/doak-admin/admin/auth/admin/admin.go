@@ -6,6 +6,8 @@ import (
     "github.com/deatil/lakego-doak/lakego/facade/permission"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    "github.com/deatil/lakego-doak-admin/admin/auth/twofactor"
     adminRepository "github.com/deatil/lakego-doak-admin/admin/repository/admin"
     authruleRepository "github.com/deatil/lakego-doak-admin/admin/repository/authrule"
     authgroupRepository "github.com/deatil/lakego-doak-admin/admin/repository/authgroup"
@@ -18,6 +20,7 @@ type Admin struct {
     AccessToken string
     Avatar      string
     AllGroup    []map[string]any
+    Domain      string
 }
 
 func New() *Admin {
@@ -54,6 +57,20 @@ func (this *Admin) GetData() map[string]any {
     return this.Data
 }
 
+func (this *Admin) WithDomain(domain string) *Admin {
+    this.Domain = domain
+
+    return this
+}
+
+func (this *Admin) GetDomain() string {
+    if this.Domain == "" {
+        return tenant.Default
+    }
+
+    return this.Domain
+}
+
 func (this *Admin) WithAvatar(avatar string) *Admin {
     this.Avatar = avatar
 
@@ -141,6 +158,23 @@ func (this *Admin) IsGroupActive() bool {
     return !status
 }
 
+// 是否开启两步验证
+func (this *Admin) IsTwoFactorEnabled() bool {
+    status, ok := this.Data["totp_status"].(float64)
+    if !ok {
+        return false
+    }
+
+    return int(status) == twofactor.StatusEnabled
+}
+
+// 是否需要先开启两步验证
+func (this *Admin) MustEnrollTwoFactor() bool {
+    isRoot, _ := this.Data["is_root"].(float64)
+
+    return twofactor.IsForced(int(isRoot)) && !this.IsTwoFactorEnabled()
+}
+
 // 当前账号信息
 func (this *Admin) GetProfile() map[string]any {
     profile := collection.Collect(this.Data).
@@ -154,6 +188,7 @@ func (this *Admin) GetProfile() map[string]any {
     profile["avatar"] = this.GetAvatar()
     profile["groups"] = this.GetGroups()
     profile["is_sa"] = this.IsSuperAdministrator()
+    profile["totp_status"] = this.IsTwoFactorEnabled()
 
     return profile
 }
@@ -164,7 +199,7 @@ func (this *Admin) HasAccess(slug string, method string) bool {
         return true
     }
 
-    can, _ := permission.New().Enforce(this.Id, slug, method)
+    can, _ := permission.New().EnforceInDomain(this.Id, this.GetDomain(), slug, method)
     if can {
         return true
     }
@@ -0,0 +1,252 @@
+package apitoken
+
+import (
+    "errors"
+    "strings"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/support/url"
+    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+    authruleRepository "github.com/deatil/lakego-doak-admin/admin/repository/authrule"
+)
+
+// 令牌前缀
+const Prefix = "lkg_"
+
+// 令牌状态
+const (
+    StatusRevoked = 0
+    StatusActive  = 1
+)
+
+// 最后使用时间更新间隔，单位秒
+const touchInterval = 60
+
+// 访问令牌不能使用的权限，授权范围包含时同样不允许
+var deniedScopes = []string{
+    "lakego-admin.profile.token-create",
+    "lakego-admin.profile.token-delete",
+}
+
+var (
+    // 令牌不存在
+    ErrTokenNotFound = errors.New("apitoken: token not found")
+
+    // 令牌已过期
+    ErrTokenExpired = errors.New("apitoken: token expired")
+
+    // 令牌数量超过限制
+    ErrTooManyTokens = errors.New("apitoken: too many tokens")
+)
+
+// 配置
+func conf(key string) string {
+    return "passport.api-token." + key
+}
+
+// 最长有效天数，为 0 时不限制
+func MaxLifetime() int {
+    return config.New("auth").GetInt(conf("max-lifetime"))
+}
+
+// 每个账号最多令牌数量
+func MaxTokens() int64 {
+    maxTokens := config.New("auth").GetInt64(conf("max-tokens"))
+    if maxTokens <= 0 {
+        maxTokens = 20
+    }
+
+    return maxTokens
+}
+
+// 是否为访问令牌
+func IsToken(token string) bool {
+    return strings.HasPrefix(token, Prefix)
+}
+
+// 生成令牌，返回明文令牌
+func Create(adminId string, name string, expiresTime int, scopes []string, ip string) (*model.AdminToken, string, error) {
+    var count int64
+    model.NewAdminToken().
+        Where("admin_id = ? AND status = ?", adminId, StatusActive).
+        Where("expires_time = 0 OR expires_time >= ?", datebin.NowTimestamp()).
+        Count(&count)
+    if count >= MaxTokens() {
+        return nil, "", ErrTooManyTokens
+    }
+
+    buf := make([]byte, 32)
+    if _, err := rand.Read(buf); err != nil {
+        return nil, "", err
+    }
+
+    plain := Prefix + hex.EncodeToString(buf)
+
+    scopeData, _ := json.Marshal(scopes)
+
+    token := &model.AdminToken{
+        AdminId:     adminId,
+        Name:        name,
+        Token:       utils.SHA256(plain),
+        Hint:        plain[:len(Prefix) + 6] + "...",
+        Scopes:      string(scopeData),
+        Status:      StatusActive,
+        ExpiresTime: expiresTime,
+        AddTime:     int(datebin.NowTimestamp()),
+        AddIp:       ip,
+    }
+
+    if err := model.NewDB().Create(token).Error; err != nil {
+        return nil, "", err
+    }
+
+    return token, plain, nil
+}
+
+// 检测令牌是否有效，同时更新最后使用时间
+func Check(plain string, ip string) (*model.AdminToken, error) {
+    if !IsToken(plain) {
+        return nil, ErrTokenNotFound
+    }
+
+    token := new(model.AdminToken)
+    err := model.NewAdminToken().
+        Where("token = ?", utils.SHA256(plain)).
+        First(token).
+        Error
+    if err != nil || token.Status != StatusActive {
+        return nil, ErrTokenNotFound
+    }
+
+    now := int(datebin.NowTimestamp())
+
+    if token.ExpiresTime > 0 && token.ExpiresTime < now {
+        return nil, ErrTokenExpired
+    }
+
+    if now - token.LastUsedTime > touchInterval || token.LastUsedIp != ip {
+        model.NewAdminToken().
+            Where("id = ?", token.ID).
+            Updates(map[string]any{
+                "last_used_time": now,
+                "last_used_ip": ip,
+            })
+    }
+
+    return token, nil
+}
+
+// 账号令牌列表
+func List(adminId string) ([]map[string]any, error) {
+    list := make([]map[string]any, 0)
+
+    err := model.NewAdminToken().
+        Select([]string{
+            "id", "name", "hint", "scopes",
+            "expires_time", "last_used_time", "last_used_ip",
+            "add_time", "add_ip",
+        }).
+        Where("admin_id = ? AND status = ?", adminId, StatusActive).
+        Order("add_time DESC").
+        Find(&list).
+        Error
+    if err != nil {
+        return nil, err
+    }
+
+    now := datebin.NowTimestamp()
+    for _, item := range list {
+        item["scopes"] = ParseScopes(item["scopes"])
+
+        expiresTime := goch.ToInt64(item["expires_time"])
+        item["expired"] = expiresTime > 0 && expiresTime < now
+    }
+
+    return list, nil
+}
+
+// 撤销令牌
+func Revoke(adminId string, id string) (bool, error) {
+    result := model.NewAdminToken().
+        Where("id = ? AND admin_id = ? AND status = ?", id, adminId, StatusActive).
+        Updates(map[string]any{
+            "status": StatusRevoked,
+            "revoke_time": int(datebin.NowTimestamp()),
+        })
+    if result.Error != nil {
+        return false, result.Error
+    }
+
+    return result.RowsAffected > 0, nil
+}
+
+// 撤销账号全部令牌
+func RevokeAll(adminId string) (int64, error) {
+    result := model.NewAdminToken().
+        Where("admin_id = ? AND status = ?", adminId, StatusActive).
+        Updates(map[string]any{
+            "status": StatusRevoked,
+            "revoke_time": int(datebin.NowTimestamp()),
+        })
+
+    return result.RowsAffected, result.Error
+}
+
+// 解析授权的权限标识
+func ParseScopes(data any) []string {
+    scopes := make([]string, 0)
+
+    if str, ok := data.(string); ok && str != "" {
+        json.Unmarshal([]byte(str), &scopes)
+    }
+
+    return scopes
+}
+
+// 权限是否不能授权给访问令牌
+func IsDeniedScope(scope string) bool {
+    return array.InArray(scope, deniedScopes)
+}
+
+// 当前请求是否在令牌授权范围内
+func Allow(ctx *router.Context, token *model.AdminToken) bool {
+    scopes := ParseScopes(token.Scopes)
+    if len(scopes) == 0 {
+        return false
+    }
+
+    rules := authruleRepository.MatchRouteRules(ctx.Request.Method, url.RoutePath(ctx))
+    for _, rule := range rules {
+        if rule.Status != 1 || IsDeniedScope(rule.Slug) {
+            continue
+        }
+
+        if array.InArray(rule.Slug, scopes) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// 当前请求使用的令牌
+func FromContext(ctx *router.Context) (*model.AdminToken, bool) {
+    data, ok := ctx.Get("api_token")
+    if !ok {
+        return nil, false
+    }
+
+    token, ok := data.(*model.AdminToken)
+
+    return token, ok
+}
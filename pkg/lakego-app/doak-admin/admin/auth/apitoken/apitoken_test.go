@@ -0,0 +1,160 @@
+package apitoken
+
+import (
+    "strings"
+    "testing"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.AdminToken{}, &model.AuthRule{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.AdminToken{}, &model.AuthRule{})
+    })
+}
+
+func createRule(t *testing.T, method string, url string, slug string) {
+    err := model.NewDB().Create(&model.AuthRule{
+        Parentid: "0",
+        Title:    slug,
+        Url:      url,
+        Method:   method,
+        Slug:     slug,
+        Status:   1,
+    }).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+// 请求路由并检测令牌权限
+func allow(token *model.AdminToken, method string, route string, path string) bool {
+    gin.SetMode(gin.TestMode)
+
+    var allowed bool
+
+    engine := gin.New()
+    engine.Handle(method, "/admin-api" + route, func(ctx *router.Context) {
+        allowed = Allow(ctx, token)
+    })
+
+    engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/admin-api" + path, nil))
+
+    return allowed
+}
+
+func Test_Check(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    token, plain, err := Create("admin-1", "test", 0, []string{"lakego-admin.admin.index"}, "127.0.0.1")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(IsToken(plain), true, "IsToken")
+    eq(strings.HasPrefix(token.Hint, plain[:10]), true, "Hint")
+    eq(token.Token != plain, true, "Token hashed")
+
+    checked, err := Check(plain, "127.0.0.2")
+    eq(err, nil, "Check")
+    eq(checked.ID, token.ID, "Check ID")
+
+    var updated model.AdminToken
+    model.NewAdminToken().Where("id = ?", token.ID).First(&updated)
+    eq(updated.LastUsedIp, "127.0.0.2", "Check touch")
+
+    _, err = Check(plain + "0", "127.0.0.1")
+    eq(err, ErrTokenNotFound, "Check wrong token")
+
+    _, err = Check("eyJhbGciOiJIUzI1NiJ9", "127.0.0.1")
+    eq(err, ErrTokenNotFound, "Check jwt")
+
+    // 撤销后不能使用
+    ok, err := Revoke("admin-2", token.ID)
+    eq(ok, false, "Revoke other admin")
+
+    ok, err = Revoke("admin-1", token.ID)
+    eq(err, nil, "Revoke error")
+    eq(ok, true, "Revoke")
+
+    _, err = Check(plain, "127.0.0.1")
+    eq(err, ErrTokenNotFound, "Check revoked")
+
+    // 过期
+    _, expiredPlain, _ := Create("admin-1", "expired", int(datebin.NowTimestamp()) - 10, []string{"lakego-admin.admin.index"}, "127.0.0.1")
+
+    _, err = Check(expiredPlain, "127.0.0.1")
+    eq(err, ErrTokenExpired, "Check expired")
+}
+
+func Test_Allow(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    createRule(t, "GET", "/admin", "lakego-admin.admin.index")
+    createRule(t, "GET", "/admin/{id}", "lakego-admin.admin.detail")
+    createRule(t, "DELETE", "/admin/{id}", "lakego-admin.admin.delete")
+
+    token, _, err := Create("admin-1", "test", 0, []string{
+        "lakego-admin.admin.index",
+        "lakego-admin.admin.detail",
+    }, "127.0.0.1")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(allow(token, "GET", "/admin", "/admin"), true, "Allow index")
+    eq(allow(token, "GET", "/admin/:id", "/admin/1"), true, "Allow detail")
+    eq(allow(token, "DELETE", "/admin/:id", "/admin/1"), false, "Allow not in scopes")
+    eq(allow(token, "GET", "/attachment", "/attachment"), false, "Allow no rule")
+
+    // 没有授权范围
+    empty := &model.AdminToken{
+        Scopes: "[]",
+    }
+    eq(allow(empty, "GET", "/admin", "/admin"), false, "Allow empty scopes")
+}
+
+func Test_Allow_DeniedScopes(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    createRule(t, "GET", "/profile/tokens", "lakego-admin.profile.tokens")
+    createRule(t, "POST", "/profile/tokens", "lakego-admin.profile.token-create")
+    createRule(t, "DELETE", "/profile/tokens/{id}", "lakego-admin.profile.token-delete")
+
+    // 授权范围包含令牌管理时同样不允许
+    token := &model.AdminToken{
+        Scopes: `["lakego-admin.profile.tokens","lakego-admin.profile.token-create","lakego-admin.profile.token-delete"]`,
+    }
+
+    eq(allow(token, "GET", "/profile/tokens", "/profile/tokens"), true, "Allow token list")
+    eq(allow(token, "POST", "/profile/tokens", "/profile/tokens"), false, "Allow token create")
+    eq(allow(token, "DELETE", "/profile/tokens/:id", "/profile/tokens/1"), false, "Allow token delete")
+
+    eq(IsDeniedScope("lakego-admin.profile.token-create"), true, "IsDeniedScope create")
+    eq(IsDeniedScope("lakego-admin.profile.token-delete"), true, "IsDeniedScope delete")
+    eq(IsDeniedScope("lakego-admin.profile.tokens"), false, "IsDeniedScope list")
+}
@@ -0,0 +1,324 @@
+package auth
+
+import (
+    "sort"
+    "sync"
+    "errors"
+    "context"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+var (
+    // 登录方式不存在
+    ErrProviderNotFound = errors.New("auth: provider not found")
+
+    // 驱动不存在
+    ErrDriverNotFound = errors.New("auth: driver not found")
+
+    // 账号或者密码错误
+    ErrInvalidCredentials = errors.New("auth: invalid credentials")
+)
+
+type (
+    // 驱动，根据配置生成验证器
+    DriverFunc = func(name string, conf Options) (Authenticator, error)
+)
+
+/**
+ * 外部账号信息
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Identity struct {
+    // 登录方式名称
+    Provider string
+
+    // 外部账号唯一标识
+    Subject string
+
+    // 账号
+    Name string
+
+    // 昵称
+    Nickname string
+
+    // 邮箱
+    Email string
+
+    // 邮箱是否已验证，已验证时才可以关联本地账号
+    EmailVerified bool
+
+    // 所属分组，LDAP 为分组 DN，OIDC 为 groups 声明
+    Groups []string
+}
+
+/**
+ * 验证器
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Authenticator interface {
+    // 验证并返回外部账号信息
+    // LDAP 使用 name 和 password，OIDC 使用 code 和 nonce
+    Authenticate(ctx context.Context, credentials map[string]string) (*Identity, error)
+}
+
+/**
+ * 需要跳转到外部页面登录的验证器
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Redirector interface {
+    // 外部登录地址
+    AuthCodeURL(ctx context.Context, state string, nonce string) (string, error)
+}
+
+/**
+ * 登录方式
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Provider struct {
+    // 名称
+    Name string
+
+    // 标题
+    Title string
+
+    // 驱动
+    Driver string
+
+    // 验证器
+    Authenticator Authenticator
+
+    // 账号创建设置
+    Provision ProvisionOptions
+}
+
+// 验证并返回外部账号信息
+func (this *Provider) Authenticate(ctx context.Context, credentials map[string]string) (*Identity, error) {
+    identity, err := this.Authenticator.Authenticate(ctx, credentials)
+    if err != nil {
+        return nil, err
+    }
+
+    identity.Provider = this.Name
+
+    return identity, nil
+}
+
+// 外部登录地址
+func (this *Provider) AuthCodeURL(ctx context.Context, state string, nonce string) (string, error) {
+    redirector, ok := this.Authenticator.(Redirector)
+    if !ok {
+        return "", ErrProviderNotFound
+    }
+
+    return redirector.AuthCodeURL(ctx, state, nonce)
+}
+
+// 是否需要跳转登录
+func (this *Provider) Redirect() bool {
+    _, ok := this.Authenticator.(Redirector)
+
+    return ok
+}
+
+var (
+    // 锁定
+    mu sync.RWMutex
+
+    // 已注册驱动
+    drivers = make(map[string]DriverFunc)
+
+    // 已创建的登录方式，OIDC 需要缓存发现文档和公钥
+    providers = make(map[string]*Provider)
+)
+
+// 注册驱动
+func AddDriver(name string, driver DriverFunc) {
+    mu.Lock()
+    defer mu.Unlock()
+
+    drivers[name] = driver
+}
+
+// 获取驱动
+func GetDriver(name string) DriverFunc {
+    mu.RLock()
+    defer mu.RUnlock()
+
+    if driver, ok := drivers[name]; ok {
+        return driver
+    }
+
+    return nil
+}
+
+// 获取已开启的登录方式
+func GetProvider(name string) (*Provider, error) {
+    mu.RLock()
+    provider, ok := providers[name]
+    mu.RUnlock()
+
+    if ok {
+        return provider, nil
+    }
+
+    conf := ProviderOptions(name)
+    if conf == nil || !conf.Bool("enable") {
+        return nil, ErrProviderNotFound
+    }
+
+    driverName := conf.String("driver")
+
+    driver := GetDriver(driverName)
+    if driver == nil {
+        return nil, ErrDriverNotFound
+    }
+
+    authenticator, err := driver(name, conf)
+    if err != nil {
+        return nil, err
+    }
+
+    title := conf.String("title")
+    if title == "" {
+        title = name
+    }
+
+    provider = &Provider{
+        Name:          name,
+        Title:         title,
+        Driver:        driverName,
+        Authenticator: authenticator,
+        Provision:     NewProvisionOptions(conf),
+    }
+
+    mu.Lock()
+    providers[name] = provider
+    mu.Unlock()
+
+    return provider, nil
+}
+
+// 已开启的登录方式列表
+func Providers() []*Provider {
+    list := make([]*Provider, 0)
+
+    names := make([]string, 0)
+    for name := range config.New("auth").GetStringMap("passport.providers") {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+
+    for _, name := range names {
+        provider, err := GetProvider(name)
+        if err != nil {
+            continue
+        }
+
+        list = append(list, provider)
+    }
+
+    return list
+}
+
+// 登录方式配置
+func ProviderOptions(name string) Options {
+    if name == "" {
+        return nil
+    }
+
+    return Options(config.New("auth").GetStringMap("passport.providers." + name))
+}
+
+/**
+ * 配置
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Options map[string]any
+
+// 获取
+func (this Options) Get(key string) any {
+    if data, ok := this[key]; ok {
+        return data
+    }
+
+    return nil
+}
+
+// 字符
+func (this Options) String(key string) string {
+    return goch.ToString(this.Get(key))
+}
+
+// 布尔
+func (this Options) Bool(key string) bool {
+    return goch.ToBool(this.Get(key))
+}
+
+// 数字
+func (this Options) Int(key string) int {
+    return goch.ToInt(this.Get(key))
+}
+
+// 字符切片
+func (this Options) Strings(key string) []string {
+    data := make([]string, 0)
+
+    switch items := this.Get(key).(type) {
+        case []string:
+            data = append(data, items...)
+        case []any:
+            for _, item := range items {
+                data = append(data, goch.ToString(item))
+            }
+        case string:
+            if items != "" {
+                data = append(data, items)
+            }
+    }
+
+    return data
+}
+
+// 子配置
+func (this Options) Options(key string) Options {
+    switch item := this.Get(key).(type) {
+        case map[string]any:
+            return Options(item)
+        case Options:
+            return item
+    }
+
+    return Options{}
+}
+
+// 子配置列表
+func (this Options) List(key string) []Options {
+    list := make([]Options, 0)
+
+    items, ok := this.Get(key).([]any)
+    if !ok {
+        return list
+    }
+
+    for _, item := range items {
+        if data, ok := item.(map[string]any); ok {
+            list = append(list, Options(data))
+        }
+    }
+
+    return list
+}
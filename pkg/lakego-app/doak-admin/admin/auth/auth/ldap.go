@@ -0,0 +1,569 @@
+package auth
+
+import (
+    "net"
+    "time"
+    "bufio"
+    "errors"
+    "context"
+    "strings"
+    "strconv"
+    "net/url"
+    "crypto/tls"
+)
+
+// LDAP 协议操作
+const (
+    ldapBindRequest       byte = 0x60
+    ldapBindResponse      byte = 0x61
+    ldapUnbindRequest     byte = 0x42
+    ldapSearchRequest     byte = 0x63
+    ldapSearchResultEntry byte = 0x64
+    ldapSearchResultDone  byte = 0x65
+    ldapSearchResultRef   byte = 0x73
+)
+
+// LDAP 结果码
+const (
+    ldapSuccess            = 0
+    ldapSizeLimitExceeded  = 4
+    ldapInvalidCredentials = 49
+)
+
+func init() {
+    AddDriver("ldap", func(name string, conf Options) (Authenticator, error) {
+        return NewLDAP(NewLDAPConfig(conf))
+    })
+}
+
+/**
+ * LDAP 配置
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type LDAPConfig struct {
+    // 地址，ldap://host:389 或者 ldaps://host:636
+    URL string
+
+    // 跳过证书验证
+    InsecureSkipVerify bool
+
+    // 超时时间
+    Timeout time.Duration
+
+    // 查询账号使用的 DN 和密码，为空时匿名查询
+    BindDN       string
+    BindPassword string
+
+    // 账号查询
+    BaseDN     string
+    UserFilter string
+
+    // 分组查询，为空时只使用账号的 memberOf 属性
+    GroupBaseDN string
+    GroupFilter string
+
+    // 属性
+    SubjectAttr  string
+    NameAttr     string
+    NicknameAttr string
+    EmailAttr    string
+    MemberOfAttr string
+
+    // 目录中的邮箱由管理员维护，可以用于关联本地账号
+    EmailVerified bool
+}
+
+// 从配置生成
+func NewLDAPConfig(conf Options) LDAPConfig {
+    attrs := conf.Options("attributes")
+
+    timeout := conf.Int("timeout")
+    if timeout <= 0 {
+        timeout = 10
+    }
+
+    cfg := LDAPConfig{
+        URL:                conf.String("url"),
+        InsecureSkipVerify: conf.Bool("insecure-skip-verify"),
+        Timeout:            time.Duration(timeout) * time.Second,
+        BindDN:             conf.String("bind-dn"),
+        BindPassword:       conf.String("bind-password"),
+        BaseDN:             conf.String("base-dn"),
+        UserFilter:         conf.String("user-filter"),
+        GroupBaseDN:        conf.String("group-base-dn"),
+        GroupFilter:        conf.String("group-filter"),
+        SubjectAttr:        attrs.String("subject"),
+        NameAttr:           attrs.String("name"),
+        NicknameAttr:       attrs.String("nickname"),
+        EmailAttr:          attrs.String("email"),
+        MemberOfAttr:       attrs.String("member-of"),
+        EmailVerified:      conf.Bool("email-verified"),
+    }
+
+    if cfg.UserFilter == "" {
+        cfg.UserFilter = "(uid={name})"
+    }
+    if cfg.NameAttr == "" {
+        cfg.NameAttr = "uid"
+    }
+    if cfg.MemberOfAttr == "" {
+        cfg.MemberOfAttr = "memberOf"
+    }
+
+    return cfg
+}
+
+/**
+ * LDAP 验证器，查询账号后使用账号 DN 和密码绑定验证
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type LDAP struct {
+    // 配置
+    config LDAPConfig
+}
+
+// 构造函数
+func NewLDAP(config LDAPConfig) (*LDAP, error) {
+    if config.URL == "" {
+        return nil, errors.New("auth: ldap url is empty")
+    }
+
+    if _, err := ldapCompileFilter(strings.ReplaceAll(config.UserFilter, "{name}", "name")); err != nil {
+        return nil, err
+    }
+
+    return &LDAP{
+        config: config,
+    }, nil
+}
+
+// 验证账号密码
+func (this *LDAP) Authenticate(ctx context.Context, credentials map[string]string) (*Identity, error) {
+    name := credentials["name"]
+    password := credentials["password"]
+
+    // 空密码会被服务端当作匿名绑定
+    if name == "" || password == "" {
+        return nil, ErrInvalidCredentials
+    }
+
+    conn, err := dialLDAP(ctx, this.config)
+    if err != nil {
+        return nil, err
+    }
+    defer conn.Close()
+
+    if err := this.bindService(conn); err != nil {
+        return nil, err
+    }
+
+    filter := strings.ReplaceAll(this.config.UserFilter, "{name}", ldapEscapeFilter(name))
+
+    entries, err := conn.Search(this.config.BaseDN, filter, this.attributes(), 2)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(entries) != 1 {
+        return nil, ErrInvalidCredentials
+    }
+
+    entry := entries[0]
+
+    if err := conn.Bind(entry.DN, password); err != nil {
+        return nil, err
+    }
+
+    groups := entry.Values(this.config.MemberOfAttr)
+
+    // 查询所属分组
+    if this.config.GroupBaseDN != "" && this.config.GroupFilter != "" {
+        if err := this.bindService(conn); err != nil {
+            return nil, err
+        }
+
+        groupFilter := strings.NewReplacer(
+            "{dn}", ldapEscapeFilter(entry.DN),
+            "{name}", ldapEscapeFilter(name),
+        ).Replace(this.config.GroupFilter)
+
+        groupEntries, err := conn.Search(this.config.GroupBaseDN, groupFilter, []string{"cn"}, 0)
+        if err != nil {
+            return nil, err
+        }
+
+        for _, groupEntry := range groupEntries {
+            groups = append(groups, groupEntry.DN)
+        }
+    }
+
+    identity := &Identity{
+        Subject:  strings.ToLower(entry.DN),
+        Name:     entry.Value(this.config.NameAttr),
+        Nickname: entry.Value(this.config.NicknameAttr),
+        Email:    entry.Value(this.config.EmailAttr),
+        Groups:   groups,
+    }
+
+    identity.EmailVerified = this.config.EmailVerified && identity.Email != ""
+
+    if this.config.SubjectAttr != "" {
+        if subject := entry.Value(this.config.SubjectAttr); subject != "" {
+            identity.Subject = subject
+        }
+    }
+
+    if identity.Name == "" {
+        identity.Name = name
+    }
+
+    return identity, nil
+}
+
+// 使用查询账号绑定
+func (this *LDAP) bindService(conn *ldapConn) error {
+    if this.config.BindDN == "" {
+        return nil
+    }
+
+    err := conn.Bind(this.config.BindDN, this.config.BindPassword)
+    if err == ErrInvalidCredentials {
+        return errors.New("auth: ldap service bind failed")
+    }
+
+    return err
+}
+
+// 需要查询的属性
+func (this *LDAP) attributes() []string {
+    attrs := make([]string, 0)
+
+    for _, attr := range []string{
+        this.config.SubjectAttr,
+        this.config.NameAttr,
+        this.config.NicknameAttr,
+        this.config.EmailAttr,
+        this.config.MemberOfAttr,
+    } {
+        if attr != "" {
+            attrs = append(attrs, attr)
+        }
+    }
+
+    return attrs
+}
+
+/**
+ * LDAP 查询结果
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type ldapEntry struct {
+    // DN
+    DN string
+
+    // 属性，属性名为小写
+    Attributes map[string][]string
+}
+
+// 属性值列表
+func (this ldapEntry) Values(attr string) []string {
+    if attr == "" {
+        return nil
+    }
+
+    return this.Attributes[strings.ToLower(attr)]
+}
+
+// 属性值
+func (this ldapEntry) Value(attr string) string {
+    values := this.Values(attr)
+    if len(values) == 0 {
+        return ""
+    }
+
+    return values[0]
+}
+
+/**
+ * LDAP 连接，只实现了登录需要的绑定和查询
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type ldapConn struct {
+    conn   net.Conn
+    reader *bufio.Reader
+    msgId  int
+}
+
+// 连接
+func dialLDAP(ctx context.Context, config LDAPConfig) (*ldapConn, error) {
+    u, err := url.Parse(config.URL)
+    if err != nil {
+        return nil, err
+    }
+
+    host := u.Host
+    if u.Port() == "" {
+        if u.Scheme == "ldaps" {
+            host = net.JoinHostPort(u.Hostname(), "636")
+        } else {
+            host = net.JoinHostPort(u.Hostname(), "389")
+        }
+    }
+
+    dialer := &net.Dialer{
+        Timeout: config.Timeout,
+    }
+
+    var conn net.Conn
+    switch u.Scheme {
+        case "ldap":
+            conn, err = dialer.DialContext(ctx, "tcp", host)
+        case "ldaps":
+            tlsDialer := &tls.Dialer{
+                NetDialer: dialer,
+                Config: &tls.Config{
+                    ServerName:         u.Hostname(),
+                    InsecureSkipVerify: config.InsecureSkipVerify,
+                },
+            }
+
+            conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+        default:
+            return nil, errors.New("auth: ldap url scheme not support")
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    conn.SetDeadline(time.Now().Add(config.Timeout))
+
+    return &ldapConn{
+        conn:   conn,
+        reader: bufio.NewReader(conn),
+    }, nil
+}
+
+// 绑定
+func (this *ldapConn) Bind(dn string, password string) error {
+    msgId, err := this.send(berEncode(ldapBindRequest,
+        berEncodeInt(berInteger, 3),
+        berEncodeString(berOctetString, dn),
+        berEncodeString(0x80, password),
+    ))
+    if err != nil {
+        return err
+    }
+
+    op, err := this.receive(msgId)
+    if err != nil {
+        return err
+    }
+
+    if op.Tag != ldapBindResponse {
+        return errBerInvalid
+    }
+
+    resultCode, err := ldapResultCode(op)
+    if err != nil {
+        return err
+    }
+
+    switch resultCode {
+        case ldapSuccess:
+            return nil
+        case ldapInvalidCredentials:
+            return ErrInvalidCredentials
+    }
+
+    return ldapResultError(op)
+}
+
+// 查询
+func (this *ldapConn) Search(baseDN string, filter string, attrs []string, sizeLimit int) ([]ldapEntry, error) {
+    filterData, err := ldapCompileFilter(filter)
+    if err != nil {
+        return nil, err
+    }
+
+    attrData := make([][]byte, 0)
+    for _, attr := range attrs {
+        attrData = append(attrData, berEncodeString(berOctetString, attr))
+    }
+
+    msgId, err := this.send(berEncode(ldapSearchRequest,
+        berEncodeString(berOctetString, baseDN),
+        berEncodeInt(berEnumerated, 2),
+        berEncodeInt(berEnumerated, 0),
+        berEncodeInt(berInteger, sizeLimit),
+        berEncodeInt(berInteger, 0),
+        berEncodeBool(false),
+        filterData,
+        berEncode(berSequence, attrData...),
+    ))
+    if err != nil {
+        return nil, err
+    }
+
+    entries := make([]ldapEntry, 0)
+    for {
+        op, err := this.receive(msgId)
+        if err != nil {
+            return nil, err
+        }
+
+        switch op.Tag {
+            case ldapSearchResultEntry:
+                entry, err := ldapParseEntry(op)
+                if err != nil {
+                    return nil, err
+                }
+
+                entries = append(entries, entry)
+            case ldapSearchResultRef:
+                continue
+            case ldapSearchResultDone:
+                resultCode, err := ldapResultCode(op)
+                if err != nil {
+                    return nil, err
+                }
+
+                if resultCode != ldapSuccess && resultCode != ldapSizeLimitExceeded {
+                    return nil, ldapResultError(op)
+                }
+
+                return entries, nil
+            default:
+                return nil, errBerInvalid
+        }
+    }
+}
+
+// 关闭
+func (this *ldapConn) Close() error {
+    this.msgId++
+
+    this.conn.Write(berEncode(berSequence,
+        berEncodeInt(berInteger, this.msgId),
+        []byte{ldapUnbindRequest, 0x00},
+    ))
+
+    return this.conn.Close()
+}
+
+// 发送请求
+func (this *ldapConn) send(op []byte) (int, error) {
+    this.msgId++
+
+    packet := berEncode(berSequence,
+        berEncodeInt(berInteger, this.msgId),
+        op,
+    )
+
+    if _, err := this.conn.Write(packet); err != nil {
+        return 0, err
+    }
+
+    return this.msgId, nil
+}
+
+// 读取响应
+func (this *ldapConn) receive(msgId int) (berElement, error) {
+    packet, err := berRead(this.reader)
+    if err != nil {
+        return berElement{}, err
+    }
+
+    children, err := packet.Children()
+    if err != nil {
+        return berElement{}, err
+    }
+
+    if packet.Tag != berSequence || len(children) < 2 {
+        return berElement{}, errBerInvalid
+    }
+
+    if children[0].Int() != msgId {
+        return berElement{}, errors.New("auth: ldap message id mismatch")
+    }
+
+    return children[1], nil
+}
+
+// 结果码
+func ldapResultCode(op berElement) (int, error) {
+    children, err := op.Children()
+    if err != nil {
+        return 0, err
+    }
+
+    if len(children) < 3 {
+        return 0, errBerInvalid
+    }
+
+    return children[0].Int(), nil
+}
+
+// 结果错误
+func ldapResultError(op berElement) error {
+    children, err := op.Children()
+    if err != nil || len(children) < 3 {
+        return errBerInvalid
+    }
+
+    message := children[2].String()
+    if message == "" {
+        message = "result code " + strconv.Itoa(children[0].Int())
+    }
+
+    return errors.New("auth: ldap " + message)
+}
+
+// 解析查询结果
+func ldapParseEntry(op berElement) (ldapEntry, error) {
+    children, err := op.Children()
+    if err != nil {
+        return ldapEntry{}, err
+    }
+
+    if len(children) < 2 {
+        return ldapEntry{}, errBerInvalid
+    }
+
+    entry := ldapEntry{
+        DN:         children[0].String(),
+        Attributes: make(map[string][]string),
+    }
+
+    attrs, err := children[1].Children()
+    if err != nil {
+        return ldapEntry{}, err
+    }
+
+    for _, attr := range attrs {
+        parts, err := attr.Children()
+        if err != nil || len(parts) < 2 {
+            return ldapEntry{}, errBerInvalid
+        }
+
+        values, err := parts[1].Children()
+        if err != nil {
+            return ldapEntry{}, err
+        }
+
+        name := strings.ToLower(parts[0].String())
+        for _, value := range values {
+            entry.Attributes[name] = append(entry.Attributes[name], value.String())
+        }
+    }
+
+    return entry, nil
+}
@@ -0,0 +1,439 @@
+package auth
+
+import (
+    "io"
+    "errors"
+    "strings"
+    "encoding/hex"
+)
+
+// BER 标签
+const (
+    berBoolean     byte = 0x01
+    berInteger     byte = 0x02
+    berOctetString byte = 0x04
+    berEnumerated  byte = 0x0a
+    berSequence    byte = 0x30
+    berSet         byte = 0x31
+)
+
+// 单个数据最大长度
+const berMaxLength = 16 << 20
+
+var (
+    // 数据格式错误
+    errBerInvalid = errors.New("auth: invalid ber data")
+
+    // 过滤条件格式错误
+    errLdapFilter = errors.New("auth: invalid ldap filter")
+)
+
+/**
+ * BER 数据
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type berElement struct {
+    // 标签
+    Tag byte
+
+    // 内容
+    Content []byte
+}
+
+// 子数据
+func (this berElement) Children() ([]berElement, error) {
+    children := make([]berElement, 0)
+
+    data := this.Content
+    for len(data) > 0 {
+        child, rest, err := berDecode(data)
+        if err != nil {
+            return nil, err
+        }
+
+        children = append(children, child)
+        data = rest
+    }
+
+    return children, nil
+}
+
+// 数字
+func (this berElement) Int() int {
+    n := 0
+    for i, b := range this.Content {
+        if i == 0 && b&0x80 != 0 {
+            n = -1
+        }
+
+        n = n<<8 | int(b)
+    }
+
+    return n
+}
+
+// 字符
+func (this berElement) String() string {
+    return string(this.Content)
+}
+
+// 编码数据
+func berEncode(tag byte, content ...[]byte) []byte {
+    size := 0
+    for _, c := range content {
+        size += len(c)
+    }
+
+    data := append([]byte{tag}, berEncodeLength(size)...)
+    for _, c := range content {
+        data = append(data, c...)
+    }
+
+    return data
+}
+
+// 编码长度
+func berEncodeLength(n int) []byte {
+    if n < 0x80 {
+        return []byte{byte(n)}
+    }
+
+    data := make([]byte, 0)
+    for ; n > 0; n >>= 8 {
+        data = append([]byte{byte(n)}, data...)
+    }
+
+    return append([]byte{0x80 | byte(len(data))}, data...)
+}
+
+// 编码数字
+func berEncodeInt(tag byte, n int) []byte {
+    data := []byte{byte(n)}
+
+    // 使用最少的字节，同时保持符号位正确
+    for v := n >> 8; ; v >>= 8 {
+        if (v == 0 && data[0]&0x80 == 0) || (v == -1 && data[0]&0x80 != 0) {
+            break
+        }
+
+        data = append([]byte{byte(v)}, data...)
+    }
+
+    return berEncode(tag, data)
+}
+
+// 编码字符
+func berEncodeString(tag byte, s string) []byte {
+    return berEncode(tag, []byte(s))
+}
+
+// 编码布尔
+func berEncodeBool(b bool) []byte {
+    if b {
+        return berEncode(berBoolean, []byte{0xff})
+    }
+
+    return berEncode(berBoolean, []byte{0x00})
+}
+
+// 解码数据
+func berDecode(data []byte) (berElement, []byte, error) {
+    if len(data) < 2 {
+        return berElement{}, nil, errBerInvalid
+    }
+
+    tag := data[0]
+
+    length, n, err := berDecodeLength(data[1:])
+    if err != nil {
+        return berElement{}, nil, err
+    }
+
+    start := 1 + n
+    if len(data) - start < length {
+        return berElement{}, nil, errBerInvalid
+    }
+
+    return berElement{
+        Tag:     tag,
+        Content: data[start:start+length],
+    }, data[start+length:], nil
+}
+
+// 解码长度，返回长度和使用的字节数
+func berDecodeLength(data []byte) (int, int, error) {
+    if len(data) < 1 {
+        return 0, 0, errBerInvalid
+    }
+
+    if data[0] < 0x80 {
+        return int(data[0]), 1, nil
+    }
+
+    // 不支持不定长格式
+    size := int(data[0] & 0x7f)
+    if size == 0 || size > 4 || len(data) < size + 1 {
+        return 0, 0, errBerInvalid
+    }
+
+    length := 0
+    for _, b := range data[1:size+1] {
+        length = length<<8 | int(b)
+    }
+
+    if length > berMaxLength {
+        return 0, 0, errBerInvalid
+    }
+
+    return length, size + 1, nil
+}
+
+// 从连接读取一个数据
+func berRead(r io.Reader) (berElement, error) {
+    head := make([]byte, 2)
+    if _, err := io.ReadFull(r, head); err != nil {
+        return berElement{}, err
+    }
+
+    lengthBytes := []byte{head[1]}
+    if head[1] >= 0x80 {
+        size := int(head[1] & 0x7f)
+        if size == 0 || size > 4 {
+            return berElement{}, errBerInvalid
+        }
+
+        more := make([]byte, size)
+        if _, err := io.ReadFull(r, more); err != nil {
+            return berElement{}, err
+        }
+
+        lengthBytes = append(lengthBytes, more...)
+    }
+
+    length, _, err := berDecodeLength(lengthBytes)
+    if err != nil {
+        return berElement{}, err
+    }
+
+    content := make([]byte, length)
+    if _, err := io.ReadFull(r, content); err != nil {
+        return berElement{}, err
+    }
+
+    return berElement{
+        Tag:     head[0],
+        Content: content,
+    }, nil
+}
+
+// 转义过滤条件中的值
+func ldapEscapeFilter(s string) string {
+    var b strings.Builder
+
+    for i := 0; i < len(s); i++ {
+        switch c := s[i]; c {
+            case '\\', '*', '(', ')', 0:
+                b.WriteString("\\" + hex.EncodeToString([]byte{c}))
+            default:
+                b.WriteByte(c)
+        }
+    }
+
+    return b.String()
+}
+
+// 编译过滤条件，比如 (&(objectClass=person)(uid=name))
+func ldapCompileFilter(filter string) ([]byte, error) {
+    filter = strings.TrimSpace(filter)
+    if !strings.HasPrefix(filter, "(") {
+        filter = "(" + filter + ")"
+    }
+
+    data, pos, err := ldapParseFilter(filter, 0)
+    if err != nil {
+        return nil, err
+    }
+
+    if pos != len(filter) {
+        return nil, errLdapFilter
+    }
+
+    return data, nil
+}
+
+// 解析单个过滤条件
+func ldapParseFilter(filter string, pos int) ([]byte, int, error) {
+    if pos >= len(filter) || filter[pos] != '(' {
+        return nil, 0, errLdapFilter
+    }
+
+    pos++
+    if pos >= len(filter) {
+        return nil, 0, errLdapFilter
+    }
+
+    switch filter[pos] {
+        case '&', '|':
+            tag := byte(0xa0)
+            if filter[pos] == '|' {
+                tag = 0xa1
+            }
+
+            pos++
+
+            children := make([][]byte, 0)
+            for pos < len(filter) && filter[pos] == '(' {
+                child, next, err := ldapParseFilter(filter, pos)
+                if err != nil {
+                    return nil, 0, err
+                }
+
+                children = append(children, child)
+                pos = next
+            }
+
+            if len(children) == 0 || pos >= len(filter) || filter[pos] != ')' {
+                return nil, 0, errLdapFilter
+            }
+
+            return berEncode(tag, children...), pos + 1, nil
+
+        case '!':
+            child, next, err := ldapParseFilter(filter, pos + 1)
+            if err != nil {
+                return nil, 0, err
+            }
+
+            if next >= len(filter) || filter[next] != ')' {
+                return nil, 0, errLdapFilter
+            }
+
+            return berEncode(0xa2, child), next + 1, nil
+    }
+
+    end := strings.IndexByte(filter[pos:], ')')
+    if end < 0 {
+        return nil, 0, errLdapFilter
+    }
+
+    data, err := ldapCompileItem(filter[pos:pos+end])
+    if err != nil {
+        return nil, 0, err
+    }
+
+    return data, pos + end + 1, nil
+}
+
+// 编译比较条件
+func ldapCompileItem(item string) ([]byte, error) {
+    eq := strings.IndexByte(item, '=')
+    if eq < 1 {
+        return nil, errLdapFilter
+    }
+
+    attr := item[:eq]
+    value := item[eq+1:]
+
+    var tag byte = 0xa3
+    switch attr[len(attr)-1] {
+        case '>':
+            tag = 0xa5
+        case '<':
+            tag = 0xa6
+        case '~':
+            tag = 0xa8
+    }
+
+    if tag != 0xa3 {
+        attr = attr[:len(attr)-1]
+    }
+
+    if attr == "" {
+        return nil, errLdapFilter
+    }
+
+    if tag == 0xa3 {
+        // 存在
+        if value == "*" {
+            return berEncodeString(0x87, attr), nil
+        }
+
+        // 模糊匹配
+        if strings.Contains(value, "*") {
+            return ldapCompileSubstrings(attr, value)
+        }
+    }
+
+    val, err := ldapUnescapeFilter(value)
+    if err != nil {
+        return nil, err
+    }
+
+    return berEncode(tag,
+        berEncodeString(berOctetString, attr),
+        berEncodeString(berOctetString, val),
+    ), nil
+}
+
+// 编译模糊匹配条件
+func ldapCompileSubstrings(attr string, value string) ([]byte, error) {
+    parts := strings.Split(value, "*")
+
+    subs := make([][]byte, 0)
+    for i, part := range parts {
+        if part == "" {
+            continue
+        }
+
+        val, err := ldapUnescapeFilter(part)
+        if err != nil {
+            return nil, err
+        }
+
+        var tag byte = 0x81
+        if i == 0 {
+            tag = 0x80
+        } else if i == len(parts) - 1 {
+            tag = 0x82
+        }
+
+        subs = append(subs, berEncodeString(tag, val))
+    }
+
+    return berEncode(0xa4,
+        berEncodeString(berOctetString, attr),
+        berEncode(berSequence, subs...),
+    ), nil
+}
+
+// 还原转义的值
+func ldapUnescapeFilter(s string) (string, error) {
+    if !strings.Contains(s, "\\") {
+        return s, nil
+    }
+
+    var b strings.Builder
+
+    for i := 0; i < len(s); i++ {
+        if s[i] != '\\' {
+            b.WriteByte(s[i])
+            continue
+        }
+
+        if i + 3 > len(s) {
+            return "", errLdapFilter
+        }
+
+        c, err := hex.DecodeString(s[i+1:i+3])
+        if err != nil {
+            return "", errLdapFilter
+        }
+
+        b.Write(c)
+        i += 2
+    }
+
+    return b.String(), nil
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+    "encoding/hex"
+)
+
+func Test_BerInt(t *testing.T) {
+    eq := assertT(t)
+
+    tests := map[int]string{
+        0:      "020100",
+        1:      "020101",
+        127:    "02017f",
+        128:    "02020080",
+        256:    "02020100",
+        65535:  "020300ffff",
+        -1:     "0201ff",
+        -128:   "020180",
+        -129:   "0202ff7f",
+        -65536: "0203ff0000",
+    }
+
+    for n, expected := range tests {
+        data := berEncodeInt(berInteger, n)
+        eq(hex.EncodeToString(data), expected, "berEncodeInt " + hex.EncodeToString(data))
+
+        element, rest, err := berDecode(data)
+        eq(err, nil, "berDecode error")
+        eq(len(rest), 0, "berDecode rest")
+        eq(element.Tag, berInteger, "berDecode tag")
+        eq(element.Int(), n, "berDecode int " + expected)
+    }
+}
+
+func Test_BerLength(t *testing.T) {
+    eq := assertT(t)
+
+    for _, size := range []int{0, 1, 127, 128, 255, 256, 65535, 65536} {
+        content := bytes.Repeat([]byte{'a'}, size)
+
+        data := berEncode(berOctetString, content)
+
+        element, rest, err := berDecode(data)
+        eq(err, nil, "berDecode error")
+        eq(len(rest), 0, "berDecode rest")
+        eq(bytes.Equal(element.Content, content), true, "berDecode content")
+
+        // 从连接读取
+        element, err = berRead(bytes.NewReader(data))
+        eq(err, nil, "berRead error")
+        eq(bytes.Equal(element.Content, content), true, "berRead content")
+    }
+
+    eq(hex.EncodeToString(berEncodeLength(127)), "7f", "berEncodeLength short")
+    eq(hex.EncodeToString(berEncodeLength(128)), "8180", "berEncodeLength long")
+    eq(hex.EncodeToString(berEncodeLength(256)), "820100", "berEncodeLength 2 bytes")
+}
+
+func Test_BerSequence(t *testing.T) {
+    eq := assertT(t)
+
+    data := berEncode(berSequence,
+        berEncodeInt(berInteger, 3),
+        berEncodeString(berOctetString, "cn=admin,dc=example,dc=com"),
+        berEncodeBool(true),
+        berEncodeBool(false),
+        berEncode(berSet,
+            berEncodeString(berOctetString, "a"),
+            berEncodeString(berOctetString, "b"),
+        ),
+    )
+
+    element, _, err := berDecode(data)
+    eq(err, nil, "berDecode error")
+    eq(element.Tag, berSequence, "berDecode tag")
+
+    children, err := element.Children()
+    eq(err, nil, "Children error")
+    eq(len(children), 5, "Children len")
+    eq(children[0].Int(), 3, "Children int")
+    eq(children[1].String(), "cn=admin,dc=example,dc=com", "Children string")
+    eq(hex.EncodeToString(children[2].Content), "ff", "Children true")
+    eq(hex.EncodeToString(children[3].Content), "00", "Children false")
+
+    set, err := children[4].Children()
+    eq(err, nil, "Children set error")
+    eq(len(set), 2, "Children set len")
+    eq(set[1].String(), "b", "Children set value")
+
+    // 多个数据连续读取
+    reader := bytes.NewReader(append(append([]byte{}, data...), berEncodeInt(berInteger, 7)...))
+
+    first, err := berRead(reader)
+    eq(err, nil, "berRead first error")
+    eq(bytes.Equal(first.Content, element.Content), true, "berRead first")
+
+    second, err := berRead(reader)
+    eq(err, nil, "berRead second error")
+    eq(second.Int(), 7, "berRead second")
+}
+
+func Test_BerDecode_Invalid(t *testing.T) {
+    eq := assertT(t)
+
+    for _, data := range []string{
+        "",
+        "04",
+        // 长度超出数据
+        "0405616263",
+        // 不定长格式
+        "0480",
+        // 长度字节过多
+        "04850000000001",
+        // 超过最大长度
+        "048402000000",
+    } {
+        b, _ := hex.DecodeString(data)
+
+        _, _, err := berDecode(b)
+        eq(err, errBerInvalid, "berDecode " + data)
+    }
+
+    // 子数据格式错误
+    element := berElement{
+        Tag:     berSequence,
+        Content: []byte{0x04, 0x05, 0x61},
+    }
+    _, err := element.Children()
+    eq(err, errBerInvalid, "Children invalid")
+
+    _, err = berRead(bytes.NewReader([]byte{0x04, 0x80}))
+    eq(err, errBerInvalid, "berRead indefinite length")
+
+    _, err = berRead(bytes.NewReader([]byte{0x04, 0x05, 0x61}))
+    eq(err != nil, true, "berRead short content")
+}
+
+func Test_LdapFilter(t *testing.T) {
+    eq := assertT(t)
+
+    data, err := ldapCompileFilter("(&(objectClass=person)(uid=" + ldapEscapeFilter("a*b(c)") + "))")
+    eq(err, nil, "ldapCompileFilter error")
+
+    element, _, err := berDecode(data)
+    eq(err, nil, "berDecode error")
+    eq(element.Tag, byte(0xa0), "and tag")
+
+    children, _ := element.Children()
+    eq(len(children), 2, "and children")
+    eq(children[0].Tag, byte(0xa3), "equality tag")
+
+    parts, _ := children[1].Children()
+    eq(parts[0].String(), "uid", "equality attr")
+    eq(parts[1].String(), "a*b(c)", "equality escaped value")
+
+    // 存在和模糊匹配
+    data, err = ldapCompileFilter("(|(mail=*)(cn=ad*mi*n)(!(uid>=b)))")
+    eq(err, nil, "ldapCompileFilter or error")
+
+    element, _, _ = berDecode(data)
+    children, _ = element.Children()
+    eq(element.Tag, byte(0xa1), "or tag")
+    eq(children[0].Tag, byte(0x87), "present tag")
+    eq(children[0].String(), "mail", "present attr")
+    eq(children[1].Tag, byte(0xa4), "substrings tag")
+    eq(children[2].Tag, byte(0xa2), "not tag")
+
+    subs, _ := children[1].Children()
+    items, _ := subs[1].Children()
+    eq(len(items), 3, "substrings items")
+    eq(items[0].Tag, byte(0x80), "substrings initial")
+    eq(items[1].Tag, byte(0x81), "substrings any")
+    eq(items[2].Tag, byte(0x82), "substrings final")
+
+    not, _ := children[2].Children()
+    eq(not[0].Tag, byte(0xa5), "greater or equal tag")
+
+    // 不带括号
+    _, err = ldapCompileFilter("uid=admin")
+    eq(err, nil, "ldapCompileFilter without parentheses")
+
+    for _, filter := range []string{
+        "(uid=admin",
+        "(&)",
+        "(=admin)",
+        "(uid=admin))",
+        "(uid=a\\zz)",
+    } {
+        _, err = ldapCompileFilter(filter)
+        eq(err, errLdapFilter, "ldapCompileFilter invalid " + filter)
+    }
+
+    eq(strings.Contains(ldapEscapeFilter("a\\b"), "\\5c"), true, "ldapEscapeFilter backslash")
+}
@@ -0,0 +1,574 @@
+package auth
+
+import (
+    "io"
+    "sync"
+    "time"
+    "errors"
+    "context"
+    "strings"
+    "net/url"
+    "net/http"
+    "encoding/json"
+    "encoding/base64"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/lakego-jwt/jwt"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+)
+
+// 公钥缓存时间
+const oidcKeysExpiresIn = time.Hour
+
+var (
+    // id_token 验证失败
+    ErrInvalidIdToken = errors.New("auth: invalid id token")
+
+    // 公钥不存在
+    ErrKeyNotFound = errors.New("auth: signing key not found")
+)
+
+// 允许的签名方式，不允许使用 HS 和 none
+var oidcAlgorithms = []string{
+    "RS256", "RS384", "RS512",
+    "PS256", "PS384", "PS512",
+    "ES256", "ES384", "ES512",
+    "EdDSA",
+}
+
+func init() {
+    AddDriver("oidc", func(name string, conf Options) (Authenticator, error) {
+        return NewOIDC(NewOIDCConfig(conf))
+    })
+}
+
+/**
+ * OIDC 配置
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type OIDCConfig struct {
+    // 发行方，用于获取发现文档
+    Issuer string
+
+    // 客户端
+    ClientID     string
+    ClientSecret string
+
+    // 回调地址
+    RedirectURL string
+
+    // 权限范围
+    Scopes []string
+
+    // 超时时间
+    Timeout time.Duration
+
+    // 声明字段
+    SubjectClaim  string
+    NameClaim     string
+    NicknameClaim string
+    EmailClaim    string
+    GroupsClaim   string
+}
+
+// 从配置生成
+func NewOIDCConfig(conf Options) OIDCConfig {
+    claims := conf.Options("claims")
+
+    timeout := conf.Int("timeout")
+    if timeout <= 0 {
+        timeout = 10
+    }
+
+    cfg := OIDCConfig{
+        Issuer:        strings.TrimRight(conf.String("issuer"), "/"),
+        ClientID:      conf.String("client-id"),
+        ClientSecret:  conf.String("client-secret"),
+        RedirectURL:   conf.String("redirect-url"),
+        Scopes:        conf.Strings("scopes"),
+        Timeout:       time.Duration(timeout) * time.Second,
+        SubjectClaim:  claims.String("subject"),
+        NameClaim:     claims.String("name"),
+        NicknameClaim: claims.String("nickname"),
+        EmailClaim:    claims.String("email"),
+        GroupsClaim:   claims.String("groups"),
+    }
+
+    if len(cfg.Scopes) == 0 {
+        cfg.Scopes = []string{"openid", "profile", "email"}
+    }
+    if cfg.SubjectClaim == "" {
+        cfg.SubjectClaim = "sub"
+    }
+    if cfg.NameClaim == "" {
+        cfg.NameClaim = "preferred_username"
+    }
+
+    return cfg
+}
+
+/**
+ * OIDC 发现文档
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type OIDCDiscovery struct {
+    Issuer                string `json:"issuer"`
+    AuthorizationEndpoint string `json:"authorization_endpoint"`
+    TokenEndpoint         string `json:"token_endpoint"`
+    JwksURI               string `json:"jwks_uri"`
+}
+
+/**
+ * OIDC 验证器，使用授权码登录
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type OIDC struct {
+    // 锁定
+    mu sync.RWMutex
+
+    // 配置
+    config OIDCConfig
+
+    // 请求客户端
+    client *http.Client
+
+    // 发现文档
+    discovery *OIDCDiscovery
+
+    // 签名公钥，PEM 格式
+    keys map[string]*oidcKey
+
+    // 公钥获取时间
+    keysTime time.Time
+}
+
+// 构造函数
+func NewOIDC(config OIDCConfig) (*OIDC, error) {
+    if config.Issuer == "" || config.ClientID == "" {
+        return nil, errors.New("auth: oidc issuer or client id is empty")
+    }
+
+    return &OIDC{
+        config: config,
+        client: &http.Client{
+            Timeout: config.Timeout,
+        },
+        keys: make(map[string]*oidcKey),
+    }, nil
+}
+
+// 设置请求客户端
+func (this *OIDC) WithClient(client *http.Client) *OIDC {
+    this.client = client
+    return this
+}
+
+// 外部登录地址
+func (this *OIDC) AuthCodeURL(ctx context.Context, state string, nonce string) (string, error) {
+    discovery, err := this.Discovery(ctx)
+    if err != nil {
+        return "", err
+    }
+
+    query := url.Values{}
+    query.Set("response_type", "code")
+    query.Set("client_id", this.config.ClientID)
+    query.Set("redirect_uri", this.config.RedirectURL)
+    query.Set("scope", strings.Join(this.config.Scopes, " "))
+    query.Set("state", state)
+    query.Set("nonce", nonce)
+
+    endpoint := discovery.AuthorizationEndpoint
+    if strings.Contains(endpoint, "?") {
+        return endpoint + "&" + query.Encode(), nil
+    }
+
+    return endpoint + "?" + query.Encode(), nil
+}
+
+// 使用授权码换取并验证 id_token
+func (this *OIDC) Authenticate(ctx context.Context, credentials map[string]string) (*Identity, error) {
+    code := credentials["code"]
+    nonce := credentials["nonce"]
+    if code == "" || nonce == "" {
+        return nil, ErrInvalidCredentials
+    }
+
+    idToken, err := this.Exchange(ctx, code)
+    if err != nil {
+        return nil, err
+    }
+
+    claims, err := this.Verify(ctx, idToken)
+    if err != nil {
+        return nil, err
+    }
+
+    if goch.ToString(claims["nonce"]) != nonce {
+        return nil, ErrInvalidIdToken
+    }
+
+    identity := &Identity{
+        Subject:  goch.ToString(claims[this.config.SubjectClaim]),
+        Name:     goch.ToString(claims[this.config.NameClaim]),
+        Nickname: this.claimString(claims, this.config.NicknameClaim),
+        Email:    this.claimString(claims, this.config.EmailClaim),
+        Groups:   make([]string, 0),
+    }
+
+    // 只使用标准的 email_verified 声明
+    switch verified := claims["email_verified"].(type) {
+        case bool:
+            identity.EmailVerified = verified
+        case string:
+            identity.EmailVerified = verified == "true"
+    }
+
+    if this.config.GroupsClaim != "" {
+        switch groups := claims[this.config.GroupsClaim].(type) {
+            case []any:
+                for _, group := range groups {
+                    identity.Groups = append(identity.Groups, goch.ToString(group))
+                }
+            case string:
+                identity.Groups = append(identity.Groups, groups)
+        }
+    }
+
+    return identity, nil
+}
+
+// 使用授权码换取 id_token
+func (this *OIDC) Exchange(ctx context.Context, code string) (string, error) {
+    discovery, err := this.Discovery(ctx)
+    if err != nil {
+        return "", err
+    }
+
+    form := url.Values{}
+    form.Set("grant_type", "authorization_code")
+    form.Set("code", code)
+    form.Set("redirect_uri", this.config.RedirectURL)
+    form.Set("client_id", this.config.ClientID)
+    form.Set("client_secret", this.config.ClientSecret)
+
+    req, err := http.NewRequestWithContext(ctx, "POST", discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+    if err != nil {
+        return "", err
+    }
+
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("Accept", "application/json")
+
+    var token struct {
+        IdToken          string `json:"id_token"`
+        Error            string `json:"error"`
+        ErrorDescription string `json:"error_description"`
+    }
+
+    if err := this.doJSON(req, &token); err != nil {
+        if token.Error == "invalid_grant" {
+            return "", ErrInvalidCredentials
+        }
+
+        return "", err
+    }
+
+    if token.IdToken == "" {
+        return "", ErrInvalidIdToken
+    }
+
+    return token.IdToken, nil
+}
+
+// 验证 id_token 并返回载荷
+func (this *OIDC) Verify(ctx context.Context, idToken string) (jwt.MapClaims, error) {
+    discovery, err := this.Discovery(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    header, err := parseTokenHeader(idToken)
+    if err != nil {
+        return nil, err
+    }
+
+    alg := goch.ToString(header["alg"])
+    if !array.InArray(alg, oidcAlgorithms) {
+        return nil, ErrInvalidIdToken
+    }
+
+    key, err := this.Key(ctx, goch.ToString(header["kid"]), alg)
+    if err != nil {
+        return nil, err
+    }
+
+    // 使用 jwt 包的签名验证
+    verifier := jwt.New(
+        jwt.WithSigningMethod(alg),
+        jwt.WithPublicKey(key.PEM),
+    )
+
+    token, err := verifier.ParseToken(idToken)
+    if err != nil || token.Method.Alg() != alg {
+        return nil, ErrInvalidIdToken
+    }
+
+    claims, err := verifier.GetClaimsFromToken(token)
+    if err != nil {
+        return nil, ErrInvalidIdToken
+    }
+
+    // 发行方和受众必须存在并且匹配，jwt 包在缺少时不检测
+    if iss, ok := claims["iss"].(string); !ok || iss != discovery.Issuer {
+        return nil, ErrInvalidIdToken
+    }
+
+    if !this.matchAudience(claims) {
+        return nil, ErrInvalidIdToken
+    }
+
+    // 必须有过期时间
+    exp, ok := claims["exp"].(float64)
+    if !ok || time.Now().Unix() >= int64(exp) {
+        return nil, ErrInvalidIdToken
+    }
+
+    return claims, nil
+}
+
+// 受众必须包含当前客户端，多个受众时 azp 需为当前客户端
+func (this *OIDC) matchAudience(claims jwt.MapClaims) bool {
+    switch aud := claims["aud"].(type) {
+        case string:
+            return aud == this.config.ClientID
+        case []any:
+            found := false
+            for _, v := range aud {
+                if s, ok := v.(string); ok && s == this.config.ClientID {
+                    found = true
+                }
+            }
+
+            if !found {
+                return false
+            }
+
+            if len(aud) > 1 {
+                azp, _ := claims["azp"].(string)
+                return azp == this.config.ClientID
+            }
+
+            return true
+    }
+
+    return false
+}
+
+// 获取发现文档
+func (this *OIDC) Discovery(ctx context.Context) (*OIDCDiscovery, error) {
+    this.mu.RLock()
+    discovery := this.discovery
+    this.mu.RUnlock()
+
+    if discovery != nil {
+        return discovery, nil
+    }
+
+    req, err := http.NewRequestWithContext(ctx, "GET", this.config.Issuer + "/.well-known/openid-configuration", nil)
+    if err != nil {
+        return nil, err
+    }
+
+    discovery = new(OIDCDiscovery)
+    if err := this.doJSON(req, discovery); err != nil {
+        return nil, err
+    }
+
+    if strings.TrimRight(discovery.Issuer, "/") != this.config.Issuer {
+        return nil, errors.New("auth: oidc issuer mismatch")
+    }
+
+    if discovery.AuthorizationEndpoint == "" ||
+        discovery.TokenEndpoint == "" ||
+        discovery.JwksURI == "" {
+        return nil, errors.New("auth: oidc discovery document invalid")
+    }
+
+    this.mu.Lock()
+    this.discovery = discovery
+    this.mu.Unlock()
+
+    return discovery, nil
+}
+
+// 获取签名公钥，找不到时重新获取公钥列表
+func (this *OIDC) Key(ctx context.Context, kid string, alg string) (*oidcKey, error) {
+    key, expired := this.findKey(kid, alg)
+    if key != nil && !expired {
+        return key, nil
+    }
+
+    // 限制公钥列表获取频率
+    this.mu.RLock()
+    recently := time.Since(this.keysTime) < time.Minute
+    this.mu.RUnlock()
+
+    if key == nil && recently {
+        return nil, ErrKeyNotFound
+    }
+
+    if err := this.fetchKeys(ctx); err != nil {
+        if key != nil {
+            return key, nil
+        }
+
+        return nil, err
+    }
+
+    key, _ = this.findKey(kid, alg)
+    if key == nil {
+        return nil, ErrKeyNotFound
+    }
+
+    return key, nil
+}
+
+// 查找公钥
+func (this *OIDC) findKey(kid string, alg string) (*oidcKey, bool) {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    expired := time.Since(this.keysTime) > oidcKeysExpiresIn
+
+    if kid != "" {
+        key, ok := this.keys[kid]
+        if ok && key.Match(alg) {
+            return key, expired
+        }
+
+        return nil, expired
+    }
+
+    // 没有 kid 时只有一个匹配的公钥才使用
+    var found *oidcKey
+    for _, key := range this.keys {
+        if key.Match(alg) {
+            if found != nil {
+                return nil, expired
+            }
+
+            found = key
+        }
+    }
+
+    return found, expired
+}
+
+// 获取公钥列表
+func (this *OIDC) fetchKeys(ctx context.Context) error {
+    discovery, err := this.Discovery(ctx)
+    if err != nil {
+        return err
+    }
+
+    req, err := http.NewRequestWithContext(ctx, "GET", discovery.JwksURI, nil)
+    if err != nil {
+        return err
+    }
+
+    var jwks struct {
+        Keys []oidcJWK `json:"keys"`
+    }
+
+    this.mu.Lock()
+    this.keysTime = time.Now()
+    this.mu.Unlock()
+
+    if err := this.doJSON(req, &jwks); err != nil {
+        return err
+    }
+
+    keys := make(map[string]*oidcKey)
+    for i, jwk := range jwks.Keys {
+        if jwk.Use != "" && jwk.Use != "sig" {
+            continue
+        }
+
+        key, err := jwk.Key()
+        if err != nil {
+            continue
+        }
+
+        kid := jwk.Kid
+        if kid == "" {
+            kid = "#" + goch.ToString(i)
+        }
+
+        keys[kid] = key
+    }
+
+    this.mu.Lock()
+    this.keys = keys
+    this.mu.Unlock()
+
+    return nil
+}
+
+// 请求并解析 json
+func (this *OIDC) doJSON(req *http.Request, data any) error {
+    resp, err := this.client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, 1 << 20))
+    if err != nil {
+        return err
+    }
+
+    err = json.Unmarshal(body, data)
+
+    if resp.StatusCode != http.StatusOK {
+        return errors.New("auth: oidc request failed with status " + goch.ToString(resp.StatusCode))
+    }
+
+    return err
+}
+
+// 获取字符声明
+func (this *OIDC) claimString(claims jwt.MapClaims, name string) string {
+    if name == "" {
+        return ""
+    }
+
+    return goch.ToString(claims[name])
+}
+
+// 解析 token 头数据
+func parseTokenHeader(token string) (map[string]any, error) {
+    parts := strings.Split(token, ".")
+    if len(parts) != 3 {
+        return nil, ErrInvalidIdToken
+    }
+
+    data, err := base64.RawURLEncoding.DecodeString(parts[0])
+    if err != nil {
+        return nil, ErrInvalidIdToken
+    }
+
+    header := make(map[string]any)
+    if err := json.Unmarshal(data, &header); err != nil {
+        return nil, ErrInvalidIdToken
+    }
+
+    return header, nil
+}
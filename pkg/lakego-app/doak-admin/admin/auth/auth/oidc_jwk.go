@@ -0,0 +1,166 @@
+package auth
+
+import (
+    "errors"
+    "strings"
+    "math/big"
+    "crypto/rsa"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "encoding/pem"
+    "encoding/base64"
+)
+
+/**
+ * JWK 公钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type oidcJWK struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid"`
+    Use string `json:"use"`
+    Alg string `json:"alg"`
+    Crv string `json:"crv"`
+    N   string `json:"n"`
+    E   string `json:"e"`
+    X   string `json:"x"`
+    Y   string `json:"y"`
+}
+
+// 转换为 PEM 格式的公钥，用于 jwt 包验证签名
+func (this oidcJWK) Key() (*oidcKey, error) {
+    var publicKey any
+
+    switch this.Kty {
+        case "RSA":
+            n, err := decodeJWKInt(this.N)
+            if err != nil {
+                return nil, err
+            }
+
+            e, err := decodeJWKInt(this.E)
+            if err != nil {
+                return nil, err
+            }
+
+            if !e.IsInt64() || e.Int64() > 1<<31 - 1 {
+                return nil, errors.New("auth: jwk rsa exponent invalid")
+            }
+
+            publicKey = &rsa.PublicKey{
+                N: n,
+                E: int(e.Int64()),
+            }
+
+        case "EC":
+            var curve elliptic.Curve
+            switch this.Crv {
+                case "P-256":
+                    curve = elliptic.P256()
+                case "P-384":
+                    curve = elliptic.P384()
+                case "P-521":
+                    curve = elliptic.P521()
+                default:
+                    return nil, errors.New("auth: jwk curve not support")
+            }
+
+            x, err := decodeJWKInt(this.X)
+            if err != nil {
+                return nil, err
+            }
+
+            y, err := decodeJWKInt(this.Y)
+            if err != nil {
+                return nil, err
+            }
+
+            if !curve.IsOnCurve(x, y) {
+                return nil, errors.New("auth: jwk ec point invalid")
+            }
+
+            publicKey = &ecdsa.PublicKey{
+                Curve: curve,
+                X:     x,
+                Y:     y,
+            }
+
+        case "OKP":
+            if this.Crv != "Ed25519" {
+                return nil, errors.New("auth: jwk curve not support")
+            }
+
+            x, err := base64.RawURLEncoding.DecodeString(this.X)
+            if err != nil || len(x) != ed25519.PublicKeySize {
+                return nil, errors.New("auth: jwk ed25519 key invalid")
+            }
+
+            publicKey = ed25519.PublicKey(x)
+
+        default:
+            return nil, errors.New("auth: jwk key type not support")
+    }
+
+    der, err := x509.MarshalPKIXPublicKey(publicKey)
+    if err != nil {
+        return nil, err
+    }
+
+    return &oidcKey{
+        Kty: this.Kty,
+        Alg: this.Alg,
+        PEM: pem.EncodeToMemory(&pem.Block{
+            Type:  "PUBLIC KEY",
+            Bytes: der,
+        }),
+    }, nil
+}
+
+/**
+ * 签名公钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type oidcKey struct {
+    // 类型
+    Kty string
+
+    // 限定的签名方式
+    Alg string
+
+    // PEM 格式公钥
+    PEM []byte
+}
+
+// 是否可以用于签名方式
+func (this *oidcKey) Match(alg string) bool {
+    if this.Alg != "" && this.Alg != alg {
+        return false
+    }
+
+    switch {
+        case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+            return this.Kty == "RSA"
+        case strings.HasPrefix(alg, "ES"):
+            return this.Kty == "EC"
+        case alg == "EdDSA":
+            return this.Kty == "OKP"
+    }
+
+    return false
+}
+
+// 解码数字
+func decodeJWKInt(s string) (*big.Int, error) {
+    data, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil || len(data) == 0 {
+        return nil, errors.New("auth: jwk value invalid")
+    }
+
+    return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,311 @@
+package auth
+
+import (
+    "time"
+    "context"
+    "strings"
+    "testing"
+    "math/big"
+    "net/http"
+    "net/http/httptest"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/x509"
+    "encoding/pem"
+    "encoding/json"
+    "encoding/base64"
+
+    "github.com/deatil/lakego-jwt/jwt"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+/**
+ * 测试用 OIDC 服务
+ */
+type testOIDCServer struct {
+    server *httptest.Server
+    key    *rsa.PrivateKey
+
+    // 换取的 id_token
+    idToken string
+}
+
+func newTestOIDCServer(t *testing.T) *testOIDCServer {
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    s := &testOIDCServer{
+        key: key,
+    }
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(map[string]string{
+            "issuer":                 s.server.URL,
+            "authorization_endpoint": s.server.URL + "/authorize",
+            "token_endpoint":         s.server.URL + "/token",
+            "jwks_uri":               s.server.URL + "/jwks",
+        })
+    })
+    mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(map[string]any{
+            "keys": []map[string]string{
+                {
+                    "kty": "RSA",
+                    "kid": "k1",
+                    "use": "sig",
+                    "alg": "RS256",
+                    "n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+                    "e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+                },
+            },
+        })
+    })
+    mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(map[string]string{
+            "id_token": s.idToken,
+        })
+    })
+
+    s.server = httptest.NewServer(mux)
+    t.Cleanup(s.server.Close)
+
+    return s
+}
+
+// 默认载荷
+func (this *testOIDCServer) claims() map[string]any {
+    return map[string]any{
+        "iss":   this.server.URL,
+        "aud":   "client-1",
+        "sub":   "user-1",
+        "nonce": "nonce-1",
+        "exp":   time.Now().Add(time.Hour).Unix(),
+        "iat":   time.Now().Unix(),
+
+        "preferred_username": "lakego",
+        "email":              "lakego@example.com",
+        "email_verified":     true,
+        "groups":             []string{"admins", "users"},
+    }
+}
+
+// 签名生成 token
+func (this *testOIDCServer) sign(t *testing.T, claims map[string]any) string {
+    privateKey := pem.EncodeToMemory(&pem.Block{
+        Type:  "RSA PRIVATE KEY",
+        Bytes: x509.MarshalPKCS1PrivateKey(this.key),
+    })
+
+    signer := jwt.New(
+        jwt.WithSigningMethod("RS256"),
+        jwt.WithPrivateKey(privateKey),
+        jwt.WithHeader("kid", "k1"),
+    )
+    for k, v := range claims {
+        signer.WithClaim(k, v)
+    }
+
+    token, err := signer.MakeToken()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return token
+}
+
+func (this *testOIDCServer) oidc(t *testing.T) *OIDC {
+    o, err := NewOIDC(OIDCConfig{
+        Issuer:       this.server.URL,
+        ClientID:     "client-1",
+        SubjectClaim: "sub",
+        NameClaim:    "preferred_username",
+        EmailClaim:   "email",
+        GroupsClaim:  "groups",
+        Timeout:      5 * time.Second,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return o
+}
+
+func Test_OIDC_Authenticate(t *testing.T) {
+    eq := assertT(t)
+
+    s := newTestOIDCServer(t)
+    o := s.oidc(t)
+
+    s.idToken = s.sign(t, s.claims())
+
+    identity, err := o.Authenticate(context.Background(), map[string]string{
+        "code":  "code-1",
+        "nonce": "nonce-1",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(identity.Subject, "user-1", "Subject")
+    eq(identity.Name, "lakego", "Name")
+    eq(identity.Email, "lakego@example.com", "Email")
+    eq(identity.EmailVerified, true, "EmailVerified")
+    eq(len(identity.Groups), 2, "Groups")
+
+    // nonce 不匹配
+    _, err = o.Authenticate(context.Background(), map[string]string{
+        "code":  "code-1",
+        "nonce": "nonce-2",
+    })
+    eq(err, ErrInvalidIdToken, "Authenticate bad nonce")
+
+    // 没有 nonce
+    claims := s.claims()
+    delete(claims, "nonce")
+    s.idToken = s.sign(t, claims)
+
+    _, err = o.Authenticate(context.Background(), map[string]string{
+        "code":  "code-1",
+        "nonce": "nonce-1",
+    })
+    eq(err, ErrInvalidIdToken, "Authenticate missing nonce")
+
+    _, err = o.Authenticate(context.Background(), map[string]string{
+        "code": "code-1",
+    })
+    eq(err, ErrInvalidCredentials, "Authenticate empty nonce")
+}
+
+func Test_OIDC_Verify(t *testing.T) {
+    eq := assertT(t)
+
+    s := newTestOIDCServer(t)
+    o := s.oidc(t)
+
+    claims, err := o.Verify(context.Background(), s.sign(t, s.claims()))
+    eq(err, nil, "Verify")
+    eq(claims["sub"], "user-1", "Verify sub")
+
+    // 多个受众时需要 azp
+    multiAud := s.claims()
+    multiAud["aud"] = []string{"client-2", "client-1"}
+    multiAud["azp"] = "client-1"
+
+    _, err = o.Verify(context.Background(), s.sign(t, multiAud))
+    eq(err, nil, "Verify multiple aud with azp")
+
+    tests := map[string]func(map[string]any){
+        "bad aud": func(c map[string]any) {
+            c["aud"] = "client-2"
+        },
+        "missing aud": func(c map[string]any) {
+            delete(c, "aud")
+        },
+        "aud not in list": func(c map[string]any) {
+            c["aud"] = []string{"client-2", "client-3"}
+        },
+        "multiple aud without azp": func(c map[string]any) {
+            c["aud"] = []string{"client-2", "client-1"}
+        },
+        "multiple aud bad azp": func(c map[string]any) {
+            c["aud"] = []string{"client-2", "client-1"}
+            c["azp"] = "client-2"
+        },
+        "bad iss": func(c map[string]any) {
+            c["iss"] = "https://evil.example.com"
+        },
+        "missing iss": func(c map[string]any) {
+            delete(c, "iss")
+        },
+        "expired": func(c map[string]any) {
+            c["exp"] = time.Now().Add(-time.Minute).Unix()
+        },
+        "missing exp": func(c map[string]any) {
+            delete(c, "exp")
+        },
+    }
+
+    for name, modify := range tests {
+        claims := s.claims()
+        modify(claims)
+
+        _, err := o.Verify(context.Background(), s.sign(t, claims))
+        eq(err, ErrInvalidIdToken, "Verify " + name)
+    }
+}
+
+func Test_OIDC_Verify_Alg(t *testing.T) {
+    eq := assertT(t)
+
+    s := newTestOIDCServer(t)
+    o := s.oidc(t)
+
+    token := s.sign(t, s.claims())
+
+    // HS 签名不允许使用
+    hs := jwt.New(
+        jwt.WithSigningMethod("HS256"),
+        jwt.WithSecret(base64.StdEncoding.EncodeToString([]byte("secret"))),
+        jwt.WithHeader("kid", "k1"),
+    )
+    for k, v := range s.claims() {
+        hs.WithClaim(k, v)
+    }
+
+    hsToken, err := hs.MakeToken()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, err = o.Verify(context.Background(), hsToken)
+    eq(err, ErrInvalidIdToken, "Verify alg HS256")
+
+    // alg none
+    header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"k1"}`))
+    parts := strings.Split(token, ".")
+
+    _, err = o.Verify(context.Background(), header + "." + parts[1] + ".")
+    eq(err, ErrInvalidIdToken, "Verify alg none")
+
+    // 公钥限定 RS256
+    header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS384","kid":"k1"}`))
+
+    _, err = o.Verify(context.Background(), header + "." + parts[1] + "." + parts[2])
+    eq(err, ErrKeyNotFound, "Verify alg mismatch key")
+
+    // 修改载荷
+    payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"` + s.server.URL + `","aud":"client-1","sub":"admin"}`))
+
+    _, err = o.Verify(context.Background(), parts[0] + "." + payload + "." + parts[2])
+    eq(err, ErrInvalidIdToken, "Verify tampered payload")
+
+    // 公钥不存在
+    other := jwt.New(
+        jwt.WithSigningMethod("RS256"),
+        jwt.WithPrivateKey(pem.EncodeToMemory(&pem.Block{
+            Type:  "RSA PRIVATE KEY",
+            Bytes: x509.MarshalPKCS1PrivateKey(s.key),
+        })),
+        jwt.WithHeader("kid", "k2"),
+    )
+    other.WithClaim("iss", s.server.URL)
+
+    otherToken, err := other.MakeToken()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, err = o.Verify(context.Background(), otherToken)
+    eq(err, ErrKeyNotFound, "Verify unknown kid")
+}
+
@@ -0,0 +1,427 @@
+package auth
+
+import (
+    "errors"
+    "strings"
+    "crypto/rand"
+    "encoding/hex"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/facade/permission"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    auth_password "github.com/deatil/lakego-doak-admin/admin/password"
+)
+
+var (
+    // 账号不存在且不允许自动创建
+    ErrAdminNotFound = errors.New("auth: admin not found")
+
+    // 同名本地账号已存在
+    ErrAdminExists = errors.New("auth: admin already exists")
+
+    // 账号已禁用
+    ErrAdminDisabled = errors.New("auth: admin disabled")
+
+    // 外部账号信息不完整
+    ErrInvalidIdentity = errors.New("auth: invalid identity")
+)
+
+/**
+ * 外部分组对应的管理分组
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type GroupMapping struct {
+    // 外部分组，LDAP 可以使用分组 DN 或者 cn
+    Group string
+
+    // 管理分组 ID
+    AuthGroup string
+}
+
+/**
+ * 账号创建设置
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type ProvisionOptions struct {
+    // 首次登录时自动创建账号
+    AutoCreate bool
+
+    // 关联邮箱相同的本地账号，邮箱需要已验证，超级管理员不会关联
+    LinkExisting bool
+
+    // 每次登录同步昵称和邮箱
+    SyncProfile bool
+
+    // 每次登录同步映射的分组
+    SyncGroups bool
+
+    // 创建账号时没有映射分组使用的默认分组
+    DefaultGroups []string
+
+    // 分组映射
+    GroupMapping []GroupMapping
+}
+
+// 从配置生成
+func NewProvisionOptions(conf Options) ProvisionOptions {
+    opts := ProvisionOptions{
+        AutoCreate:    conf.Bool("auto-create"),
+        LinkExisting:  conf.Bool("link-existing"),
+        SyncProfile:   conf.Bool("sync-profile"),
+        SyncGroups:    conf.Bool("sync-groups"),
+        DefaultGroups: conf.Strings("default-groups"),
+        GroupMapping:  make([]GroupMapping, 0),
+    }
+
+    for _, item := range conf.List("group-mapping") {
+        group := item.String("group")
+        authGroup := item.String("auth-group")
+        if group == "" || authGroup == "" {
+            continue
+        }
+
+        opts.GroupMapping = append(opts.GroupMapping, GroupMapping{
+            Group:     group,
+            AuthGroup: authGroup,
+        })
+    }
+
+    return opts
+}
+
+// 外部分组对应的管理分组
+func (this ProvisionOptions) MapGroups(groups []string) []string {
+    authGroups := make([]string, 0)
+
+    for _, mapping := range this.GroupMapping {
+        for _, group := range groups {
+            if matchGroup(group, mapping.Group) {
+                authGroups = appendUnique(authGroups, mapping.AuthGroup)
+                break
+            }
+        }
+    }
+
+    return authGroups
+}
+
+// 分组映射管理的全部分组
+func (this ProvisionOptions) ManagedGroups() []string {
+    authGroups := make([]string, 0)
+
+    for _, mapping := range this.GroupMapping {
+        authGroups = appendUnique(authGroups, mapping.AuthGroup)
+    }
+
+    return authGroups
+}
+
+// 获取或者创建外部账号对应的管理员
+func Provision(identity *Identity, opts ProvisionOptions, ip string) (*model.Admin, error) {
+    if identity.Provider == "" || identity.Subject == "" || identity.Name == "" {
+        return nil, ErrInvalidIdentity
+    }
+
+    // 管理员账号最长 30 个字符
+    if len(identity.Name) > 30 {
+        return nil, ErrInvalidIdentity
+    }
+
+    now := int(datebin.NowTimestamp())
+
+    admin, created, err := findOrCreateAdmin(identity, opts, ip)
+    if err != nil {
+        return nil, err
+    }
+
+    if admin.Status != 1 {
+        return nil, ErrAdminDisabled
+    }
+
+    model.NewAdminIdentity().
+        Where("provider = ?", identity.Provider).
+        Where("subject = ?", identity.Subject).
+        Updates(map[string]any{
+            "last_active": now,
+            "last_ip": ip,
+        })
+
+    // 同步资料
+    if opts.SyncProfile && !created {
+        data := make(map[string]any)
+        if identity.Nickname != "" && identity.Nickname != admin.Nickname {
+            data["nickname"] = identity.Nickname
+        }
+        if identity.Email != "" && identity.Email != admin.Email {
+            data["email"] = identity.Email
+        }
+
+        if len(data) > 0 {
+            model.NewAdmin().
+                Where("id = ?", admin.ID).
+                Updates(data)
+        }
+    }
+
+    // 同步分组
+    if created || opts.SyncGroups {
+        authGroups := opts.MapGroups(identity.Groups)
+        if created && len(authGroups) == 0 {
+            authGroups = opts.DefaultGroups
+        }
+
+        if err := syncGroups(admin.ID, authGroups, opts.ManagedGroups(), created); err != nil {
+            return nil, err
+        }
+    }
+
+    return admin, nil
+}
+
+// 获取关联的管理员，没有时关联或者创建
+func findOrCreateAdmin(identity *Identity, opts ProvisionOptions, ip string) (*model.Admin, bool, error) {
+    now := int(datebin.NowTimestamp())
+
+    admin := new(model.Admin)
+
+    // 已关联，查询没有数据时不返回错误，需要判断 ID
+    link := new(model.AdminIdentity)
+    err := model.NewAdminIdentity().
+        Where("provider = ?", identity.Provider).
+        Where("subject = ?", identity.Subject).
+        First(link).
+        Error
+    if err == nil && link.ID != "" {
+        err = model.NewAdmin().
+            Where("id = ?", link.AdminId).
+            First(admin).
+            Error
+        if err == nil && admin.ID != "" {
+            return admin, false, nil
+        }
+
+        // 管理员已删除时移除关联
+        model.NewAdminIdentity().
+            Where("id = ?", link.ID).
+            Delete(&model.AdminIdentity{})
+    }
+
+    created := false
+
+    linked, err := findLinkAdmin(identity, opts)
+    if err != nil {
+        return nil, false, err
+    }
+
+    if linked != nil {
+        admin = linked
+    } else {
+        // 同名的本地账号不会通过账号名关联
+        var count int64
+        model.NewAdmin().
+            Where("name = ?", identity.Name).
+            Count(&count)
+        if count > 0 {
+            return nil, false, ErrAdminExists
+        }
+
+        if !opts.AutoCreate {
+            return nil, false, ErrAdminNotFound
+        }
+
+        // 外部账号不能使用本地密码登录
+        password, salt, err := auth_password.MakePassword(randomString(32))
+        if err != nil {
+            return nil, false, err
+        }
+
+        admin = &model.Admin{
+            Name:         identity.Name,
+            Nickname:     identity.Nickname,
+            Email:        identity.Email,
+            Password:     password,
+            PasswordSalt: salt,
+            IsRoot:       0,
+            Status:       1,
+            AddTime:      now,
+            AddIp:        ip,
+        }
+
+        if err := model.NewDB().Create(admin).Error; err != nil {
+            return nil, false, err
+        }
+
+        created = true
+    }
+
+    err = model.NewDB().Create(&model.AdminIdentity{
+        AdminId:  admin.ID,
+        Provider: identity.Provider,
+        Subject:  identity.Subject,
+        AddTime:  now,
+        AddIp:    ip,
+    }).Error
+    if err != nil {
+        return nil, false, err
+    }
+
+    return admin, created, nil
+}
+
+// 需要关联的本地账号，只使用已验证的邮箱匹配
+// 账号名由外部账号控制，不能用于关联，超级管理员不会自动关联
+func findLinkAdmin(identity *Identity, opts ProvisionOptions) (*model.Admin, error) {
+    if !opts.LinkExisting || !identity.EmailVerified || identity.Email == "" {
+        return nil, nil
+    }
+
+    admins := make([]*model.Admin, 0)
+    err := model.NewAdmin().
+        Where("email = ?", identity.Email).
+        Where("is_root = ?", 0).
+        Limit(2).
+        Find(&admins).
+        Error
+    if err != nil {
+        return nil, err
+    }
+
+    // 多个账号使用相同邮箱时不关联
+    if len(admins) != 1 {
+        return nil, nil
+    }
+
+    // 已关联同一登录方式的其他外部账号时不关联
+    var count int64
+    model.NewAdminIdentity().
+        Where("admin_id = ?", admins[0].ID).
+        Where("provider = ?", identity.Provider).
+        Count(&count)
+    if count > 0 {
+        return nil, nil
+    }
+
+    return admins[0], nil
+}
+
+// 同步分组，只移除分组映射管理的分组
+func syncGroups(adminId string, authGroups []string, managedGroups []string, created bool) error {
+    groups := make([]model.AuthGroup, 0)
+    if len(authGroups) > 0 {
+        model.NewAuthGroup().
+            Where("id in ?", authGroups).
+            Find(&groups)
+    }
+
+    existIds := make([]string, 0)
+    model.NewAuthGroupAccess().
+        Where("admin_id = ?", adminId).
+        Pluck("group_id", &existIds)
+
+    c := permission.New()
+
+    // 移除
+    if !created {
+        removeIds := make([]string, 0)
+        for _, id := range managedGroups {
+            if array.InArray(id, existIds) && !array.InArray(id, authGroups) {
+                removeIds = append(removeIds, id)
+            }
+        }
+
+        if len(removeIds) > 0 {
+            removeGroups := make([]model.AuthGroup, 0)
+            model.NewAuthGroup().
+                Where("id in ?", removeIds).
+                Find(&removeGroups)
+
+            err := model.NewAuthGroupAccess().
+                Where("admin_id = ?", adminId).
+                Where("group_id in ?", removeIds).
+                Delete(&model.AuthGroupAccess{}).
+                Error
+            if err != nil {
+                return err
+            }
+
+            for _, group := range removeGroups {
+                c.DeleteRoleForUserInDomain(adminId, group.ID, tenant.Domain(group.TenantId))
+            }
+        }
+    }
+
+    // 添加
+    for _, group := range groups {
+        if array.InArray(group.ID, existIds) {
+            continue
+        }
+
+        err := model.NewDB().Create(&model.AuthGroupAccess{
+            AdminId: adminId,
+            GroupId: group.ID,
+        }).Error
+        if err != nil {
+            return err
+        }
+
+        // 分组所属租户
+        if group.TenantId != "" {
+            var count int64
+            model.NewTenantAccess().
+                Where("admin_id = ?", adminId).
+                Where("tenant_id = ?", group.TenantId).
+                Count(&count)
+            if count == 0 {
+                model.NewDB().Create(&model.TenantAccess{
+                    AdminId:  adminId,
+                    TenantId: group.TenantId,
+                })
+            }
+        }
+
+        if group.Status == 1 {
+            c.AddRoleForUserInDomain(adminId, group.ID, tenant.Domain(group.TenantId))
+        }
+    }
+
+    return nil
+}
+
+// 外部分组是否匹配，LDAP 分组可以使用 DN 或者第一个 RDN 的值
+func matchGroup(group string, name string) bool {
+    if strings.EqualFold(group, name) {
+        return true
+    }
+
+    rdn := strings.SplitN(group, ",", 2)[0]
+    if i := strings.IndexByte(rdn, '='); i > 0 {
+        return strings.EqualFold(strings.TrimSpace(rdn[i+1:]), name)
+    }
+
+    return false
+}
+
+// 添加不重复的值
+func appendUnique(items []string, item string) []string {
+    if array.InArray(item, items) {
+        return items
+    }
+
+    return append(items, item)
+}
+
+// 随机字符
+func randomString(n int) string {
+    buf := make([]byte, n)
+    rand.Read(buf)
+
+    return hex.EncodeToString(buf)
+}
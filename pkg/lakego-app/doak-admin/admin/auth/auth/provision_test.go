@@ -0,0 +1,123 @@
+package auth
+
+import (
+    "testing"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func migrateProvision(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.Admin{}, &model.AdminIdentity{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.Admin{}, &model.AdminIdentity{})
+    })
+}
+
+func createTestAdmin(t *testing.T, name string, email string, isRoot int) *model.Admin {
+    admin := &model.Admin{
+        Name:     name,
+        Nickname: name,
+        Email:    email,
+        IsRoot:   isRoot,
+        Status:   1,
+    }
+
+    if err := model.NewDB().Create(admin).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return admin
+}
+
+func Test_FindOrCreateAdmin_Link(t *testing.T) {
+    eq := assertT(t)
+
+    migrateProvision(t)
+
+    root := createTestAdmin(t, "admin", "root@example.com", 1)
+    local := createTestAdmin(t, "lakego", "lakego@example.com", 0)
+
+    opts := ProvisionOptions{
+        LinkExisting: true,
+    }
+
+    // 同名账号不会通过账号名关联
+    _, _, err := findOrCreateAdmin(&Identity{
+        Provider: "oidc",
+        Subject:  "sub-1",
+        Name:     "admin",
+    }, opts, "127.0.0.1")
+    eq(err, ErrAdminExists, "link by name")
+
+    // 超级管理员不会通过邮箱关联
+    _, _, err = findOrCreateAdmin(&Identity{
+        Provider:      "oidc",
+        Subject:       "sub-2",
+        Name:          "other",
+        Email:         root.Email,
+        EmailVerified: true,
+    }, opts, "127.0.0.1")
+    eq(err, ErrAdminNotFound, "link root")
+
+    // 邮箱未验证不关联
+    _, _, err = findOrCreateAdmin(&Identity{
+        Provider: "oidc",
+        Subject:  "sub-3",
+        Name:     "other",
+        Email:    local.Email,
+    }, opts, "127.0.0.1")
+    eq(err, ErrAdminNotFound, "link unverified email")
+
+    var count int64
+    model.NewAdminIdentity().Count(&count)
+    eq(count, int64(0), "no identity linked")
+
+    // 已验证的邮箱关联
+    admin, created, err := findOrCreateAdmin(&Identity{
+        Provider:      "oidc",
+        Subject:       "sub-4",
+        Name:          "other",
+        Email:         local.Email,
+        EmailVerified: true,
+    }, opts, "127.0.0.1")
+    eq(err, nil, "link verified email")
+    eq(created, false, "link verified email created")
+    if admin == nil {
+        t.Fatal("link verified email fail")
+    }
+    eq(admin.ID, local.ID, "link verified email admin")
+
+    // 已关联同一登录方式时不再关联其他外部账号
+    _, _, err = findOrCreateAdmin(&Identity{
+        Provider:      "oidc",
+        Subject:       "sub-5",
+        Name:          "other2",
+        Email:         local.Email,
+        EmailVerified: true,
+    }, opts, "127.0.0.1")
+    eq(err, ErrAdminNotFound, "link second subject")
+
+    // 已关联的外部账号使用存储的关联
+    admin, _, err = findOrCreateAdmin(&Identity{
+        Provider: "oidc",
+        Subject:  "sub-4",
+        Name:     "admin",
+    }, ProvisionOptions{}, "127.0.0.1")
+    eq(err, nil, "linked identity")
+    if admin != nil {
+        eq(admin.ID, local.ID, "linked identity admin")
+    }
+
+    // 不允许关联时不关联
+    _, _, err = findOrCreateAdmin(&Identity{
+        Provider:      "ldap",
+        Subject:       "uid=lakego",
+        Name:          "other",
+        Email:         local.Email,
+        EmailVerified: true,
+    }, ProvisionOptions{}, "127.0.0.1")
+    eq(err, ErrAdminNotFound, "link disabled")
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+    "strings"
+    "crypto/rand"
+    "encoding/hex"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+
+    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+)
+
+// 跳转登录 state 有效时间，单位秒
+const StateExpiresIn = 600
+
+// 生成跳转登录使用的 state 和 nonce
+func MakeState(provider string) (string, string, error) {
+    buf := make([]byte, 64)
+    if _, err := rand.Read(buf); err != nil {
+        return "", "", err
+    }
+
+    state := hex.EncodeToString(buf[:32])
+    nonce := hex.EncodeToString(buf[32:])
+
+    err := facade.Cache.Put(stateKey(state), provider + "|" + nonce, StateExpiresIn)
+    if err != nil {
+        return "", "", err
+    }
+
+    return state, nonce, nil
+}
+
+// 获取并删除 state，返回登录方式和 nonce
+func PullState(state string) (string, string) {
+    if state == "" {
+        return "", ""
+    }
+
+    key := stateKey(state)
+
+    data, err := facade.Cache.Get(key)
+    if err != nil || data == nil {
+        return "", ""
+    }
+
+    // state 只能使用一次
+    facade.Cache.Forget(key)
+
+    parts := strings.SplitN(goch.ToString(data), "|", 2)
+    if len(parts) != 2 {
+        return "", ""
+    }
+
+    return parts[0], parts[1]
+}
+
+// state 缓存 key
+func stateKey(state string) string {
+    return "passport:state:" + utils.SHA256(state)
+}
@@ -0,0 +1,153 @@
+package datascope
+
+import (
+    "strings"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/collection"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    authgroupRepository "github.com/deatil/lakego-doak-admin/admin/repository/authgroup"
+)
+
+// 数据范围
+const (
+    // 全部数据
+    All = "all"
+
+    // 本人数据
+    Self = "self"
+
+    // 所在分组数据
+    Department = "department"
+
+    // 所在分组及子分组数据
+    DepartmentAndChildren = "department-and-children"
+
+    // 自定义分组数据
+    Custom = "custom"
+)
+
+// 可用数据范围
+func Scopes() []string {
+    return []string{
+        All,
+        Self,
+        Department,
+        DepartmentAndChildren,
+        Custom,
+    }
+}
+
+// 数据范围是否可用
+func IsValid(scope string) bool {
+    for _, s := range Scopes() {
+        if s == scope {
+            return true
+        }
+    }
+
+    return false
+}
+
+/**
+ * 账号可见的数据范围
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type DataScope struct {
+    // 可见全部数据
+    All bool
+
+    // 可见数据所属的账号
+    AdminIds []string
+}
+
+// 账号在租户内的数据范围，多个分组时取并集
+func New(adminId string, tenantId string) *DataScope {
+    groups := make([]model.AuthGroup, 0)
+    model.NewAuthGroup().
+        Where("tenant_id = ?", tenantId).
+        Where("status = ?", 1).
+        Where("id in (?)", model.NewAuthGroupAccess().
+            Select("group_id").
+            Where("admin_id = ?", adminId),
+        ).
+        Find(&groups)
+
+    groupIds := make([]string, 0)
+    for _, group := range groups {
+        switch group.DataScope {
+            case All, "":
+                return &DataScope{
+                    All: true,
+                }
+            case Department:
+                groupIds = append(groupIds, group.ID)
+            case DepartmentAndChildren:
+                groupIds = append(groupIds, group.ID)
+                groupIds = append(groupIds, authgroupRepository.GetChildrenIds(group.ID)...)
+            case Custom:
+                groupIds = append(groupIds, SplitGroups(group.DataScopeGroups)...)
+        }
+    }
+
+    // 本人数据总是可见
+    adminIds := []string{adminId}
+
+    if len(groupIds) > 0 {
+        var groupAdminIds []string
+        model.NewAuthGroupAccess().
+            Where("group_id in ?", groupIds).
+            Pluck("admin_id", &groupAdminIds)
+
+        adminIds = append(adminIds, groupAdminIds...)
+    }
+
+    return &DataScope{
+        AdminIds: collection.Collect(adminIds).Unique().ToStringArray(),
+    }
+}
+
+// 当前请求账号的数据范围
+func FromContext(ctx *router.Context) *DataScope {
+    if scope, ok := ctx.Get("data_scope"); ok {
+        if ds, ok := scope.(*DataScope); ok {
+            return ds
+        }
+    }
+
+    var ds *DataScope
+
+    adminInfo, _ := ctx.Get("admin")
+    if adminData, ok := adminInfo.(*admin.Admin); ok {
+        if adminData.IsSuperAdministrator() {
+            ds = &DataScope{
+                All: true,
+            }
+        } else {
+            ds = New(adminData.GetId(), tenant.Current(ctx))
+        }
+    } else {
+        ds = &DataScope{}
+    }
+
+    ctx.Set("data_scope", ds)
+
+    return ds
+}
+
+// 自定义分组
+func SplitGroups(groups string) []string {
+    ids := make([]string, 0)
+    for _, id := range strings.Split(groups, ",") {
+        if id = strings.TrimSpace(id); id != "" {
+            ids = append(ids, id)
+        }
+    }
+
+    return ids
+}
@@ -0,0 +1,274 @@
+package datascope
+
+import (
+    "sort"
+    "strings"
+    "testing"
+    "net/http"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.AuthGroup{}, &model.AuthGroupAccess{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.AuthGroupAccess{}, &model.AuthGroup{})
+    })
+}
+
+func createGroup(t *testing.T, tenantId string, parentid string, scope string, scopeGroups ...string) string {
+    group := &model.AuthGroup{
+        TenantId:        tenantId,
+        Parentid:        parentid,
+        Title:           scope,
+        DataScope:       scope,
+        DataScopeGroups: strings.Join(scopeGroups, ","),
+        Status:          1,
+    }
+    if err := model.NewDB().Create(group).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return group.ID
+}
+
+func addAccess(t *testing.T, adminId string, groupId string) {
+    err := model.NewDB().Create(&model.AuthGroupAccess{
+        AdminId: adminId,
+        GroupId: groupId,
+    }).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func sortedIds(ds *DataScope) string {
+    ids := append([]string{}, ds.AdminIds...)
+    sort.Strings(ids)
+
+    return strings.Join(ids, ",")
+}
+
+/**
+ * 分组结构：
+ *   dept (admin-dept)
+ *     child (admin-child)
+ *       grandchild (admin-grandchild)
+ *   other (admin-other)
+ */
+func setupGroups(t *testing.T) map[string]string {
+    migrate(t)
+
+    dept := createGroup(t, "tenant-a", "0", Department)
+    child := createGroup(t, "tenant-a", dept, Department)
+    grandchild := createGroup(t, "tenant-a", child, Department)
+    other := createGroup(t, "tenant-a", "0", Department)
+
+    addAccess(t, "admin-dept", dept)
+    addAccess(t, "admin-child", child)
+    addAccess(t, "admin-grandchild", grandchild)
+    addAccess(t, "admin-other", other)
+
+    return map[string]string{
+        "dept":       dept,
+        "child":      child,
+        "grandchild": grandchild,
+        "other":      other,
+    }
+}
+
+func Test_New_All(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    allGroup := createGroup(t, "tenant-a", "0", All)
+    addAccess(t, "admin-1", allGroup)
+
+    ds := New("admin-1", "tenant-a")
+    eq(ds.All, true, "All")
+
+    // 未设置的按全部数据
+    emptyGroup := createGroup(t, "tenant-a", "0", "")
+    model.NewAuthGroup().Where("id = ?", emptyGroup).Update("data_scope", "")
+    addAccess(t, "admin-2", emptyGroup)
+
+    ds = New("admin-2", "tenant-a")
+    eq(ds.All, true, "All empty")
+
+    // 多个分组时取并集
+    addAccess(t, "admin-3", groups["other"])
+    addAccess(t, "admin-3", allGroup)
+
+    ds = New("admin-3", "tenant-a")
+    eq(ds.All, true, "All union")
+}
+
+func Test_New_Self(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    self := createGroup(t, "tenant-a", groups["dept"], Self)
+    addAccess(t, "admin-1", self)
+    addAccess(t, "admin-2", self)
+
+    ds := New("admin-1", "tenant-a")
+    eq(ds.All, false, "Self All")
+    eq(sortedIds(ds), "admin-1", "Self AdminIds")
+}
+
+func Test_New_Department(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    addAccess(t, "admin-1", groups["dept"])
+
+    ds := New("admin-1", "tenant-a")
+    eq(ds.All, false, "Department All")
+    eq(sortedIds(ds), "admin-1,admin-dept", "Department AdminIds")
+}
+
+func Test_New_DepartmentAndChildren(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    model.NewAuthGroup().
+        Where("id = ?", groups["dept"]).
+        Update("data_scope", DepartmentAndChildren)
+
+    ds := New("admin-dept", "tenant-a")
+    eq(ds.All, false, "DepartmentAndChildren All")
+    eq(sortedIds(ds), "admin-child,admin-dept,admin-grandchild", "DepartmentAndChildren AdminIds")
+
+    // 子分组只包含自身及以下
+    model.NewAuthGroup().
+        Where("id = ?", groups["child"]).
+        Update("data_scope", DepartmentAndChildren)
+
+    ds = New("admin-child", "tenant-a")
+    eq(sortedIds(ds), "admin-child,admin-grandchild", "DepartmentAndChildren child AdminIds")
+}
+
+func Test_New_Custom(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    custom := createGroup(t, "tenant-a", "0", Custom, groups["grandchild"], " " + groups["other"] + " ", "")
+    addAccess(t, "admin-1", custom)
+
+    ds := New("admin-1", "tenant-a")
+    eq(ds.All, false, "Custom All")
+    eq(sortedIds(ds), "admin-1,admin-grandchild,admin-other", "Custom AdminIds")
+
+    // 没有设置分组时只能看到本人数据
+    emptyCustom := createGroup(t, "tenant-a", "0", Custom)
+    addAccess(t, "admin-2", emptyCustom)
+
+    ds = New("admin-2", "tenant-a")
+    eq(sortedIds(ds), "admin-2", "Custom empty AdminIds")
+}
+
+func Test_New_Union(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    custom := createGroup(t, "tenant-a", "0", Custom, groups["other"])
+    addAccess(t, "admin-1", custom)
+    addAccess(t, "admin-1", groups["child"])
+
+    ds := New("admin-1", "tenant-a")
+    eq(sortedIds(ds), "admin-1,admin-child,admin-other", "Union AdminIds")
+}
+
+func Test_New_Tenant(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    // 其他租户和禁用的分组不计算
+    allGroup := createGroup(t, "tenant-b", "0", All)
+    disabled := createGroup(t, "tenant-a", "0", All)
+    model.NewAuthGroup().Where("id = ?", disabled).Update("status", 0)
+
+    addAccess(t, "admin-1", allGroup)
+    addAccess(t, "admin-1", disabled)
+    addAccess(t, "admin-1", groups["dept"])
+
+    ds := New("admin-1", "tenant-a")
+    eq(ds.All, false, "Tenant All")
+    eq(sortedIds(ds), "admin-1,admin-dept", "Tenant AdminIds")
+
+    ds = New("admin-1", "tenant-b")
+    eq(ds.All, true, "Tenant b All")
+
+    // 没有分组时只能看到本人数据
+    ds = New("admin-1", "tenant-c")
+    eq(ds.All, false, "No group All")
+    eq(sortedIds(ds), "admin-1", "No group AdminIds")
+}
+
+func Test_FromContext(t *testing.T) {
+    eq := assertT(t)
+
+    groups := setupGroups(t)
+
+    gin.SetMode(gin.TestMode)
+
+    newCtx := func() *router.Context {
+        ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+        ctx.Request = httptest.NewRequest(http.MethodGet, "/admin/attachment", nil)
+        ctx.Set("tenant_id", "tenant-a")
+
+        return ctx
+    }
+
+    // 未登录
+    ctx := newCtx()
+    ds := FromContext(ctx)
+    eq(ds.All, false, "Guest All")
+    eq(len(ds.AdminIds), 0, "Guest AdminIds")
+
+    // 普通账号
+    ctx = newCtx()
+    ctx.Set("admin", admin.New().WithId("admin-dept"))
+
+    ds = FromContext(ctx)
+    eq(sortedIds(ds), "admin-dept", "Admin AdminIds")
+
+    // 同一请求使用缓存
+    addAccess(t, "admin-dept", groups["other"])
+    eq(FromContext(ctx), ds, "FromContext cache")
+
+    // 超级管理员
+    ctx = newCtx()
+    ctx.Set("admin", admin.New().
+        WithId("642eb7b3-91ea-4808-bba6-f5f10938929a").
+        WithData(map[string]any{
+            "is_root": float64(1),
+        }))
+
+    ds = FromContext(ctx)
+    eq(ds.All, true, "Root All")
+}
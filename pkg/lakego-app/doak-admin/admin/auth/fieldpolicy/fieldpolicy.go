@@ -0,0 +1,282 @@
+package fieldpolicy
+
+import (
+    "fmt"
+    "strings"
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/support/url"
+    authruleRepository "github.com/deatil/lakego-doak-admin/admin/repository/authrule"
+)
+
+// 字段策略
+const (
+    // 不输出
+    Hide = "hide"
+
+    // 输出掩码
+    Mask = "mask"
+
+    // 只读，不允许提交
+    ReadOnly = "readonly"
+)
+
+// 策略优先级，合并时使用更严格的策略
+var priorities = map[string]int{
+    ReadOnly: 1,
+    Mask:     2,
+    Hide:     3,
+}
+
+/**
+ * 字段策略，字段名 => 策略
+ *
+ * 隐藏和掩码的字段同样不允许提交
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Policy map[string]string
+
+// 解析
+func Parse(data string) (Policy, error) {
+    policy := Policy{}
+
+    data = strings.TrimSpace(data)
+    if data == "" {
+        return policy, nil
+    }
+
+    if err := json.Unmarshal([]byte(data), &policy); err != nil {
+        return nil, fmt.Errorf("fieldpolicy: %w", err)
+    }
+
+    for field, value := range policy {
+        if field == "" {
+            return nil, fmt.Errorf("fieldpolicy: field is empty")
+        }
+
+        if _, ok := priorities[value]; !ok {
+            return nil, fmt.Errorf("fieldpolicy: invalid policy '%s' for field '%s'", value, field)
+        }
+    }
+
+    return policy, nil
+}
+
+// 格式化为保存的字符
+func Format(data any) (string, error) {
+    var policy Policy
+
+    switch v := data.(type) {
+        case nil:
+            return "", nil
+        case string:
+            p, err := Parse(v)
+            if err != nil {
+                return "", err
+            }
+
+            policy = p
+        default:
+            s, err := json.Marshal(v)
+            if err != nil {
+                return "", err
+            }
+
+            p, err := Parse(string(s))
+            if err != nil {
+                return "", err
+            }
+
+            policy = p
+    }
+
+    if len(policy) == 0 {
+        return "", nil
+    }
+
+    s, err := json.Marshal(policy)
+    return string(s), err
+}
+
+// 合并，使用更严格的策略
+func (this Policy) Merge(other Policy) Policy {
+    for field, value := range other {
+        if priorities[value] > priorities[this[field]] {
+            this[field] = value
+        }
+    }
+
+    return this
+}
+
+// 字段是否允许提交
+func (this Policy) Writable(field string) bool {
+    _, ok := this[field]
+    return !ok
+}
+
+// 当前请求的字段策略，超级管理员不限制
+func FromContext(ctx *router.Context) Policy {
+    if policy, ok := ctx.Get("field_policy"); ok {
+        if p, ok := policy.(Policy); ok {
+            return p
+        }
+    }
+
+    policy := Policy{}
+
+    adminInfo, _ := ctx.Get("admin")
+    if adminData, ok := adminInfo.(*admin.Admin); ok && !adminData.IsSuperAdministrator() {
+        policy = Match(url.RoutePath(ctx))
+    }
+
+    ctx.Set("field_policy", policy)
+
+    return policy
+}
+
+// 路由所属的资源，去除参数及之后的部分，比如 /admin/:id/avatar 为 /admin
+func Resource(path string) string {
+    segments := strings.Split(path, "/")
+    for i, segment := range segments {
+        if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+            segments = segments[:i]
+            break
+        }
+    }
+
+    return strings.TrimRight(strings.Join(segments, "/"), "/")
+}
+
+// 路由匹配的字段策略
+// 按资源匹配，不区分请求方式。读取时隐藏的字段在同一资源的其他接口同样不能提交
+func Match(path string) Policy {
+    policy := Policy{}
+
+    resource := Resource(path)
+    if resource == "" {
+        return policy
+    }
+
+    for _, rule := range authruleRepository.GetRouteRules() {
+        if rule.Status != 1 || rule.FieldPolicy == "" {
+            continue
+        }
+
+        if Resource(url.RuleRoutePath(rule.Url)) != resource {
+            continue
+        }
+
+        if p, err := Parse(rule.FieldPolicy); err == nil {
+            policy.Merge(p)
+        }
+    }
+
+    return policy
+}
+
+// 不允许提交的字段，没有时返回空字符
+func CheckWrite(ctx *router.Context, data map[string]any) string {
+    policy := FromContext(ctx)
+    if len(policy) == 0 {
+        return ""
+    }
+
+    for field := range data {
+        if !policy.Writable(field) {
+            return field
+        }
+    }
+
+    return ""
+}
+
+// 响应数据过滤
+func Filter(ctx *router.Context, data any) any {
+    policy := FromContext(ctx)
+    if len(policy) == 0 || data == nil {
+        return data
+    }
+
+    // 统一转为通用格式
+    var newData any
+    switch data.(type) {
+        case map[string]any, []any, []map[string]any, router.H:
+            newData = data
+        default:
+            s, err := json.Marshal(data)
+            if err != nil {
+                return data
+            }
+
+            if err := json.Unmarshal(s, &newData); err != nil {
+                return data
+            }
+    }
+
+    return apply(policy, newData)
+}
+
+// 处理数据，包括嵌套数据
+func apply(policy Policy, data any) any {
+    switch v := data.(type) {
+        case router.H:
+            return apply(policy, map[string]any(v))
+        case map[string]any:
+            newData := make(map[string]any, len(v))
+            for key, value := range v {
+                switch policy[key] {
+                    case Hide:
+                        continue
+                    case Mask:
+                        newData[key] = MaskValue(value)
+                    default:
+                        newData[key] = apply(policy, value)
+                }
+            }
+
+            return newData
+        case []map[string]any:
+            list := make([]any, 0, len(v))
+            for _, value := range v {
+                list = append(list, apply(policy, value))
+            }
+
+            return list
+        case []any:
+            list := make([]any, 0, len(v))
+            for _, value := range v {
+                list = append(list, apply(policy, value))
+            }
+
+            return list
+    }
+
+    return data
+}
+
+// 掩码，邮箱保留首字符和域名，其他保留首尾字符
+func MaskValue(value any) string {
+    s := []rune(goch.ToString(value))
+    if len(s) == 0 {
+        return ""
+    }
+
+    if at := strings.LastIndex(string(s), "@"); at > 0 {
+        name := []rune(string(s)[:at])
+        return string(name[0]) + "***" + string(s)[at:]
+    }
+
+    if len(s) <= 2 {
+        return strings.Repeat("*", len(s))
+    }
+
+    return string(s[0]) + "***" + string(s[len(s)-1])
+}
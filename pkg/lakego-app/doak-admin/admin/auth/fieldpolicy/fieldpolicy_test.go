@@ -0,0 +1,205 @@
+package fieldpolicy
+
+import (
+    "testing"
+    "reflect"
+    "net/http"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.AuthRule{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.AuthRule{})
+    })
+}
+
+func createRule(t *testing.T, method string, url string, policy string) {
+    err := model.NewDB().Create(&model.AuthRule{
+        Parentid:    "0",
+        Title:       method + " " + url,
+        Url:         url,
+        Method:      method,
+        Slug:        method + ":" + url,
+        FieldPolicy: policy,
+        Status:      1,
+    }).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func Test_Parse(t *testing.T) {
+    eq := assertT(t)
+
+    policy, err := Parse(`{"email": "mask", "password": "hide"}`)
+    eq(err, nil, "Parse error")
+    eq(policy, Policy{"email": Mask, "password": Hide}, "Parse")
+
+    policy, err = Parse(" ")
+    eq(err, nil, "Parse empty error")
+    eq(policy, Policy{}, "Parse empty")
+
+    for _, data := range []string{
+        `{"email": "show"}`,
+        `{"": "hide"}`,
+        `["email"]`,
+    } {
+        _, err = Parse(data)
+        eq(err != nil, true, "Parse invalid " + data)
+    }
+
+    s, err := Format(map[string]any{"email": "mask"})
+    eq(err, nil, "Format error")
+    eq(s, `{"email":"mask"}`, "Format")
+
+    s, err = Format(nil)
+    eq(s, "", "Format nil")
+
+    _, err = Format(`{"email": "show"}`)
+    eq(err != nil, true, "Format invalid")
+}
+
+func Test_Merge(t *testing.T) {
+    eq := assertT(t)
+
+    policy := Policy{"email": ReadOnly, "phone": Hide}
+    policy.Merge(Policy{"email": Hide, "phone": Mask, "name": ReadOnly})
+
+    eq(policy, Policy{"email": Hide, "phone": Hide, "name": ReadOnly}, "Merge")
+    eq(policy.Writable("email"), false, "Writable email")
+    eq(policy.Writable("nickname"), true, "Writable nickname")
+}
+
+func Test_Resource(t *testing.T) {
+    eq := assertT(t)
+
+    eq(Resource("/admin"), "/admin", "Resource index")
+    eq(Resource("/admin/:id"), "/admin", "Resource detail")
+    eq(Resource("/admin/:id/avatar"), "/admin", "Resource action")
+    eq(Resource("/auth/rule/:id/sort"), "/auth/rule", "Resource nested")
+    eq(Resource("/attachment/download/:code"), "/attachment/download", "Resource named")
+    eq(Resource("/static/*filepath"), "/static", "Resource wildcard")
+    eq(Resource("/admin/"), "/admin", "Resource trailing slash")
+    eq(Resource(""), "", "Resource empty")
+}
+
+func Test_Match(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    createRule(t, "GET", "/admin/{id}", `{"email": "hide"}`)
+    createRule(t, "GET", "/admin", `{"email": "mask", "phone": "mask"}`)
+    createRule(t, "PUT", "/admin/{id}", `{"nickname": "readonly"}`)
+    createRule(t, "GET", "/auth/group/{id}", `{"title": "hide"}`)
+
+    expected := Policy{"email": Hide, "phone": Mask, "nickname": ReadOnly}
+
+    // 同一资源的全部接口使用相同的策略
+    for _, path := range []string{
+        "/admin",
+        "/admin/:id",
+        "/admin/:id/avatar",
+    } {
+        eq(Match(path), expected, "Match " + path)
+    }
+
+    eq(Match("/auth/group/:id/access"), Policy{"title": Hide}, "Match other resource")
+    eq(Match("/admin/groups"), Policy{}, "Match sub resource")
+    eq(Match(""), Policy{}, "Match empty")
+
+    // 禁用的规则不使用
+    model.NewAuthRule().Where("method = ?", "PUT").Update("status", 0)
+    eq(Match("/admin/:id"), Policy{"email": Hide, "phone": Mask}, "Match disabled")
+}
+
+func Test_CheckWrite(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    // 只在读取接口设置隐藏
+    createRule(t, "GET", "/admin/{id}", `{"email": "hide", "phone": "mask"}`)
+
+    gin.SetMode(gin.TestMode)
+
+    request := func(method string, route string, path string, adminData *admin.Admin, data map[string]any) string {
+        var field string
+
+        engine := gin.New()
+        engine.Handle(method, "/admin-api" + route, func(ctx *router.Context) {
+            if adminData != nil {
+                ctx.Set("admin", adminData)
+            }
+
+            field = CheckWrite(ctx, data)
+        })
+
+        engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/admin-api" + path, nil))
+
+        return field
+    }
+
+    user := admin.New().WithId("admin-1")
+
+    eq(request(http.MethodPut, "/admin/:id", "/admin/1", user, map[string]any{"email": "a@b.c"}), "email", "PUT hide field")
+    eq(request(http.MethodPost, "/admin", "/admin", user, map[string]any{"phone": "123"}), "phone", "POST mask field")
+    eq(request(http.MethodPatch, "/admin/:id/avatar", "/admin/1/avatar", user, map[string]any{"email": "a@b.c"}), "email", "PATCH hide field")
+    eq(request(http.MethodPut, "/admin/:id", "/admin/1", user, map[string]any{"nickname": "test"}), "", "PUT writable field")
+    eq(request(http.MethodPut, "/auth/group/:id", "/auth/group/1", user, map[string]any{"email": "a@b.c"}), "", "PUT other resource")
+
+    root := admin.New().
+        WithId("642eb7b3-91ea-4808-bba6-f5f10938929a").
+        WithData(map[string]any{
+            "is_root": float64(1),
+        })
+    eq(request(http.MethodPut, "/admin/:id", "/admin/1", root, map[string]any{"email": "a@b.c"}), "", "PUT root")
+}
+
+func Test_Filter(t *testing.T) {
+    eq := assertT(t)
+
+    policy := Policy{"email": Mask, "password": Hide}
+
+    data := apply(policy, []any{
+        map[string]any{
+            "name":     "admin",
+            "email":    "admin@example.com",
+            "password": "123456",
+            "group": map[string]any{
+                "password": "123456",
+            },
+        },
+    })
+
+    eq(data, []any{
+        map[string]any{
+            "name":  "admin",
+            "email": "a***@example.com",
+            "group": map[string]any{},
+        },
+    }, "apply")
+
+    eq(MaskValue("13800138000"), "1***0", "MaskValue")
+    eq(MaskValue("ab"), "**", "MaskValue short")
+    eq(MaskValue(""), "", "MaskValue empty")
+}
@@ -0,0 +1,202 @@
+package lockout
+
+import (
+    "time"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/go-events/events"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/limiter"
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+)
+
+// 配置
+func conf(key string) string {
+    return "passport.throttle." + key
+}
+
+// 配置数值，未设置时使用默认值
+func confInt64(key string, def int64) int64 {
+    val := config.New("auth").GetInt64(conf(key))
+    if val <= 0 {
+        return def
+    }
+
+    return val
+}
+
+// 统计窗口
+func Window() time.Duration {
+    return time.Duration(confInt64("window", 900)) * time.Second
+}
+
+// 限流，使用滑动日志计算最早一次失败过期的时间
+func NewLimiter() *limiter.Log {
+    return limiter.NewLog(facade.Cache)
+}
+
+// 检测是否允许尝试登录，不允许时返回需要等待的秒数
+func Check(ip string, name string) (bool, int64) {
+    l := NewLimiter()
+    window := Window()
+
+    wait := l.AvailableIn(ipKey(ip), confInt64("ip-max-attempts", 20), window)
+    if nameWait := l.AvailableIn(nameKey(name), confInt64("name-max-attempts", 10), window); nameWait > wait {
+        wait = nameWait
+    }
+
+    if wait > 0 {
+        return false, int64((wait + time.Second - 1) / time.Second)
+    }
+
+    // 渐进延迟
+    until, _ := facade.Cache.Get(delayKey(name))
+    if wait := goch.ToInt64(until) - datebin.NowTimestamp(); wait > 0 {
+        return false, wait
+    }
+
+    return true, 0
+}
+
+// 登录失败，adminId 为空时表示账号不存在
+func Failed(ip string, name string, adminId string) {
+    l := NewLimiter()
+    window := Window()
+
+    l.Hit(ipKey(ip), window)
+    attempts := l.Hit(nameKey(name), window)
+
+    // 超过次数后每次失败延迟翻倍
+    delayAfter := confInt64("delay-after", 3)
+    if attempts >= delayAfter {
+        delay := confInt64("delay", 1) << uint(attempts - delayAfter)
+        if maxDelay := confInt64("max-delay", 60); delay > maxDelay || delay <= 0 {
+            delay = maxDelay
+        }
+
+        facade.Cache.Put(delayKey(name), datebin.NowTimestamp() + delay, delay)
+    }
+
+    if adminId == "" {
+        return
+    }
+
+    // 距离首次失败超过统计窗口时重新计数
+    now := datebin.NowTimestamp()
+    model.NewAdmin().
+        Where("id = ? AND login_fail_time <= ?", adminId, now - int64(window / time.Second)).
+        Updates(map[string]any{
+            "login_failures": 0,
+            "login_fail_time": now,
+        })
+
+    model.NewAdmin().
+        Where("id = ?", adminId).
+        Update("login_failures", gorm.Expr("login_failures + ?", 1))
+
+    result := map[string]any{}
+    err := model.NewAdmin().
+        Select("login_failures").
+        Where("id = ?", adminId).
+        First(&result).
+        Error
+    if err != nil {
+        return
+    }
+
+    if goch.ToInt64(result["login_failures"]) >= confInt64("lockout-attempts", 5) {
+        Lock(adminId, name, ip)
+    }
+}
+
+// 登录成功，清除失败记录
+func Succeeded(name string, adminId string) {
+    NewLimiter().Clear(nameKey(name))
+    facade.Cache.Forget(delayKey(name))
+
+    model.NewAdmin().
+        Where("id = ? AND login_failures > ?", adminId, 0).
+        Update("login_failures", 0)
+}
+
+// 锁定到期时间，未锁定返回 0
+func LockedUntil(lockedUntil any) int64 {
+    until := goch.ToInt64(lockedUntil)
+    if until <= datebin.NowTimestamp() {
+        return 0
+    }
+
+    return until
+}
+
+// 锁定账号
+func Lock(adminId string, name string, ip string) {
+    until := datebin.NowTimestamp() + confInt64("lockout-time", 1800)
+
+    err := model.NewAdmin().
+        Where("id = ?", adminId).
+        Updates(map[string]any{
+            "login_failures": 0,
+            "locked_until": until,
+        }).
+        Error
+    if err != nil {
+        return
+    }
+
+    events.DoAction("admin.passport-lockout.lock", map[string]any{
+        "admin_id": adminId,
+        "name": name,
+        "ip": ip,
+        "locked_until": until,
+    })
+}
+
+// 解锁账号
+func Unlock(adminId string, name string, operatorId string, ip string) error {
+    err := model.NewAdmin().
+        Where("id = ?", adminId).
+        Updates(map[string]any{
+            "login_failures": 0,
+            "locked_until": 0,
+        }).
+        Error
+    if err != nil {
+        return err
+    }
+
+    NewLimiter().Clear(nameKey(name))
+    facade.Cache.Forget(delayKey(name))
+
+    events.DoAction("admin.passport-lockout.unlock", map[string]any{
+        "admin_id": adminId,
+        "name": name,
+        "ip": ip,
+        "operator_id": operatorId,
+    })
+
+    return nil
+}
+
+// ip 限流 key
+func ipKey(ip string) string {
+    return "login:ip:" + utils.MD5(ip)
+}
+
+// 账号限流 key
+func nameKey(name string) string {
+    return "login:name:" + utils.MD5(name)
+}
+
+// 延迟 key
+func delayKey(name string) string {
+    return "login:delay:" + utils.MD5(name)
+}
+
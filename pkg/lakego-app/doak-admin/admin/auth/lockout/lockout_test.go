@@ -0,0 +1,149 @@
+package lockout
+
+import (
+    "testing"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.Admin{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.Admin{})
+    })
+}
+
+func createAdmin(t *testing.T, name string) *model.Admin {
+    admin := &model.Admin{
+        Name:   name,
+        Status: 1,
+    }
+    if err := model.NewDB().Create(admin).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return admin
+}
+
+func findAdmin(t *testing.T, id string) model.Admin {
+    var admin model.Admin
+    if err := model.NewDB().Where("id = ?", id).First(&admin).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return admin
+}
+
+func Test_Check(t *testing.T) {
+    eq := assertT(t)
+
+    ip := "127.0.0.10"
+    name := "lockout-check"
+
+    allow, wait := Check(ip, name)
+    eq(allow, true, "Check")
+    eq(wait, int64(0), "Check wait")
+
+    // 超过渐进延迟次数
+    for i := 0; i < 3; i++ {
+        Failed(ip, name, "")
+    }
+
+    allow, wait = Check(ip, name)
+    eq(allow, false, "Check delay")
+    eq(wait > 0 && wait <= 60, true, "Check delay wait")
+
+    // 超过账号次数，等待到最早一次失败过期
+    for i := 0; i < 7; i++ {
+        Failed(ip, name, "")
+    }
+
+    window := int64(Window().Seconds())
+
+    allow, wait = Check(ip, name)
+    eq(allow, false, "Check name")
+    eq(wait > window - 5 && wait <= window, true, "Check name wait")
+
+    // 其他账号只受 ip 次数限制
+    allow, _ = Check(ip, "lockout-other")
+    eq(allow, true, "Check other name")
+
+    Succeeded(name, "")
+
+    allow, _ = Check(ip, name)
+    eq(allow, true, "Check succeeded")
+}
+
+func Test_Lock(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    ip := "127.0.0.11"
+    admin := createAdmin(t, "lockout-lock")
+
+    for i := 0; i < 4; i++ {
+        Failed(ip, admin.Name, admin.ID)
+    }
+
+    data := findAdmin(t, admin.ID)
+    eq(data.LoginFailures, 4, "Failed login_failures")
+    eq(LockedUntil(data.LockedUntil), int64(0), "Failed not locked")
+
+    Failed(ip, admin.Name, admin.ID)
+
+    data = findAdmin(t, admin.ID)
+    eq(data.LoginFailures, 0, "Lock login_failures")
+    eq(LockedUntil(data.LockedUntil) > datebin.NowTimestamp(), true, "Lock locked_until")
+
+    err := Unlock(admin.ID, admin.Name, "operator", ip)
+    eq(err, nil, "Unlock")
+
+    data = findAdmin(t, admin.ID)
+    eq(LockedUntil(data.LockedUntil), int64(0), "Unlock locked_until")
+
+    allow, _ := Check(ip, admin.Name)
+    eq(allow, true, "Unlock Check")
+}
+
+func Test_FailedWindow(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    ip := "127.0.0.12"
+    admin := createAdmin(t, "lockout-window")
+
+    for i := 0; i < 4; i++ {
+        Failed(ip, admin.Name, admin.ID)
+    }
+
+    // 首次失败在统计窗口之前，重新计数
+    model.NewAdmin().
+        Where("id = ?", admin.ID).
+        Update("login_fail_time", datebin.NowTimestamp() - int64(Window().Seconds()))
+
+    Failed(ip, admin.Name, admin.ID)
+
+    data := findAdmin(t, admin.ID)
+    eq(data.LoginFailures, 1, "Failed window login_failures")
+    eq(LockedUntil(data.LockedUntil), int64(0), "Failed window not locked")
+
+    Succeeded(admin.Name, admin.ID)
+
+    data = findAdmin(t, admin.ID)
+    eq(data.LoginFailures, 0, "Succeeded login_failures")
+}
@@ -0,0 +1,377 @@
+package session
+
+import (
+    "errors"
+    "strings"
+
+    "gorm.io/plugin/dbresolver"
+
+    "github.com/deatil/go-events/events"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/auth"
+    "github.com/deatil/lakego-doak-admin/admin/support/token"
+)
+
+var (
+    // 会话不存在
+    ErrSessionNotFound = errors.New("session: session not found")
+
+    // 会话已撤销
+    ErrSessionRevoked = errors.New("session: session revoked")
+
+    // 刷新 token 重复使用
+    ErrTokenReused = errors.New("session: refresh token reused")
+)
+
+// 会话状态
+const (
+    StatusRevoked = 0
+    StatusActive  = 1
+)
+
+// 最后活动时间更新间隔，单位秒
+const touchInterval = 60
+
+/**
+ * 登录生成的 token
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Tokens struct {
+    AdminId      string
+    AccessToken  string
+    RefreshToken string
+    ExpiresIn    int
+}
+
+// 创建会话并生成 token
+func Create(ctx *router.Context, adminId string) (*Tokens, error) {
+    jwter := auth.NewWithAud(auth.GetJwtAud(ctx))
+
+    family := uuid.ToUUIDString()
+    refreshId := uuid.ToUUIDString()
+
+    tokens, err := makeTokens(jwter, adminId, family, refreshId)
+    if err != nil {
+        return nil, err
+    }
+
+    ip := router.GetRequestIp(ctx)
+    useragent := ctx.Request.Header.Get("User-Agent")
+    now := int(datebin.NowTimestamp())
+
+    err = model.NewDB().Create(&model.AdminSession{
+        AdminId:     adminId,
+        Family:      family,
+        RefreshId:   refreshId,
+        Device:      ParseDevice(useragent),
+        Ip:          ip,
+        Useragent:   useragent,
+        Status:      StatusActive,
+        LastActive:  now,
+        LastIp:      ip,
+        ExpiresTime: now + jwter.GetRefreshExpiresIn(),
+        AddTime:     now,
+    }).Error
+    if err != nil {
+        return nil, err
+    }
+
+    return tokens, nil
+}
+
+// 使用刷新 token 生成新的 token，旧的刷新 token 失效
+// 已失效的刷新 token 再次使用时撤销整个会话
+func Refresh(ctx *router.Context, refreshToken string) (*Tokens, error) {
+    jwter := auth.NewWithAud(auth.GetJwtAud(ctx))
+
+    claims, err := jwter.GetRefreshTokenClaims(refreshToken)
+    if err != nil {
+        return nil, err
+    }
+
+    adminId := jwter.GetDataFromTokenClaims(claims, "id")
+    family := jwter.GetDataFromTokenClaims(claims, "sid")
+    refreshId := jwter.GetDataFromTokenClaims(claims, "rid")
+    if adminId == "" || family == "" || refreshId == "" {
+        return nil, ErrSessionNotFound
+    }
+
+    sess, err := findByFamily(family)
+    if err != nil || sess.AdminId != adminId {
+        return nil, ErrSessionNotFound
+    }
+
+    if sess.Status != StatusActive {
+        return nil, ErrSessionRevoked
+    }
+
+    if sess.RefreshId != refreshId {
+        reused(sess, router.GetRequestIp(ctx))
+        return nil, ErrTokenReused
+    }
+
+    newRefreshId := uuid.ToUUIDString()
+
+    tokens, err := makeTokens(jwter, adminId, family, newRefreshId)
+    if err != nil {
+        return nil, err
+    }
+
+    now := int(datebin.NowTimestamp())
+
+    // 条件更新，并发刷新时只有一个成功
+    result := model.NewAdminSession().
+        Where("family = ? AND refresh_id = ? AND status = ?", family, refreshId, StatusActive).
+        Updates(map[string]any{
+            "refresh_id": newRefreshId,
+            "last_active": now,
+            "last_ip": router.GetRequestIp(ctx),
+            "expires_time": now + jwter.GetRefreshExpiresIn(),
+        })
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    if result.RowsAffected == 0 {
+        reused(sess, router.GetRequestIp(ctx))
+        return nil, ErrTokenReused
+    }
+
+    return tokens, nil
+}
+
+// 检测会话是否有效，同时更新最后活动时间
+func Check(adminId string, family string, ip string) bool {
+    if family == "" {
+        return false
+    }
+
+    sess, err := findByFamily(family)
+    if err != nil {
+        return false
+    }
+
+    now := int(datebin.NowTimestamp())
+
+    if sess.AdminId != adminId ||
+        sess.Status != StatusActive ||
+        sess.ExpiresTime < now {
+        return false
+    }
+
+    if now - sess.LastActive > touchInterval {
+        model.NewAdminSession().
+            Where("id = ?", sess.ID).
+            Updates(map[string]any{
+                "last_active": now,
+                "last_ip": ip,
+            })
+    }
+
+    return true
+}
+
+// 账号有效会话列表，标记当前会话
+func List(adminId string, currentFamily string) ([]map[string]any, error) {
+    list := make([]map[string]any, 0)
+
+    err := model.NewAdminSession().
+        Select([]string{
+            "id", "family", "device",
+            "ip", "useragent",
+            "last_active", "last_ip",
+            "expires_time", "add_time",
+        }).
+        Where("admin_id = ? AND status = ? AND expires_time >= ?", adminId, StatusActive, datebin.NowTimestamp()).
+        Order("last_active DESC").
+        Find(&list).
+        Error
+    if err != nil {
+        return nil, err
+    }
+
+    for _, item := range list {
+        item["current"] = item["family"] == currentFamily
+        delete(item, "family")
+    }
+
+    return list, nil
+}
+
+// 撤销账号的某个会话
+func Revoke(adminId string, id string) (bool, error) {
+    result := model.NewAdminSession().
+        Where("id = ? AND admin_id = ? AND status = ?", id, adminId, StatusActive).
+        Updates(revokeData())
+    if result.Error != nil {
+        return false, result.Error
+    }
+
+    return result.RowsAffected > 0, nil
+}
+
+// 撤销 token 所在的会话
+func RevokeFamily(family string) error {
+    return model.NewAdminSession().
+        Where("family = ? AND status = ?", family, StatusActive).
+        Updates(revokeData()).
+        Error
+}
+
+// 撤销账号全部会话，可排除指定会话
+func RevokeAll(adminId string, exceptFamily ...string) (int64, error) {
+    query := model.NewAdminSession().
+        Where("admin_id = ? AND status = ?", adminId, StatusActive)
+
+    if len(exceptFamily) > 0 && exceptFamily[0] != "" {
+        query = query.Where("family != ?", exceptFamily[0])
+    }
+
+    result := query.Updates(revokeData())
+
+    return result.RowsAffected, result.Error
+}
+
+// 撤销刷新 token 所在的会话，返回账号 ID
+func RevokeByRefreshToken(refreshToken string) (string, error) {
+    jwter := auth.New()
+
+    claims, err := jwter.GetRefreshTokenClaims(refreshToken, false)
+    if err != nil {
+        return "", err
+    }
+
+    adminId := jwter.GetDataFromTokenClaims(claims, "id")
+    family := jwter.GetDataFromTokenClaims(claims, "sid")
+    if family == "" {
+        return "", ErrSessionNotFound
+    }
+
+    sess, err := findByFamily(family)
+    if err != nil || sess.AdminId != adminId {
+        return "", ErrSessionNotFound
+    }
+
+    if sess.Status != StatusActive {
+        return "", ErrSessionRevoked
+    }
+
+    return adminId, RevokeFamily(family)
+}
+
+// 清除过期的会话
+// 已撤销的会话保留到过期，用于检测刷新 token 重复使用
+func Clean() (int64, error) {
+    result := model.NewDB().
+        Where("expires_time < ?", datebin.NowTimestamp()).
+        Delete(&model.AdminSession{})
+
+    return result.RowsAffected, result.Error
+}
+
+// 根据 UA 解析设备名称
+func ParseDevice(useragent string) string {
+    ua := strings.ToLower(useragent)
+
+    system := "Unknown"
+    for _, item := range [][2]string{
+        {"iphone", "iPhone"},
+        {"ipad", "iPad"},
+        {"android", "Android"},
+        {"windows", "Windows"},
+        {"mac os", "macOS"},
+        {"linux", "Linux"},
+    } {
+        if strings.Contains(ua, item[0]) {
+            system = item[1]
+            break
+        }
+    }
+
+    browser := "Unknown"
+    for _, item := range [][2]string{
+        {"edg/", "Edge"},
+        {"opr/", "Opera"},
+        {"firefox/", "Firefox"},
+        {"chrome/", "Chrome"},
+        {"safari/", "Safari"},
+        {"curl/", "curl"},
+        {"postman", "Postman"},
+    } {
+        if strings.Contains(ua, item[0]) {
+            browser = item[1]
+            break
+        }
+    }
+
+    return browser + " on " + system
+}
+
+// 生成 token
+func makeTokens(jwter *token.Token, adminId string, family string, refreshId string) (*Tokens, error) {
+    accessToken, err := jwter.MakeAccessToken(map[string]string{
+        "id": adminId,
+        "sid": family,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    refreshToken, err := jwter.MakeRefreshToken(map[string]string{
+        "id": adminId,
+        "sid": family,
+        "rid": refreshId,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &Tokens{
+        AdminId:      adminId,
+        AccessToken:  accessToken,
+        RefreshToken: refreshToken,
+        ExpiresIn:    jwter.GetAccessExpiresIn(),
+    }, nil
+}
+
+// 刷新 token 重复使用，撤销整个会话
+func reused(sess *model.AdminSession, ip string) {
+    RevokeFamily(sess.Family)
+
+    events.DoAction("admin.passport-session.reused", map[string]any{
+        "admin_id": sess.AdminId,
+        "session_id": sess.ID,
+        "ip": ip,
+    })
+}
+
+// 查询会话，读主库，避免刷新 token 轮换后从库读到旧数据
+func findByFamily(family string) (*model.AdminSession, error) {
+    sess := new(model.AdminSession)
+
+    err := model.NewAdminSession().
+        Clauses(dbresolver.Write).
+        Where("family = ?", family).
+        First(sess).
+        Error
+    if err != nil {
+        return nil, err
+    }
+
+    return sess, nil
+}
+
+// 撤销数据
+func revokeData() map[string]any {
+    return map[string]any{
+        "status": StatusRevoked,
+        "revoke_time": int(datebin.NowTimestamp()),
+    }
+}
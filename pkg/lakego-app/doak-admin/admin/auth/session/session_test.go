@@ -0,0 +1,207 @@
+package session
+
+import (
+    "testing"
+    "net/http"
+    "net/http/httptest"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+    "gorm.io/plugin/dbresolver"
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func newTestContext() *router.Context {
+    gin.SetMode(gin.TestMode)
+
+    ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+    ctx.Request = httptest.NewRequest(http.MethodPost, "/passport/refresh-token", nil)
+    ctx.Request.RemoteAddr = "127.0.0.1:8080"
+    ctx.Request.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0")
+
+    return ctx
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.AdminSession{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.AdminSession{})
+    })
+}
+
+func Test_Refresh(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    ctx := newTestContext()
+    adminId := "642eb7b3-91ea-4808-bba6-f5f10938929a"
+
+    tokens, err := Create(ctx, adminId)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    newTokens, err := Refresh(ctx, tokens.RefreshToken)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(newTokens.AdminId, adminId, "Refresh AdminId")
+    eq(newTokens.RefreshToken != tokens.RefreshToken, true, "Refresh rotate")
+
+    // 新的刷新 token 可以继续使用
+    lastTokens, err := Refresh(ctx, newTokens.RefreshToken)
+    eq(err, nil, "Refresh rotated token")
+    if lastTokens == nil {
+        t.Fatal("Refresh rotated token fail")
+    }
+
+    var sess model.AdminSession
+    model.NewAdminSession().Where("admin_id = ?", adminId).First(&sess)
+    eq(sess.Status, StatusActive, "session active")
+    eq(Check(adminId, sess.Family, "127.0.0.1"), true, "Check active")
+}
+
+func Test_Refresh_Reused(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    ctx := newTestContext()
+    adminId := "642eb7b3-91ea-4808-bba6-f5f10938929a"
+
+    // 另一个会话不受影响
+    other, err := Create(ctx, adminId)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tokens, err := Create(ctx, adminId)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    newTokens, err := Refresh(ctx, tokens.RefreshToken)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 重复使用已轮换的刷新 token
+    _, err = Refresh(ctx, tokens.RefreshToken)
+    eq(err, ErrTokenReused, "Refresh reused")
+
+    // 整个会话被撤销，新的刷新 token 也失效
+    _, err = Refresh(ctx, newTokens.RefreshToken)
+    eq(err, ErrSessionRevoked, "Refresh after reused")
+
+    sessions := make([]model.AdminSession, 0)
+    model.NewAdminSession().
+        Where("admin_id = ?", adminId).
+        Order("add_time ASC").
+        Find(&sessions)
+    eq(len(sessions), 2, "sessions count")
+
+    revoked := 0
+    for _, sess := range sessions {
+        if sess.Status == StatusRevoked {
+            revoked++
+
+            eq(sess.RevokeTime > 0, true, "revoke time")
+            eq(Check(adminId, sess.Family, "127.0.0.1"), false, "Check revoked")
+        }
+    }
+    eq(revoked, 1, "revoked count")
+
+    _, err = Refresh(ctx, other.RefreshToken)
+    eq(err, nil, "other session Refresh")
+}
+
+// 使用读写分离的数据库，从库数据不会同步
+func useReplica(t *testing.T) *gorm.DB {
+    dir := t.TempDir()
+
+    config := &gorm.Config{
+        NamingStrategy: facade.DB.NamingStrategy,
+    }
+
+    db, err := gorm.Open(sqlite.Open(dir + "/source.db"), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    replica, err := gorm.Open(sqlite.Open(dir + "/replica.db"), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    db.AutoMigrate(&model.AdminSession{})
+    replica.AutoMigrate(&model.AdminSession{})
+
+    err = db.Use(dbresolver.Register(dbresolver.Config{
+        Replicas: []gorm.Dialector{sqlite.Open(dir + "/replica.db")},
+    }))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    old := facade.DB
+    facade.DB = db
+
+    t.Cleanup(func() {
+        facade.DB = old
+    })
+
+    return replica
+}
+
+func Test_Refresh_Replica(t *testing.T) {
+    eq := assertT(t)
+
+    replica := useReplica(t)
+
+    ctx := newTestContext()
+    adminId := "642eb7b3-91ea-4808-bba6-f5f10938929a"
+
+    tokens, err := Create(ctx, adminId)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 从库只有轮换前的会话
+    var sess model.AdminSession
+    model.NewAdminSession().
+        Clauses(dbresolver.Write).
+        Where("admin_id = ?", adminId).
+        First(&sess)
+    replica.Create(&sess)
+
+    newTokens, err := Refresh(ctx, tokens.RefreshToken)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 轮换后立即刷新不会读到从库的旧刷新 token
+    _, err = Refresh(ctx, newTokens.RefreshToken)
+    eq(err, nil, "Refresh after rotation")
+
+    // 主库撤销后检测失效
+    eq(RevokeFamily(sess.Family), nil, "RevokeFamily")
+    eq(Check(adminId, sess.Family, "127.0.0.1"), false, "Check revoked on source")
+}
@@ -0,0 +1,152 @@
+package tenant
+
+import (
+    "net"
+    "errors"
+    "strings"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/permission"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+// 未指定租户时使用的权限域
+const Default = permission.DefaultDomain
+
+// 租户不存在
+var ErrTenantNotFound = errors.New("tenant: tenant not found")
+
+// 配置
+func conf(key string) string {
+    return "tenant." + key
+}
+
+// 是否开启多租户
+func Enabled() bool {
+    return config.New("admin").GetBool(conf("enable"))
+}
+
+// 从请求中获取租户标识，先检测请求头，再检测子域名
+func Code(ctx *router.Context) string {
+    cfg := config.New("admin")
+
+    header := cfg.GetString(conf("header"))
+    if header == "" {
+        header = "X-Tenant"
+    }
+
+    if code := strings.TrimSpace(ctx.GetHeader(header)); code != "" {
+        return code
+    }
+
+    // 子域名，比如 acme.admin.example.com 的租户为 acme
+    domain := strings.Trim(cfg.GetString(conf("domain")), ".")
+    if domain == "" {
+        return ""
+    }
+
+    host := ctx.Request.Host
+    if h, _, err := net.SplitHostPort(host); err == nil {
+        host = h
+    }
+
+    suffix := "." + strings.ToLower(domain)
+    host = strings.ToLower(host)
+    if !strings.HasSuffix(host, suffix) {
+        return ""
+    }
+
+    code := strings.TrimSuffix(host, suffix)
+    if code == "" || strings.Contains(code, ".") {
+        return ""
+    }
+
+    return code
+}
+
+// 解析当前请求的租户，没有指定租户时返回 nil
+func Resolve(ctx *router.Context) (*model.Tenant, error) {
+    if !Enabled() {
+        return nil, nil
+    }
+
+    code := Code(ctx)
+    if code == "" {
+        return nil, nil
+    }
+
+    return Find(code)
+}
+
+// 根据标识获取启用的租户
+func Find(code string) (*model.Tenant, error) {
+    var info model.Tenant
+    err := model.NewTenant().
+        Where("code = ?", code).
+        Where("status = ?", 1).
+        First(&info).
+        Error
+    if err != nil {
+        return nil, ErrTenantNotFound
+    }
+
+    return &info, nil
+}
+
+// 管理员是否属于租户
+func IsMember(adminId string, tenantId string) bool {
+    var total int64
+    err := model.NewTenantAccess().
+        Where("admin_id = ?", adminId).
+        Where("tenant_id = ?", tenantId).
+        Count(&total).
+        Error
+    if err != nil {
+        return false
+    }
+
+    return total > 0
+}
+
+// 管理员所属的租户
+func AdminTenants(adminId string) []model.Tenant {
+    list := make([]model.Tenant, 0)
+
+    accessDB := model.NewTenantAccess().
+        Select("tenant_id").
+        Where("admin_id = ?", adminId)
+
+    model.NewTenant().
+        Where("id in (?)", accessDB).
+        Where("status = ?", 1).
+        Order("listorder ASC").
+        Find(&list)
+
+    return list
+}
+
+// 租户对应的权限域
+func Domain(tenantId string) string {
+    if tenantId == "" {
+        return Default
+    }
+
+    return tenantId
+}
+
+// 当前请求的租户 id，未指定租户时为空
+func Current(ctx *router.Context) string {
+    tenantId, _ := ctx.Get("tenant_id")
+    if id, ok := tenantId.(string); ok {
+        return id
+    }
+
+    return ""
+}
+
+// 当前请求的权限域
+func CurrentDomain(ctx *router.Context) string {
+    return Domain(Current(ctx))
+}
@@ -0,0 +1,296 @@
+package twofactor
+
+import (
+    "time"
+    "strings"
+    "strconv"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/totp"
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/facade/crypt"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+)
+
+// 两步验证状态
+const (
+    StatusDisabled = 0
+    StatusEnabled  = 1
+)
+
+// 配置
+func conf(key string) string {
+    return "passport.2fa." + key
+}
+
+// 发行方，显示在验证器应用中
+func Issuer() string {
+    issuer := config.New("auth").GetString(conf("issuer"))
+    if issuer == "" {
+        issuer = "lakego-admin"
+    }
+
+    return issuer
+}
+
+// 超级管理员是否必须开启
+func ForceRoot() bool {
+    return config.New("auth").GetBool(conf("force-root"))
+}
+
+// 账号是否必须开启两步验证
+func IsForced(isRoot int) bool {
+    return isRoot == 1 && ForceRoot()
+}
+
+// 验证器
+func NewTOTP(secret string) *totp.TOTP {
+    c := config.New("auth")
+
+    otp := totp.New(secret)
+    if skew := c.GetInt(conf("skew")); skew > 0 {
+        otp.WithSkew(skew)
+    }
+
+    return otp
+}
+
+// 生成密钥
+func GenerateSecret() (string, error) {
+    return totp.GenerateSecret()
+}
+
+// 加密密钥，保存到数据库前使用
+func EncryptSecret(secret string) (string, error) {
+    return crypt.Encrypt(secret)
+}
+
+// 解密数据库保存的密钥
+func DecryptSecret(secret string) (string, error) {
+    return crypt.Decrypt(secret)
+}
+
+// 验证器绑定链接
+func URI(secret string, account string) string {
+    return NewTOTP(secret).URI(Issuer(), account)
+}
+
+// 生成恢复码，返回明文和保存用的哈希列表
+func GenerateRecoveryCodes() ([]string, string, error) {
+    num := config.New("auth").GetInt(conf("recovery-codes"))
+    if num <= 0 {
+        num = 8
+    }
+
+    codes := make([]string, 0, num)
+    hashes := make([]string, 0, num)
+    for i := 0; i < num; i++ {
+        buf := make([]byte, 5)
+        if _, err := rand.Read(buf); err != nil {
+            return nil, "", err
+        }
+
+        code := hex.EncodeToString(buf)
+        code = code[:5] + "-" + code[5:]
+
+        codes = append(codes, code)
+        hashes = append(hashes, HashRecoveryCode(code))
+    }
+
+    data, err := json.Marshal(hashes)
+    if err != nil {
+        return nil, "", err
+    }
+
+    return codes, string(data), nil
+}
+
+// 恢复码哈希
+func HashRecoveryCode(code string) string {
+    code = strings.ToLower(strings.TrimSpace(code))
+    code = strings.Replace(code, "-", "", -1)
+
+    return utils.SHA256(code)
+}
+
+// 剩余恢复码数量
+func RecoveryCodesCount(recovery string) int {
+    return len(parseRecoveryCodes(recovery))
+}
+
+// 校验验证码，同一验证码只能使用一次，
+// secret 为数据库保存的加密密钥
+func VerifyCode(adminId string, secret string, code string) bool {
+    if secret == "" {
+        return false
+    }
+
+    secret, err := DecryptSecret(secret)
+    if err != nil {
+        return false
+    }
+
+    otp := NewTOTP(secret)
+
+    counter, ok := otp.Validate(code, time.Now())
+    if !ok {
+        return false
+    }
+
+    key := "2fa:used:" + adminId + ":" + strconv.FormatInt(counter, 10)
+
+    added, err := facade.Cache.Add(key, "1", 300)
+    if err != nil || !added {
+        return false
+    }
+
+    return true
+}
+
+// 使用恢复码，使用后从列表删除
+func UseRecoveryCode(adminId string, recovery string, code string) bool {
+    hashes := parseRecoveryCodes(recovery)
+    if len(hashes) == 0 {
+        return false
+    }
+
+    hashed := HashRecoveryCode(code)
+
+    found := false
+    newHashes := make([]string, 0, len(hashes))
+    for _, h := range hashes {
+        if !found && h == hashed {
+            found = true
+            continue
+        }
+
+        newHashes = append(newHashes, h)
+    }
+
+    if !found {
+        return false
+    }
+
+    data, _ := json.Marshal(newHashes)
+
+    // 条件更新，防止同一恢复码并发使用
+    result := model.NewAdmin().
+        Where("id = ? AND totp_recovery = ?", adminId, recovery).
+        Updates(map[string]any{
+            "totp_recovery": string(data),
+        })
+    if result.Error != nil || result.RowsAffected == 0 {
+        return false
+    }
+
+    return true
+}
+
+// 校验验证码或者恢复码
+func Verify(admin *model.Admin, code string) bool {
+    code = strings.TrimSpace(code)
+    if code == "" {
+        return false
+    }
+
+    if VerifyCode(admin.ID, admin.TotpSecret, code) {
+        return true
+    }
+
+    return UseRecoveryCode(admin.ID, admin.TotpRecovery, code)
+}
+
+// 登录挑战有效时间
+func ChallengeExpiresIn() int64 {
+    expiresIn := config.New("auth").GetInt64(conf("challenge-expires-in"))
+    if expiresIn <= 0 {
+        expiresIn = 300
+    }
+
+    return expiresIn
+}
+
+// 生成登录挑战 token
+func MakeChallenge(adminId string) (string, error) {
+    buf := make([]byte, 32)
+    if _, err := rand.Read(buf); err != nil {
+        return "", err
+    }
+
+    token := hex.EncodeToString(buf)
+    key := challengeKey(token)
+
+    expiresIn := ChallengeExpiresIn()
+
+    if err := facade.Cache.Put(key, adminId, expiresIn); err != nil {
+        return "", err
+    }
+
+    facade.Cache.Put(key + ":attempts", 0, expiresIn)
+
+    return token, nil
+}
+
+// 获取登录挑战对应的账号 id
+func GetChallenge(token string) string {
+    if token == "" {
+        return ""
+    }
+
+    data, err := facade.Cache.Get(challengeKey(token))
+    if err != nil || data == nil {
+        return ""
+    }
+
+    return goch.ToString(data)
+}
+
+// 记录失败次数，超过限制后挑战失效
+func FailChallenge(token string) {
+    key := challengeKey(token)
+
+    facade.Cache.Increment(key + ":attempts")
+
+    attempts, _ := facade.Cache.Get(key + ":attempts")
+
+    maxAttempts := config.New("auth").GetInt(conf("max-attempts"))
+    if maxAttempts <= 0 {
+        maxAttempts = 5
+    }
+
+    if goch.ToInt(attempts) >= maxAttempts {
+        ForgetChallenge(token)
+    }
+}
+
+// 删除登录挑战
+func ForgetChallenge(token string) {
+    key := challengeKey(token)
+
+    facade.Cache.Forget(key)
+    facade.Cache.Forget(key + ":attempts")
+}
+
+// 挑战缓存 key
+func challengeKey(token string) string {
+    return "2fa:challenge:" + utils.SHA256(token)
+}
+
+// 解析恢复码
+func parseRecoveryCodes(recovery string) []string {
+    hashes := make([]string, 0)
+    if recovery == "" {
+        return hashes
+    }
+
+    json.Unmarshal([]byte(recovery), &hashes)
+
+    return hashes
+}
@@ -0,0 +1,163 @@
+package twofactor
+
+import (
+    "time"
+    "testing"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.Admin{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.Admin{})
+    })
+}
+
+// 开启两步验证的账号，返回账号和明文密钥
+func enrol(t *testing.T, name string) (*model.Admin, string) {
+    secret, err := GenerateSecret()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    encrypted, err := EncryptSecret(secret)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, recovery, err := GenerateRecoveryCodes()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    admin := &model.Admin{
+        Name:         name,
+        Status:       1,
+        TotpSecret:   encrypted,
+        TotpStatus:   StatusEnabled,
+        TotpRecovery: recovery,
+    }
+    if err := model.NewDB().Create(admin).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return admin, secret
+}
+
+func findAdmin(t *testing.T, id string) *model.Admin {
+    admin := new(model.Admin)
+    if err := model.NewDB().Where("id = ?", id).First(admin).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return admin
+}
+
+func Test_Enrol(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    admin, secret := enrol(t, "2fa-enrol")
+
+    // 数据库不保存明文
+    data := findAdmin(t, admin.ID)
+    eq(data.TotpSecret != secret, true, "TotpSecret encrypted")
+
+    decrypted, err := DecryptSecret(data.TotpSecret)
+    eq(err, nil, "DecryptSecret err")
+    eq(decrypted, secret, "DecryptSecret")
+
+    eq(URI(secret, "2fa-enrol") != "", true, "URI")
+}
+
+func Test_VerifyCode(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    admin, secret := enrol(t, "2fa-verify")
+
+    totpCode, err := NewTOTP(secret).Generate(time.Now())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(VerifyCode(admin.ID, admin.TotpSecret, "000000x"), false, "VerifyCode wrong")
+    eq(VerifyCode(admin.ID, "", totpCode), false, "VerifyCode empty secret")
+
+    // 明文密钥不能通过校验
+    eq(VerifyCode(admin.ID, secret, totpCode), false, "VerifyCode plain secret")
+
+    eq(VerifyCode(admin.ID, admin.TotpSecret, totpCode), true, "VerifyCode")
+
+    // 同一验证码不能重复使用
+    eq(VerifyCode(admin.ID, admin.TotpSecret, totpCode), false, "VerifyCode replay")
+    eq(Verify(findAdmin(t, admin.ID), totpCode), false, "Verify replay")
+}
+
+func Test_RecoveryCodes(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    admin, _ := enrol(t, "2fa-recovery")
+
+    codes, recovery, err := GenerateRecoveryCodes()
+    eq(err, nil, "GenerateRecoveryCodes err")
+    eq(len(codes), 8, "GenerateRecoveryCodes len")
+    eq(RecoveryCodesCount(recovery), 8, "RecoveryCodesCount")
+
+    model.NewAdmin().
+        Where("id = ?", admin.ID).
+        Update("totp_recovery", recovery)
+
+    // 不区分大小写和前后空格
+    eq(Verify(findAdmin(t, admin.ID), " " + codes[0] + " "), true, "Verify recovery code")
+
+    data := findAdmin(t, admin.ID)
+    eq(RecoveryCodesCount(data.TotpRecovery), 7, "RecoveryCodesCount used")
+
+    // 恢复码只能使用一次
+    eq(Verify(data, codes[0]), false, "Verify recovery code used")
+
+    // 旧的恢复码列表已失效
+    eq(UseRecoveryCode(admin.ID, recovery, codes[1]), false, "UseRecoveryCode stale")
+
+    eq(Verify(data, "00000-00000"), false, "Verify wrong recovery code")
+    eq(Verify(data, ""), false, "Verify empty")
+}
+
+func Test_Challenge(t *testing.T) {
+    eq := assertT(t)
+
+    token, err := MakeChallenge("admin-id")
+    eq(err, nil, "MakeChallenge err")
+    eq(GetChallenge(token), "admin-id", "GetChallenge")
+    eq(GetChallenge(""), "", "GetChallenge empty")
+
+    // 超过最大次数后挑战失效
+    for i := 0; i < 4; i++ {
+        FailChallenge(token)
+    }
+    eq(GetChallenge(token), "admin-id", "GetChallenge before limit")
+
+    FailChallenge(token)
+    eq(GetChallenge(token), "", "GetChallenge after limit")
+
+    token, _ = MakeChallenge("admin-id")
+    ForgetChallenge(token)
+    eq(GetChallenge(token), "", "ForgetChallenge")
+}
@@ -8,6 +8,7 @@ import (
     "github.com/deatil/lakego-filesystem/filesystem"
     "github.com/deatil/lakego-doak/lakego/path"
     "github.com/deatil/lakego-doak/lakego/command"
+    "github.com/deatil/lakego-doak/lakego/migration"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
 )
@@ -82,6 +83,16 @@ func runInsatll() {
         }
     }
 
+    // 执行已注册的数据库迁移
+    done, err := migration.NewMigrator(db).Run(migration.Migrations())
+    for _, name := range done {
+        fmt.Println(name, "\t 迁移成功！")
+    }
+    if err != nil {
+        fmt.Println(err, "\t 迁移失败！")
+        os.Exit(1)
+    }
+
     installFile, _ := os.OpenFile("./install.lock", os.O_RDWR|os.O_CREATE, os.ModePerm)
     installFile.WriteString("")
 
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+    "fmt"
+
+    "github.com/deatil/lakego-doak/lakego/command"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/twofactor"
+)
+
+/**
+ * 重置账号两步验证，用于丢失验证器的账号
+ *
+ * > ./main lakego-admin:passport-2fa-reset --name=[name]
+ * > main.exe lakego-admin:passport-2fa-reset --name=[name]
+ * > go run main.go lakego-admin:passport-2fa-reset --name=[name]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var PassportTwoFactorResetCmd = &command.Command{
+    Use: "lakego-admin:passport-2fa-reset",
+    Short: "lakego-admin passport-2fa-reset.",
+    Example: "{execfile} lakego-admin:passport-2fa-reset --name=[name]",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+
+    },
+    Run: func(cmd *command.Command, args []string) {
+        PassportTwoFactorReset()
+    },
+}
+
+var twoFactorName string
+
+func init() {
+    pf := PassportTwoFactorResetCmd.Flags()
+    pf.StringVarP(&twoFactorName, "name", "n", "", "账号")
+
+    command.MarkFlagRequired(pf, "name")
+}
+
+// 重置两步验证
+func PassportTwoFactorReset() {
+    if twoFactorName == "" {
+        fmt.Println("账号不能为空")
+        return
+    }
+
+    // 查询
+    result := map[string]any{}
+    err := model.NewAdmin().
+        Where("name = ?", twoFactorName).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        fmt.Println("账号信息不存在")
+        return
+    }
+
+    err = model.NewAdmin().
+        Where("name = ?", twoFactorName).
+        Updates(map[string]any{
+            "totp_secret": "",
+            "totp_status": twofactor.StatusDisabled,
+            "totp_recovery": "",
+        }).
+        Error
+    if err != nil {
+        fmt.Println("重置两步验证失败")
+        return
+    }
+
+    fmt.Println("重置两步验证成功")
+}
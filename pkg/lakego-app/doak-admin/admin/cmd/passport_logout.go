@@ -5,20 +5,20 @@ import (
 
     "github.com/deatil/go-datebin/datebin"
     "github.com/deatil/lakego-doak/lakego/command"
-    "github.com/deatil/lakego-doak/lakego/facade"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
-    "github.com/deatil/lakego-doak-admin/admin/auth/auth"
-    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
 )
 
 /**
- * 强制将 jwt 的 refreshToken 放入黑名单
+ * 强制退出 jwt 的 refreshToken 所在的登录会话
  *
  * > ./main lakego-admin:passport-logout --refreshToken=[token]
  * > main.exe lakego-admin:passport-logout --refreshToken=[token]
  * > go run main.go lakego-admin:passport-logout --refreshToken=[token]
  *
+ * > go run main.go lakego-admin:passport-logout --name=[name]
+ *
  * @create 2021-9-26
  * @author deatil
  */
@@ -36,6 +36,7 @@ var PassportLogoutCmd = &command.Command{
 }
 
 var refreshToken string
+var logoutName string
 
 func init() {
     // 全局
@@ -44,41 +45,47 @@ func init() {
     // 当前命令
     pf := PassportLogoutCmd.Flags()
     pf.StringVarP(&refreshToken, "refreshToken", "r", "", "刷新token")
-
-    command.MarkFlagRequired(pf, "refreshToken")
+    pf.StringVarP(&logoutName, "name", "n", "", "账号，退出账号全部会话")
 }
 
-// 强制将 jwt 的 refreshToken 放入黑名单
+// 强制退出登录会话
 func PassportLogout() {
-    c := facade.Cache
-
-    if c.Has(utils.MD5(refreshToken)) {
-        fmt.Println("refreshToken 已失效")
+    if refreshToken == "" && logoutName == "" {
+        fmt.Println("refreshToken 和账号不能同时为空")
         return
     }
 
-    // jwt
-    jwter := auth.New()
-
-    // 拿取数据
-    claims, claimsErr := jwter.GetRefreshTokenClaims(refreshToken, false)
-    if claimsErr != nil {
-        fmt.Println("refreshToken 已失效")
-        return
+    var adminId string
+
+    if refreshToken != "" {
+        var err error
+
+        adminId, err = session.RevokeByRefreshToken(refreshToken)
+        if err != nil {
+            fmt.Println("refreshToken 已失效")
+            return
+        }
+    } else {
+        result := map[string]any{}
+        err := model.NewAdmin().
+            Where("name = ?", logoutName).
+            First(&result).
+            Error
+        if err != nil || len(result) < 1 {
+            fmt.Println("账号信息不存在")
+            return
+        }
+
+        adminId = result["id"].(string)
+
+        if _, err := session.RevokeAll(adminId); err != nil {
+            fmt.Println("账号退出失败")
+            return
+        }
     }
 
-    // 当前账号ID
-    refreshAdminid := jwter.GetDataFromTokenClaims(claims, "id")
-
-    // 过期时间
-    exp := jwter.GetFromTokenClaims(claims, "exp")
-    iat := jwter.GetFromTokenClaims(claims, "iat")
-    refreshTokenExpiresIn := exp.(float64) - iat.(float64)
-
-    c.Put(utils.MD5(refreshToken), "no", int64(refreshTokenExpiresIn))
-
     model.NewAdmin().
-        Where("id = ?", refreshAdminid).
+        Where("id = ?", adminId).
         Updates(map[string]any{
             "refresh_time": int(datebin.NowTimestamp()),
             "refresh_ip": "127.0.0.1",
@@ -86,4 +93,3 @@ func PassportLogout() {
 
     fmt.Println("账号退出成功")
 }
-
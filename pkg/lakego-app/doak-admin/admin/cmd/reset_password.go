@@ -72,8 +72,12 @@ func ResetPassword() {
         return
     }
 
-    // 生成密码
-    pass, encrypt := auth_password.MakePassword(password)
+    // 使用当前加密方式生成密码
+    pass, encrypt, err := auth_password.MakePassword(password)
+    if err != nil {
+        fmt.Println("生成密码失败：" + err.Error())
+        return
+    }
 
     err3 := model.NewAdmin().
         Where("name = ?", userName).
@@ -87,6 +91,6 @@ func ResetPassword() {
         return
     }
 
-    fmt.Println("修改密码成功")
+    fmt.Println("修改密码成功，加密方式：" + auth_password.GetHasher().Name())
 }
 
@@ -5,15 +5,16 @@ import (
 
     "github.com/deatil/lakego-doak/lakego/command"
 
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
     "github.com/deatil/lakego-doak-admin/admin/permission"
 )
 
 /**
  * 重设权限
  *
- * > ./main lakego-admin:reset-permission
- * > main.exe lakego-admin:reset-permission
- * > go run main.go lakego-admin:reset-permission
+ * > ./main lakego-admin:reset-permission [--tenant=code]
+ * > main.exe lakego-admin:reset-permission [--tenant=code]
+ * > go run main.go lakego-admin:reset-permission [--tenant=code]
  *
  * @create 2021-9-25
  * @author deatil
@@ -21,7 +22,7 @@ import (
 var ResetPermissionCmd = &command.Command{
     Use: "lakego-admin:reset-permission",
     Short: "lakego-admin reset enforcer'permission.",
-    Example: "{execfile} lakego-admin:reset-permission",
+    Example: "{execfile} lakego-admin:reset-permission [--tenant=code]",
     SilenceUsage: true,
     PreRun: func(cmd *command.Command, args []string) {
 
@@ -31,10 +32,29 @@ var ResetPermissionCmd = &command.Command{
     },
 }
 
+var resetPermissionTenant string
+
+func init() {
+    pf := ResetPermissionCmd.Flags()
+    pf.StringVarP(&resetPermissionTenant, "tenant", "t", "", "租户标识，为空时重设全部租户")
+}
+
 // 重设权限
 func ResetPermission() {
+    tenantIds := make([]string, 0)
+
+    if resetPermissionTenant != "" {
+        info, err := tenant.Find(resetPermissionTenant)
+        if err != nil {
+            fmt.Println("租户不存在或者已被禁用")
+            return
+        }
+
+        tenantIds = append(tenantIds, info.ID)
+    }
+
     // 重设权限
-    res := permission.ResetPermission()
+    res := permission.ResetPermission(tenantIds...)
     if res == false {
         fmt.Println("权限同步失败")
         return
@@ -42,4 +62,3 @@ func ResetPermission() {
 
     fmt.Println("权限同步成功")
 }
-
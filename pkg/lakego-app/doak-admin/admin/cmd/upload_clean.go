@@ -0,0 +1,49 @@
+package cmd
+
+import (
+    "time"
+
+    "github.com/deatil/lakego-doak/lakego/color"
+    "github.com/deatil/lakego-doak/lakego/command"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+    "github.com/deatil/lakego-doak/lakego/facade/upload"
+)
+
+/**
+ * 清除过期未完成的分片上传
+ *
+ * > ./main lakego-admin:upload-clean
+ * > main.exe lakego-admin:upload-clean
+ * > go run main.go lakego-admin:upload-clean
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var UploadCleanCmd = &command.Command{
+    Use: "lakego-admin:upload-clean",
+    Short: "lakego-admin clean expired chunk uploads.",
+    Example: "{execfile} lakego-admin:upload-clean",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+
+    },
+    Run: func(cmd *command.Command, args []string) {
+        count, err := UploadClean()
+        if err != nil {
+            color.Redln("清除分片上传失败：" + err.Error())
+            return
+        }
+
+        color.Greenln("清除分片上传成功，共 %d 个", count)
+    },
+}
+
+// 清除过期未完成的分片上传
+func UploadClean() (int, error) {
+    expire := config.New("admin").GetDuration("Upload.Chunk.Expire")
+    if expire <= 0 {
+        expire = 24 * time.Hour
+    }
+
+    return upload.NewChunk().Clean(expire)
+}
@@ -10,7 +10,6 @@ import (
 
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/collection"
-    "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/facade/config"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
@@ -18,7 +17,9 @@ import (
     "github.com/deatil/lakego-doak-admin/admin/permission"
     "github.com/deatil/lakego-doak-admin/admin/auth/auth"
     "github.com/deatil/lakego-doak-admin/admin/auth/admin"
-    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+    "github.com/deatil/lakego-doak-admin/admin/auth/lockout"
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
     auth_password "github.com/deatil/lakego-doak-admin/admin/password"
     admin_validate "github.com/deatil/lakego-doak-admin/admin/validate/admin"
     admin_repository "github.com/deatil/lakego-doak-admin/admin/repository/admin"
@@ -53,10 +54,10 @@ type Admin struct {
 // @x-lakego {"slug": "lakego-admin.admin.index"}
 func (this *Admin) Index(ctx *router.Context) {
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 模型
-    adminModel := model.NewAdmin().
+    adminModel := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb))
 
     // 排序
@@ -78,7 +79,7 @@ func (this *Admin) Index(ctx *router.Context) {
         searchword = "%" + searchword + "%"
 
         adminModel = adminModel.Where(
-            model.NewDB().
+            model.NewDB(ctx).
                 Where("name LIKE ?", searchword).
                 Or("nickname LIKE ?", searchword).
                 Or("email LIKE ?", searchword),
@@ -120,6 +121,7 @@ func (this *Admin) Index(ctx *router.Context) {
             "id", "name", "nickname",
             "email", "avatar",
             "is_root", "status",
+            "locked_until",
             "last_active", "last_ip",
             "update_time", "update_ip",
             "add_time", "add_ip",
@@ -174,10 +176,10 @@ func (this *Admin) Detail(ctx *router.Context) {
     var info = model.Admin{}
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 模型
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Preload("Groups").
@@ -239,10 +241,10 @@ func (this *Admin) Rules(ctx *router.Context) {
     var info = model.Admin{}
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 模型
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Preload("Groups").
@@ -262,7 +264,7 @@ func (this *Admin) Rules(ctx *router.Context) {
         Pluck("id").
         ToStringArray()
 
-    rules := admin_repository.GetRules(groupids)
+    rules := admin_repository.GetRules(groupids, ctx)
 
     this.SuccessWithData(ctx, "获取成功", router.H{
         "list": rules,
@@ -285,7 +287,8 @@ func (this *Admin) Groups(ctx *router.Context) {
 
     list := make([]map[string]any, 0)
     if adminData.IsSuperAdministrator() {
-        err := model.NewAuthGroup().
+        err := model.NewAuthGroup(ctx).
+            Scopes(scope.WithTenant(ctx)).
             Order("listorder ASC").
             Order("add_time ASC").
             Select([]string{
@@ -349,6 +352,10 @@ func (this *Admin) Create(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := admin_validate.Create(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -362,7 +369,7 @@ func (this *Admin) Create(ctx *router.Context) {
 
     // 模型
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Where("name = ?", post["name"].(string)).
         Or("email = ?", post["email"].(string)).
         First(&result).
@@ -382,7 +389,7 @@ func (this *Admin) Create(ctx *router.Context) {
         AddIp: router.GetRequestIp(ctx),
     }
 
-    err2 := model.NewDB().
+    err2 := model.NewDBWithContext(ctx).
         Create(&insertData).
         Error
     if err2 != nil {
@@ -390,7 +397,7 @@ func (this *Admin) Create(ctx *router.Context) {
         return
     }
 
-    model.NewDB().Create(&model.AuthGroupAccess{
+    model.NewDB(ctx).Create(&model.AuthGroupAccess{
         AdminId: insertData.ID,
         GroupId: post["group_id"].(string),
     })
@@ -430,11 +437,11 @@ func (this *Admin) Update(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -448,6 +455,10 @@ func (this *Admin) Update(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := admin_validate.Update(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -460,11 +471,11 @@ func (this *Admin) Update(ctx *router.Context) {
     }
 
     // 链接db
-    db := model.NewDB()
+    db := model.NewDB(ctx)
 
     // 验证
     result2 := map[string]any{}
-    err2 := model.NewAdmin().
+    err2 := model.NewAdmin(ctx).
         Where(db.Where("id != ?", id).Where("name = ?", post["name"].(string))).
         Or(db.Where("id != ?", id).Where("email = ?", post["email"].(string))).
         First(&result2).
@@ -474,7 +485,7 @@ func (this *Admin) Update(ctx *router.Context) {
         return
     }
 
-    err3 := model.NewAdmin().
+    err3 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Updates(map[string]any{
@@ -522,10 +533,10 @@ func (this *Admin) Delete(ctx *router.Context) {
     result := map[string]any{}
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 模型
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -542,7 +553,7 @@ func (this *Admin) Delete(ctx *router.Context) {
     }
 
     // 删除
-    err2 := model.NewAdmin().
+    err2 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Delete(&model.Admin{
             ID: id,
@@ -582,11 +593,11 @@ func (this *Admin) UpdateAvatar(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -600,13 +611,17 @@ func (this *Admin) UpdateAvatar(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := admin_validate.UpdateAvatar(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
         return
     }
 
-    err3 := model.NewAdmin().
+    err3 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Updates(map[string]any{
@@ -647,11 +662,11 @@ func (this *Admin) UpdatePasssword(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -665,6 +680,10 @@ func (this *Admin) UpdatePasssword(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     password := post["password"].(string)
     if len(password) != 32 {
         this.Error(ctx, "密码格式错误")
@@ -672,9 +691,13 @@ func (this *Admin) UpdatePasssword(ctx *router.Context) {
     }
 
     // 生成密码
-    pass, encrypt := auth_password.MakePassword(password)
+    pass, encrypt, err := auth_password.MakePassword(password)
+    if err != nil {
+        this.Error(ctx, "密码生成失败")
+        return
+    }
 
-    err3 := model.NewAdmin().
+    err3 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Updates(map[string]any{
@@ -687,6 +710,9 @@ func (this *Admin) UpdatePasssword(ctx *router.Context) {
         return
     }
 
+    // 修改密码后退出全部会话
+    session.RevokeAll(id)
+
     this.Success(ctx, "密码修改成功")
 }
 
@@ -715,11 +741,11 @@ func (this *Admin) Enable(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -738,7 +764,7 @@ func (this *Admin) Enable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAdmin().
+    err2 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Updates(map[string]any{
@@ -778,11 +804,11 @@ func (this *Admin) Disable(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -801,7 +827,7 @@ func (this *Admin) Disable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAdmin().
+    err2 := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         Updates(map[string]any{
@@ -813,6 +839,9 @@ func (this *Admin) Disable(ctx *router.Context) {
         return
     }
 
+    // 禁用后退出全部会话
+    session.RevokeAll(id)
+
     this.Success(ctx, "禁用账号成功")
 }
 
@@ -834,48 +863,88 @@ func (this *Admin) Logout(ctx *router.Context) {
         return
     }
 
-    c := facade.Cache
+    // 刷新 token 所属账号
+    jwter := auth.New()
+    refreshAdminid := jwter.GetRefreshTokenData(refreshToken, "id", false)
 
-    if c.Has(utils.MD5(refreshToken)) {
+    nowAdminId, _ := ctx.Get("admin_id")
+    if refreshAdminid == nowAdminId.(string) {
+        this.Error(ctx, "你不能退出你的账号")
+        return
+    }
+
+    // 撤销刷新 token 所在会话
+    _, err := session.RevokeByRefreshToken(refreshToken)
+    if err != nil {
         this.Error(ctx, "refreshToken已失效")
         return
     }
 
-    // jwt
-    aud := auth.GetJwtAud(ctx)
-    jwter := auth.NewWithAud(aud)
+    model.NewAdmin(ctx).
+        Where("id = ?", refreshAdminid).
+        Updates(map[string]any{
+            "refresh_time": int(datebin.NowTimestamp()),
+            "refresh_ip": router.GetRequestIp(ctx),
+        })
+
+    this.Success(ctx, "账号退出成功")
+}
 
-    // 拿取数据
-    claims, claimsErr := jwter.GetRefreshTokenClaims(refreshToken)
-    if claimsErr != nil {
-        this.Error(ctx, "refreshToken 已失效")
+// 账号全部会话退出
+// @Summary 账号全部会话退出
+// @Description 撤销管理员账号的全部登录会话
+// @Tags 管理员
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "管理员ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /admin/{id}/sessions [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.admin.sessions-delete"}
+func (this *Admin) RevokeSessions(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "账号ID不能为空")
         return
     }
 
-    // 当前账号ID
-    refreshAdminid := jwter.GetDataFromTokenClaims(claims, "id")
+    adminId, _ := ctx.Get("admin_id")
+    if id == adminId.(string) {
+        this.Error(ctx, "你不能退出你的账号")
+        return
+    }
 
-    // 过期时间
-    exp := jwter.GetFromTokenClaims(claims, "exp")
-    iat := jwter.GetFromTokenClaims(claims, "iat")
-    refreshTokenExpiresIn := exp.(float64) - iat.(float64)
+    // 授权数据
+    gadb := model.NewAuthGroupAccess(ctx)
 
-    nowAdminId, _ := ctx.Get("admin_id")
-    if refreshAdminid == nowAdminId.(string) {
-        this.Error(ctx, "你不能退出你的账号")
+    // 查询
+    result := map[string]any{}
+    err := model.NewAdmin(ctx).
+        Scopes(scope.AdminWithAccess(ctx, gadb)).
+        Where("id = ?", id).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        this.Error(ctx, "账号信息不存在")
         return
     }
 
-    c.Put(utils.MD5(refreshToken), "no", int64(refreshTokenExpiresIn))
+    count, err := session.RevokeAll(id)
+    if err != nil {
+        this.Error(ctx, "账号退出失败")
+        return
+    }
 
-    model.NewAdmin().
-        Where("id = ?", refreshAdminid).
+    model.NewAdmin(ctx).
+        Where("id = ?", id).
         Updates(map[string]any{
             "refresh_time": int(datebin.NowTimestamp()),
             "refresh_ip": router.GetRequestIp(ctx),
         })
 
-    this.Success(ctx, "账号退出成功")
+    this.SuccessWithData(ctx, "账号退出成功", router.H{
+        "count": count,
+    })
 }
 
 // 账号授权
@@ -904,11 +973,11 @@ func (this *Admin) Access(ctx *router.Context) {
     }
 
     // 授权数据
-    gadb := model.NewAuthGroupAccess()
+    gadb := model.NewAuthGroupAccess(ctx)
 
     // 查询
     result := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Scopes(scope.AdminWithAccess(ctx, gadb)).
         Where("id = ?", id).
         First(&result).
@@ -918,9 +987,24 @@ func (this *Admin) Access(ctx *router.Context) {
         return
     }
 
-    // 模型
-    err2 := model.NewAuthGroupAccess().
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
+    // 当前租户的分组
+    var tenantGroupIds []string
+    model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
+        Pluck("id", &tenantGroupIds)
+
+    // 只替换当前租户的分组
+    err2 := model.NewAuthGroupAccess(ctx).
         Where("admin_id = ?", id).
+        Where("group_id in ?", tenantGroupIds).
         Delete(&model.AuthGroupAccess{}).
         Error
     if err2 != nil {
@@ -928,10 +1012,6 @@ func (this *Admin) Access(ctx *router.Context) {
         return
     }
 
-    // 接收数据
-    post := make(map[string]any)
-    this.ShouldBindJSON(ctx, &post)
-
     access := post["access"].(string)
     if access != "" {
         adminInfo, _ := ctx.Get("admin")
@@ -955,6 +1035,11 @@ func (this *Admin) Access(ctx *router.Context) {
             intersectAccess = newAccessIds
         }
 
+        intersectAccess = collection.
+            Collect(intersectAccess).
+            Intersect(tenantGroupIds).
+            ToStringArray()
+
         insertData := make([]model.AuthGroupAccess, 0)
         for _, value := range intersectAccess {
             if value == "" {
@@ -967,12 +1052,93 @@ func (this *Admin) Access(ctx *router.Context) {
             })
         }
 
-        model.NewDB().Create(&insertData)
+        model.NewDB(ctx).Create(&insertData)
     }
 
     this.Success(ctx, "账号授权分组成功")
 }
 
+// 锁定账号列表
+// @Summary 锁定账号列表
+// @Description 登录失败次数过多被锁定的管理员账号列表
+// @Tags 管理员
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /admin/lockouts [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.admin.lockouts"}
+func (this *Admin) Lockouts(ctx *router.Context) {
+    // 授权数据
+    gadb := model.NewAuthGroupAccess(ctx)
+
+    list := make([]map[string]any, 0)
+    err := model.NewAdmin(ctx).
+        Scopes(scope.AdminWithAccess(ctx, gadb)).
+        Where("locked_until > ?", datebin.NowTimestamp()).
+        Order("locked_until DESC").
+        Select([]string{
+            "id", "name", "nickname",
+            "email", "status",
+            "login_failures", "locked_until",
+            "last_active", "last_ip",
+        }).
+        Find(&list).
+        Error
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
+
+// 账号解锁
+// @Summary 账号解锁
+// @Description 解除管理员账号登录锁定
+// @Tags 管理员
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "管理员ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /admin/{id}/unlock [patch]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.admin.unlock"}
+func (this *Admin) Unlock(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "账号ID不能为空")
+        return
+    }
+
+    // 授权数据
+    gadb := model.NewAuthGroupAccess(ctx)
+
+    // 查询
+    result := map[string]any{}
+    err := model.NewAdmin(ctx).
+        Scopes(scope.AdminWithAccess(ctx, gadb)).
+        Where("id = ?", id).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        this.Error(ctx, "账号信息不存在")
+        return
+    }
+
+    adminId, _ := ctx.Get("admin_id")
+
+    err = lockout.Unlock(id, result["name"].(string), adminId.(string), router.GetRequestIp(ctx))
+    if err != nil {
+        this.Error(ctx, "账号解锁失败")
+        return
+    }
+
+    this.Success(ctx, "账号解锁成功")
+}
+
 // 账号权限同步
 // @Summary 账号权限同步
 // @Description 管理员账号权限同步
@@ -984,8 +1150,14 @@ func (this *Admin) Access(ctx *router.Context) {
 // @Security Bearer
 // @x-lakego {"slug": "lakego-admin.admin.reset-permission", "sort": "200"}
 func (this *Admin) ResetPermission(ctx *router.Context) {
+    // 指定租户时只重设当前租户
+    tenantIds := make([]string, 0)
+    if tenantId := tenant.Current(ctx); tenantId != "" {
+        tenantIds = append(tenantIds, tenantId)
+    }
+
     // 重设权限
-    res := permission.ResetPermission()
+    res := permission.ResetPermission(tenantIds...)
     if res == false {
         this.Error(ctx, "权限同步失败")
         return
@@ -1,6 +1,8 @@
 package controller
 
 import (
+    "gorm.io/gorm"
+
     "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-events/events"
     "github.com/deatil/go-datebin/datebin"
@@ -12,6 +14,7 @@ import (
     "github.com/deatil/lakego-doak/lakego/facade/storage"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/model/scope"
     "github.com/deatil/lakego-doak-admin/admin/support/url"
     "github.com/deatil/lakego-doak-admin/admin/support/utils"
 )
@@ -45,7 +48,8 @@ type Attachment struct {
 // @x-lakego {"slug": "lakego-admin.attachment.index","sort":"151"}
 func (this *Attachment) Index(ctx *router.Context) {
     // 附件模型
-    attachModel := model.NewAttachment()
+    attachModel := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx))
 
     // 排序
     order := ctx.DefaultQuery("order", "add_time__DESC")
@@ -66,7 +70,7 @@ func (this *Attachment) Index(ctx *router.Context) {
         searchword = "%" + searchword + "%"
 
         attachModel = attachModel.Where(
-            model.NewDB().
+            model.NewDB(ctx).
                 Where("name LIKE ?", searchword).
                 Or("extension LIKE ?", searchword).
                 Or("disk LIKE ?", searchword),
@@ -155,7 +159,8 @@ func (this *Attachment) Detail(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", newId).
         First(&result).
         Error
@@ -195,7 +200,8 @@ func (this *Attachment) Delete(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -204,8 +210,26 @@ func (this *Attachment) Delete(ctx *router.Context) {
         return
     }
 
+    // 多次引用时只减少引用计数
+    refCount := goch.ToInt(result["ref_count"])
+    if refCount > 1 {
+        err2 := model.NewAttachment(ctx).
+            Scopes(scope.AttachmentWithDataScope(ctx)).
+            Where("id = ?", id).
+            Update("ref_count", gorm.Expr("ref_count - ?", 1)).
+            Error
+        if err2 != nil {
+            this.Error(ctx, "文件删除失败")
+            return
+        }
+
+        this.Success(ctx, "文件删除成功")
+        return
+    }
+
     // 附件模型
-    err2 := model.NewAttachment().
+    err2 := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Delete(&model.Attachment{
             ID: id,
         }).
@@ -244,7 +268,8 @@ func (this *Attachment) Enable(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -258,7 +283,8 @@ func (this *Attachment) Enable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAttachment().
+    err2 := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 1,
@@ -294,7 +320,8 @@ func (this *Attachment) Disable(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -308,7 +335,8 @@ func (this *Attachment) Disable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAttachment().
+    err2 := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 0,
@@ -344,7 +372,8 @@ func (this *Attachment) DownloadCode(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
+        Scopes(scope.AttachmentWithDataScope(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -390,7 +419,7 @@ func (this *Attachment) Download(ctx *router.Context) {
     result := map[string]any{}
 
     // 附件模型
-    err := model.NewAttachment().
+    err := model.NewAttachment(ctx).
         Where("id = ?", fileId).
         First(&result).
         Error
@@ -11,6 +11,9 @@ import (
     "github.com/deatil/lakego-doak/lakego/collection"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/model/scope"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    "github.com/deatil/lakego-doak-admin/admin/auth/datascope"
     authGroupValidate "github.com/deatil/lakego-doak-admin/admin/validate/authgroup"
     authGroupRepository "github.com/deatil/lakego-doak-admin/admin/repository/authgroup"
 )
@@ -44,7 +47,8 @@ type AuthGroup struct {
 // @x-lakego {"slug": "lakego-admin.auth-group.index"}
 func (this *AuthGroup) Index(ctx *router.Context) {
     // 模型
-    groupModel := model.NewAuthGroup()
+    groupModel := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx))
 
     // 排序
     order := ctx.DefaultQuery("order", "add_time__DESC")
@@ -131,7 +135,8 @@ func (this *AuthGroup) Index(ctx *router.Context) {
 func (this *AuthGroup) IndexTree(ctx *router.Context) {
     list := make([]map[string]any, 0)
 
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Order("listorder ASC").
         Order("add_time ASC").
         Find(&list).
@@ -175,7 +180,7 @@ func (this *AuthGroup) IndexChildren(ctx *router.Context) {
     if typ == "list" {
         data = authGroupRepository.GetChildren(id)
     } else {
-        data = authGroupRepository.GetChildrenIds(id)
+        data = authGroupRepository.GetChildrenIds(id, ctx)
     }
 
     this.SuccessWithData(ctx, "获取成功", router.H{
@@ -204,7 +209,8 @@ func (this *AuthGroup) Detail(ctx *router.Context) {
     var info model.AuthGroup
 
     // 模型
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         Preload("RuleAccesses").
         First(&info).
@@ -236,6 +242,8 @@ func (this *AuthGroup) Detail(ctx *router.Context) {
             "parentid",
             "title",
             "description",
+            "data_scope",
+            "data_scope_groups",
             "listorder",
             "status",
             "update_time",
@@ -258,6 +266,8 @@ func (this *AuthGroup) Detail(ctx *router.Context) {
 // @Param parentid    formData string true "父级ID"
 // @Param title       formData string true "名称"
 // @Param description formData string false "描述"
+// @Param data_scope  formData string false "数据范围，可选值：all | self | department | department-and-children | custom"
+// @Param data_scope_groups formData string false "自定义数据范围的分组ID，半角逗号分隔"
 // @Param listorder   formData string true "排序"
 // @Param status      formData string true "状态"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
@@ -269,6 +279,10 @@ func (this *AuthGroup) Create(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := authGroupValidate.Create(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -284,17 +298,26 @@ func (this *AuthGroup) Create(ctx *router.Context) {
         status = 0
     }
 
+    dataScope, dataScopeGroups, dataScopeErr := this.formatDataScope(ctx, post)
+    if dataScopeErr != "" {
+        this.Error(ctx, dataScopeErr)
+        return
+    }
+
     insertData := model.AuthGroup{
+        TenantId: tenant.Current(ctx),
         Parentid: post["parentid"].(string),
         Title: post["title"].(string),
         Description: post["description"].(string),
+        DataScope: dataScope,
+        DataScopeGroups: dataScopeGroups,
         Listorder: listorder,
         Status: status,
         AddTime: int(datebin.NowTimestamp()),
         AddIp: router.GetRequestIp(ctx),
     }
 
-    err2 := model.NewDB().
+    err2 := model.NewDBWithContext(ctx).
         Create(&insertData).
         Error
     if err2 != nil {
@@ -317,6 +340,8 @@ func (this *AuthGroup) Create(ctx *router.Context) {
 // @Param parentid    formData string true "父级ID"
 // @Param title       formData string true "名称"
 // @Param description formData string false "描述"
+// @Param data_scope  formData string false "数据范围，可选值：all | self | department | department-and-children | custom"
+// @Param data_scope_groups formData string false "自定义数据范围的分组ID，半角逗号分隔"
 // @Param listorder   formData string true "排序"
 // @Param status      formData string true "状态"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
@@ -332,7 +357,8 @@ func (this *AuthGroup) Update(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -345,6 +371,10 @@ func (this *AuthGroup) Update(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := authGroupValidate.Update(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -360,12 +390,21 @@ func (this *AuthGroup) Update(ctx *router.Context) {
         status = 0
     }
 
-    err3 := model.NewAuthGroup().
+    dataScope, dataScopeGroups, dataScopeErr := this.formatDataScope(ctx, post)
+    if dataScopeErr != "" {
+        this.Error(ctx, dataScopeErr)
+        return
+    }
+
+    err3 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "parentid": post["parentid"].(string),
             "title": post["title"].(string),
             "description": post["description"].(string),
+            "data_scope": dataScope,
+            "data_scope_groups": dataScopeGroups,
             "listorder": listorder,
             "status": status,
             "update_time": int(datebin.NowTimestamp()),
@@ -400,7 +439,8 @@ func (this *AuthGroup) Delete(ctx *router.Context) {
 
     // 详情
     var info model.AuthGroup
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&info).
         Error
@@ -411,7 +451,8 @@ func (this *AuthGroup) Delete(ctx *router.Context) {
 
     // 子级
     var total int64
-    err2 := model.NewAuthGroup().
+    err2 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("parentid = ?", id).
         Count(&total).
         Error
@@ -421,7 +462,8 @@ func (this *AuthGroup) Delete(ctx *router.Context) {
     }
 
     // 删除
-    err3 := model.NewAuthGroup().
+    err3 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Delete(&model.AuthGroup{
             ID: id,
         }).
@@ -455,7 +497,8 @@ func (this *AuthGroup) Listorder(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -468,6 +511,10 @@ func (this *AuthGroup) Listorder(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 排序
     listorder := 0
     if post["listorder"] != "" {
@@ -476,7 +523,8 @@ func (this *AuthGroup) Listorder(ctx *router.Context) {
         listorder = 100
     }
 
-    err2 := model.NewAuthGroup().
+    err2 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "listorder": listorder,
@@ -510,7 +558,8 @@ func (this *AuthGroup) Enable(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -528,7 +577,8 @@ func (this *AuthGroup) Enable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAuthGroup().
+    err2 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 1,
@@ -562,7 +612,8 @@ func (this *AuthGroup) Disable(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -580,7 +631,8 @@ func (this *AuthGroup) Disable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAuthGroup().
+    err2 := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 0,
@@ -615,7 +667,8 @@ func (this *AuthGroup) Access(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
         Where("id = ?", id).
         First(&result).
         Error
@@ -624,8 +677,16 @@ func (this *AuthGroup) Access(ctx *router.Context) {
         return
     }
 
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 模型
-    err2 := model.NewAuthRuleAccess().
+    err2 := model.NewAuthRuleAccess(ctx).
         Where("group_id = ?", id).
         Delete(&model.AuthRuleAccess{}).
         Error
@@ -634,10 +695,6 @@ func (this *AuthGroup) Access(ctx *router.Context) {
         return
     }
 
-    // 接收数据
-    post := make(map[string]any)
-    this.ShouldBindJSON(ctx, &post)
-
     // 添加权限
     access := post["access"].(string)
     if access != "" {
@@ -660,9 +717,37 @@ func (this *AuthGroup) Access(ctx *router.Context) {
             })
         }
 
-        model.NewDB().Create(&insertData)
+        model.NewDB(ctx).Create(&insertData)
     }
 
     this.Success(ctx, "授权成功")
 }
 
+// 格式化数据范围，自定义分组只保留当前租户的分组
+func (this *AuthGroup) formatDataScope(ctx *router.Context, post map[string]any) (string, string, string) {
+    dataScope := goch.ToString(post["data_scope"])
+    if dataScope == "" {
+        dataScope = datascope.All
+    }
+
+    if !datascope.IsValid(dataScope) {
+        return "", "", "数据范围错误"
+    }
+
+    if dataScope != datascope.Custom {
+        return dataScope, "", ""
+    }
+
+    groupIds := datascope.SplitGroups(goch.ToString(post["data_scope_groups"]))
+    if len(groupIds) == 0 {
+        return "", "", "自定义数据范围的分组不能为空"
+    }
+
+    var ids []string
+    model.NewAuthGroup(ctx).
+        Scopes(scope.WithTenant(ctx)).
+        Where("id in ?", groupIds).
+        Pluck("id", &ids)
+
+    return dataScope, strings.Join(ids, ","), ""
+}
@@ -10,6 +10,7 @@ import (
     "github.com/deatil/lakego-doak/lakego/router"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/fieldpolicy"
     authRuleValidate "github.com/deatil/lakego-doak-admin/admin/validate/authrule"
     authRuleRepository "github.com/deatil/lakego-doak-admin/admin/repository/authrule"
 )
@@ -44,7 +45,7 @@ type AuthRule struct {
 // @x-lakego {"slug": "lakego-admin.auth-rule.index"}
 func (this *AuthRule) Index(ctx *router.Context) {
     // 模型
-    ruleModel := model.NewAuthRule()
+    ruleModel := model.NewAuthRule(ctx)
 
     // 排序
     order := ctx.DefaultQuery("order", "add_time__DESC")
@@ -141,7 +142,7 @@ func (this *AuthRule) Index(ctx *router.Context) {
 func (this *AuthRule) IndexTree(ctx *router.Context) {
     list := make([]map[string]any, 0)
 
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Order("listorder ASC").
         Order("add_time ASC").
         Find(&list).
@@ -185,7 +186,7 @@ func (this *AuthRule) IndexChildren(ctx *router.Context) {
     if typ == "list" {
         data = authRuleRepository.GetChildren(id)
     } else {
-        data = authRuleRepository.GetChildrenIds(id)
+        data = authRuleRepository.GetChildrenIds(id, ctx)
     }
 
     this.SuccessWithData(ctx, "获取成功", router.H{
@@ -214,7 +215,7 @@ func (this *AuthRule) Detail(ctx *router.Context) {
     var info model.AuthRule
 
     // 模型
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&info).
         Error
@@ -241,6 +242,7 @@ func (this *AuthRule) Detail(ctx *router.Context) {
 // @Param method      formData string true "请求方式"
 // @Param slug        formData string true "别名 Slug"
 // @Param description formData string false "描述"
+// @Param field_policy formData string false "字段策略，JSON 格式，字段对应 hide | mask | readonly，作用于同一资源的全部接口"
 // @Param listorder   formData string true "排序"
 // @Param status      formData string true "状态"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
@@ -252,6 +254,10 @@ func (this *AuthRule) Create(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := authRuleValidate.Create(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -275,6 +281,12 @@ func (this *AuthRule) Create(ctx *router.Context) {
         description = post["description"].(string)
     }
 
+    fieldPolicy, fieldPolicyErr := fieldpolicy.Format(post["field_policy"])
+    if fieldPolicyErr != nil {
+        this.Error(ctx, "字段策略格式错误")
+        return
+    }
+
     insertData := model.AuthRule{
         Parentid: post["parentid"].(string),
         Title: post["title"].(string),
@@ -282,13 +294,14 @@ func (this *AuthRule) Create(ctx *router.Context) {
         Method: strings.ToUpper(post["method"].(string)),
         Slug: post["slug"].(string),
         Description: description,
+        FieldPolicy: fieldPolicy,
         Listorder: listorder,
         Status: status,
         AddTime: int(datebin.NowTimestamp()),
         AddIp: router.GetRequestIp(ctx),
     }
 
-    err2 := model.NewDB().
+    err2 := model.NewDBWithContext(ctx).
         Create(&insertData).
         Error
     if err2 != nil {
@@ -314,6 +327,7 @@ func (this *AuthRule) Create(ctx *router.Context) {
 // @Param method      formData string true "请求方式"
 // @Param slug        formData string true "别名 Slug"
 // @Param description formData string false "描述"
+// @Param field_policy formData string false "字段策略，JSON 格式，字段对应 hide | mask | readonly，作用于同一资源的全部接口"
 // @Param listorder   formData string true "排序"
 // @Param status      formData string true "状态"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
@@ -329,7 +343,7 @@ func (this *AuthRule) Update(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&result).
         Error
@@ -342,6 +356,10 @@ func (this *AuthRule) Update(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     validateErr := authRuleValidate.Update(post)
     if validateErr != "" {
         this.Error(ctx, validateErr)
@@ -360,7 +378,13 @@ func (this *AuthRule) Update(ctx *router.Context) {
         status = 1
     }
 
-    err3 := model.NewAuthRule().
+    fieldPolicy, fieldPolicyErr := fieldpolicy.Format(post["field_policy"])
+    if fieldPolicyErr != nil {
+        this.Error(ctx, "字段策略格式错误")
+        return
+    }
+
+    err3 := model.NewAuthRule(ctx).
         Where("id = ?", id).
         Updates(map[string]any{
             "parentid": post["parentid"].(string),
@@ -369,6 +393,7 @@ func (this *AuthRule) Update(ctx *router.Context) {
             "method": post["method"].(string),
             "slug": post["slug"].(string),
             "description": post["description"].(string),
+            "field_policy": fieldPolicy,
             "listorder": listorder,
             "status": status,
             "update_time": int(datebin.NowTimestamp()),
@@ -403,7 +428,7 @@ func (this *AuthRule) Delete(ctx *router.Context) {
 
     // 详情
     var info model.AuthRule
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&info).
         Error
@@ -414,7 +439,7 @@ func (this *AuthRule) Delete(ctx *router.Context) {
 
     // 子级
     var total int64
-    err2 := model.NewAuthRule().
+    err2 := model.NewAuthRule(ctx).
         Where("parentid = ?", id).
         Count(&total).
         Error
@@ -424,7 +449,7 @@ func (this *AuthRule) Delete(ctx *router.Context) {
     }
 
     // 删除
-    err3 := model.NewAuthRule().
+    err3 := model.NewAuthRule(ctx).
         Delete(&model.AuthRule{
             ID: id,
         }).
@@ -458,7 +483,7 @@ func (this *AuthRule) Listorder(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&result).
         Error
@@ -471,6 +496,10 @@ func (this *AuthRule) Listorder(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 排序
     listorder := 0
     if post["listorder"] != "" {
@@ -479,7 +508,7 @@ func (this *AuthRule) Listorder(ctx *router.Context) {
         listorder = 100
     }
 
-    err2 := model.NewAuthRule().
+    err2 := model.NewAuthRule(ctx).
         Where("id = ?", id).
         Updates(map[string]any{
             "listorder": listorder,
@@ -513,7 +542,7 @@ func (this *AuthRule) Enable(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&result).
         Error
@@ -531,7 +560,7 @@ func (this *AuthRule) Enable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAuthRule().
+    err2 := model.NewAuthRule(ctx).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 1,
@@ -565,7 +594,7 @@ func (this *AuthRule) Disable(ctx *router.Context) {
 
     // 查询
     result := map[string]any{}
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx).
         Where("id = ?", id).
         First(&result).
         Error
@@ -583,7 +612,7 @@ func (this *AuthRule) Disable(ctx *router.Context) {
         return
     }
 
-    err2 := model.NewAuthRule().
+    err2 := model.NewAuthRule(ctx).
         Where("id = ?", id).
         Updates(map[string]any{
             "status": 0,
@@ -623,7 +652,7 @@ func (this *AuthRule) Clear(ctx *router.Context) {
     for _, id := range newIds {
         // 详情
         var info model.AuthRule
-        err := model.NewAuthRule().
+        err := model.NewAuthRule(ctx).
             Where("id = ?", id).
             First(&info).
             Error
@@ -633,7 +662,7 @@ func (this *AuthRule) Clear(ctx *router.Context) {
 
         // 子级
         var total int64
-        err2 := model.NewAuthRule().
+        err2 := model.NewAuthRule(ctx).
             Where("parentid = ?", id).
             Count(&total).
             Error
@@ -642,7 +671,7 @@ func (this *AuthRule) Clear(ctx *router.Context) {
         }
 
         // 删除
-        err3 := model.NewAuthRule().
+        err3 := model.NewAuthRule(ctx).
             Delete(&model.AuthRule{
                 ID: id,
             }).
@@ -7,6 +7,7 @@ import (
 
     "github.com/deatil/lakego-doak/lakego/router"
 
+    "github.com/deatil/lakego-doak-admin/admin/auth/fieldpolicy"
     "github.com/deatil/lakego-doak-admin/admin/support/controller"
 )
 
@@ -68,3 +69,12 @@ func (this *Base) FormatOrderBy(order string, def ...string) []string {
     return orders
 }
 
+// 检测提交的字段是否允许修改，不允许时输出错误
+func (this *Base) CheckFieldPolicy(ctx *router.Context, data map[string]any) bool {
+    if field := fieldpolicy.CheckWrite(ctx, data); field != "" {
+        this.Error(ctx, "字段[" + field + "]不允许修改")
+        return false
+    }
+
+    return true
+}
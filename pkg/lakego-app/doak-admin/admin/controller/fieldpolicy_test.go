@@ -0,0 +1,170 @@
+package controller
+
+import (
+    "bytes"
+    "testing"
+    "reflect"
+    "net/http"
+    "encoding/json"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/datascope"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T, models ...any) {
+    if err := model.NewDB().AutoMigrate(models...); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(models...)
+    })
+}
+
+func createRule(t *testing.T, method string, url string, policy string) {
+    err := model.NewDB().Create(&model.AuthRule{
+        Parentid:    "0",
+        Title:       method + " " + url,
+        Url:         url,
+        Method:      method,
+        Slug:        method + ":" + url,
+        FieldPolicy: policy,
+        Status:      1,
+    }).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+// 请求接口，返回响应信息
+func request(t *testing.T, method string, route string, path string, adminData *admin.Admin, handler router.HandlerFunc, data map[string]any) string {
+    engine := gin.New()
+    engine.Handle(method, "/admin-api" + route, func(ctx *router.Context) {
+        ctx.Set("admin_id", adminData.GetId())
+        ctx.Set("admin", adminData)
+        ctx.Set("data_scope", &datascope.DataScope{All: true})
+    }, handler)
+
+    body, _ := json.Marshal(data)
+
+    w := httptest.NewRecorder()
+    engine.ServeHTTP(w, httptest.NewRequest(method, "/admin-api" + path, bytes.NewReader(body)))
+
+    var res struct {
+        Message string `json:"message"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+        t.Fatal(err)
+    }
+
+    return res.Message
+}
+
+func Test_FieldPolicy(t *testing.T) {
+    eq := assertT(t)
+
+    gin.SetMode(gin.TestMode)
+
+    migrate(t, &model.AuthRule{})
+
+    createRule(t, "GET", "/admin/{id}", `{"email": "hide", "access": "readonly"}`)
+    createRule(t, "PATCH", "/profile/avatar", `{"avatar": "readonly"}`)
+    createRule(t, "PATCH", "/profile/password", `{"newpassword": "readonly"}`)
+
+    user := admin.New().WithId("admin-1")
+
+    adminCtl := &Admin{}
+    profileCtl := &Profile{}
+
+    eq(request(t, http.MethodPost, "/admin", "/admin", user, adminCtl.Create, map[string]any{
+        "name":  "lakego",
+        "email": "lakego@example.com",
+    }), "字段[email]不允许修改", "Admin.Create")
+
+    eq(request(t, http.MethodPatch, "/profile/avatar", "/profile/avatar", user, profileCtl.UpdateAvatar, map[string]any{
+        "avatar": "attach-1",
+    }), "字段[avatar]不允许修改", "Profile.UpdateAvatar")
+
+    eq(request(t, http.MethodPatch, "/profile/password", "/profile/password", user, profileCtl.UpdatePasssword, map[string]any{
+        "oldpassword":         "old",
+        "newpassword":         "new",
+        "newpassword_confirm": "new",
+    }), "字段[newpassword]不允许修改", "Profile.UpdatePasssword")
+}
+
+func Test_FieldPolicy_Access(t *testing.T) {
+    eq := assertT(t)
+
+    gin.SetMode(gin.TestMode)
+
+    migrate(t, &model.Admin{}, &model.AuthRule{}, &model.AuthGroup{}, &model.AuthGroupAccess{})
+
+    createRule(t, "GET", "/admin/{id}", `{"access": "readonly"}`)
+
+    target := &model.Admin{
+        Name:  "target",
+        Email: "target@example.com",
+    }
+    if err := model.NewDB().Create(target).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    parent := &model.AuthGroup{
+        Parentid: "0",
+        Title:    "parent",
+        Status:   1,
+    }
+    if err := model.NewDB().Create(parent).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    group := &model.AuthGroup{
+        Parentid: parent.ID,
+        Title:    "group",
+        Status:   1,
+    }
+    if err := model.NewDB().Create(group).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    model.NewDB().Create(&model.AuthGroupAccess{
+        AdminId: target.ID,
+        GroupId: group.ID,
+    })
+
+    // 上级分组的账号
+    user := admin.New().
+        WithId("admin-1").
+        WithData(map[string]any{
+            "Groups": []any{
+                map[string]any{"id": parent.ID},
+            },
+        })
+
+    adminCtl := &Admin{}
+
+    eq(request(t, http.MethodPatch, "/admin/:id/access", "/admin/" + target.ID + "/access", user, adminCtl.Access, map[string]any{
+        "access": "",
+    }), "字段[access]不允许修改", "Admin.Access")
+
+    // 拒绝时不删除已有授权
+    var count int64
+    model.NewDB().Model(&model.AuthGroupAccess{}).
+        Where("admin_id = ?", target.ID).
+        Count(&count)
+    eq(count, int64(1), "Admin.Access keep access")
+}
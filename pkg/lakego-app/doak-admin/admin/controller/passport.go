@@ -1,6 +1,9 @@
 package controller
 
 import (
+    "strconv"
+
+    "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-events/events"
     "github.com/deatil/go-datebin/datebin"
 
@@ -8,8 +11,11 @@ import (
     "github.com/deatil/lakego-doak/lakego/facade"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/apitoken"
     "github.com/deatil/lakego-doak-admin/admin/auth/auth"
-    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+    "github.com/deatil/lakego-doak-admin/admin/auth/lockout"
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
+    "github.com/deatil/lakego-doak-admin/admin/auth/twofactor"
     "github.com/deatil/lakego-doak-admin/admin/support/http/code"
     auth_password "github.com/deatil/lakego-doak-admin/admin/password"
     passport_validate "github.com/deatil/lakego-doak-admin/admin/validate/passport"
@@ -60,6 +66,7 @@ func (this *Passport) Captcha(ctx *router.Context) {
 // @Param name formData string true "账号"
 // @Param password formData string true "密码"
 // @Param captcha formData string true "验证码"
+// @Param provider formData string false "登录方式，默认本地账号"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
 // @Router /passport/login [post]
 // @x-lakego {"slug": "lakego-admin.passport.login"}
@@ -80,6 +87,16 @@ func (this *Passport) Login(ctx *router.Context) {
     password := post["password"].(string)
     captchaCode := post["captcha"].(string)
 
+    // 登录限流
+    ip := router.GetRequestIp(ctx)
+    if allow, wait := lockout.Check(ip, name); !allow {
+        events.DoAction("admin.passport-login.throttled", name)
+
+        this.SetHeader(ctx, "Retry-After", strconv.FormatInt(wait, 10))
+        this.Error(ctx, "登录尝试过于频繁，请" + strconv.FormatInt(wait, 10) + "秒后重试", code.LoginError)
+        return
+    }
+
     // 验证码检测
     key := facade.Config("auth").GetString("passport.header-captcha-key")
     captchaId := ctx.GetHeader(key)
@@ -90,156 +107,311 @@ func (this *Passport) Login(ctx *router.Context) {
         return
     }
 
+    // 外部账号登录
+    provider, _ := post["provider"].(string)
+    if provider != "" && provider != "local" {
+        this.loginWithProvider(ctx, provider, name, password)
+        return
+    }
+
     // 用户信息
     admin := map[string]any{}
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Where(&model.Admin{Name: name}).
         First(&admin).
         Error
     if err != nil {
+        lockout.Failed(ip, name, "")
+
         events.DoAction("admin.passport-login.name-error", name)
 
         this.Error(ctx, "账号或者密码错误", code.LoginError)
         return
     }
 
+    // 账号锁定
+    if lockedUntil := lockout.LockedUntil(admin["locked_until"]); lockedUntil > 0 {
+        events.DoAction("admin.passport-login.locked", name)
+
+        lockedTime := datebin.FromTimestamp(lockedUntil).ToDatetimeString()
+        this.Error(ctx, "账号已被锁定，请于" + lockedTime + "后重试", code.LoginError)
+        return
+    }
+
     // 验证密码
     checkStatus := auth_password.CheckPassword(admin["password"].(string), password, admin["password_salt"].(string))
     if !checkStatus {
+        lockout.Failed(ip, name, admin["id"].(string))
+
         events.DoAction("admin.passport-login.password-error", name)
 
         this.Error(ctx, "账号或者密码错误", code.LoginError)
         return
     }
 
-    // 生成 token
-    aud := auth.GetJwtAud(ctx)
-    jwter := auth.NewWithAud(aud)
+    lockout.Succeeded(name, admin["id"].(string))
+
+    // 旧的密码使用当前加密方式重新生成
+    if auth_password.NeedsRehash(admin["password"].(string)) {
+        pass, salt, err := auth_password.MakePassword(password)
+        if err == nil {
+            err = model.NewAdmin(ctx).
+                Where("id = ?", admin["id"]).
+                Updates(map[string]any{
+                    "password": pass,
+                    "password_salt": salt,
+                }).
+                Error
+        }
+
+        // 重新生成失败不影响登录，下次登录时重试
+        if err != nil {
+            facade.Logger.Error("[passport] rehash password error: " + err.Error())
+        }
+    }
+
+    this.loginAdmin(
+        ctx,
+        admin["id"].(string),
+        name,
+        goch.ToInt(admin["totp_status"]),
+        goch.ToInt(admin["is_root"]),
+    )
+}
+
+// 外部登录方式
+// @Summary 外部登录方式
+// @Description 已开启的外部登录方式列表
+// @Tags 登陆相关
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /passport/providers [get]
+// @x-lakego {"slug": "lakego-admin.passport.providers"}
+func (this *Passport) Providers(ctx *router.Context) {
+    list := make([]router.H, 0)
+    for _, provider := range auth.Providers() {
+        list = append(list, router.H{
+            "name": provider.Name,
+            "title": provider.Title,
+            "driver": provider.Driver,
+            "redirect": provider.Redirect(),
+        })
+    }
 
-    // 账号ID
-    adminid := admin["id"].(string)
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
 
-    // token 数据
-    tokenData := map[string]string{
-        "id": adminid,
+// 外部登录地址
+// @Summary 外部登录地址
+// @Description 获取跳转到外部页面登录的地址，比如 OIDC
+// @Tags 登陆相关
+// @Accept application/json
+// @Produce application/json
+// @Param name path string true "登录方式"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /passport/provider/{name}/redirect [get]
+// @x-lakego {"slug": "lakego-admin.passport.provider-redirect"}
+func (this *Passport) ProviderRedirect(ctx *router.Context) {
+    provider, err := auth.GetProvider(ctx.Param("name"))
+    if err != nil || !provider.Redirect() {
+        this.Error(ctx, "登录方式不存在", code.LoginError)
+        return
     }
 
-    // 授权 token
-    accessToken, err := jwter.MakeAccessToken(tokenData)
+    state, nonce, err := auth.MakeState(provider.Name)
     if err != nil {
-        events.DoAction("admin.passport-login.make-accesstoken-fail", err.Error())
-
-        this.Error(ctx, "授权token生成失败", code.LoginError)
+        this.Error(ctx, "获取登录地址失败", code.LoginError)
         return
     }
 
-    // 刷新 token
-    refreshToken, err := jwter.MakeRefreshToken(tokenData)
+    authUrl, err := provider.AuthCodeURL(ctx.Request.Context(), state, nonce)
     if err != nil {
-        events.DoAction("admin.passport-login.make-refreshtoken-fail", err.Error())
+        events.DoAction("admin.passport-provider.error", err.Error())
 
-        this.Error(ctx, "刷新token生成失败", code.LoginError)
+        this.Error(ctx, "获取登录地址失败", code.LoginError)
         return
     }
 
-    // 授权 token 过期时间
-    expiresIn := jwter.GetAccessExpiresIn()
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "url": authUrl,
+        "state": state,
+        "expires_in": auth.StateExpiresIn,
+    })
+}
 
-    // 更新登录时间
-    model.NewAdmin().
-        Where("id = ?", adminid).
-        Updates(map[string]any{
-            "last_active": int(datebin.NowTimestamp()),
-            "last_ip": router.GetRequestIp(ctx),
-        })
+// 外部登录回调
+// @Summary 外部登录回调
+// @Description 使用外部页面返回的授权码登录
+// @Tags 登陆相关
+// @Accept application/json
+// @Produce application/json
+// @Param name  path     string true "登录方式"
+// @Param code  formData string true "授权码"
+// @Param state formData string true "登录地址返回的 state"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /passport/provider/{name}/callback [post]
+// @x-lakego {"slug": "lakego-admin.passport.provider-callback"}
+func (this *Passport) ProviderCallback(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
 
-    events.DoAction("admin.passport-login.end", name)
+    events.DoAction("admin.passport-provider-callback.start", post)
 
-    // 数据输出
-    this.SuccessWithData(ctx, "登录成功", router.H{
-        "access_token": accessToken,
-        "expires_in": expiresIn,
-        "refresh_token": refreshToken,
+    authCode, _ := post["code"].(string)
+    state, _ := post["state"].(string)
+    if authCode == "" || state == "" {
+        this.Error(ctx, "授权码不能为空", code.LoginError)
+        return
+    }
+
+    name, nonce := auth.PullState(state)
+    if name == "" || name != ctx.Param("name") {
+        this.Error(ctx, "登录已过期，请重新登录", code.LoginError)
+        return
+    }
+
+    provider, err := auth.GetProvider(name)
+    if err != nil {
+        this.Error(ctx, "登录方式不存在", code.LoginError)
+        return
+    }
+
+    identity, err := provider.Authenticate(ctx.Request.Context(), map[string]string{
+        "code": authCode,
+        "nonce": nonce,
     })
+    if err != nil {
+        events.DoAction("admin.passport-provider.error", err.Error())
+
+        this.Error(ctx, "登录失败", code.LoginError)
+        return
+    }
+
+    this.loginWithIdentity(ctx, provider, identity, identity.Name)
 }
 
-// 刷新 token
-// @Summary 刷新 token
-// @Description 刷新 token
+// 两步验证登陆
+// @Summary 两步验证登陆
+// @Description 两步验证登陆，验证码可以使用恢复码
 // @Tags 登陆相关
 // @Accept application/json
 // @Produce application/json
-// @Param refresh_token formData string true "刷新 Token"
+// @Param challenge_token formData string true "登陆挑战 token"
+// @Param code            formData string true "验证码或者恢复码"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
-// @Router /passport/refresh-token [post]
-// @x-lakego {"slug": "lakego-admin.passport.refresh-token"}
-func (this *Passport) RefreshToken(ctx *router.Context) {
+// @Router /passport/login/2fa [post]
+// @x-lakego {"slug": "lakego-admin.passport.login-2fa"}
+func (this *Passport) LoginTwoFactor(ctx *router.Context) {
     // 接收数据
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
-    events.DoAction("admin.passport-refreshtoken.start", post)
+    challengeToken, _ := post["challenge_token"].(string)
+    totpCode, _ := post["code"].(string)
 
-    var refreshToken any
-    var ok bool
+    // 验证码不传给事件
+    delete(post, "code")
 
-    if refreshToken, ok = post["refresh_token"]; !ok {
-        this.Error(ctx, "refreshToken不能为空", code.JwtRefreshTokenFail)
+    events.DoAction("admin.passport-login-2fa.start", post)
+
+    if challengeToken == "" || totpCode == "" {
+        this.Error(ctx, "验证码不能为空", code.TwoFactorError)
         return
     }
 
-    refreshTokenPutTime, _ := facade.Cache.Get(utils.MD5(refreshToken.(string)))
-    refreshTokenPutTime = refreshTokenPutTime.(string)
-    if refreshTokenPutTime != "" {
-        this.Error(ctx, "refreshToken已失效", code.JwtRefreshTokenFail)
+    adminid := twofactor.GetChallenge(challengeToken)
+    if adminid == "" {
+        this.Error(ctx, "登录已过期，请重新登录", code.LoginError)
         return
     }
 
-    // jwt
-    aud := auth.GetJwtAud(ctx)
-    jwter := auth.NewWithAud(aud)
+    // 用户信息
+    adminInfo := new(model.Admin)
+    err := model.NewAdmin(ctx).
+        Where("id = ?", adminid).
+        First(adminInfo).
+        Error
+    if err != nil || adminInfo.TotpStatus != twofactor.StatusEnabled {
+        twofactor.ForgetChallenge(challengeToken)
 
-    // 拿取数据
-    adminId := jwter.GetRefreshTokenData(refreshToken.(string), "id")
-    if adminId == "" {
-        this.Error(ctx, "刷新Token失败", code.JwtRefreshTokenFail)
+        this.Error(ctx, "登录已过期，请重新登录", code.LoginError)
         return
     }
 
-    // token 数据
-    tokenData := map[string]string{
-        "id": adminId,
+    if !twofactor.Verify(adminInfo, totpCode) {
+        twofactor.FailChallenge(challengeToken)
+
+        events.DoAction("admin.passport-login-2fa.code-error", adminInfo.Name)
+
+        this.Error(ctx, "验证码错误", code.TwoFactorError)
+        return
     }
 
-    // 授权 token
-    accessToken, err := jwter.MakeAccessToken(tokenData)
-    if err != nil {
-        events.DoAction("admin.passport-refreshtoken.make-accesstoken-fail", err.Error())
+    twofactor.ForgetChallenge(challengeToken)
 
-        this.Error(ctx, "生成 access_token 失败", code.JwtRefreshTokenFail)
+    this.loginSuccess(ctx, adminid, adminInfo.Name, adminInfo.IsRoot)
+}
+
+// 刷新 token
+// @Summary 刷新 token
+// @Description 刷新 token，刷新后旧的刷新 token 失效
+// @Tags 登陆相关
+// @Accept application/json
+// @Produce application/json
+// @Param refresh_token formData string true "刷新 Token"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /passport/refresh-token [post]
+// @x-lakego {"slug": "lakego-admin.passport.refresh-token"}
+func (this *Passport) RefreshToken(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    events.DoAction("admin.passport-refreshtoken.start", post)
+
+    refreshToken, _ := post["refresh_token"].(string)
+    if refreshToken == "" {
+        this.Error(ctx, "refreshToken不能为空", code.JwtRefreshTokenFail)
         return
     }
 
-    // 授权 token 过期时间
-    expiresIn := jwter.GetAccessExpiresIn()
+    // 轮换刷新 token
+    tokens, err := session.Refresh(ctx, refreshToken)
+    if err != nil {
+        if err == session.ErrTokenReused {
+            this.Error(ctx, "refreshToken已失效，请重新登录", code.JwtRefreshTokenFail)
+            return
+        }
 
-    events.DoAction("admin.passport-refreshtoken.end", adminId)
+        if err != session.ErrSessionNotFound && err != session.ErrSessionRevoked {
+            events.DoAction("admin.passport-refreshtoken.make-accesstoken-fail", err.Error())
+        }
+
+        this.Error(ctx, "刷新Token失败", code.JwtRefreshTokenFail)
+        return
+    }
+
+    events.DoAction("admin.passport-refreshtoken.end", tokens.AdminId)
 
     // 数据输出
     this.SuccessWithData(ctx, "获取成功", router.H{
-        "access_token": accessToken,
-        "expires_in": expiresIn,
+        "access_token": tokens.AccessToken,
+        "expires_in": tokens.ExpiresIn,
+        "refresh_token": tokens.RefreshToken,
     })
 }
 
 // 账号退出
 // @Summary 当前账号退出
-// @Description 当前账号退出
+// @Description 当前账号退出，使用访问令牌时撤销该令牌
 // @Tags 登陆相关
 // @Accept  application/json
 // @Produce application/json
-// @Param Authorization header   string false "Bearer 用户令牌"
-// @Param refresh_token formData string true  "刷新 Token"
+// @Param Authorization header string false "Bearer 用户令牌"
 // @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
 // @Router /passport/logout [delete]
 // @Security Bearer
@@ -251,57 +423,143 @@ func (this *Passport) Logout(ctx *router.Context) {
 
     events.DoAction("admin.passport-logout.start", post)
 
-    var refreshToken any
-    var ok bool
+    adminId, _ := ctx.Get("admin_id")
+
+    var err error
+    if token, ok := apitoken.FromContext(ctx); ok {
+        // 访问令牌没有会话，撤销当前令牌
+        _, err = apitoken.Revoke(token.AdminId, token.ID)
+    } else {
+        // 撤销当前会话
+        family, _ := ctx.Get("session_family")
+        err = session.RevokeFamily(family.(string))
+    }
+
+    if err != nil {
+        this.Error(ctx, "退出失败", code.LogoutError)
+        return
+    }
+
+    events.DoAction("admin.passport-logout.end", adminId)
+
+    // 数据输出
+    this.Success(ctx, "退出成功")
+}
+
+// 外部账号密码登录，比如 LDAP
+func (this *Passport) loginWithProvider(ctx *router.Context, providerName string, name string, password string) {
+    provider, err := auth.GetProvider(providerName)
+    if err != nil || provider.Redirect() {
+        this.Error(ctx, "登录方式不存在", code.LoginError)
+        return
+    }
+
+    identity, err := provider.Authenticate(ctx.Request.Context(), map[string]string{
+        "name": name,
+        "password": password,
+    })
+    if err != nil {
+        if err == auth.ErrInvalidCredentials {
+            lockout.Failed(router.GetRequestIp(ctx), name, "")
+
+            events.DoAction("admin.passport-login.password-error", name)
+
+            this.Error(ctx, "账号或者密码错误", code.LoginError)
+            return
+        }
 
-    if refreshToken, ok = post["refresh_token"]; !ok {
-        this.Error(ctx, "refreshToken 不能为空", code.JwtRefreshTokenFail)
+        events.DoAction("admin.passport-provider.error", err.Error())
+
+        this.Error(ctx, "登录失败", code.LoginError)
         return
     }
 
-    c := facade.Cache
+    this.loginWithIdentity(ctx, provider, identity, name)
+}
+
+// 外部账号登录，获取或者创建对应的管理员
+func (this *Passport) loginWithIdentity(ctx *router.Context, provider *auth.Provider, identity *auth.Identity, name string) {
+    adminInfo, err := auth.Provision(identity, provider.Provision, router.GetRequestIp(ctx))
+    if err != nil {
+        switch err {
+            case auth.ErrAdminNotFound:
+                this.Error(ctx, "账号不存在", code.LoginError)
+            case auth.ErrAdminExists:
+                this.Error(ctx, "账号已存在，请联系管理员关联账号", code.LoginError)
+            case auth.ErrAdminDisabled:
+                this.Error(ctx, "账号已被禁用", code.LoginError)
+            default:
+                events.DoAction("admin.passport-provider.provision-error", err.Error())
+
+                this.Error(ctx, "登录失败", code.LoginError)
+        }
 
-    refreshTokenPutString, _ := c.Get(utils.MD5(refreshToken.(string)))
-    refreshTokenPutString = refreshTokenPutString.(string)
-    if refreshTokenPutString != "" {
-        this.Error(ctx, "refreshToken 已失效", code.JwtRefreshTokenFail)
         return
     }
 
-    // jwt
-    aud := auth.GetJwtAud(ctx)
-    jwter := auth.NewWithAud(aud)
+    // 账号锁定
+    if lockedUntil := lockout.LockedUntil(adminInfo.LockedUntil); lockedUntil > 0 {
+        events.DoAction("admin.passport-login.locked", name)
 
-    // 拿取数据
-    claims, claimsErr := jwter.GetRefreshTokenClaims(refreshToken.(string))
-    if claimsErr != nil {
-        this.Error(ctx, "refreshToken 已失效", code.JwtRefreshTokenFail)
+        lockedTime := datebin.FromTimestamp(lockedUntil).ToDatetimeString()
+        this.Error(ctx, "账号已被锁定，请于" + lockedTime + "后重试", code.LoginError)
         return
     }
 
-    // 当前账号ID
-    adminId := jwter.GetDataFromTokenClaims(claims, "id")
+    lockout.Succeeded(name, adminInfo.ID)
 
-    // 过期时间
-    exp := jwter.GetFromTokenClaims(claims, "exp")
-    iat := jwter.GetFromTokenClaims(claims, "iat")
-    refreshTokenExpiresIn := exp.(float64) - iat.(float64)
+    events.DoAction("admin.passport-provider.login", identity)
 
-    nowAdminId, _ := ctx.Get("admin_id")
-    if adminId != nowAdminId.(string) {
-        this.Error(ctx, "退出失败", code.JwtRefreshTokenFail)
+    this.loginAdmin(ctx, adminInfo.ID, adminInfo.Name, adminInfo.TotpStatus, adminInfo.IsRoot)
+}
+
+// 账号验证通过，已开启两步验证时返回登录挑战
+func (this *Passport) loginAdmin(ctx *router.Context, adminid string, name string, totpStatus int, isRoot int) {
+    if totpStatus == twofactor.StatusEnabled {
+        challengeToken, err := twofactor.MakeChallenge(adminid)
+        if err != nil {
+            this.Error(ctx, "登录失败", code.LoginError)
+            return
+        }
+
+        events.DoAction("admin.passport-login.2fa-challenge", name)
+
+        this.SuccessWithData(ctx, "请输入两步验证码", router.H{
+            "requires_2fa": true,
+            "challenge_token": challengeToken,
+            "expires_in": twofactor.ChallengeExpiresIn(),
+        })
         return
     }
 
-    // 当前 accessToken
-    accessToken, _ := ctx.Get("access_token")
+    this.loginSuccess(ctx, adminid, name, isRoot)
+}
 
-    // 加入黑名单
-    c.Put(utils.MD5(accessToken.(string)), "no", int64(refreshTokenExpiresIn))
-    c.Put(utils.MD5(refreshToken.(string)), "no", int64(refreshTokenExpiresIn))
+// 登录成功，创建会话并生成 token
+func (this *Passport) loginSuccess(ctx *router.Context, adminid string, name string, isRoot int) {
+    tokens, err := session.Create(ctx, adminid)
+    if err != nil {
+        events.DoAction("admin.passport-login.make-accesstoken-fail", err.Error())
 
-    events.DoAction("admin.passport-logout.end", adminId)
+        this.Error(ctx, "授权token生成失败", code.LoginError)
+        return
+    }
+
+    // 更新登录时间
+    model.NewAdmin(ctx).
+        Where("id = ?", adminid).
+        Updates(map[string]any{
+            "last_active": int(datebin.NowTimestamp()),
+            "last_ip": router.GetRequestIp(ctx),
+        })
+
+    events.DoAction("admin.passport-login.end", name)
 
     // 数据输出
-    this.Success(ctx, "退出成功")
+    this.SuccessWithData(ctx, "登录成功", router.H{
+        "access_token": tokens.AccessToken,
+        "expires_in": tokens.ExpiresIn,
+        "refresh_token": tokens.RefreshToken,
+        "requires_2fa_enroll": twofactor.IsForced(isRoot),
+    })
 }
@@ -1,12 +1,25 @@
 package controller
 
 import (
+    "strings"
+    "encoding/base64"
+
+    "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-events/events"
+    "github.com/deatil/go-datebin/datebin"
 
+    "github.com/deatil/lakego-doak/lakego/totp"
+    "github.com/deatil/lakego-doak/lakego/array"
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/collection"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
     "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    "github.com/deatil/lakego-doak-admin/admin/auth/apitoken"
+    "github.com/deatil/lakego-doak-admin/admin/auth/twofactor"
+    "github.com/deatil/lakego-doak-admin/admin/support/http/code"
     auth_password "github.com/deatil/lakego-doak-admin/admin/password"
     profile_validate "github.com/deatil/lakego-doak-admin/admin/validate/profile"
 )
@@ -61,6 +74,10 @@ func (this *Profile) Update(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 检测
     validateErr := profile_validate.Update(post)
     if validateErr != "" {
@@ -77,7 +94,7 @@ func (this *Profile) Update(ctx *router.Context) {
 
     adminid := adminInfo.(*admin.Admin).GetId()
 
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Where("id = ?", adminid).
         Updates(map[string]any{
             "nickname": post["nickname"].(string),
@@ -112,6 +129,10 @@ func (this *Profile) UpdateAvatar(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 检测
     validateErr := profile_validate.UpdateAvatar(post)
     if validateErr != "" {
@@ -128,7 +149,7 @@ func (this *Profile) UpdateAvatar(ctx *router.Context) {
 
     adminid := adminInfo.(*admin.Admin).GetId()
 
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx).
         Where("id = ?", adminid).
         Updates(map[string]any{
             "avatar": post["avatar"].(string),
@@ -163,6 +184,10 @@ func (this *Profile) UpdatePasssword(ctx *router.Context) {
     post := make(map[string]any)
     this.ShouldBindJSON(ctx, &post)
 
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
     // 检测
     validateErr := profile_validate.UpdatePasssword(post)
     if validateErr != "" {
@@ -200,9 +225,13 @@ func (this *Profile) UpdatePasssword(ctx *router.Context) {
     }
 
     // 生成密码
-    pass, encrypt := auth_password.MakePassword(newpassword)
+    pass, encrypt, err := auth_password.MakePassword(newpassword)
+    if err != nil {
+        this.Error(ctx, "密码生成失败")
+        return
+    }
 
-    err := model.NewAdmin().
+    err = model.NewAdmin(ctx).
         Where("id = ?", adminid).
         Updates(map[string]any{
             "password": pass,
@@ -214,6 +243,10 @@ func (this *Profile) UpdatePasssword(ctx *router.Context) {
         return
     }
 
+    // 修改密码后退出其他会话
+    family, _ := ctx.Get("session_family")
+    session.RevokeAll(adminid, family.(string))
+
     // 事件
     events.DoAction("admin.profile.update-passsword-after", adminid)
 
@@ -243,3 +276,559 @@ func (this *Profile) Rules(ctx *router.Context) {
         "list": rules,
     })
 }
+
+// 所属租户列表
+// @Summary 所属租户列表
+// @Description 当前账号所属的租户列表，请求时通过请求头或者子域名指定租户
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/tenants [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.tenants"}
+func (this *Profile) Tenants(ctx *router.Context) {
+    adminId, _ := ctx.Get("admin_id")
+
+    list := make([]map[string]any, 0)
+    for _, info := range tenant.AdminTenants(adminId.(string)) {
+        list = append(list, map[string]any{
+            "id": info.ID,
+            "code": info.Code,
+            "title": info.Title,
+            "description": info.Description,
+        })
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "current": tenant.Current(ctx),
+        "list": list,
+    })
+}
+
+// 登录会话列表
+// @Summary 登录会话列表
+// @Description 当前账号的登录会话列表
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/sessions [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.sessions"}
+func (this *Profile) Sessions(ctx *router.Context) {
+    adminId, _ := ctx.Get("admin_id")
+    family, _ := ctx.Get("session_family")
+
+    list, err := session.List(adminId.(string), family.(string))
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
+
+// 撤销登录会话
+// @Summary 撤销登录会话
+// @Description 撤销当前账号的某个登录会话
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "会话ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/sessions/{id} [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.session-delete"}
+func (this *Profile) RevokeSession(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "会话ID不能为空")
+        return
+    }
+
+    adminId, _ := ctx.Get("admin_id")
+
+    ok, err := session.Revoke(adminId.(string), id)
+    if err != nil {
+        this.Error(ctx, "撤销会话失败")
+        return
+    }
+
+    if !ok {
+        this.Error(ctx, "会话不存在")
+        return
+    }
+
+    this.Success(ctx, "撤销会话成功")
+}
+
+// 撤销其他登录会话
+// @Summary 撤销其他登录会话
+// @Description 撤销当前账号除当前会话外的全部登录会话
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/sessions [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.sessions-delete"}
+func (this *Profile) RevokeOtherSessions(ctx *router.Context) {
+    adminId, _ := ctx.Get("admin_id")
+    family, _ := ctx.Get("session_family")
+
+    count, err := session.RevokeAll(adminId.(string), family.(string))
+    if err != nil {
+        this.Error(ctx, "撤销会话失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "撤销会话成功", router.H{
+        "count": count,
+    })
+}
+
+// 访问令牌列表
+// @Summary 访问令牌列表
+// @Description 当前账号的个人访问令牌列表
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/tokens [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.tokens"}
+func (this *Profile) Tokens(ctx *router.Context) {
+    adminId, _ := ctx.Get("admin_id")
+
+    list, err := apitoken.List(adminId.(string))
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
+
+// 创建访问令牌
+// @Summary 创建访问令牌
+// @Description 创建个人访问令牌，令牌只在创建时返回一次
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param name         formData string true  "令牌名称"
+// @Param scopes       formData string true  "授权的权限标识，多个用英文逗号分隔"
+// @Param expires_time formData int    false "过期时间戳，为 0 时不过期"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/tokens [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.token-create"}
+func (this *Profile) TokenCreate(ctx *router.Context) {
+    if _, ok := apitoken.FromContext(ctx); ok {
+        this.Error(ctx, "访问令牌不能创建访问令牌")
+        return
+    }
+
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    validateErr := profile_validate.TokenCreate(post)
+    if validateErr != "" {
+        this.Error(ctx, validateErr)
+        return
+    }
+
+    name := goch.ToString(post["name"])
+
+    scopes := collection.
+        Collect(strings.Split(goch.ToString(post["scopes"]), ",")).
+        Unique().
+        ToStringArray()
+
+    // 只能授权账号拥有的权限
+    adminInfo, _ := ctx.Get("admin")
+    adminSlugs := adminInfo.(*admin.Admin).GetRuleSlugs()
+
+    newScopes := make([]string, 0)
+    for _, scope := range scopes {
+        scope = strings.TrimSpace(scope)
+        if scope == "" {
+            continue
+        }
+
+        if !array.InArray(scope, adminSlugs) {
+            this.Error(ctx, "权限[" + scope + "]不存在或者没有权限")
+            return
+        }
+
+        if apitoken.IsDeniedScope(scope) {
+            this.Error(ctx, "权限[" + scope + "]不能授权给访问令牌")
+            return
+        }
+
+        newScopes = append(newScopes, scope)
+    }
+
+    if len(newScopes) == 0 {
+        this.Error(ctx, "授权权限不能为空")
+        return
+    }
+
+    // 过期时间
+    now := int(datebin.NowTimestamp())
+    expiresTime := goch.ToInt(post["expires_time"])
+    if expiresTime < 0 || (expiresTime > 0 && expiresTime <= now) {
+        this.Error(ctx, "过期时间错误")
+        return
+    }
+
+    if maxLifetime := apitoken.MaxLifetime(); maxLifetime > 0 {
+        maxTime := now + maxLifetime * 86400
+        if expiresTime == 0 || expiresTime > maxTime {
+            this.Error(ctx, "令牌有效期不能超过" + goch.ToString(maxLifetime) + "天")
+            return
+        }
+    }
+
+    adminId, _ := ctx.Get("admin_id")
+
+    token, plain, err := apitoken.Create(adminId.(string), name, expiresTime, newScopes, router.GetRequestIp(ctx))
+    if err != nil {
+        if err == apitoken.ErrTooManyTokens {
+            this.Error(ctx, "访问令牌数量超过限制")
+            return
+        }
+
+        this.Error(ctx, "创建访问令牌失败")
+        return
+    }
+
+    events.DoAction("admin.profile-token.create", token)
+
+    this.SuccessWithData(ctx, "创建访问令牌成功", router.H{
+        "id": token.ID,
+        "name": token.Name,
+        "token": plain,
+        "scopes": newScopes,
+        "expires_time": token.ExpiresTime,
+    })
+}
+
+// 撤销访问令牌
+// @Summary 撤销访问令牌
+// @Description 撤销当前账号的某个访问令牌
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "令牌ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/tokens/{id} [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.token-delete"}
+func (this *Profile) TokenDelete(ctx *router.Context) {
+    if _, ok := apitoken.FromContext(ctx); ok {
+        this.Error(ctx, "访问令牌不能撤销访问令牌")
+        return
+    }
+
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "令牌ID不能为空")
+        return
+    }
+
+    adminId, _ := ctx.Get("admin_id")
+
+    ok, err := apitoken.Revoke(adminId.(string), id)
+    if err != nil {
+        this.Error(ctx, "撤销访问令牌失败")
+        return
+    }
+
+    if !ok {
+        this.Error(ctx, "访问令牌不存在")
+        return
+    }
+
+    this.Success(ctx, "撤销访问令牌成功")
+}
+
+// 两步验证状态
+// @Summary 两步验证状态
+// @Description 两步验证状态
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/2fa [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.2fa"}
+func (this *Profile) TwoFactor(ctx *router.Context) {
+    adminInfo, ok := this.currentAdmin(ctx)
+    if !ok {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "enabled": adminInfo.TotpStatus == twofactor.StatusEnabled,
+        "forced": twofactor.IsForced(adminInfo.IsRoot),
+        "recovery_codes": twofactor.RecoveryCodesCount(adminInfo.TotpRecovery),
+    })
+}
+
+// 生成两步验证密钥
+// @Summary 生成两步验证密钥
+// @Description 生成两步验证密钥，返回绑定链接和二维码，确认验证码后开启
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/2fa [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.2fa-create"}
+func (this *Profile) TwoFactorCreate(ctx *router.Context) {
+    adminInfo, ok := this.currentAdmin(ctx)
+    if !ok {
+        this.Error(ctx, "生成密钥失败")
+        return
+    }
+
+    if adminInfo.TotpStatus == twofactor.StatusEnabled {
+        this.Error(ctx, "两步验证已开启")
+        return
+    }
+
+    secret, err := twofactor.GenerateSecret()
+    if err != nil {
+        this.Error(ctx, "生成密钥失败")
+        return
+    }
+
+    uri := twofactor.URI(secret, adminInfo.Name)
+
+    png, err := totp.QRCode(uri, 256)
+    if err != nil {
+        this.Error(ctx, "生成二维码失败")
+        return
+    }
+
+    // 密钥加密保存
+    encrypted, err := twofactor.EncryptSecret(secret)
+    if err != nil {
+        this.Error(ctx, "生成密钥失败")
+        return
+    }
+
+    // 确认前保存为未开启状态
+    err = model.NewAdmin(ctx).
+        Where("id = ?", adminInfo.ID).
+        Updates(map[string]any{
+            "totp_secret": encrypted,
+            "totp_status": twofactor.StatusDisabled,
+            "totp_recovery": "",
+        }).
+        Error
+    if err != nil {
+        this.Error(ctx, "生成密钥失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "secret": secret,
+        "uri": uri,
+        "qrcode": "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+    })
+}
+
+// 确认开启两步验证
+// @Summary 确认开启两步验证
+// @Description 确认开启两步验证，恢复码只返回一次
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param code formData string true "验证码"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/2fa/confirm [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.2fa-confirm"}
+func (this *Profile) TwoFactorConfirm(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    adminInfo, ok := this.currentAdmin(ctx)
+    if !ok {
+        this.Error(ctx, "开启失败")
+        return
+    }
+
+    if adminInfo.TotpStatus == twofactor.StatusEnabled {
+        this.Error(ctx, "两步验证已开启")
+        return
+    }
+
+    if adminInfo.TotpSecret == "" {
+        this.Error(ctx, "请先生成密钥")
+        return
+    }
+
+    totpCode, _ := post["code"].(string)
+    if !twofactor.VerifyCode(adminInfo.ID, adminInfo.TotpSecret, totpCode) {
+        this.Error(ctx, "验证码错误", code.TwoFactorError)
+        return
+    }
+
+    codes, recovery, err := twofactor.GenerateRecoveryCodes()
+    if err != nil {
+        this.Error(ctx, "开启失败")
+        return
+    }
+
+    err = model.NewAdmin(ctx).
+        Where("id = ?", adminInfo.ID).
+        Updates(map[string]any{
+            "totp_status": twofactor.StatusEnabled,
+            "totp_recovery": recovery,
+        }).
+        Error
+    if err != nil {
+        this.Error(ctx, "开启失败")
+        return
+    }
+
+    // 事件
+    events.DoAction("admin.profile.2fa-enable-after", adminInfo.ID)
+
+    this.SuccessWithData(ctx, "开启成功", router.H{
+        "recovery_codes": codes,
+    })
+}
+
+// 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 重新生成恢复码，旧的恢复码失效
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param code formData string true "验证码"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/2fa/recovery-codes [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.2fa-recovery-codes"}
+func (this *Profile) TwoFactorRecoveryCodes(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    adminInfo, ok := this.currentAdmin(ctx)
+    if !ok || adminInfo.TotpStatus != twofactor.StatusEnabled {
+        this.Error(ctx, "两步验证未开启")
+        return
+    }
+
+    totpCode, _ := post["code"].(string)
+    if !twofactor.VerifyCode(adminInfo.ID, adminInfo.TotpSecret, totpCode) {
+        this.Error(ctx, "验证码错误", code.TwoFactorError)
+        return
+    }
+
+    codes, recovery, err := twofactor.GenerateRecoveryCodes()
+    if err != nil {
+        this.Error(ctx, "生成恢复码失败")
+        return
+    }
+
+    err = model.NewAdmin(ctx).
+        Where("id = ?", adminInfo.ID).
+        Updates(map[string]any{
+            "totp_recovery": recovery,
+        }).
+        Error
+    if err != nil {
+        this.Error(ctx, "生成恢复码失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "生成成功", router.H{
+        "recovery_codes": codes,
+    })
+}
+
+// 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 关闭两步验证，验证码可以使用恢复码
+// @Tags 个人信息
+// @Accept  application/json
+// @Produce application/json
+// @Param code formData string true "验证码或者恢复码"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /profile/2fa [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.profile.2fa-delete"}
+func (this *Profile) TwoFactorDelete(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    adminInfo, ok := this.currentAdmin(ctx)
+    if !ok || adminInfo.TotpStatus != twofactor.StatusEnabled {
+        this.Error(ctx, "两步验证未开启")
+        return
+    }
+
+    if twofactor.IsForced(adminInfo.IsRoot) {
+        this.Error(ctx, "当前账号必须开启两步验证")
+        return
+    }
+
+    totpCode, _ := post["code"].(string)
+    if !twofactor.Verify(adminInfo, totpCode) {
+        this.Error(ctx, "验证码错误", code.TwoFactorError)
+        return
+    }
+
+    err := model.NewAdmin(ctx).
+        Where("id = ?", adminInfo.ID).
+        Updates(map[string]any{
+            "totp_secret": "",
+            "totp_status": twofactor.StatusDisabled,
+            "totp_recovery": "",
+        }).
+        Error
+    if err != nil {
+        this.Error(ctx, "关闭失败")
+        return
+    }
+
+    // 事件
+    events.DoAction("admin.profile.2fa-disable-after", adminInfo.ID)
+
+    this.Success(ctx, "关闭成功")
+}
+
+// 当前账号数据
+func (this *Profile) currentAdmin(ctx *router.Context) (*model.Admin, bool) {
+    adminInfo, ok := ctx.Get("admin")
+    if !ok {
+        return nil, false
+    }
+
+    adminid := adminInfo.(*admin.Admin).GetId()
+
+    adminData := new(model.Admin)
+    err := model.NewAdmin(ctx).
+        Where("id = ?", adminid).
+        First(adminData).
+        Error
+    if err != nil {
+        return nil, false
+    }
+
+    return adminData, true
+}
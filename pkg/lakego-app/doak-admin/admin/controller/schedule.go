@@ -0,0 +1,100 @@
+package controller
+
+import (
+    "time"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/schedule"
+)
+
+/**
+ * 计划任务
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Schedule struct {
+    Base
+
+    // 计划任务
+    Schedule *schedule.Schedule
+}
+
+// 计划任务列表
+// @Summary 计划任务列表
+// @Description 计划任务列表
+// @Tags 计划任务
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /schedule [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.schedule.index","sort":"175"}
+func (this *Schedule) Index(ctx *router.Context) {
+    list := make([]router.H, 0)
+
+    if this.Schedule != nil {
+        now := time.Now()
+
+        for _, entry := range this.Schedule.Entries() {
+            next := ""
+            if t, err := entry.Next(now); err == nil {
+                next = datebin.FromStdTime(t).ToDatetimeString()
+            }
+
+            list = append(list, router.H{
+                "name": entry.Name,
+                "spec": entry.Spec,
+                "next_time": next,
+                "without_overlapping": entry.IsWithoutOverlapping(),
+                "on_one_server": entry.IsOnOneServer(),
+                "timeout": int64(entry.GetTimeout() / time.Second),
+            })
+        }
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
+
+// 计划任务执行记录
+// @Summary 计划任务执行记录
+// @Description 计划任务执行记录
+// @Tags 计划任务
+// @Accept application/json
+// @Produce application/json
+// @Param name  query string false "任务名称"
+// @Param limit query string false "数量，最大 100"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /schedule/history [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.schedule.history","sort":"176"}
+func (this *Schedule) History(ctx *router.Context) {
+    if this.Schedule == nil {
+        this.SuccessWithData(ctx, "获取成功", router.H{
+            "list": []schedule.History{},
+        })
+        return
+    }
+
+    name := ctx.DefaultQuery("name", "")
+
+    limit := goch.ToInt(ctx.DefaultQuery("limit", "20"))
+    if limit <= 0 || limit > 100 {
+        limit = 20
+    }
+
+    list, err := this.Schedule.History(name, limit)
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": list,
+    })
+}
@@ -0,0 +1,456 @@
+package controller
+
+import (
+    "strings"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/collection"
+    "github.com/deatil/lakego-doak/lakego/facade/permission"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    tenantValidate "github.com/deatil/lakego-doak-admin/admin/validate/tenant"
+)
+
+/**
+ * 租户
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Tenant struct {
+    Base
+}
+
+// 租户列表
+// @Summary 租户列表
+// @Description 租户列表
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param order      query string false "排序，示例：id__DESC"
+// @Param searchword query string false "搜索关键字"
+// @Param status     query string false "状态"
+// @Param start      query string false "开始数据量"
+// @Param limit      query string false "每页数量"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.index"}
+func (this *Tenant) Index(ctx *router.Context) {
+    // 模型
+    tenantModel := model.NewTenant(ctx)
+
+    // 排序
+    order := ctx.DefaultQuery("order", "add_time__DESC")
+    orders := this.FormatOrderBy(order)
+    if orders[0] == "" ||
+        (orders[0] != "id" &&
+        orders[0] != "code" &&
+        orders[0] != "title" &&
+        orders[0] != "listorder" &&
+        orders[0] != "add_time") {
+        orders[0] = "add_time"
+    }
+
+    tenantModel = tenantModel.Order(orders[0] + " " + orders[1])
+
+    // 搜索条件
+    searchword := ctx.DefaultQuery("searchword", "")
+    if searchword != "" {
+        searchword = "%" + searchword + "%"
+
+        tenantModel = tenantModel.
+            Where("code LIKE ? OR title LIKE ?", searchword, searchword)
+    }
+
+    status := this.SwitchStatus(ctx.DefaultQuery("status", ""))
+    if status != -1 {
+        tenantModel = tenantModel.Where("status = ?", status)
+    }
+
+    // 分页相关
+    start := ctx.DefaultQuery("start", "0")
+    limit := ctx.DefaultQuery("limit", "10")
+
+    newStart := goch.ToInt(start)
+    newLimit := goch.ToInt(limit)
+
+    tenantModel = tenantModel.
+        Offset(newStart).
+        Limit(newLimit)
+
+    list := make([]map[string]any, 0)
+
+    // 列表
+    tenantModel.Find(&list)
+
+    var total int64
+
+    // 总数
+    err := tenantModel.
+        Offset(-1).
+        Limit(-1).
+        Count(&total).
+        Error
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "start": start,
+        "limit": limit,
+        "total": total,
+        "list": list,
+    })
+}
+
+// 租户详情
+// @Summary 租户详情
+// @Description 租户详情
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "租户ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant/{id} [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.detail"}
+func (this *Tenant) Detail(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "ID不能为空")
+        return
+    }
+
+    result := map[string]any{}
+    err := model.NewTenant(ctx).
+        Where("id = ?", id).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        this.Error(ctx, "信息不存在")
+        return
+    }
+
+    // 租户成员
+    var adminIds []string
+    model.NewTenantAccess(ctx).
+        Where("tenant_id = ?", id).
+        Pluck("admin_id", &adminIds)
+
+    result["admins"] = adminIds
+
+    this.SuccessWithData(ctx, "获取成功", result)
+}
+
+// 租户添加
+// @Summary 租户添加
+// @Description 租户添加
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param code        formData string true "标识，用于请求头和子域名"
+// @Param title       formData string true "名称"
+// @Param description formData string false "描述"
+// @Param listorder   formData string true "排序"
+// @Param status      formData string true "状态"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.create"}
+func (this *Tenant) Create(ctx *router.Context) {
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
+    validateErr := tenantValidate.Create(post)
+    if validateErr != "" {
+        this.Error(ctx, validateErr)
+        return
+    }
+
+    code := strings.ToLower(goch.ToString(post["code"]))
+    if this.codeExists(code, "") {
+        this.Error(ctx, "标识已经存在")
+        return
+    }
+
+    status := goch.ToInt(post["status"])
+    if status != 1 {
+        status = 0
+    }
+
+    insertData := model.Tenant{
+        Code: code,
+        Title: goch.ToString(post["title"]),
+        Description: goch.ToString(post["description"]),
+        Listorder: goch.ToInt(post["listorder"]),
+        Status: status,
+        AddTime: int(datebin.NowTimestamp()),
+        AddIp: router.GetRequestIp(ctx),
+    }
+
+    err := model.NewDBWithContext(ctx).
+        Create(&insertData).
+        Error
+    if err != nil {
+        this.Error(ctx, "信息添加失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "信息添加成功", router.H{
+        "id": insertData.ID,
+    })
+}
+
+// 租户更新
+// @Summary 租户更新
+// @Description 租户更新
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param id          path     string true "租户ID"
+// @Param code        formData string true "标识，用于请求头和子域名"
+// @Param title       formData string true "名称"
+// @Param description formData string false "描述"
+// @Param listorder   formData string true "排序"
+// @Param status      formData string true "状态"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant/{id} [put]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.update"}
+func (this *Tenant) Update(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "ID不能为空")
+        return
+    }
+
+    // 查询
+    result := map[string]any{}
+    err := model.NewTenant(ctx).
+        Where("id = ?", id).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        this.Error(ctx, "信息不存在")
+        return
+    }
+
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
+    validateErr := tenantValidate.Update(post)
+    if validateErr != "" {
+        this.Error(ctx, validateErr)
+        return
+    }
+
+    code := strings.ToLower(goch.ToString(post["code"]))
+    if this.codeExists(code, id) {
+        this.Error(ctx, "标识已经存在")
+        return
+    }
+
+    status := goch.ToInt(post["status"])
+    if status != 1 {
+        status = 0
+    }
+
+    err2 := model.NewTenant(ctx).
+        Where("id = ?", id).
+        Updates(map[string]any{
+            "code": code,
+            "title": goch.ToString(post["title"]),
+            "description": goch.ToString(post["description"]),
+            "listorder": goch.ToInt(post["listorder"]),
+            "status": status,
+            "update_time": int(datebin.NowTimestamp()),
+            "update_ip": router.GetRequestIp(ctx),
+        }).
+        Error
+    if err2 != nil {
+        this.Error(ctx, "信息修改失败")
+        return
+    }
+
+    this.Success(ctx, "信息修改成功")
+}
+
+// 租户删除
+// @Summary 租户删除
+// @Description 租户删除，同时删除租户的权限数据
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "租户ID"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant/{id} [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.delete"}
+func (this *Tenant) Delete(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "ID不能为空")
+        return
+    }
+
+    // 详情
+    var info model.Tenant
+    err := model.NewTenant(ctx).
+        Where("id = ?", id).
+        First(&info).
+        Error
+    if err != nil {
+        this.Error(ctx, "信息不存在")
+        return
+    }
+
+    // 分组
+    var total int64
+    err2 := model.NewAuthGroup(ctx).
+        Where("tenant_id = ?", id).
+        Count(&total).
+        Error
+    if err2 != nil || total > 0 {
+        this.Error(ctx, "请删除租户下的分组后再操作")
+        return
+    }
+
+    err3 := model.NewDB(ctx).
+        Where("tenant_id = ?", id).
+        Delete(&model.TenantAccess{}).
+        Error
+    if err3 != nil {
+        this.Error(ctx, "信息删除失败")
+        return
+    }
+
+    err4 := model.NewTenant(ctx).
+        Delete(&model.Tenant{
+            ID: id,
+        }).
+        Error
+    if err4 != nil {
+        this.Error(ctx, "信息删除失败")
+        return
+    }
+
+    // 删除权限数据
+    permission.New().DeleteDomains(tenant.Domain(id))
+
+    this.Success(ctx, "信息删除成功")
+}
+
+// 租户成员
+// @Summary 租户成员
+// @Description 设置租户的管理员，覆盖原有成员
+// @Tags 租户
+// @Accept  application/json
+// @Produce application/json
+// @Param id     path     string true "租户ID"
+// @Param admins formData string true "管理员ID列表，半角逗号分隔"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /tenant/{id}/admins [patch]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.tenant.admins"}
+func (this *Tenant) Admins(ctx *router.Context) {
+    id := ctx.Param("id")
+    if id == "" {
+        this.Error(ctx, "ID不能为空")
+        return
+    }
+
+    // 查询
+    result := map[string]any{}
+    err := model.NewTenant(ctx).
+        Where("id = ?", id).
+        First(&result).
+        Error
+    if err != nil || len(result) < 1 {
+        this.Error(ctx, "信息不存在")
+        return
+    }
+
+    // 接收数据
+    post := make(map[string]any)
+    this.ShouldBindJSON(ctx, &post)
+
+    if !this.CheckFieldPolicy(ctx, post) {
+        return
+    }
+
+    adminIds := make([]string, 0)
+    if admins := goch.ToString(post["admins"]); admins != "" {
+        adminIds = collection.
+            Collect(strings.Split(admins, ",")).
+            Unique().
+            ToStringArray()
+    }
+
+    // 只保留存在的账号
+    var existIds []string
+    if len(adminIds) > 0 {
+        model.NewAdmin(ctx).
+            Where("id in ?", adminIds).
+            Pluck("id", &existIds)
+    }
+
+    err2 := model.NewDB(ctx).Transaction(func(tx *gorm.DB) error {
+        err := tx.Where("tenant_id = ?", id).
+            Delete(&model.TenantAccess{}).
+            Error
+        if err != nil {
+            return err
+        }
+
+        if len(existIds) == 0 {
+            return nil
+        }
+
+        insertData := make([]model.TenantAccess, 0)
+        for _, adminId := range existIds {
+            insertData = append(insertData, model.TenantAccess{
+                AdminId: adminId,
+                TenantId: id,
+            })
+        }
+
+        return tx.Create(&insertData).Error
+    })
+    if err2 != nil {
+        this.Error(ctx, "设置成员失败")
+        return
+    }
+
+    this.Success(ctx, "设置成员成功")
+}
+
+// 标识是否已经存在
+func (this *Tenant) codeExists(code string, exceptId string) bool {
+    db := model.NewTenant().Where("code = ?", code)
+    if exceptId != "" {
+        db = db.Where("id != ?", exceptId)
+    }
+
+    var total int64
+    db.Count(&total)
+
+    return total > 0
+}
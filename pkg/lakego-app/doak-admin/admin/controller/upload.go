@@ -1,10 +1,13 @@
 package controller
 
 import (
+    "io"
     "strconv"
 
     "github.com/deatil/go-datebin/datebin"
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/database"
+    lakeUpload "github.com/deatil/lakego-doak/lakego/upload"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     "github.com/deatil/lakego-doak/lakego/facade/upload"
     "github.com/deatil/lakego-doak/lakego/facade/storage"
@@ -36,22 +39,10 @@ type Upload struct {
 // @Security Bearer
 // @x-lakego {"slug": "lakego-admin.upload.file"}
 func (this *Upload) File(ctx *router.Context) {
-    // 账号信息
-    adminInfo, _ := ctx.Get("admin")
-    adminId := adminInfo.(*admin.Admin).GetId()
-
     conf := config.New("admin")
 
-    // 上传目录
-    uploadDir := conf.GetString("Upload.Directory.File")
-
     // 上传文件类型
     uploadType := ctx.DefaultQuery("type", "file")
-    if uploadType == "image" {
-        uploadDir = conf.GetString("Upload.Directory.Image")
-    } else if uploadType == "media" {
-        uploadDir = conf.GetString("Upload.Directory.Media")
-    }
 
     file, err := ctx.FormFile(conf.GetString("Upload.Field"))
     if err != nil {
@@ -60,7 +51,7 @@ func (this *Upload) File(ctx *router.Context) {
     }
 
     // 设置目录
-    up := upload.New().WithDir(uploadDir)
+    up := upload.New().WithDir(this.uploadDirectory(uploadType))
 
     // 文件信息
     fileinfo := up.GetFileinfo()
@@ -68,16 +59,281 @@ func (this *Upload) File(ctx *router.Context) {
     // 设置文件流
     fileinfo = fileinfo.WithFile(file)
 
-    // 原始名称
-    name := fileinfo.GetOriginalFilename()
-    mimeType := fileinfo.GetMimeType()
-    extension := fileinfo.GetExtension()
-    size := fileinfo.GetSize()
-    md5 := fileinfo.GetMd5()
-    sha1 := fileinfo.GetSha1()
-
     // 关闭打开的文件
-    fileinfo.CloseFile()
+    defer fileinfo.CloseFile()
+
+    // 保存前校验
+    validateFile, err := fileinfo.GetValidateFile()
+    if err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    if err := up.Validate(validateFile); err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    // 文件摘要
+    hashes, err := fileinfo.GetHashes()
+    if err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    attachData := &model.Attachment{
+        Name: fileinfo.GetOriginalFilename(),
+        Mime: validateFile.Mime,
+        Extension: fileinfo.GetExtension(),
+        Size: strconv.FormatInt(fileinfo.GetSize(), 10),
+        Md5: hashes.Md5(),
+        Sha1: hashes.Sha1(),
+        Sha256: hashes.Sha256(),
+    }
+
+    this.saveAttachment(ctx, up, uploadType, attachData, func() string {
+        return up.SaveFile(file)
+    })
+}
+
+// 分片上传开始
+// @Summary 分片上传开始
+// @Description 分片上传开始，返回分片上传 id
+// @Tags 上传
+// @Accept  application/json
+// @Produce application/json
+// @Param filename   formData string true "文件名称"
+// @Param size       formData int    true "文件大小"
+// @Param chunk_size formData int    true "分片大小"
+// @Param md5        formData string true "文件 md5"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /upload/chunk [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.upload.chunk-init","sort":"177"}
+func (this *Upload) ChunkInit(ctx *router.Context) {
+    // 接收数据
+    var post struct {
+        Filename  string `json:"filename"`
+        Size      int64  `json:"size"`
+        ChunkSize int64  `json:"chunk_size"`
+        Md5       string `json:"md5"`
+    }
+    this.ShouldBindJSON(ctx, &post)
+
+    session, err := this.chunk(ctx).Init(post.Filename, post.Size, post.ChunkSize, post.Md5)
+    if err != nil {
+        this.Error(ctx, "分片上传创建失败，原因：" + err.Error())
+        return
+    }
+
+    this.SuccessWithData(ctx, "分片上传创建成功", router.H{
+        "id": session.ID,
+        "total": session.Total,
+        "chunk_size": session.ChunkSize,
+    })
+}
+
+// 分片上传状态
+// @Summary 分片上传状态
+// @Description 获取已经上传的分片序号，用于断点续传
+// @Tags 上传
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "分片上传 id"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /upload/chunk/{id} [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.upload.chunk-status","sort":"178"}
+func (this *Upload) ChunkStatus(ctx *router.Context) {
+    id := ctx.Param("id")
+
+    chunk := this.chunk(ctx)
+
+    session, err := chunk.Session(id)
+    if err != nil {
+        this.Error(ctx, "分片上传不存在")
+        return
+    }
+
+    uploaded, err := chunk.Uploaded(id)
+    if err != nil {
+        this.Error(ctx, "获取失败")
+        return
+    }
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "id": session.ID,
+        "total": session.Total,
+        "chunk_size": session.ChunkSize,
+        "uploaded": uploaded,
+    })
+}
+
+// 上传分片
+// @Summary 上传分片
+// @Description 上传分片，请求体为分片原始数据
+// @Tags 上传
+// @Accept  application/octet-stream
+// @Produce application/json
+// @Param id     path   string true "分片上传 id"
+// @Param index  query  int    true "分片序号，从 0 开始"
+// @Param offset query  int    true "分片在文件中的偏移"
+// @Param md5    query  string true "分片 md5"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /upload/chunk/{id} [put]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.upload.chunk-put","sort":"179"}
+func (this *Upload) ChunkPut(ctx *router.Context) {
+    id := ctx.Param("id")
+
+    index, err := strconv.Atoi(ctx.Query("index"))
+    if err != nil {
+        this.Error(ctx, "分片序号错误")
+        return
+    }
+
+    offset, err := strconv.ParseInt(ctx.Query("offset"), 10, 64)
+    if err != nil {
+        this.Error(ctx, "分片偏移错误")
+        return
+    }
+
+    _, err = this.chunk(ctx).Put(id, index, offset, ctx.Request.Body, ctx.Query("md5"))
+    if err != nil {
+        this.Error(ctx, "上传分片失败，原因：" + err.Error())
+        return
+    }
+
+    this.SuccessWithData(ctx, "上传分片成功", router.H{
+        "index": index,
+    })
+}
+
+// 分片上传完成
+// @Summary 分片上传完成
+// @Description 合并分片并校验文件
+// @Tags 上传
+// @Accept  application/json
+// @Produce application/json
+// @Param id   path  string true  "分片上传 id"
+// @Param type query string false "文件类型，可选数据：image | media | file。默认：file"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /upload/chunk/{id}/complete [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.upload.chunk-complete","sort":"180"}
+func (this *Upload) ChunkComplete(ctx *router.Context) {
+    id := ctx.Param("id")
+
+    // 上传文件类型
+    uploadType := ctx.DefaultQuery("type", "file")
+
+    chunk := this.chunk(ctx)
+
+    session, filePath, err := chunk.Complete(id)
+    if err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    // 合并完成后清除临时数据
+    defer chunk.Abort(id)
+
+    // 设置目录
+    up := upload.New().WithDir(this.uploadDirectory(uploadType))
+
+    // 合并后的文件保存前校验
+    stream, err := chunk.GetStorage().ReadStream(filePath)
+    if err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+    defer stream.Close()
+
+    validateFile, err := lakeUpload.NewValidateFile(session.Filename, session.Size, stream)
+    if err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    if err := up.Validate(validateFile); err != nil {
+        this.Error(ctx, "上传文件失败，原因：" + err.Error())
+        return
+    }
+
+    attachData := &model.Attachment{
+        Name: session.Filename,
+        Mime: validateFile.Mime,
+        Extension: session.Extension(),
+        Size: strconv.FormatInt(session.Size, 10),
+        Md5: session.Md5,
+        Sha1: session.Sha1,
+        Sha256: session.Sha256,
+    }
+
+    this.saveAttachment(ctx, up, uploadType, attachData, func() string {
+        // 从临时磁盘复制到上传磁盘
+        if _, err := stream.Seek(0, io.SeekStart); err != nil {
+            return ""
+        }
+
+        return up.SaveStream(stream, session.Filename)
+    })
+}
+
+// 取消分片上传
+// @Summary 取消分片上传
+// @Description 取消分片上传并删除已上传的分片
+// @Tags 上传
+// @Accept  application/json
+// @Produce application/json
+// @Param id path string true "分片上传 id"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /upload/chunk/{id} [delete]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.upload.chunk-abort","sort":"181"}
+func (this *Upload) ChunkAbort(ctx *router.Context) {
+    id := ctx.Param("id")
+
+    if err := this.chunk(ctx).Abort(id); err != nil {
+        this.Error(ctx, "取消分片上传失败")
+        return
+    }
+
+    this.Success(ctx, "取消分片上传成功")
+}
+
+// 分片上传，只能操作当前账号的分片
+func (this *Upload) chunk(ctx *router.Context) *lakeUpload.Chunk {
+    adminInfo, _ := ctx.Get("admin")
+    adminId := adminInfo.(*admin.Admin).GetId()
+
+    return upload.NewChunk().WithOwner(adminId)
+}
+
+// 上传目录
+func (this *Upload) uploadDirectory(uploadType string) string {
+    conf := config.New("admin")
+
+    if uploadType == "image" {
+        return conf.GetString("Upload.Directory.Image")
+    } else if uploadType == "media" {
+        return conf.GetString("Upload.Directory.Media")
+    }
+
+    return conf.GetString("Upload.Directory.File")
+}
+
+// 保存附件，同一账号在相同磁盘和上传类型中 sha256 相同时复用已有附件并增加引用计数
+func (this *Upload) saveAttachment(
+    ctx *router.Context,
+    up *lakeUpload.Upload,
+    uploadType string,
+    attachData *model.Attachment,
+    save func() string,
+) {
+    // 账号信息
+    adminInfo, _ := ctx.Get("admin")
+    adminId := adminInfo.(*admin.Admin).GetId()
 
     uploadDisk := storage.GetDefaultDisk()
 
@@ -86,101 +342,90 @@ func (this *Upload) File(ctx *router.Context) {
         driver = uploadDisk
     }
 
+    if uploadType != "image" && uploadType != "media" {
+        uploadType = "file"
+    }
+
     // 文件系统
     storager := up.GetStorage()
 
-    // 模型
-    adminModel := model.NewAdmin()
-    attachmentModel := model.NewAttachment()
-
-    attach := map[string]any{}
-    attachErr := attachmentModel.
-        Where("md5 = ?", md5).
-        First(&attach).
-        Error
-    if attachErr == nil && len(attach) > 0 {
-        attachUpdateErr := attachmentModel.
-            Where("md5 = ?", md5).
-            Updates(map[string]any{
-                "update_time": datebin.NowTimestamp(),
-            }).
-            Error
-        if attachUpdateErr != nil {
-            this.Error(ctx, "上传文件失败")
-            return
-        }
+    attachData.OwnerType = "admin"
+    attachData.OwnerID = adminId
+    attachData.Disk = driver
+    attachData.Type = uploadType
 
+    // 返回数据
+    response := func(id string, path string) {
         if uploadType == "image" || uploadType == "media" {
             this.SuccessWithData(ctx, "上传文件成功", router.H{
-                "id": attach["id"],
-                "url": storager.Url(attach["path"].(string)),
+                "id": id,
+                "url": storager.Url(path),
             })
             return
         }
 
         this.SuccessWithData(ctx, "上传文件成功", router.H{
-            "id": attach["id"],
+            "id": id,
         })
+    }
+
+    attach, err := model.ReuseAttachment(model.NewDB(ctx), attachData)
+    if err != nil {
+        this.Error(ctx, "上传文件失败")
+        return
+    }
+
+    if attach != nil {
+        response(attach.ID, attach.Path)
         return
     }
 
     // 获取当前账号信息
     var adminer model.Admin
-    adminFindErr := adminModel.
+    adminFindErr := model.NewAdmin(ctx).
         Where("id = ?", adminId).
         First(&adminer).
         Error
-    if adminFindErr != nil {
+    if adminFindErr != nil || adminer.ID == "" {
         this.Error(ctx, "上传文件失败")
         return
     }
 
     // 上传
-    path := up.SaveFile(file)
+    path := save()
     if path == "" {
         this.Error(ctx, "上传文件失败" )
         return
     }
 
     // 添加数据
-    attachData := &model.Attachment{
-        Name: name,
-        Path: path,
-        Mime: mimeType,
-        Extension: extension,
-        Size: strconv.FormatInt(size, 10),
-        Md5: md5,
-        Sha1: sha1,
-        Disk: driver,
-        Status: 1,
-        CreateTime: int(datebin.NowTimestamp()),
-        AddTime: int(datebin.NowTimestamp()),
-        AddIp: router.GetRequestIp(ctx),
-    }
-    addError := model.NewDB().
+    attachData.Path = path
+    attachData.Status = 1
+    attachData.RefCount = 1
+    attachData.CreateTime = int(datebin.NowTimestamp())
+    attachData.AddTime = int(datebin.NowTimestamp())
+    attachData.AddIp = router.GetRequestIp(ctx)
+
+    addError := model.NewDB(ctx).
         Model(&adminer).
         Association("Attachments").
         Append(attachData)
-    // 添加数据库失败
     if addError != nil {
         up.Destroy(path)
-        this.Error(ctx, "上传文件失败")
-        return
-    }
-
-    // 返回数据
-    data := router.H{
-        "id": attachData.ID,
-    }
-
-    if uploadType == "image" || uploadType == "media" {
-        url := storager.Url(path)
 
-        data = router.H{
-            "id": attachData.ID,
-            "url": url,
+        // 同时上传相同文件时唯一索引冲突，复用先保存的附件
+        if database.IsDuplicateKey(addError) {
+            attach, err := model.ReuseAttachment(model.NewDB(ctx), attachData)
+            if err == nil && attach != nil {
+                response(attach.ID, attach.Path)
+                return
+            }
         }
+
+        // 添加数据库失败
+        this.Error(ctx, "上传文件失败")
+        return
     }
 
-    this.SuccessWithData(ctx, "上传文件成功", data)
+    response(attachData.ID, path)
 }
@@ -8,6 +8,8 @@ import (
 
     "github.com/deatil/lakego-doak-admin/admin/auth/auth"
     "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
+    "github.com/deatil/lakego-doak-admin/admin/auth/apitoken"
     "github.com/deatil/lakego-doak-admin/admin/support/url"
     "github.com/deatil/lakego-doak-admin/admin/support/except"
     "github.com/deatil/lakego-doak-admin/admin/support/response"
@@ -47,22 +49,44 @@ func jwtCheck(ctx *router.Context) bool {
     // 授权 token
     accessToken := strings.TrimPrefix(authJwt, prefix)
 
-    aud := auth.GetJwtAud(ctx)
-    jwter := auth.NewWithAud(aud)
+    var userId, family string
 
-    // 解析 token
-    claims, err := jwter.GetAccessTokenClaims(accessToken)
-    if err != nil {
-        response.Error(ctx, "token 已过期", code.JwtAccessTokenFail)
-        return false
-    }
+    if apitoken.IsToken(accessToken) {
+        // 个人访问令牌
+        apiToken, err := apitoken.Check(accessToken, router.GetRequestIp(ctx))
+        if err != nil {
+            response.Error(ctx, "token 已失效", code.JwtAccessTokenFail)
+            return false
+        }
+
+        userId = apiToken.AdminId
+
+        ctx.Set("api_token", apiToken)
+    } else {
+        aud := auth.GetJwtAud(ctx)
+        jwter := auth.NewWithAud(aud)
+
+        // 解析 token
+        claims, err := jwter.GetAccessTokenClaims(accessToken)
+        if err != nil {
+            response.Error(ctx, "token 已过期", code.JwtAccessTokenFail)
+            return false
+        }
 
-    // 用户ID
-    userId := jwter.GetDataFromTokenClaims(claims, "id")
+        // 用户ID
+        userId = jwter.GetDataFromTokenClaims(claims, "id")
+
+        // 登录会话
+        family = jwter.GetDataFromTokenClaims(claims, "sid")
+        if !session.Check(userId, family, router.GetRequestIp(ctx)) {
+            response.Error(ctx, "token 已失效", code.JwtAccessTokenFail)
+            return false
+        }
+    }
 
     // 用户信息
     adminInfo := new(model.Admin)
-    modelErr := model.NewDB().
+    modelErr := model.NewDB(ctx).
         Where(&model.Admin{ID: userId}).
         Preload("Groups").
         First(&adminInfo).
@@ -92,8 +116,15 @@ func jwtCheck(ctx *router.Context) bool {
         return false
     }
 
+    // 必须先开启两步验证
+    if adminer.MustEnrollTwoFactor() && !shouldPassEnroll(ctx) {
+        response.Error(ctx, "请先开启两步验证", code.TwoFactorEnroll)
+        return false
+    }
+
     ctx.Set("admin_id", userId)
     ctx.Set("access_token", accessToken)
+    ctx.Set("session_family", family)
     ctx.Set("admin", adminer)
 
     return true
@@ -105,6 +136,10 @@ func shouldPassThrough(ctx *router.Context) bool {
     defaultExcepts := []string{
         "GET:passport/captcha",
         "POST:passport/login",
+        "POST:passport/login/2fa",
+        "GET:passport/providers",
+        "GET:passport/provider/*/redirect",
+        "POST:passport/provider/*/callback",
         "PUT:passport/refresh-token",
         "GET:attachment/download/*",
     }
@@ -134,3 +169,25 @@ func shouldPassThrough(ctx *router.Context) bool {
 
     return false
 }
+
+// 未开启两步验证时允许访问的路由
+func shouldPassEnroll(ctx *router.Context) bool {
+    excepts := []string{
+        "GET:profile",
+        "GET,POST,DELETE:profile/2fa*",
+        "DELETE:passport/logout",
+    }
+
+    urlPath := strings.Split(ctx.Request.URL.String(), "?")[0]
+
+    for _, ae := range excepts {
+        newStr := strings.SplitN(ae, ":", 2)
+
+        newUrl := newStr[0] + ":" + url.AdminUrl(newStr[1])
+        if url.MatchPath(ctx, newUrl, urlPath) {
+            return true
+        }
+    }
+
+    return false
+}
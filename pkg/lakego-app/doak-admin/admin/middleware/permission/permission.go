@@ -9,6 +9,8 @@ import (
     "github.com/deatil/lakego-doak/lakego/facade/permission"
 
     "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    "github.com/deatil/lakego-doak-admin/admin/auth/apitoken"
     "github.com/deatil/lakego-doak-admin/admin/support/url"
     "github.com/deatil/lakego-doak-admin/admin/support/except"
     "github.com/deatil/lakego-doak-admin/admin/support/response"
@@ -23,6 +25,11 @@ import (
  */
 func Handler() router.HandlerFunc {
     return func(ctx *router.Context) {
+        // 访问令牌只能访问授权的权限
+        if !apiTokenCheck(ctx) {
+            return
+        }
+
         // 权限检测
         if shouldPassThrough(ctx) || permissionCheck(ctx) {
             ctx.Next()
@@ -31,6 +38,21 @@ func Handler() router.HandlerFunc {
     }
 }
 
+// 访问令牌权限检测
+func apiTokenCheck(ctx *router.Context) bool {
+    token, ok := apitoken.FromContext(ctx)
+    if !ok {
+        return true
+    }
+
+    if !apitoken.Allow(ctx, token) {
+        response.Error(ctx, "访问令牌没有访问权限", code.AuthError)
+        return false
+    }
+
+    return true
+}
+
 // 权限检测
 func permissionCheck(ctx *router.Context) bool {
     if checkSuperAdmin(ctx) {
@@ -64,7 +86,7 @@ func permissionCheck(ctx *router.Context) bool {
     }
 
     c := permission.New()
-    ok2, err2 := c.Enforce(adminId.(string), newRequestPath, method)
+    ok2, err2 := c.EnforceInDomain(adminId.(string), tenant.CurrentDomain(ctx), newRequestPath, method)
 
     if err2 != nil {
         response.Error(ctx, "你没有访问权限", code.AuthError)
@@ -99,6 +121,11 @@ func shouldPassThrough(ctx *router.Context) bool {
     defaultExcepts := []string{
         "GET:passport/captcha",
         "POST:passport/login",
+        "POST:passport/login/2fa",
+        "GET:passport/providers",
+        "GET:passport/provider/*/redirect",
+        "POST:passport/provider/*/callback",
+        "GET,POST,DELETE:profile/2fa*",
         "DELETE:passport/logout",
         "PUT:passport/refresh-token",
         "GET:attachment/download/*",
@@ -0,0 +1,57 @@
+package tenant
+
+import (
+    "github.com/deatil/lakego-doak/lakego/router"
+
+    "github.com/deatil/lakego-doak-admin/admin/auth/admin"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+    "github.com/deatil/lakego-doak-admin/admin/support/response"
+    "github.com/deatil/lakego-doak-admin/admin/support/http/code"
+)
+
+/**
+ * 租户解析
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func Handler() router.HandlerFunc {
+    return func(ctx *router.Context) {
+        if tenantCheck(ctx) {
+            ctx.Next()
+        }
+    }
+}
+
+// 解析租户并检测账号是否属于该租户
+func tenantCheck(ctx *router.Context) bool {
+    info, err := tenant.Resolve(ctx)
+    if err != nil {
+        response.Error(ctx, "租户不存在或者已被禁用", code.TenantError)
+        return false
+    }
+
+    tenantId := ""
+    if info != nil {
+        tenantId = info.ID
+    }
+
+    // 登录后检测
+    if tenantId != "" {
+        if adminInfo, ok := ctx.Get("admin"); ok {
+            adminer := adminInfo.(*admin.Admin)
+
+            if !adminer.IsSuperAdministrator() && !tenant.IsMember(adminer.GetId(), tenantId) {
+                response.Error(ctx, "你不属于当前租户", code.TenantError)
+                return false
+            }
+
+            adminer.WithDomain(tenant.Domain(tenantId))
+        }
+    }
+
+    ctx.Set("tenant_id", tenantId)
+    ctx.Set("tenant_domain", tenant.Domain(tenantId))
+
+    return true
+}
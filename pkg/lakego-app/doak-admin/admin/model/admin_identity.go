@@ -0,0 +1,33 @@
+package model
+
+import (
+    "context"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+)
+
+// 管理员关联的外部账号
+type AdminIdentity struct {
+    ID         string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    AdminId    string `gorm:"column:admin_id;type:char(36);not null;index;" json:"admin_id"`
+    Provider   string `gorm:"column:provider;type:varchar(50);not null;uniqueIndex:idx_provider_subject;" json:"provider"`
+    Subject    string `gorm:"column:subject;type:varchar(191);not null;uniqueIndex:idx_provider_subject;" json:"subject"`
+    LastActive int    `gorm:"column:last_active;type:int(10);" json:"last_active"`
+    LastIp     string `gorm:"column:last_ip;type:varchar(50);" json:"last_ip"`
+    AddTime    int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
+    AddIp      string `gorm:"column:add_ip;type:varchar(50);" json:"add_ip"`
+
+    Admin Admin `gorm:"foreignKey:ID;references:AdminId"`
+}
+
+func (this *AdminIdentity) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewAdminIdentity(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AdminIdentity{})
+}
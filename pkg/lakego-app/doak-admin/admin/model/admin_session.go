@@ -0,0 +1,36 @@
+package model
+
+import (
+    "context"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+)
+
+// 登录会话
+type AdminSession struct {
+    ID          string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    AdminId     string `gorm:"column:admin_id;type:char(36);not null;index;" json:"admin_id"`
+    Family      string `gorm:"column:family;type:char(36);not null;uniqueIndex;" json:"-"`
+    RefreshId   string `gorm:"column:refresh_id;type:char(36);not null;" json:"-"`
+    Device      string `gorm:"column:device;type:varchar(100);" json:"device"`
+    Ip          string `gorm:"column:ip;type:varchar(50);" json:"ip"`
+    Useragent   string `gorm:"column:useragent;type:text;" json:"useragent"`
+    Status      int    `gorm:"column:status;not null;type:tinyint(1);" json:"status"`
+    LastActive  int    `gorm:"column:last_active;type:int(10);" json:"last_active"`
+    LastIp      string `gorm:"column:last_ip;type:varchar(50);" json:"last_ip"`
+    ExpiresTime int    `gorm:"column:expires_time;type:int(10);index;" json:"expires_time"`
+    RevokeTime  int    `gorm:"column:revoke_time;type:int(10);" json:"revoke_time"`
+    AddTime     int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
+}
+
+func (this *AdminSession) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewAdminSession(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AdminSession{})
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+    "context"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+)
+
+// 个人访问令牌
+type AdminToken struct {
+    ID           string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    AdminId      string `gorm:"column:admin_id;type:char(36);not null;index;" json:"admin_id"`
+    Name         string `gorm:"column:name;type:varchar(50);not null;" json:"name"`
+    Token        string `gorm:"column:token;type:char(64);not null;uniqueIndex;" json:"-"`
+    Hint         string `gorm:"column:hint;type:varchar(20);" json:"hint"`
+    Scopes       string `gorm:"column:scopes;type:text;" json:"scopes"`
+    Status       int    `gorm:"column:status;not null;type:tinyint(1);" json:"status"`
+    ExpiresTime  int    `gorm:"column:expires_time;type:int(10);" json:"expires_time"`
+    LastUsedTime int    `gorm:"column:last_used_time;type:int(10);" json:"last_used_time"`
+    LastUsedIp   string `gorm:"column:last_used_ip;type:varchar(50);" json:"last_used_ip"`
+    RevokeTime   int    `gorm:"column:revoke_time;type:int(10);" json:"revoke_time"`
+    AddTime      int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
+    AddIp        string `gorm:"column:add_ip;type:varchar(50);" json:"add_ip"`
+}
+
+func (this *AdminToken) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewAdminToken(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AdminToken{})
+}
@@ -1,8 +1,12 @@
 package model
 
 import (
+    "context"
+
     "gorm.io/gorm"
 
+    "github.com/deatil/go-datebin/datebin"
+
     "github.com/deatil/lakego-doak/lakego/uuid"
 
     "github.com/deatil/lakego-doak-admin/admin/support/url"
@@ -11,8 +15,8 @@ import (
 // 附件
 type Attachment struct {
     ID         string `gorm:"column:id;size:36;not null;index;" json:"id"`
-    OwnerType  string `gorm:"column:owner_type;size:50;not null;" json:"owner_type"`
-    OwnerID    string `gorm:"column:owner_id;size:36;" json:"owner_id"`
+    OwnerType  string `gorm:"column:owner_type;size:50;not null;uniqueIndex:idx_attachment_owner_sha256;" json:"owner_type"`
+    OwnerID    string `gorm:"column:owner_id;size:36;uniqueIndex:idx_attachment_owner_sha256;" json:"owner_id"`
     Name       string `gorm:"column:name;size:50;" json:"name"`
     Path       string `gorm:"column:path;size:255;" json:"path"`
     Mime       string `gorm:"column:mime;size:100;" json:"mime"`
@@ -20,7 +24,10 @@ type Attachment struct {
     Size       string `gorm:"column:size;size:100;" json:"size"`
     Md5        string `gorm:"column:md5;size:32;" json:"md5"`
     Sha1       string `gorm:"column:sha1;size:40;" json:"sha1"`
-    Disk       string `gorm:"column:disk;size:16;" json:"disk"`
+    Sha256     string `gorm:"column:sha256;size:64;uniqueIndex:idx_attachment_owner_sha256;" json:"sha256"`
+    RefCount   int    `gorm:"column:ref_count;not null;default:1;" json:"ref_count"`
+    Disk       string `gorm:"column:disk;size:16;uniqueIndex:idx_attachment_owner_sha256;" json:"disk"`
+    Type       string `gorm:"column:type;size:10;uniqueIndex:idx_attachment_owner_sha256;" json:"type"`
     Status     int    `gorm:"column:status;not null;size:1;" json:"status"`
     UpdateTime int    `gorm:"column:update_time;size:10;" json:"update_time"`
     CreateTime int    `gorm:"column:create_time;size:10;" json:"create_time"`
@@ -34,8 +41,42 @@ func (this *Attachment) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
-func NewAttachment() *gorm.DB {
-    return NewDB().Model(&Attachment{})
+func NewAttachment(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&Attachment{})
+}
+
+// 账号已上传的相同文件，增加引用计数后返回，没有时返回 nil
+// 只查找同一账号、磁盘和上传类型的附件，不会复用其他账号的文件
+func ReuseAttachment(db *gorm.DB, data *Attachment) (*Attachment, error) {
+    var attach *Attachment
+
+    err := db.Transaction(func(tx *gorm.DB) error {
+        info := new(Attachment)
+        err := tx.Model(&Attachment{}).
+            Where("owner_type = ? AND owner_id = ?", data.OwnerType, data.OwnerID).
+            Where("disk = ? AND type = ? AND sha256 = ?", data.Disk, data.Type, data.Sha256).
+            First(info).
+            Error
+        if err != nil || info.ID == "" {
+            return err
+        }
+
+        err = tx.Model(&Attachment{}).
+            Where("id = ?", info.ID).
+            Updates(map[string]any{
+                "ref_count": gorm.Expr("ref_count + ?", 1),
+                "update_time": datebin.NowTimestamp(),
+            }).
+            Error
+        if err != nil {
+            return err
+        }
+
+        attach = info
+        return nil
+    })
+
+    return attach, err
 }
 
 // 附件链接
@@ -1,6 +1,8 @@
 package model
 
 import (
+    "context"
+
     "gorm.io/gorm"
 
     "github.com/deatil/lakego-doak/lakego/uuid"
@@ -8,16 +10,19 @@ import (
 
 // 权限分组
 type AuthGroup struct {
-    ID          string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
-    Parentid    string `gorm:"column:parentid;type:char(36);not null;" json:"parentid"`
-    Title       string `gorm:"column:title;type:varchar(50);" json:"title"`
-    Description string `gorm:"column:description;type:varchar(80);" json:"description"`
-    Listorder   int    `gorm:"column:listorder;type:int(10);" json:"listorder"`
-    Status      int    `gorm:"column:status;not null;type:tinyint(1);" json:"status"`
-    UpdateTime  int    `gorm:"column:update_time;type:int(10);" json:"update_time"`
-    UpdateIp    string `gorm:"column:update_ip;type:varchar(50);" json:"update_ip"`
-    AddTime     int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
-    AddIp       string `gorm:"column:add_ip;type:varchar(50);" json:"add_ip"`
+    ID              string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    TenantId        string `gorm:"column:tenant_id;type:char(36);not null;default:'';index;" json:"tenant_id"`
+    Parentid        string `gorm:"column:parentid;type:char(36);not null;" json:"parentid"`
+    Title           string `gorm:"column:title;type:varchar(50);" json:"title"`
+    Description     string `gorm:"column:description;type:varchar(80);" json:"description"`
+    DataScope       string `gorm:"column:data_scope;type:varchar(30);not null;default:'all';" json:"data_scope"`
+    DataScopeGroups string `gorm:"column:data_scope_groups;type:text;" json:"data_scope_groups"`
+    Listorder       int    `gorm:"column:listorder;type:int(10);" json:"listorder"`
+    Status          int    `gorm:"column:status;not null;type:tinyint(1);" json:"status"`
+    UpdateTime      int    `gorm:"column:update_time;type:int(10);" json:"update_time"`
+    UpdateIp        string `gorm:"column:update_ip;type:varchar(50);" json:"update_ip"`
+    AddTime         int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
+    AddIp           string `gorm:"column:add_ip;type:varchar(50);" json:"add_ip"`
 
     Admins []Admin `gorm:"many2many:auth_group_access;foreignKey:ID;joinForeignKey:GroupId;References:ID;JoinReferences:AdminId"`
     Rules []AuthRule `gorm:"many2many:auth_rule_access;foreignKey:ID;joinForeignKey:GroupId;References:ID;JoinReferences:RuleId"`
@@ -32,6 +37,6 @@ func (this *AuthGroup) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
-func NewAuthGroup() *gorm.DB {
-    return NewDB().Model(&AuthGroup{})
+func NewAuthGroup(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AuthGroup{})
 }
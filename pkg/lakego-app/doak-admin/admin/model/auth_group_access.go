@@ -1,6 +1,8 @@
 package model
 
 import (
+    "context"
+
     "gorm.io/gorm"
 )
 
@@ -13,6 +15,6 @@ type AuthGroupAccess struct {
     Group AuthGroup `gorm:"foreignKey:ID;references:GroupId"`
 }
 
-func NewAuthGroupAccess() *gorm.DB {
-    return NewDB().Model(&AuthGroupAccess{})
+func NewAuthGroupAccess(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AuthGroupAccess{})
 }
@@ -1,11 +1,18 @@
 package model
 
 import (
+    "context"
+
     "gorm.io/gorm"
 
     "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/database"
 )
 
+// 权限规则缓存，规则变动后清除
+const AuthRuleCacheKey = "lakego-admin:auth-rules"
+
 // 菜单权限
 type AuthRule struct {
     ID          string `gorm:"column:id;size:36;not null;index;" json:"id"`
@@ -15,6 +22,7 @@ type AuthRule struct {
     Method      string `gorm:"column:method;not null;size:10;" json:"method"`
     Slug        string `gorm:"column:slug;not null;size:50;" json:"slug"`
     Description string `gorm:"column:description;" json:"description"`
+    FieldPolicy string `gorm:"column:field_policy;type:text;" json:"field_policy"`
     Listorder   int    `gorm:"column:listorder;size:10;" json:"listorder"`
     Status     	int    `gorm:"column:status;not null;" json:"status"`
     UpdateTime  int    `gorm:"column:update_time;size:10;" json:"update_time"`
@@ -31,6 +39,24 @@ func (this *AuthRule) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
-func NewAuthRule() *gorm.DB {
-    return NewDB().Model(&AuthRule{})
+// 事务提交后清除缓存，避免提交前读取到旧数据重新写入缓存
+func (this *AuthRule) AfterSave(tx *gorm.DB) error {
+    database.AfterCommit(tx, ClearAuthRuleCache)
+
+    return nil
+}
+
+func (this *AuthRule) AfterDelete(tx *gorm.DB) error {
+    database.AfterCommit(tx, ClearAuthRuleCache)
+
+    return nil
+}
+
+// 清除权限规则缓存
+func ClearAuthRuleCache() {
+    facade.Cache.Forget(AuthRuleCacheKey)
+}
+
+func NewAuthRule(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AuthRule{})
 }
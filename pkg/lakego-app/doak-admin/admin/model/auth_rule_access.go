@@ -1,6 +1,8 @@
 package model
 
 import (
+    "context"
+
     "gorm.io/gorm"
 )
 
@@ -13,6 +15,6 @@ type AuthRuleAccess struct {
     Group AuthGroup `gorm:"foreignKey:ID;references:GroupId"`
 }
 
-func NewAuthRuleAccess() *gorm.DB {
-    return NewDB().Model(&AuthRuleAccess{})
+func NewAuthRuleAccess(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&AuthRuleAccess{})
 }
@@ -0,0 +1,413 @@
+package model
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+    "github.com/deatil/lakego-doak/lakego/facade/crypt"
+    "github.com/deatil/lakego-doak/lakego/permission"
+)
+
+// 数据库迁移
+var Migrations = []migration.Migration{
+    {
+        Name: "2026_10_18_000001_add_sha256_to_attachment_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"Sha256", "RefCount", "Type"} {
+                if !m.HasColumn(&Attachment{}, column) {
+                    if err := m.AddColumn(&Attachment{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            // 同一账号、磁盘和上传类型的文件唯一
+            if !m.HasIndex(&Attachment{}, "idx_attachment_owner_sha256") {
+                if err := m.CreateIndex(&Attachment{}, "idx_attachment_owner_sha256"); err != nil {
+                    return err
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasIndex(&Attachment{}, "idx_attachment_owner_sha256") {
+                if err := m.DropIndex(&Attachment{}, "idx_attachment_owner_sha256"); err != nil {
+                    return err
+                }
+            }
+
+            for _, column := range []string{"Sha256", "RefCount", "Type"} {
+                if m.HasColumn(&Attachment{}, column) {
+                    if err := m.DropColumn(&Attachment{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000002_change_password_on_admin_table",
+        Up: func(db *gorm.DB) error {
+            return db.Migrator().AlterColumn(&Admin{}, "Password")
+        },
+        Down: func(db *gorm.DB) error {
+            // 回滚后新的加密方式生成的密码会被截断
+            type adminPassword struct {
+                Password string `gorm:"column:password;type:char(32);"`
+            }
+
+            // 使用账号表的表名
+            stmt := &gorm.Statement{DB: db}
+            if err := stmt.Parse(&Admin{}); err != nil {
+                return err
+            }
+
+            return db.Table(stmt.Table).Migrator().AlterColumn(&adminPassword{}, "Password")
+        },
+    },
+    {
+        Name: "2026_10_18_000003_add_totp_to_admin_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"TotpSecret", "TotpStatus", "TotpRecovery"} {
+                if !m.HasColumn(&Admin{}, column) {
+                    if err := m.AddColumn(&Admin{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"TotpSecret", "TotpStatus", "TotpRecovery"} {
+                if m.HasColumn(&Admin{}, column) {
+                    if err := m.DropColumn(&Admin{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000004_add_lockout_to_admin_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"LoginFailures", "LockedUntil"} {
+                if !m.HasColumn(&Admin{}, column) {
+                    if err := m.AddColumn(&Admin{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"LoginFailures", "LockedUntil"} {
+                if m.HasColumn(&Admin{}, column) {
+                    if err := m.DropColumn(&Admin{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000005_create_admin_session_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&AdminSession{}) {
+                return nil
+            }
+
+            return m.CreateTable(&AdminSession{})
+        },
+        Down: func(db *gorm.DB) error {
+            return db.Migrator().DropTable(&AdminSession{})
+        },
+    },
+    {
+        Name: "2026_10_18_000006_create_tenant_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, table := range []any{&Tenant{}, &TenantAccess{}} {
+                if !m.HasTable(table) {
+                    if err := m.CreateTable(table); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            if !m.HasColumn(&AuthGroup{}, "TenantId") {
+                if err := m.AddColumn(&AuthGroup{}, "TenantId"); err != nil {
+                    return err
+                }
+            }
+
+            if !m.HasIndex(&AuthGroup{}, "TenantId") {
+                if err := m.CreateIndex(&AuthGroup{}, "TenantId"); err != nil {
+                    return err
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasIndex(&AuthGroup{}, "TenantId") {
+                if err := m.DropIndex(&AuthGroup{}, "TenantId"); err != nil {
+                    return err
+                }
+            }
+
+            if m.HasColumn(&AuthGroup{}, "TenantId") {
+                if err := m.DropColumn(&AuthGroup{}, "TenantId"); err != nil {
+                    return err
+                }
+            }
+
+            return m.DropTable(&TenantAccess{}, &Tenant{})
+        },
+    },
+    {
+        Name: "2026_10_18_000007_add_data_scope_to_auth_group_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"DataScope", "DataScopeGroups"} {
+                if !m.HasColumn(&AuthGroup{}, column) {
+                    if err := m.AddColumn(&AuthGroup{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            for _, column := range []string{"DataScope", "DataScopeGroups"} {
+                if m.HasColumn(&AuthGroup{}, column) {
+                    if err := m.DropColumn(&AuthGroup{}, column); err != nil {
+                        return err
+                    }
+                }
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000008_add_field_policy_to_auth_rule_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasColumn(&AuthRule{}, "FieldPolicy") {
+                return m.AddColumn(&AuthRule{}, "FieldPolicy")
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasColumn(&AuthRule{}, "FieldPolicy") {
+                return m.DropColumn(&AuthRule{}, "FieldPolicy")
+            }
+
+            return nil
+        },
+    },
+    {
+        Name: "2026_10_18_000009_create_admin_identity_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&AdminIdentity{}) {
+                return nil
+            }
+
+            return m.CreateTable(&AdminIdentity{})
+        },
+        Down: func(db *gorm.DB) error {
+            return db.Migrator().DropTable(&AdminIdentity{})
+        },
+    },
+    {
+        Name: "2026_10_18_000010_create_admin_token_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&AdminToken{}) {
+                return nil
+            }
+
+            return m.CreateTable(&AdminToken{})
+        },
+        Down: func(db *gorm.DB) error {
+            return db.Migrator().DropTable(&AdminToken{})
+        },
+    },
+    {
+        Name: "2026_10_18_000011_move_rules_to_default_domain",
+        Up: func(db *gorm.DB) error {
+            table, err := rulesTable(db)
+            if err != nil {
+                return err
+            }
+
+            // 旧的规则没有域，移到默认域。mysql 按顺序赋值，需先更新后面的字段
+            // p = sub, obj, act -> p = sub, dom, obj, act
+            err = db.Exec(
+                "UPDATE " + table + " SET v3 = v2, v2 = v1, v1 = ? WHERE ptype = ? AND (v3 = ? OR v3 IS NULL)",
+                permission.DefaultDomain, "p", "",
+            ).Error
+            if err != nil {
+                return err
+            }
+
+            // g = _, _ -> g = _, _, _
+            return db.Exec(
+                "UPDATE " + table + " SET v2 = ? WHERE ptype = ? AND (v2 = ? OR v2 IS NULL)",
+                permission.DefaultDomain, "g", "",
+            ).Error
+        },
+        Down: func(db *gorm.DB) error {
+            table, err := rulesTable(db)
+            if err != nil {
+                return err
+            }
+
+            // 旧的规则没有域，只保留默认域的规则
+            err = db.Exec(
+                "DELETE FROM " + table + " WHERE (ptype = ? AND v1 != ?) OR (ptype = ? AND v2 != ?)",
+                "p", permission.DefaultDomain, "g", permission.DefaultDomain,
+            ).Error
+            if err != nil {
+                return err
+            }
+
+            err = db.Exec(
+                "UPDATE " + table + " SET v1 = v2, v2 = v3, v3 = ? WHERE ptype = ?",
+                "", "p",
+            ).Error
+            if err != nil {
+                return err
+            }
+
+            return db.Exec(
+                "UPDATE " + table + " SET v2 = ? WHERE ptype = ?",
+                "", "g",
+            ).Error
+        },
+    },
+    {
+        Name: "2026_10_18_000012_add_login_fail_time_to_admin_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasColumn(&Admin{}, "LoginFailTime") {
+                return m.AddColumn(&Admin{}, "LoginFailTime")
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasColumn(&Admin{}, "LoginFailTime") {
+                return m.DropColumn(&Admin{}, "LoginFailTime")
+            }
+
+            return nil
+        },
+    },    {
+        Name: "2026_10_18_000013_encrypt_totp_secret_in_admin_table",
+        Up: func(db *gorm.DB) error {
+            if err := db.Migrator().AlterColumn(&Admin{}, "TotpSecret"); err != nil {
+                return err
+            }
+
+            return convertTotpSecrets(db, crypt.Encrypt)
+        },
+        Down: func(db *gorm.DB) error {
+            if err := convertTotpSecrets(db, crypt.Decrypt); err != nil {
+                return err
+            }
+
+            // 回滚后密钥为明文，长度不超过 64
+            type adminTotp struct {
+                TotpSecret string `gorm:"column:totp_secret;type:varchar(64);"`
+            }
+
+            stmt := &gorm.Statement{DB: db}
+            if err := stmt.Parse(&Admin{}); err != nil {
+                return err
+            }
+
+            return db.Table(stmt.Table).Migrator().AlterColumn(&adminTotp{}, "TotpSecret")
+        },
+    },
+}
+
+// 转换已保存的两步验证密钥
+func convertTotpSecrets(db *gorm.DB, convert func(string) (string, error)) error {
+    var admins []Admin
+    err := db.Model(&Admin{}).
+        Select("id", "totp_secret").
+        Where("totp_secret != ?", "").
+        Find(&admins).
+        Error
+    if err != nil {
+        return err
+    }
+
+    for _, admin := range admins {
+        secret, err := convert(admin.TotpSecret)
+        if err != nil {
+            return err
+        }
+
+        err = db.Model(&Admin{}).
+            Where("id = ?", admin.ID).
+            Update("totp_secret", secret).
+            Error
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 权限规则表名
+func rulesTable(db *gorm.DB) (string, error) {
+    stmt := &gorm.Statement{DB: db}
+    if err := stmt.Parse(&Rules{}); err != nil {
+        return "", err
+    }
+
+    return stmt.Quote(stmt.Table), nil
+}
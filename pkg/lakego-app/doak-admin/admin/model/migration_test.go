@@ -0,0 +1,118 @@
+package model
+
+import (
+    "strings"
+    "testing"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+
+    "github.com/deatil/lakego-doak/lakego/permission"
+)
+
+func getMigration(name string) (up func(*gorm.DB) error, down func(*gorm.DB) error) {
+    for _, m := range Migrations {
+        if m.Name == name {
+            return m.Up, m.Down
+        }
+    }
+
+    return nil, nil
+}
+
+func Test_Migration_ChangePasswordDown(t *testing.T) {
+    eq := assertT(t)
+
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/migration.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = db.AutoMigrate(&Admin{}); err != nil {
+        t.Fatal(err)
+    }
+
+    _, down := getMigration("2026_10_18_000002_change_password_on_admin_table")
+    if down == nil {
+        t.Fatal("migration not found")
+    }
+
+    if err = down(db); err != nil {
+        t.Fatal(err)
+    }
+
+    // 只修改账号表
+    tables, _ := db.Migrator().GetTables()
+    for _, table := range tables {
+        if strings.Contains(table, "password") {
+            t.Errorf("Failed Down create table: %s", table)
+        }
+    }
+
+    columnType := ""
+    columns, _ := db.Migrator().ColumnTypes(&Admin{})
+    for _, column := range columns {
+        if column.Name() == "password" {
+            columnType, _ = column.ColumnType()
+        }
+    }
+
+    eq(strings.ToLower(columnType), "char(32)", "Down password column")
+}
+
+func Test_Migration_MoveRulesToDefaultDomain(t *testing.T) {
+    eq := assertT(t)
+
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/migration.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = db.AutoMigrate(&Rules{}); err != nil {
+        t.Fatal(err)
+    }
+
+    // 旧版没有域的规则
+    db.Create(&Rules{Ptype: "p", V0: "group-1", V1: "/admin/user", V2: "GET"})
+    db.Create(&Rules{Ptype: "g", V0: "user-1", V1: "group-1"})
+
+    up, down := getMigration("2026_10_18_000011_move_rules_to_default_domain")
+    if up == nil || down == nil {
+        t.Fatal("migration not found")
+    }
+
+    if err = up(db); err != nil {
+        t.Fatal(err)
+    }
+
+    ruleValues := func(ptype string) string {
+        var rule Rules
+        db.Where("ptype = ?", ptype).First(&rule)
+        return strings.Join([]string{rule.V0, rule.V1, rule.V2, rule.V3}, ",")
+    }
+
+    eq(ruleValues("p"), strings.Join([]string{"group-1", permission.DefaultDomain, "/admin/user", "GET"}, ","), "Up p")
+    eq(ruleValues("g"), strings.Join([]string{"user-1", "group-1", permission.DefaultDomain, ""}, ","), "Up g")
+
+    // 重复执行不修改已有域的规则
+    if err = up(db); err != nil {
+        t.Fatal(err)
+    }
+
+    eq(ruleValues("p"), strings.Join([]string{"group-1", permission.DefaultDomain, "/admin/user", "GET"}, ","), "Up again p")
+
+    // 其他域的规则回滚时删除
+    db.Create(&Rules{Ptype: "p", V0: "group-2", V1: "tenant-a", V2: "/admin/log", V3: "GET"})
+    db.Create(&Rules{Ptype: "g", V0: "user-2", V1: "group-2", V2: "tenant-a"})
+
+    if err = down(db); err != nil {
+        t.Fatal(err)
+    }
+
+    var count int64
+    db.Model(&Rules{}).Count(&count)
+    eq(count, int64(2), "Down count")
+
+    eq(ruleValues("p"), strings.Join([]string{"group-1", "/admin/user", "GET", ""}, ","), "Down p")
+    eq(ruleValues("g"), strings.Join([]string{"user-1", "group-1", "", ""}, ","), "Down g")
+}
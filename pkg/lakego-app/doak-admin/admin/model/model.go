@@ -1,6 +1,7 @@
 package model
 
 import (
+    "context"
     "encoding/json"
 
     "gorm.io/gorm"
@@ -14,11 +15,25 @@ type (
     Session = gorm.Session
 )
 
-// 创建一个 db 连接
-func NewDB() *gorm.DB {
+// 创建一个 db 连接，传入请求上下文时同一请求写入后的查询使用主库
+func NewDB(ctx ...context.Context) *gorm.DB {
+    if len(ctx) > 0 && ctx[0] != nil {
+        return facade.DB.WithContext(ctx[0])
+    }
+
     return facade.DB
 }
 
+// 创建一个带上下文的 db 连接
+func NewDBWithContext(ctx context.Context) *gorm.DB {
+    return NewDB(ctx)
+}
+
+// 创建一个命名 db 连接
+func NewDBConnection(name string) *gorm.DB {
+    return facade.DBConnection(name)
+}
+
 // 获取配置
 func GetConfig(key string, typ ...string) any {
     conf, _ := database.GetConfig(key, typ...)
@@ -0,0 +1,155 @@
+package model
+
+import (
+    "testing"
+    "net/http"
+    "net/http/httptest"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+    "gorm.io/plugin/dbresolver"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/database"
+    "github.com/deatil/lakego-doak/lakego/middleware/sticky"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+// 主从库，从库不同步主库数据
+func stickyDB(t *testing.T) *gorm.DB {
+    dir := t.TempDir()
+
+    db, err := gorm.Open(sqlite.Open(dir + "/source.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    replica, err := gorm.Open(sqlite.Open(dir + "/replica.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    db.AutoMigrate(&Rules{})
+    replica.AutoMigrate(&Rules{})
+
+    err = db.Use(dbresolver.Register(dbresolver.Config{
+        Replicas: []gorm.Dialector{sqlite.Open(dir + "/replica.db")},
+        Policy:   database.NewPolicy("round-robin"),
+    }))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = database.RegisterStickyCallbacks(db); err != nil {
+        t.Fatal(err)
+    }
+
+    return db
+}
+
+func Test_NewDB_Sticky(t *testing.T) {
+    eq := assertT(t)
+
+    old := facade.DB
+    facade.DB = stickyDB(t)
+    defer func() {
+        facade.DB = old
+    }()
+
+    gin.SetMode(gin.TestMode)
+
+    ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+    ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+    // 请求内执行
+    sticky.Handler()(ctx)
+
+    var count int64
+
+    // 写入前读从库
+    NewRules(ctx).Count(&count)
+    eq(count, int64(0), "read before write")
+
+    err := NewDB(ctx).Create(&Rules{Ptype: "p", V0: "a"}).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 同一请求写入后读主库
+    NewRules(ctx).Count(&count)
+    eq(count, int64(1), "read after write")
+
+    NewDBWithContext(ctx).Model(&Rules{}).Count(&count)
+    eq(count, int64(1), "NewDBWithContext read after write")
+
+    // 无请求上下文读从库
+    NewRules().Count(&count)
+    eq(count, int64(0), "read without context")
+}
+
+func Test_ReuseAttachment(t *testing.T) {
+    eq := assertT(t)
+
+    if err := NewDB().AutoMigrate(&Attachment{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        NewDB().Migrator().DropTable(&Attachment{})
+    })
+
+    newAttach := func(ownerId string, typ string) *Attachment {
+        return &Attachment{
+            OwnerType: "admin",
+            OwnerID:   ownerId,
+            Disk:      "local",
+            Type:      typ,
+            Sha256:    "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+            Path:      "images/" + ownerId + ".png",
+            RefCount:  1,
+        }
+    }
+
+    attach, err := ReuseAttachment(NewDB(), newAttach("admin-1", "image"))
+    eq(err, nil, "ReuseAttachment empty error")
+    eq(attach == nil, true, "ReuseAttachment empty")
+
+    saved := newAttach("admin-1", "image")
+    if err := NewDB().Create(saved).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    // 同一账号的相同文件复用
+    attach, err = ReuseAttachment(NewDB(), newAttach("admin-1", "image"))
+    eq(err, nil, "ReuseAttachment error")
+    if attach == nil {
+        t.Fatal("ReuseAttachment fail")
+    }
+    eq(attach.ID, saved.ID, "ReuseAttachment id")
+
+    var refCount int
+    NewAttachment().Where("id = ?", saved.ID).Select("ref_count").Scan(&refCount)
+    eq(refCount, 2, "ReuseAttachment ref_count")
+
+    // 其他账号和其他上传类型不复用
+    attach, _ = ReuseAttachment(NewDB(), newAttach("admin-2", "image"))
+    eq(attach == nil, true, "ReuseAttachment other admin")
+
+    attach, _ = ReuseAttachment(NewDB(), newAttach("admin-1", "file"))
+    eq(attach == nil, true, "ReuseAttachment other type")
+
+    // 唯一索引防止同时上传时重复添加
+    err = NewDB().Create(newAttach("admin-1", "image")).Error
+    eq(database.IsDuplicateKey(err), true, "Attachment unique index")
+
+    eq(NewDB().Create(newAttach("admin-2", "image")).Error, nil, "Attachment other admin")
+}
@@ -33,7 +33,8 @@ func AdminWithAccess(ctx *router.Context, gadb *gorm.DB, ids ...[]string) func(*
         gadb.Where("group_id in ?", newIds).
             Pluck("admin_id", &adminIds)
 
-        return db.Where("id in ?", adminIds)
+        return db.Where("id in ?", adminIds).
+            Scopes(WithDataScope(ctx, "id"))
     }
 }
 
@@ -0,0 +1,33 @@
+package scope
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak-admin/admin/auth/datascope"
+)
+
+// 数据范围，column 为数据所属账号的字段
+func WithDataScope(ctx *router.Context, column string) func(*gorm.DB) *gorm.DB {
+    return func(db *gorm.DB) *gorm.DB {
+        ds := datascope.FromContext(ctx)
+        if ds.All {
+            return db
+        }
+
+        return db.Where(column + " in ?", ds.AdminIds)
+    }
+}
+
+// 附件数据范围
+func AttachmentWithDataScope(ctx *router.Context) func(*gorm.DB) *gorm.DB {
+    return func(db *gorm.DB) *gorm.DB {
+        ds := datascope.FromContext(ctx)
+        if ds.All {
+            return db
+        }
+
+        return db.Where("owner_type = ?", "admin").
+            Where("owner_id in ?", ds.AdminIds)
+    }
+}
@@ -0,0 +1,15 @@
+package scope
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
+)
+
+// 当前租户的数据
+func WithTenant(ctx *router.Context) func(*gorm.DB) *gorm.DB {
+    return func(db *gorm.DB) *gorm.DB {
+        return db.Where("tenant_id = ?", tenant.Current(ctx))
+    }
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+    "context"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+)
+
+// 租户
+type Tenant struct {
+    ID          string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    Code        string `gorm:"column:code;type:varchar(50);not null;uniqueIndex;" json:"code"`
+    Title       string `gorm:"column:title;type:varchar(50);" json:"title"`
+    Description string `gorm:"column:description;type:varchar(80);" json:"description"`
+    Listorder   int    `gorm:"column:listorder;type:int(10);" json:"listorder"`
+    Status      int    `gorm:"column:status;not null;type:tinyint(1);" json:"status"`
+    UpdateTime  int    `gorm:"column:update_time;type:int(10);" json:"update_time"`
+    UpdateIp    string `gorm:"column:update_ip;type:varchar(50);" json:"update_ip"`
+    AddTime     int    `gorm:"column:add_time;type:int(10);" json:"add_time"`
+    AddIp       string `gorm:"column:add_ip;type:varchar(50);" json:"add_ip"`
+
+    Admins []Admin `gorm:"many2many:tenant_access;foreignKey:ID;joinForeignKey:TenantId;References:ID;JoinReferences:AdminId"`
+    Groups []AuthGroup `gorm:"foreignKey:TenantId;references:ID"`
+}
+
+func (this *Tenant) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewTenant(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&Tenant{})
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+    "context"
+
+    "gorm.io/gorm"
+)
+
+// 管理员所属租户
+type TenantAccess struct {
+    AdminId  string `gorm:"column:admin_id;type:char(36);not null;index;" json:"admin_id"`
+    TenantId string `gorm:"column:tenant_id;type:char(36);not null;index;" json:"tenant_id"`
+
+    Admin Admin `gorm:"foreignKey:ID;references:AdminId"`
+    Tenant Tenant `gorm:"foreignKey:ID;references:TenantId"`
+}
+
+func NewTenantAccess(ctx ...context.Context) *gorm.DB {
+    return NewDB(ctx...).Model(&TenantAccess{})
+}
@@ -0,0 +1,139 @@
+package hasher
+
+import (
+    "fmt"
+    "strconv"
+    "crypto/subtle"
+
+    "golang.org/x/crypto/argon2"
+)
+
+// argon2id
+func NewArgon2id(time, memory uint32, threads uint8, keyLen, saltLen uint32) *Argon2id {
+    return &Argon2id{
+        Time:    time,
+        Memory:  memory,
+        Threads: threads,
+        KeyLen:  keyLen,
+        SaltLen: saltLen,
+    }
+}
+
+// 默认 argon2id
+func DefaultArgon2id() *Argon2id {
+    return NewArgon2id(1, 64 * 1024, 2, 32, 16)
+}
+
+/**
+ * argon2id
+ *
+ * $argon2id$v=19$m=65536,t=1,p=2$salt$hash
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Argon2id struct {
+    Time    uint32
+    Memory  uint32
+    Threads uint8
+    KeyLen  uint32
+    SaltLen uint32
+}
+
+// 名称
+func (this *Argon2id) Name() string {
+    return "argon2id"
+}
+
+// 生成密码
+func (this *Argon2id) Make(password string) (string, string, error) {
+    if password == "" {
+        return "", "", ErrEmptyPassword
+    }
+
+    salt, err := generateSalt(int(this.SaltLen))
+    if err != nil {
+        return "", "", err
+    }
+
+    key := argon2.IDKey([]byte(password), salt, this.Time, this.Memory, this.Threads, this.KeyLen)
+
+    hash := fmt.Sprintf(
+        "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+        argon2.Version,
+        this.Memory, this.Time, this.Threads,
+        encodeB64(salt), encodeB64(key),
+    )
+
+    return hash, "", nil
+}
+
+// 检测密码
+func (this *Argon2id) Check(hash string, password string, _ string) bool {
+    opt, salt, key, err := this.parse(hash)
+    if err != nil {
+        return false
+    }
+
+    newKey := argon2.IDKey([]byte(password), salt, opt.Time, opt.Memory, opt.Threads, uint32(len(key)))
+
+    return subtle.ConstantTimeCompare(key, newKey) == 1
+}
+
+// 是否为当前方式生成的密码
+func (this *Argon2id) Match(hash string) bool {
+    _, _, _, err := this.parse(hash)
+    return err == nil
+}
+
+// 参数变化后需要重新生成
+func (this *Argon2id) NeedsRehash(hash string) bool {
+    opt, salt, key, err := this.parse(hash)
+    if err != nil {
+        return true
+    }
+
+    return opt.Time != this.Time ||
+        opt.Memory != this.Memory ||
+        opt.Threads != this.Threads ||
+        uint32(len(key)) != this.KeyLen ||
+        uint32(len(salt)) != this.SaltLen
+}
+
+// 解析，返回参数，盐和摘要
+func (this *Argon2id) parse(hash string) (*Argon2id, []byte, []byte, error) {
+    id, params, salt, key, err := parsePHC(hash)
+    if err != nil || id != "argon2id" {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    if params["v"] != strconv.Itoa(argon2.Version) {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    memory, err1 := strconv.ParseUint(params["m"], 10, 32)
+    time, err2 := strconv.ParseUint(params["t"], 10, 32)
+    threads, err3 := strconv.ParseUint(params["p"], 10, 8)
+    if err1 != nil || err2 != nil || err3 != nil ||
+        memory == 0 || time == 0 || threads == 0 {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    saltBytes, err := decodeB64(salt)
+    if err != nil || len(saltBytes) == 0 {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    keyBytes, err := decodeB64(key)
+    if err != nil || len(keyBytes) == 0 {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    opt := &Argon2id{
+        Time:    uint32(time),
+        Memory:  uint32(memory),
+        Threads: uint8(threads),
+    }
+
+    return opt, saltBytes, keyBytes, nil
+}
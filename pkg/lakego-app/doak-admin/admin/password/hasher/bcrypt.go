@@ -0,0 +1,70 @@
+package hasher
+
+import (
+    "golang.org/x/crypto/bcrypt"
+)
+
+// bcrypt
+func NewBcrypt(cost int) *Bcrypt {
+    if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+        cost = bcrypt.DefaultCost
+    }
+
+    return &Bcrypt{
+        Cost: cost,
+    }
+}
+
+/**
+ * bcrypt
+ *
+ * $2a$10$salthash
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Bcrypt struct {
+    Cost int
+}
+
+// 名称
+func (this *Bcrypt) Name() string {
+    return "bcrypt"
+}
+
+// 生成密码，密码最长 72 字节
+func (this *Bcrypt) Make(password string) (string, string, error) {
+    hash, err := bcrypt.GenerateFromPassword([]byte(password), this.Cost)
+    if err != nil {
+        return "", "", err
+    }
+
+    return string(hash), "", nil
+}
+
+// 检测密码
+func (this *Bcrypt) Check(hash string, password string, _ string) bool {
+    if !this.Match(hash) {
+        return false
+    }
+
+    err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+
+    return err == nil
+}
+
+// 是否为当前方式生成的密码
+func (this *Bcrypt) Match(hash string) bool {
+    _, err := bcrypt.Cost([]byte(hash))
+    return err == nil
+}
+
+// 参数变化后需要重新生成
+func (this *Bcrypt) NeedsRehash(hash string) bool {
+    cost, err := bcrypt.Cost([]byte(hash))
+    if err != nil {
+        return true
+    }
+
+    return cost != this.Cost
+}
@@ -0,0 +1,83 @@
+package hasher
+
+import (
+    "errors"
+    "strings"
+    "crypto/rand"
+    "encoding/base64"
+)
+
+// 密码格式错误
+var ErrInvalidHash = errors.New("hasher: invalid hash")
+
+// 密码为空
+var ErrEmptyPassword = errors.New("hasher: empty password")
+
+/**
+ * 密码加密接口
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Hasher interface {
+    // 名称
+    Name() string
+
+    // 生成密码，返回密码和盐，盐为空时表示已包含在密码中
+    Make(password string) (string, string, error)
+
+    // 检测密码
+    Check(hash string, password string, salt string) bool
+
+    // 是否为当前方式生成的密码
+    Match(hash string) bool
+
+    // 参数变化后需要重新生成
+    NeedsRehash(hash string) bool
+}
+
+// 随机盐
+func generateSalt(size int) ([]byte, error) {
+    salt := make([]byte, size)
+    if _, err := rand.Read(salt); err != nil {
+        return nil, err
+    }
+
+    return salt, nil
+}
+
+// PHC 格式编码
+func encodeB64(data []byte) string {
+    return base64.RawStdEncoding.EncodeToString(data)
+}
+
+// PHC 格式解码
+func decodeB64(data string) ([]byte, error) {
+    return base64.RawStdEncoding.DecodeString(data)
+}
+
+// 解析 PHC 格式，$id$params$salt$hash
+// 返回 id, 参数, 盐和摘要
+func parsePHC(hash string) (string, map[string]string, string, string, error) {
+    parts := strings.Split(hash, "$")
+    if len(parts) < 5 || parts[0] != "" {
+        return "", nil, "", "", ErrInvalidHash
+    }
+
+    id := parts[1]
+
+    // 可能有多段参数，例如 $id$v=1$a=1,b=2$salt$hash
+    params := map[string]string{}
+    for _, part := range parts[2:len(parts) - 2] {
+        for _, kv := range strings.Split(part, ",") {
+            k, v, ok := strings.Cut(kv, "=")
+            if !ok {
+                return "", nil, "", "", ErrInvalidHash
+            }
+
+            params[k] = v
+        }
+    }
+
+    return id, params, parts[len(parts) - 2], parts[len(parts) - 1], nil
+}
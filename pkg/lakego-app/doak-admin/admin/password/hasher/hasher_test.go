@@ -0,0 +1,165 @@
+package hasher
+
+import (
+    "strings"
+    "testing"
+    "reflect"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_ParsePHC(t *testing.T) {
+    eq := assertT(t)
+
+    id, params, salt, key, err := parsePHC("$pbkdf2-sha256$i=1000,l=32$c2FsdA$a2V5")
+    eq(err, nil, "parsePHC error")
+    eq(id, "pbkdf2-sha256", "parsePHC id")
+    eq(params, map[string]string{"i": "1000", "l": "32"}, "parsePHC params")
+    eq(salt, "c2FsdA", "parsePHC salt")
+    eq(key, "a2V5", "parsePHC key")
+
+    // 多段参数
+    id, params, _, _, err = parsePHC("$test$v=19$m=65536,t=1,p=2$c2FsdA$a2V5")
+    eq(err, nil, "parsePHC multi error")
+    eq(id, "test", "parsePHC multi id")
+    eq(params, map[string]string{"v": "19", "m": "65536", "t": "1", "p": "2"}, "parsePHC multi params")
+
+    for _, hash := range []string{
+        "",
+        "pbkdf2-sha256$i=1000$c2FsdA$a2V5",
+        "$pbkdf2-sha256$c2FsdA$a2V5",
+        "$pbkdf2-sha256$i1000$c2FsdA$a2V5",
+        "e10adc3949ba59abbe56e057f20f883e",
+    } {
+        _, _, _, _, err = parsePHC(hash)
+        eq(err, ErrInvalidHash, "parsePHC invalid " + hash)
+    }
+
+    data := []byte{0xff, 0x00, 0x10}
+    decoded, err := decodeB64(encodeB64(data))
+    eq(err, nil, "decodeB64 error")
+    eq(decoded, data, "encodeB64 round trip")
+    eq(strings.Contains(encodeB64(data), "="), false, "encodeB64 no padding")
+}
+
+func Test_Hashers(t *testing.T) {
+    eq := assertT(t)
+
+    hashers := []Hasher{
+        NewArgon2id(1, 8 * 1024, 1, 32, 16),
+        NewBcrypt(4),
+        NewPbkdf2("sha256", 1000, 32, 16),
+        NewMd5("global-salt"),
+    }
+
+    for _, h := range hashers {
+        t.Run(h.Name(), func(t *testing.T) {
+            hash, salt, err := h.Make("123456")
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            eq(h.Match(hash), true, "Match")
+            eq(h.NeedsRehash(hash), false, "NeedsRehash")
+            eq(h.Check(hash, "123456", salt), true, "Check")
+            eq(h.Check(hash, "1234567", salt), false, "Check wrong password")
+
+            // 其他方式生成的密码不匹配
+            for _, other := range hashers {
+                if other.Name() != h.Name() {
+                    eq(other.Match(hash), false, "Match " + other.Name())
+                    eq(other.Check(hash, "123456", salt), false, "Check " + other.Name())
+                }
+            }
+        })
+    }
+}
+
+func Test_Argon2id_NeedsRehash(t *testing.T) {
+    eq := assertT(t)
+
+    h := NewArgon2id(1, 8 * 1024, 1, 32, 16)
+
+    hash, _, err := h.Make("123456")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(strings.HasPrefix(hash, "$argon2id$v=19$m=8192,t=1,p=1$"), true, "Make format")
+
+    eq(NewArgon2id(2, 8 * 1024, 1, 32, 16).NeedsRehash(hash), true, "NeedsRehash time")
+    eq(NewArgon2id(1, 16 * 1024, 1, 32, 16).NeedsRehash(hash), true, "NeedsRehash memory")
+    eq(NewArgon2id(1, 8 * 1024, 2, 32, 16).NeedsRehash(hash), true, "NeedsRehash threads")
+    eq(NewArgon2id(1, 8 * 1024, 1, 64, 16).NeedsRehash(hash), true, "NeedsRehash key-len")
+    eq(NewArgon2id(1, 8 * 1024, 1, 32, 32).NeedsRehash(hash), true, "NeedsRehash salt-len")
+
+    // 参数修改后仍然可以检测
+    eq(NewArgon2id(2, 16 * 1024, 2, 64, 32).Check(hash, "123456", ""), true, "Check with other params")
+
+    eq(h.Match("$argon2i$v=19$m=8192,t=1,p=1$c2FsdA$a2V5"), false, "Match argon2i")
+    eq(h.Match("$argon2id$v=16$m=8192,t=1,p=1$c2FsdA$a2V5"), false, "Match version")
+    eq(h.Match("$argon2id$v=19$m=8192,t=1$c2FsdA$a2V5"), false, "Match params")
+    eq(h.Match("$argon2id$v=19$m=8192,t=1,p=1$c2FsdA$a2V5"), true, "Match")
+
+    _, _, err = h.Make("")
+    eq(err, ErrEmptyPassword, "Make empty")
+}
+
+func Test_Bcrypt_NeedsRehash(t *testing.T) {
+    eq := assertT(t)
+
+    h := NewBcrypt(4)
+
+    hash, _, err := h.Make("123456")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(strings.HasPrefix(hash, "$2a$04$"), true, "Make format")
+    eq(NewBcrypt(5).NeedsRehash(hash), true, "NeedsRehash cost")
+    eq(NewBcrypt(5).Check(hash, "123456", ""), true, "Check with other cost")
+    eq(NewBcrypt(0).Cost, 10, "NewBcrypt default cost")
+}
+
+func Test_Pbkdf2_NeedsRehash(t *testing.T) {
+    eq := assertT(t)
+
+    h := NewPbkdf2("sha256", 1000, 32, 16)
+
+    hash, _, err := h.Make("123456")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(NewPbkdf2("sha512", 1000, 32, 16).NeedsRehash(hash), true, "NeedsRehash digest")
+    eq(NewPbkdf2("sha256", 2000, 32, 16).NeedsRehash(hash), true, "NeedsRehash iterations")
+    eq(NewPbkdf2("sha256", 1000, 64, 16).NeedsRehash(hash), true, "NeedsRehash key-len")
+    eq(NewPbkdf2("sha256", 1000, 32, 32).NeedsRehash(hash), true, "NeedsRehash salt-len")
+    eq(NewPbkdf2("md4", 1000, 32, 16).Digest, "sha256", "NewPbkdf2 default digest")
+}
+
+func Test_Md5(t *testing.T) {
+    eq := assertT(t)
+
+    h := NewMd5("e6c2ea864004a461e744b28a394df50c")
+
+    // md5(md5("123456" + "abcdef") + globalSalt)
+    hash := md5Hex(md5Hex("123456" + "abcdef") + "e6c2ea864004a461e744b28a394df50c")
+
+    eq(h.Encrypt("123456", "abcdef"), hash, "Encrypt")
+    eq(h.Match(hash), true, "Match")
+    eq(h.Check(hash, "123456", "abcdef"), true, "Check")
+    eq(h.Check(hash, "123456", "abcdeg"), false, "Check wrong salt")
+    eq(NewMd5("other").Check(hash, "123456", "abcdef"), false, "Check wrong global salt")
+
+    eq(h.Match(strings.ToUpper(hash)), false, "Match upper")
+    eq(h.Match(hash[:31]), false, "Match length")
+    eq(h.NeedsRehash(hash), false, "NeedsRehash")
+    eq(h.NeedsRehash("$pbkdf2-sha256$i=1000,l=32$c2FsdA$a2V5"), true, "NeedsRehash other")
+}
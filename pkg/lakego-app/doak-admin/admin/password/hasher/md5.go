@@ -0,0 +1,75 @@
+package hasher
+
+import (
+    "fmt"
+    "crypto/md5"
+    "crypto/subtle"
+
+    "github.com/deatil/lakego-doak/lakego/random"
+)
+
+// 旧版 md5
+func NewMd5(globalSalt string) *Md5 {
+    return &Md5{
+        GlobalSalt: globalSalt,
+    }
+}
+
+/**
+ * 旧版 md5，md5(md5(password + salt) + globalSalt)
+ * 盐单独保存，只用于兼容旧密码
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Md5 struct {
+    GlobalSalt string
+}
+
+// 名称
+func (this *Md5) Name() string {
+    return "md5"
+}
+
+// 生成密码
+func (this *Md5) Make(password string) (string, string, error) {
+    salt := random.String(6)
+
+    return this.Encrypt(password, salt), salt, nil
+}
+
+// 检测密码
+func (this *Md5) Check(hash string, password string, salt string) bool {
+    newHash := this.Encrypt(password, salt)
+
+    return subtle.ConstantTimeCompare([]byte(hash), []byte(newHash)) == 1
+}
+
+// 是否为当前方式生成的密码
+func (this *Md5) Match(hash string) bool {
+    if len(hash) != 32 {
+        return false
+    }
+
+    for _, c := range hash {
+        if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+            return false
+        }
+    }
+
+    return true
+}
+
+// 参数变化后需要重新生成
+func (this *Md5) NeedsRehash(hash string) bool {
+    return !this.Match(hash)
+}
+
+// 加密
+func (this *Md5) Encrypt(password string, salt string) string {
+    return md5Hex(md5Hex(password + salt) + this.GlobalSalt)
+}
+
+func md5Hex(data string) string {
+    return fmt.Sprintf("%x", md5.Sum([]byte(data)))
+}
@@ -0,0 +1,146 @@
+package hasher
+
+import (
+    "fmt"
+    "hash"
+    "strconv"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "crypto/subtle"
+
+    "github.com/deatil/go-cryptobin/kdf/pbkdf2"
+)
+
+// 支持的摘要方式
+var pbkdf2Hashes = map[string]func() hash.Hash{
+    "sha1":   sha1.New,
+    "sha256": sha256.New,
+    "sha512": sha512.New,
+}
+
+// pbkdf2
+func NewPbkdf2(digest string, iterations, keyLen, saltLen int) *Pbkdf2 {
+    if _, ok := pbkdf2Hashes[digest]; !ok {
+        digest = "sha256"
+    }
+
+    return &Pbkdf2{
+        Digest:     digest,
+        Iterations: iterations,
+        KeyLen:     keyLen,
+        SaltLen:    saltLen,
+    }
+}
+
+/**
+ * pbkdf2
+ *
+ * $pbkdf2-sha256$i=310000,l=32$salt$hash
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Pbkdf2 struct {
+    Digest     string
+    Iterations int
+    KeyLen     int
+    SaltLen    int
+}
+
+// 名称
+func (this *Pbkdf2) Name() string {
+    return "pbkdf2"
+}
+
+// 生成密码
+func (this *Pbkdf2) Make(password string) (string, string, error) {
+    salt, err := generateSalt(this.SaltLen)
+    if err != nil {
+        return "", "", err
+    }
+
+    key := this.key(this.Digest, password, salt, this.Iterations, this.KeyLen)
+
+    hash := fmt.Sprintf(
+        "$pbkdf2-%s$i=%d,l=%d$%s$%s",
+        this.Digest,
+        this.Iterations, this.KeyLen,
+        encodeB64(salt), encodeB64(key),
+    )
+
+    return hash, "", nil
+}
+
+// 检测密码
+func (this *Pbkdf2) Check(hash string, password string, _ string) bool {
+    opt, salt, key, err := this.parse(hash)
+    if err != nil {
+        return false
+    }
+
+    newKey := this.key(opt.Digest, password, salt, opt.Iterations, len(key))
+
+    return subtle.ConstantTimeCompare(key, newKey) == 1
+}
+
+// 是否为当前方式生成的密码
+func (this *Pbkdf2) Match(hash string) bool {
+    _, _, _, err := this.parse(hash)
+    return err == nil
+}
+
+// 参数变化后需要重新生成
+func (this *Pbkdf2) NeedsRehash(hash string) bool {
+    opt, salt, key, err := this.parse(hash)
+    if err != nil {
+        return true
+    }
+
+    return opt.Digest != this.Digest ||
+        opt.Iterations != this.Iterations ||
+        len(key) != this.KeyLen ||
+        len(salt) != this.SaltLen
+}
+
+// 生成 key
+func (this *Pbkdf2) key(digest string, password string, salt []byte, iterations int, keyLen int) []byte {
+    prf := pbkdf2.NewHmacPRF(pbkdf2Hashes[digest])
+
+    return pbkdf2.Key([]byte(password), salt, iterations, keyLen, prf)
+}
+
+// 解析
+func (this *Pbkdf2) parse(hash string) (*Pbkdf2, []byte, []byte, error) {
+    id, params, salt, key, err := parsePHC(hash)
+    if err != nil || len(id) < 7 || id[:7] != "pbkdf2-" {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    digest := id[7:]
+    if _, ok := pbkdf2Hashes[digest]; !ok {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    iterations, err := strconv.Atoi(params["i"])
+    if err != nil || iterations <= 0 {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    saltBytes, err := decodeB64(salt)
+    if err != nil {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    keyBytes, err := decodeB64(key)
+    if err != nil || len(keyBytes) == 0 {
+        return nil, nil, nil, ErrInvalidHash
+    }
+
+    opt := &Pbkdf2{
+        Digest:     digest,
+        Iterations: iterations,
+    }
+
+    return opt, saltBytes, keyBytes, nil
+}
@@ -1,36 +1,116 @@
 package password
 
 import (
-    "github.com/deatil/lakego-doak/lakego/random"
     "github.com/deatil/lakego-doak/lakego/facade/config"
 
-    "github.com/deatil/lakego-doak-admin/admin/support/utils"
+    "github.com/deatil/lakego-doak-admin/admin/password/hasher"
 )
 
-// 生成密码，密码为 MD5 加密后
-func MakePassword(password string) (string, string) {
-    return EncryptPassword(password)
+// 生成密码，返回密码和盐，新的加密方式盐已包含在密码中
+func MakePassword(password string) (string, string, error) {
+    return GetHasher().Make(password)
 }
 
-// 检测密码
+// 检测密码，根据密码格式选择加密方式
 func CheckPassword(password string, needPassword string, needSalt string) bool {
-    encryptPassword := EncryptPasswordWithEncrypt(needPassword, needSalt)
-    return password == encryptPassword
+    for _, h := range GetHashers() {
+        if h.Match(password) {
+            return h.Check(password, needPassword, needSalt)
+        }
+    }
+
+    return false
 }
 
-// 生成密码
+// 密码需要使用当前加密方式重新生成
+func NeedsRehash(password string) bool {
+    h := GetHasher()
+
+    return !h.Match(password) || h.NeedsRehash(password)
+}
+
+// 生成密码，旧版 md5 方式
 func EncryptPassword(password string) (pass string, encrypt string) {
-    encrypt = random.String(6)
-    pass = EncryptPasswordWithEncrypt(password, encrypt);
+    pass, encrypt, _ = hasher.NewMd5(GetPasswordSalt()).Make(password)
     return
 }
 
-// 密码加密
+// 密码加密，旧版 md5 方式
 func EncryptPasswordWithEncrypt(password string, encrypt string) string {
-    return utils.MD5(utils.MD5(password + encrypt) + GetPasswordSalt())
+    return hasher.NewMd5(GetPasswordSalt()).Encrypt(password, encrypt)
 }
 
 // 密码通用盐
 func GetPasswordSalt() string {
     return config.New("auth").GetString("passport.password-salt")
 }
+
+// 当前加密方式
+func GetHasher() hasher.Hasher {
+    conf := config.New("auth")
+
+    name := conf.GetString("passport.password-hasher")
+
+    return NewHasher(name)
+}
+
+// 全部加密方式，用于检测密码
+func GetHashers() []hasher.Hasher {
+    return []hasher.Hasher{
+        NewHasher("argon2id"),
+        NewHasher("bcrypt"),
+        NewHasher("pbkdf2"),
+        NewHasher("md5"),
+    }
+}
+
+// 加密方式
+func NewHasher(name string) hasher.Hasher {
+    conf := config.New("auth")
+
+    prefix := "passport.password-hashers." + name + "."
+
+    switch name {
+        case "bcrypt":
+            return hasher.NewBcrypt(conf.GetInt(prefix + "cost"))
+        case "pbkdf2":
+            h := hasher.NewPbkdf2(
+                conf.GetString(prefix + "digest"),
+                conf.GetInt(prefix + "iterations"),
+                conf.GetInt(prefix + "key-len"),
+                conf.GetInt(prefix + "salt-len"),
+            )
+            if h.Iterations <= 0 {
+                h.Iterations = 310000
+            }
+            if h.KeyLen <= 0 {
+                h.KeyLen = 32
+            }
+            if h.SaltLen <= 0 {
+                h.SaltLen = 16
+            }
+
+            return h
+        case "md5":
+            return hasher.NewMd5(GetPasswordSalt())
+    }
+
+    h := hasher.DefaultArgon2id()
+    if time := conf.GetUint32(prefix + "time"); time > 0 {
+        h.Time = time
+    }
+    if memory := conf.GetUint32(prefix + "memory"); memory > 0 {
+        h.Memory = memory
+    }
+    if threads := conf.GetUint(prefix + "threads"); threads > 0 {
+        h.Threads = uint8(threads)
+    }
+    if keyLen := conf.GetUint32(prefix + "key-len"); keyLen > 0 {
+        h.KeyLen = keyLen
+    }
+    if saltLen := conf.GetUint32(prefix + "salt-len"); saltLen > 0 {
+        h.SaltLen = saltLen
+    }
+
+    return h
+}
@@ -0,0 +1,68 @@
+package password
+
+import (
+    "testing"
+
+    "github.com/deatil/lakego-doak-admin/admin/password/hasher"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_MakePassword(t *testing.T) {
+    eq := assertT(t)
+
+    pass, salt, err := MakePassword("123456")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(GetHasher().Name(), "argon2id", "GetHasher")
+    eq(salt, "", "MakePassword salt")
+    eq(CheckPassword(pass, "123456", salt), true, "CheckPassword")
+    eq(CheckPassword(pass, "654321", salt), false, "CheckPassword wrong")
+    eq(NeedsRehash(pass), false, "NeedsRehash")
+
+    _, _, err = MakePassword("")
+    eq(err != nil, true, "MakePassword empty")
+}
+
+func Test_LegacyMd5(t *testing.T) {
+    eq := assertT(t)
+
+    pass, salt := EncryptPassword("123456")
+
+    eq(len(salt), 6, "EncryptPassword salt")
+    eq(EncryptPasswordWithEncrypt("123456", salt), pass, "EncryptPasswordWithEncrypt")
+
+    // 旧密码可以登录，登录后需要重新生成
+    eq(CheckPassword(pass, "123456", salt), true, "CheckPassword md5")
+    eq(CheckPassword(pass, "123456", "other"), false, "CheckPassword md5 salt")
+    eq(NeedsRehash(pass), true, "NeedsRehash md5")
+}
+
+func Test_NeedsRehash(t *testing.T) {
+    eq := assertT(t)
+
+    for _, name := range []string{"bcrypt", "pbkdf2"} {
+        pass, salt, err := NewHasher(name).Make("123456")
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        eq(CheckPassword(pass, "123456", salt), true, "CheckPassword " + name)
+        eq(NeedsRehash(pass), true, "NeedsRehash " + name)
+    }
+
+    // 参数变化
+    pass, _, _ := hasher.NewArgon2id(2, 8192, 1, 32, 16).Make("123456")
+    eq(CheckPassword(pass, "123456", ""), true, "CheckPassword argon2id params")
+    eq(NeedsRehash(pass), true, "NeedsRehash argon2id params")
+
+    eq(CheckPassword("not-a-hash", "123456", ""), false, "CheckPassword invalid")
+}
@@ -2,60 +2,99 @@ package permission
 
 import (
     "github.com/deatil/lakego-doak/lakego/facade/permission"
+    lakegoPermission "github.com/deatil/lakego-doak/lakego/permission"
 
     "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/auth/tenant"
 )
 
 /**
- * 重设权限
+ * 重设权限，传入租户 id 时只重设对应租户的权限
+ * 默认租户的 id 为空字符
  *
  * @create 2021-9-25
  * @author deatil
  */
-func ResetPermission() bool {
-    // 清空原始数据
-    model.ClearRulesData()
+func ResetPermission(tenantIds ...string) bool {
+    c := permission.New()
+
+    if len(tenantIds) == 0 {
+        // 清空原始数据
+        if err := model.ClearRulesData(); err != nil {
+            return false
+        }
+
+        // 全部租户
+        tenantIds = []string{""}
+
+        var ids []string
+        err := model.NewTenant().Pluck("id", &ids).Error
+        if err != nil {
+            return false
+        }
+
+        tenantIds = append(tenantIds, ids...)
+    } else {
+        for _, tenantId := range tenantIds {
+            if _, err := c.DeleteDomains(tenant.Domain(tenantId)); err != nil {
+                return false
+            }
+        }
+    }
+
+    for _, tenantId := range tenantIds {
+        if !resetTenantPermission(c, tenantId) {
+            return false
+        }
+    }
+
+    return true
+}
+
+// 重设租户权限
+func resetTenantPermission(c *lakegoPermission.Permission, tenantId string) bool {
+    domain := tenant.Domain(tenantId)
+
+    // 租户下启用的分组
+    groupDB := model.NewAuthGroup().
+        Select("id").
+        Where("tenant_id = ?", tenantId).
+        Where("status = ?", 1)
 
     // 权限
     ruleList := make([]model.AuthRuleAccess, 0)
     err := model.NewAuthRuleAccess().
         Preload("Rule", "status = ?", 1).
+        Where("group_id in (?)", groupDB).
         Find(&ruleList).
         Error
     if err != nil {
         return false
     }
 
-    ruleListMap := model.FormatStructToArrayMap(ruleList)
-
     // 分组
     groupList := make([]model.AuthGroupAccess, 0)
     err2 := model.NewAuthGroupAccess().
-        Preload("Group", "status = ?", 1).
+        Where("group_id in (?)", groupDB).
         Find(&groupList).
         Error
     if err2 != nil {
         return false
     }
 
-    groupListMap := model.FormatStructToArrayMap(groupList)
-
     // 添加权限
-    if len(ruleListMap) > 0 {
-        for _, rv := range ruleListMap {
-            rule := rv["Rule"].(map[string]any)
-
-            permission.New().AddPolicy(rv["group_id"].(string), rule["url"].(string), rule["method"].(string))
+    for _, rv := range ruleList {
+        if rv.Rule.ID == "" {
+            continue
         }
+
+        c.AddPolicyInDomain(rv.GroupId, domain, rv.Rule.Url, rv.Rule.Method)
     }
 
-    // 添加权限
-    if len(groupListMap) > 0 {
-        for _, gv := range groupListMap {
-            permission.New().AddRoleForUser(gv["admin_id"].(string), gv["group_id"].(string))
-        }
+    // 添加分组
+    for _, gv := range groupList {
+        c.AddRoleForUserInDomain(gv.AdminId, gv.GroupId, domain)
     }
 
     return true
 }
-
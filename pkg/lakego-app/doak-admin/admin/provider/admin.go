@@ -3,12 +3,18 @@ package provider
 import (
     "os"
     "fmt"
+    "net/http"
 
     "github.com/deatil/go-events/events"
     "github.com/deatil/go-datebin/datebin"
     "github.com/deatil/lakego-filesystem/filesystem"
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/provider"
+    "github.com/deatil/lakego-doak/lakego/schedule"
+    "github.com/deatil/lakego-doak/lakego/middleware/sticky"
+    "github.com/deatil/lakego-doak/lakego/middleware/requestid"
+    "github.com/deatil/lakego-doak/lakego/middleware/throttle"
+    "github.com/deatil/lakego-doak/lakego/facade/cache"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     pathTool "github.com/deatil/lakego-doak/lakego/path"
 
@@ -20,10 +26,14 @@ import (
     // 中间件
     "github.com/deatil/lakego-doak-admin/admin/middleware/recovery"
     "github.com/deatil/lakego-doak-admin/admin/middleware/authorization"
+    "github.com/deatil/lakego-doak-admin/admin/middleware/tenant"
     "github.com/deatil/lakego-doak-admin/admin/middleware/cors"
     "github.com/deatil/lakego-doak-admin/admin/middleware/permission"
     "github.com/deatil/lakego-doak-admin/admin/middleware/admincheck"
 
+    // 模型
+    "github.com/deatil/lakego-doak-admin/admin/model"
+
     // 路由
     adminRoute "github.com/deatil/lakego-doak-admin/admin/route"
 
@@ -32,6 +42,10 @@ import (
 
     // 事件
     "github.com/deatil/lakego-doak-admin/admin/listener"
+
+    // 登录会话
+    "github.com/deatil/lakego-doak-admin/admin/auth/session"
+    "github.com/deatil/lakego-doak-admin/admin/auth/fieldpolicy"
 )
 
 // 全局中间件
@@ -41,6 +55,12 @@ var globalMiddlewares = []router.HandlerFunc{
 
     // 跨域处理
     cors.Handler(),
+
+    // 请求 ID
+    requestid.Handler(),
+
+    // 写后读主库
+    sticky.Handler(),
 }
 
 // 路由中间件
@@ -48,6 +68,9 @@ var routeMiddlewares = map[string]router.HandlerFunc{
     // token 验证
     "lakego-admin.auth": authorization.Handler(),
 
+    // 租户解析
+    "lakego-admin.tenant": tenant.Handler(),
+
     // 权限检测
     "lakego-admin.permission": permission.Handler(),
 
@@ -60,6 +83,7 @@ var middlewareGroups = map[string][]string{
     // 常规中间件
     "lakego-admin": {
         "lakego-admin.auth",
+        "lakego-admin.tenant",
         "lakego-admin.permission",
     },
 
@@ -98,6 +122,12 @@ func (this *Admin) Boot() {
 
     // 注册事件
     this.loadEvents()
+
+    // 数据库迁移
+    this.loadMigration()
+
+    // 字段权限
+    this.loadFieldPolicy()
 }
 
 /**
@@ -128,9 +158,12 @@ func (this *Admin) loadCommand() {
     // 导入 api 路由信息
     this.AddCommand(cmd.ImportApiRouteCmd)
 
-    // 强制将 jwt 的 refreshToken 放入黑名单
+    // 强制退出登录会话
     this.AddCommand(cmd.PassportLogoutCmd)
 
+    // 重置两步验证
+    this.AddCommand(cmd.PassportTwoFactorResetCmd)
+
     // 重置密码
     this.AddCommand(cmd.ResetPasswordCmd)
 
@@ -139,6 +172,43 @@ func (this *Admin) loadCommand() {
 
     // 停止 admin 系统服务
     this.AddCommand(cmd.StopCmd)
+
+    // 清除过期的分片上传
+    this.AddCommand(cmd.UploadCleanCmd)
+}
+
+/**
+ * 计划任务
+ */
+func (this *Admin) Schedule(s *schedule.Schedule) {
+    // 清除过期的分片上传
+    s.WithEntry(schedule.NewEntry().
+        WithName("lakego-admin:upload-clean").
+        AddErrorFunc(func() error {
+            _, err := cmd.UploadClean()
+            return err
+        }).
+        Hourly().
+        OnOneServer().
+        WithoutOverlapping())
+
+    // 清除过期的登录会话
+    s.WithEntry(schedule.NewEntry().
+        WithName("lakego-admin:session-clean").
+        AddErrorFunc(func() error {
+            _, err := session.Clean()
+            return err
+        }).
+        Daily().
+        OnOneServer().
+        WithoutOverlapping())
+}
+
+/**
+ * 数据库迁移
+ */
+func (this *Admin) loadMigration() {
+    this.AddMigrations("lakego-admin", model.Migrations...)
 }
 
 /**
@@ -180,6 +250,11 @@ func (this *Admin) loadRoute() {
             router.Use(admin, conf.GetString("route.admin-middleware"))
             {
                 adminRoute.AdminRoute(admin)
+
+                // 计划任务
+                if app := this.GetApp(); app != nil {
+                    adminRoute.ScheduleRoute(admin, app.GetSchedule())
+                }
             }
         }
 
@@ -197,6 +272,10 @@ func (this *Admin) loadMiddleware() {
         m.AliasMiddleware(name, value)
     }
 
+    // 限流中间件，使用 throttle:60,1 格式设置参数
+    this.loadThrottle()
+    m.AliasMiddleware("throttle", throttle.Handler)
+
     // 导入中间件分组
     for groupName, middlewares := range middlewareGroups {
         for _, middleware := range middlewares {
@@ -205,6 +284,36 @@ func (this *Admin) loadMiddleware() {
     }
 }
 
+/**
+ * 限流设置
+ */
+func (this *Admin) loadThrottle() {
+    // 多实例部署时共享限流数据
+    if config.New("admin").GetString("throttle.store") == "cache" {
+        throttle.SetDefaultStore(throttle.NewCacheStore(cache.Default))
+    }
+
+    throttle.SetLimitFunc(func(ctx *router.Context, result throttle.Result) {
+        response.ReturnJsonWithAbort(
+            ctx,
+            false,
+            code.StatusError,
+            "请求过于频繁，请稍后再试",
+            router.H{
+                "retry_after": result.RetryAfterSeconds(),
+            },
+            http.StatusTooManyRequests,
+        )
+    })
+}
+
+/**
+ * 字段权限，输出数据时隐藏或者脱敏字段
+ */
+func (this *Admin) loadFieldPolicy() {
+    response.AddDataFilter(fieldpolicy.Filter)
+}
+
 /**
  * 推送配置
  */
@@ -1,6 +1,7 @@
 package admin
 
 import (
+    "context"
     "encoding/json"
 
     "github.com/deatil/lakego-doak/lakego/collection"
@@ -9,13 +10,13 @@ import (
 )
 
 // 账号所属分组
-func GetGroups(adminid string) []map[string]any {
+func GetGroups(adminid string, ctx ...context.Context) []map[string]any {
     var info = model.Admin{}
 
     groups := make([]map[string]any, 0)
 
     // 附件模型
-    err := model.NewAdmin().
+    err := model.NewAdmin(ctx...).
         Where("id = ?", adminid).
         Preload("Groups").
         First(&info).
@@ -39,9 +40,9 @@ func GetGroups(adminid string) []map[string]any {
 }
 
 // 当前账号所属分组
-func GetGroupIds(adminid string) []string {
+func GetGroupIds(adminid string, ctx ...context.Context) []string {
     // 格式化分组
-    adminGroups := GetGroups(adminid)
+    adminGroups := GetGroups(adminid, ctx...)
     ids := collection.
         Collect(adminGroups).
         Pluck("id").
@@ -51,10 +52,10 @@ func GetGroupIds(adminid string) []string {
 }
 
 // 权限
-func GetRules(groupids []string) []map[string]any {
+func GetRules(groupids []string, ctx ...context.Context) []map[string]any {
     // 规则列表
     var ruleIds []string
-    model.NewAuthRuleAccess().
+    model.NewAuthRuleAccess(ctx...).
         Where("group_id in ?", groupids).
         Pluck("rule_id", &ruleIds)
     if len(ruleIds) == 0 {
@@ -64,7 +65,7 @@ func GetRules(groupids []string) []map[string]any {
     var data []model.AuthRule
 
     // 规则
-    model.NewAuthRule().
+    model.NewAuthRule(ctx...).
         Select([]string{
             "id", "parentid",
             "title",
@@ -86,9 +87,9 @@ func GetRules(groupids []string) []map[string]any {
 }
 
 // 权限ID列表
-func GetRuleids(groupids []string) []string {
+func GetRuleids(groupids []string, ctx ...context.Context) []string {
     // 格式化分组
-    list := GetRules(groupids)
+    list := GetRules(groupids, ctx...)
     ids := collection.
         Collect(list).
         Pluck("id").
@@ -1,6 +1,8 @@
 package authgroup
 
 import (
+    "context"
+
     "github.com/deatil/go-tree/tree"
     "github.com/deatil/lakego-doak/lakego/collection"
 
@@ -8,11 +10,11 @@ import (
 )
 
 // 获取全部用户组
-func GetAllGroup() ([]map[string]any, error) {
+func GetAllGroup(ctx ...context.Context) ([]map[string]any, error) {
     list := make([]map[string]any, 0)
 
     // 附件模型
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx...).
         Where("status = ?", 1).
         Order("listorder ASC").
         Order("add_time ASC").
@@ -23,11 +25,11 @@ func GetAllGroup() ([]map[string]any, error) {
 }
 
 // 获取 Children
-func GetChildren(groupid string) []map[string]any {
+func GetChildren(groupid string, ctx ...context.Context) []map[string]any {
     list := make([]map[string]any, 0)
 
     // 附件模型
-    err := model.NewAuthGroup().
+    err := model.NewAuthGroup(ctx...).
         Where("status = ?", 1).
         Order("listorder ASC").
         Order("add_time ASC").
@@ -48,10 +50,10 @@ func GetChildren(groupid string) []map[string]any {
 }
 
 // 获取 Children
-func GetChildrenFromGroupids(groupids []string) []map[string]any {
+func GetChildrenFromGroupids(groupids []string, ctx ...context.Context) []map[string]any {
     data := make([]map[string]any, 0)
     for _, id := range groupids {
-        children := GetChildren(id)
+        children := GetChildren(id, ctx...)
         data = append(data, children...)
     }
 
@@ -59,9 +61,9 @@ func GetChildrenFromGroupids(groupids []string) []map[string]any {
 }
 
 // 获取 ChildrenIds
-func GetChildrenIds(groupid string) []string {
+func GetChildrenIds(groupid string, ctx ...context.Context) []string {
     // 格式化分组
-    list := GetChildren(groupid)
+    list := GetChildren(groupid, ctx...)
 
     if len(list) == 0 {
         return []string{}
@@ -75,9 +77,9 @@ func GetChildrenIds(groupid string) []string {
 }
 
 // 获取 ChildrenIds
-func GetChildrenIdsFromGroupids(groupids []string) []string {
+func GetChildrenIdsFromGroupids(groupids []string, ctx ...context.Context) []string {
     // 格式化分组
-    list := GetChildrenFromGroupids(groupids)
+    list := GetChildrenFromGroupids(groupids, ctx...)
 
     if len(list) == 0 {
         return []string{}
@@ -1,6 +1,8 @@
 package authrule
 
 import (
+    "context"
+
     "github.com/deatil/go-tree/tree"
     "github.com/deatil/lakego-doak/lakego/collection"
 
@@ -8,11 +10,11 @@ import (
 )
 
 // 全部权限
-func GetAllRule() []map[string]any {
+func GetAllRule(ctx ...context.Context) []map[string]any {
     list := make([]map[string]any, 0)
 
     // 附件模型
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx...).
         Select([]string{
             "id", "parentid",
             "title",
@@ -32,11 +34,11 @@ func GetAllRule() []map[string]any {
 }
 
 // 获取 Children
-func GetChildren(ruleid string) []map[string]any {
+func GetChildren(ruleid string, ctx ...context.Context) []map[string]any {
     list := make([]map[string]any, 0)
 
     // 附件模型
-    err := model.NewAuthRule().
+    err := model.NewAuthRule(ctx...).
         Where("status = ?", 1).
         Order("listorder ASC").
         Order("add_time ASC").
@@ -57,10 +59,10 @@ func GetChildren(ruleid string) []map[string]any {
 }
 
 // 获取 Children
-func GetChildrenFromRuleids(ruleids []string) []map[string]any {
+func GetChildrenFromRuleids(ruleids []string, ctx ...context.Context) []map[string]any {
     data := make([]map[string]any, 0)
     for _, id := range ruleids {
-        children := GetChildren(id)
+        children := GetChildren(id, ctx...)
         data = append(data, children...)
     }
 
@@ -72,8 +74,8 @@ func GetChildrenFromRuleids(ruleids []string) []map[string]any {
 }
 
 // 获取 ChildrenIds
-func GetChildrenIds(ruleid string) []string {
-    list := GetChildren(ruleid)
+func GetChildrenIds(ruleid string, ctx ...context.Context) []string {
+    list := GetChildren(ruleid, ctx...)
 
     if len(list) == 0 {
         return []string{}
@@ -87,8 +89,8 @@ func GetChildrenIds(ruleid string) []string {
 }
 
 // 获取 ChildrenIds
-func GetChildrenIdsFromRuleids(ruleids []string) []string {
-    list := GetChildrenFromRuleids(ruleids)
+func GetChildrenIdsFromRuleids(ruleids []string, ctx ...context.Context) []string {
+    list := GetChildrenFromRuleids(ruleids, ctx...)
 
     if len(list) == 0 {
         return []string{}
@@ -0,0 +1,64 @@
+package authrule
+
+import (
+    "strings"
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+    "github.com/deatil/lakego-doak/lakego/facade"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+    "github.com/deatil/lakego-doak-admin/admin/support/url"
+)
+
+// 路由规则缓存时间，单位秒。规则保存或者删除时会清除缓存
+const routeRulesExpiresIn = 3600
+
+// 全部规则，包括禁用的规则，使用缓存
+func GetRouteRules() []model.AuthRule {
+    rules := make([]model.AuthRule, 0)
+
+    if data, err := facade.Cache.Get(model.AuthRuleCacheKey); err == nil {
+        if err := json.Unmarshal([]byte(goch.ToString(data)), &rules); err == nil {
+            return rules
+        }
+    }
+
+    err := model.NewAuthRule().
+        Order("listorder ASC").
+        Order("add_time ASC").
+        Find(&rules).
+        Error
+    if err != nil {
+        return make([]model.AuthRule, 0)
+    }
+
+    if data, err := json.Marshal(rules); err == nil {
+        facade.Cache.Put(model.AuthRuleCacheKey, string(data), routeRulesExpiresIn)
+    }
+
+    return rules
+}
+
+// 路由匹配的规则，path 为去除前缀的路由，比如 /admin/:id
+// 规则请求方式为 * 时匹配全部请求方式
+func MatchRouteRules(method string, path string) []model.AuthRule {
+    matched := make([]model.AuthRule, 0)
+    if path == "" {
+        return matched
+    }
+
+    method = strings.ToUpper(method)
+
+    for _, rule := range GetRouteRules() {
+        if rule.Method != method && rule.Method != "*" {
+            continue
+        }
+
+        if url.RuleRoutePath(rule.Url) == path {
+            matched = append(matched, rule)
+        }
+    }
+
+    return matched
+}
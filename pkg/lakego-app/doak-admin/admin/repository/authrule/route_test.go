@@ -0,0 +1,147 @@
+package authrule
+
+import (
+    "errors"
+    "testing"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/database"
+
+    "github.com/deatil/lakego-doak-admin/admin/model"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.AuthRule{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.AuthRule{})
+        model.ClearAuthRuleCache()
+    })
+}
+
+func createRule(t *testing.T, method string, url string, slug string) *model.AuthRule {
+    rule := &model.AuthRule{
+        Parentid: "0",
+        Title:    slug,
+        Url:      url,
+        Method:   method,
+        Slug:     slug,
+        Status:   1,
+    }
+    if err := model.NewDB().Create(rule).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    return rule
+}
+
+func Test_MatchRouteRules(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    createRule(t, "GET", "/admin/{id}", "admin.detail")
+    createRule(t, "PUT", "/admin/{id}", "admin.update")
+    createRule(t, "*", "/attachment/{id}", "attachment.all")
+
+    rules := MatchRouteRules("get", "/admin/:id")
+    eq(len(rules), 1, "MatchRouteRules len")
+    eq(rules[0].Slug, "admin.detail", "MatchRouteRules slug")
+
+    rules = MatchRouteRules("DELETE", "/attachment/:id")
+    eq(len(rules), 1, "MatchRouteRules any method")
+
+    eq(len(MatchRouteRules("DELETE", "/admin/:id")), 0, "MatchRouteRules method")
+    eq(len(MatchRouteRules("GET", "/admin")), 0, "MatchRouteRules path")
+    eq(len(MatchRouteRules("GET", "")), 0, "MatchRouteRules empty path")
+}
+
+func Test_GetRouteRules_Cache(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    rule := createRule(t, "GET", "/admin", "admin.index")
+
+    eq(len(GetRouteRules()), 1, "GetRouteRules")
+    eq(facade.Cache.Has(model.AuthRuleCacheKey), true, "GetRouteRules cached")
+
+    // 缓存后不再查询数据库，UpdateColumn 不触发清除缓存
+    model.NewAuthRule().
+        Where("id = ?", rule.ID).
+        UpdateColumn("title", "changed")
+    eq(GetRouteRules()[0].Title, "admin.index", "GetRouteRules from cache")
+
+    // 修改后清除缓存
+    model.NewAuthRule().
+        Where("id = ?", rule.ID).
+        Updates(map[string]any{
+            "title": "updated",
+        })
+    eq(facade.Cache.Has(model.AuthRuleCacheKey), false, "Updates clear cache")
+    eq(GetRouteRules()[0].Title, "updated", "GetRouteRules after Updates")
+
+    // 添加后清除缓存
+    createRule(t, "POST", "/admin", "admin.create")
+    eq(len(GetRouteRules()), 2, "GetRouteRules after Create")
+
+    // 删除后清除缓存
+    model.NewAuthRule().
+        Where("id = ?", rule.ID).
+        Delete(&model.AuthRule{})
+    eq(facade.Cache.Has(model.AuthRuleCacheKey), false, "Delete clear cache")
+    eq(len(GetRouteRules()), 1, "GetRouteRules after Delete")
+}
+
+
+func Test_GetRouteRules_AfterCommit(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+
+    rule := createRule(t, "GET", "/admin", "admin.index")
+
+    eq(len(GetRouteRules()), 1, "GetRouteRules")
+
+    // 事务内修改时，提交前读取到的旧数据写入缓存后在提交后清除
+    err := database.Transaction(model.NewDB(), func(tx *gorm.DB) error {
+        tx.Model(&model.AuthRule{}).
+            Where("id = ?", rule.ID).
+            Updates(map[string]any{
+                "title": "updated",
+            })
+
+        eq(facade.Cache.Has(model.AuthRuleCacheKey), true, "Transaction keep cache before commit")
+
+        return nil
+    })
+    eq(err, nil, "Transaction")
+
+    eq(facade.Cache.Has(model.AuthRuleCacheKey), false, "Transaction clear cache after commit")
+    eq(GetRouteRules()[0].Title, "updated", "GetRouteRules after commit")
+
+    // 回滚时不清除
+    database.Transaction(model.NewDB(), func(tx *gorm.DB) error {
+        tx.Model(&model.AuthRule{}).
+            Where("id = ?", rule.ID).
+            Updates(map[string]any{
+                "title": "rollback",
+            })
+
+        return errors.New("rollback")
+    })
+    eq(facade.Cache.Has(model.AuthRuleCacheKey), true, "Transaction rollback keep cache")
+    eq(GetRouteRules()[0].Title, "updated", "GetRouteRules after rollback")
+}
@@ -2,6 +2,7 @@ package route
 
 import (
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/schedule"
 
     "github.com/deatil/lakego-doak-admin/admin/controller"
 )
@@ -14,6 +15,10 @@ func Route(engine router.IRouter) {
     passportController := new(controller.Passport)
     engine.GET("/passport/captcha", passportController.Captcha)
     engine.POST("/passport/login", passportController.Login)
+    engine.POST("/passport/login/2fa", passportController.LoginTwoFactor)
+    engine.GET("/passport/providers", passportController.Providers)
+    engine.GET("/passport/provider/:name/redirect", passportController.ProviderRedirect)
+    engine.POST("/passport/provider/:name/callback", passportController.ProviderCallback)
     engine.PUT("/passport/refresh-token", passportController.RefreshToken)
     engine.DELETE("/passport/logout", passportController.Logout)
 
@@ -24,10 +29,27 @@ func Route(engine router.IRouter) {
     engine.PATCH("/profile/avatar", profileController.UpdateAvatar)
     engine.PATCH("/profile/password", profileController.UpdatePasssword)
     engine.GET("/profile/rules", profileController.Rules)
+    engine.GET("/profile/tenants", profileController.Tenants)
+    engine.GET("/profile/sessions", profileController.Sessions)
+    engine.DELETE("/profile/sessions", profileController.RevokeOtherSessions)
+    engine.DELETE("/profile/sessions/:id", profileController.RevokeSession)
+    engine.GET("/profile/tokens", profileController.Tokens)
+    engine.POST("/profile/tokens", profileController.TokenCreate)
+    engine.DELETE("/profile/tokens/:id", profileController.TokenDelete)
+    engine.GET("/profile/2fa", profileController.TwoFactor)
+    engine.POST("/profile/2fa", profileController.TwoFactorCreate)
+    engine.POST("/profile/2fa/confirm", profileController.TwoFactorConfirm)
+    engine.POST("/profile/2fa/recovery-codes", profileController.TwoFactorRecoveryCodes)
+    engine.DELETE("/profile/2fa", profileController.TwoFactorDelete)
 
     // 上传
     uploadController := new(controller.Upload)
     engine.POST("/upload/file", uploadController.File)
+    engine.POST("/upload/chunk", uploadController.ChunkInit)
+    engine.GET("/upload/chunk/:id", uploadController.ChunkStatus)
+    engine.PUT("/upload/chunk/:id", uploadController.ChunkPut)
+    engine.POST("/upload/chunk/:id/complete", uploadController.ChunkComplete)
+    engine.DELETE("/upload/chunk/:id", uploadController.ChunkAbort)
 
     // 附件
     attachmentController := new(controller.Attachment)
@@ -43,6 +65,7 @@ func Route(engine router.IRouter) {
     adminController := new(controller.Admin)
     engine.GET("/admin", adminController.Index)
     engine.GET("/admin/groups", adminController.Groups)
+    engine.GET("/admin/lockouts", adminController.Lockouts)
     engine.GET("/admin/:id", adminController.Detail)
     engine.GET("/admin/:id/rules", adminController.Rules)
     engine.POST("/admin", adminController.Create)
@@ -53,7 +76,9 @@ func Route(engine router.IRouter) {
     engine.PATCH("/admin/:id/avatar", adminController.UpdateAvatar)
     engine.PATCH("/admin/:id/password", adminController.UpdatePasssword)
     engine.PATCH("/admin/:id/access", adminController.Access)
+    engine.PATCH("/admin/:id/unlock", adminController.Unlock)
     engine.DELETE("/admin/logout/:refreshToken", adminController.Logout)
+    engine.DELETE("/admin/:id/sessions", adminController.RevokeSessions)
     engine.PUT("/admin/reset-permission", adminController.ResetPermission)
 
     // 系统信息
@@ -93,4 +118,25 @@ func AdminRoute(engine router.IRouter) {
     engine.PATCH("/auth/group/:id/enable", authGroupController.Enable)
     engine.PATCH("/auth/group/:id/disable", authGroupController.Disable)
     engine.PATCH("/auth/group/:id/access", authGroupController.Access)
+
+    // 租户
+    tenantController := new(controller.Tenant)
+    engine.GET("/tenant", tenantController.Index)
+    engine.GET("/tenant/:id", tenantController.Detail)
+    engine.POST("/tenant", tenantController.Create)
+    engine.PUT("/tenant/:id", tenantController.Update)
+    engine.DELETE("/tenant/:id", tenantController.Delete)
+    engine.PATCH("/tenant/:id/admins", tenantController.Admins)
+}
+
+/**
+ * 计划任务路由
+ */
+func ScheduleRoute(engine router.IRouter, s *schedule.Schedule) {
+    // 计划任务
+    scheduleController := &controller.Schedule{
+        Schedule: s,
+    }
+    engine.GET("/schedule", scheduleController.Index)
+    engine.GET("/schedule/history", scheduleController.History)
 }
@@ -13,6 +13,13 @@ const (
     LogoutError int = 100101
     AuthError   int = 100102
 
+    // 两步验证
+    TwoFactorError  int = 100103
+    TwoFactorEnroll int = 100104
+
+    // 租户
+    TenantError int = 100105
+
     // token相关
     JwtTokenOK          int = 200100 // token 有效
     JwtTokenInvalid     int = 200101 // 无效的 token
@@ -23,6 +23,23 @@ type JSONResult struct {
 // 默认
 var Default = New()
 
+// 上下文中记录的响应结果名称
+const (
+    SuccessKey = "response_success"
+    MessageKey = "response_message"
+)
+
+// 成功响应数据过滤
+type DataFilter func(ctx *router.Context, data any) any
+
+// 数据过滤列表
+var dataFilters []DataFilter
+
+// 添加成功响应数据过滤
+func AddDataFilter(filter DataFilter) {
+    dataFilters = append(dataFilters, filter)
+}
+
 /**
  * 响应
  *
@@ -86,6 +103,9 @@ func (this *Response) ReturnJson(
         resp.WithHttpCode(httpCode[0])
     }
 
+    ctx.Set(SuccessKey, success)
+    ctx.Set(MessageKey, msg)
+
     resp.ReturnJson(JSONResult{
         Success: success,
         Code:    dataCode,
@@ -111,6 +131,9 @@ func (this *Response) ReturnJsonWithAbort(
         resp.WithHttpCode(httpCode[0])
     }
 
+    ctx.Set(SuccessKey, success)
+    ctx.Set(MessageKey, msg)
+
     resp.ReturnJson(JSONResult{
         Success: success,
         Code:    dataCode,
@@ -143,6 +166,10 @@ func (this *Response) Success(ctx *router.Context, msg string) {
 func (this *Response) SuccessWithData(ctx *router.Context, msg string, data any) {
     dataCode := code.StatusSuccess
 
+    for _, filter := range dataFilters {
+        data = filter(ctx, data)
+    }
+
     this.ReturnJson(ctx, true, dataCode, msg, data)
 }
 
@@ -1,11 +1,17 @@
 package url
 
 import (
+    "regexp"
+    "strings"
+
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/facade/storage"
 )
 
+// 权限规则链接参数，比如 {id}
+var ruleParamRegexp = regexp.MustCompile(`\{(\w+)\}`)
+
 // 匹配链接
 func MatchPath(ctx *router.Context, path string, current string) bool {
     return router.MatchPath(ctx, path, current)
@@ -18,6 +24,18 @@ func AdminUrl(url string) string {
     return "/" + group + "/" + url
 }
 
+// 当前匹配的路由，去除后台路由前缀，比如 /admin/:id
+func RoutePath(ctx *router.Context) string {
+    prefix := "/" + facade.Config("admin").GetString("Route.Prefix")
+
+    return strings.TrimPrefix(ctx.FullPath(), prefix)
+}
+
+// 权限规则链接转为路由格式，比如 /admin/{id} 转为 /admin/:id
+func RuleRoutePath(url string) string {
+    return ruleParamRegexp.ReplaceAllString(url, ":$1")
+}
+
 // 附件 url
 func AttachmentUrl(path string, disk ...string) string {
     var url string
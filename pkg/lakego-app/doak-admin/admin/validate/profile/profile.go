@@ -79,3 +79,26 @@ func UpdatePasssword(data map[string]any) string {
 
     return err
 }
+
+// 创建访问令牌
+func TokenCreate(data map[string]any) string {
+    // 规则
+    rules := map[string]any{
+        "name": "required,max=50",
+        "scopes": "required",
+    }
+
+    // 错误提示
+    messages := map[string]string{
+        "name.required": "令牌名称不能为空",
+        "name.max": "令牌名称字数超过了限制",
+        "scopes.required": "授权权限不能为空",
+    }
+
+    ok, err := validate.ValidateMapError(data, rules, messages)
+    if ok {
+        return ""
+    }
+
+    return err
+}
@@ -0,0 +1,37 @@
+package tenant
+
+import (
+    "github.com/deatil/lakego-doak/lakego/validate"
+)
+
+// 创建验证
+func Create(data map[string]any) string {
+    // 规则
+    rules := map[string]any{
+        "code": "required,max=50,alphanum",
+        "title": "required,max=50",
+        "status": "required",
+    }
+
+    // 错误提示
+    messages := map[string]string{
+        "code.required": "标识不能为空",
+        "code.max": "标识最大字符需要50个",
+        "code.alphanum": "标识只能为字母和数字",
+        "title.required": "名称不能为空",
+        "title.max": "名称最大字符需要50个",
+        "status.required": "状态选项不能为空",
+    }
+
+    ok, err := validate.ValidateMapError(data, rules, messages)
+    if ok {
+        return ""
+    }
+
+    return err
+}
+
+// 编辑验证
+func Update(data map[string]any) string {
+    return Create(data)
+}
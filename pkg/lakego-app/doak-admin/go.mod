@@ -9,5 +9,7 @@ require (
 	github.com/deatil/go-tree v0.0.3
 	github.com/deatil/go-event v0.0.3
 	github.com/deatil/go-datebin v0.0.3
+	github.com/deatil/go-cryptobin v0.0.3
 	github.com/deatil/lakego-doak v0.0.3
+	golang.org/x/crypto v0.24.0
 )
@@ -25,45 +25,31 @@ type DbVersion struct {
 // 数据库管理
 type Database struct {}
 
-// 获取mysql的版本
-func (this Database) GetMysqlVersion() string {
-    var dbVersion DbVersion
+// 获取数据库的版本
+func (this Database) GetVersion() string {
+    db := NewDB()
 
-    err := NewDB().Raw("select VERSION() as db_version").Scan(&dbVersion)
-    if err != nil {
-        return "not found."
-    }
+    return NewDialect(db).Version(db)
+}
 
-    return dbVersion.DbVersion
+// 获取mysql的版本
+// Deprecated: 使用 GetVersion
+func (this Database) GetMysqlVersion() string {
+    return this.GetVersion()
 }
 
 // 获取所有数据表的状态
 func (this Database) GetTableStatus() []map[string]string {
-    var maps []map[string]any
-
-    var resultMaps []map[string]string
-    NewDB().Raw("SHOW TABLE STATUS").Scan(&maps)
-
-    if len(maps) > 0 {
-        for _, item := range maps {
-            resultMaps = append(resultMaps, map[string]string{
-                "name":        this.toString(item["Name"]),
-                "comment":     this.toString(item["Comment"]),
-                "engine":      this.toString(item["Engine"]),
-                "collation":   this.toString(item["Collation"]),
-                "data_length": this.toString(item["Data_length"]),
-                "create_time": this.toString(item["Create_time"]),
-                "update_time": this.toString(item["Update_time"]),
-            })
-        }
-    }
+    db := NewDB()
 
-    return resultMaps
+    return NewDialect(db).TableStatus(db)
 }
 
 // 优化数据表
 func (this Database) OptimizeTable(tableName string) bool {
-    err := NewDB().Exec("OPTIMIZE TABLE `" + tableName + "`").Error
+    db := NewDB()
+
+    err := NewDialect(db).OptimizeTable(db, tableName)
     if err == nil {
         return true
     }
@@ -73,7 +59,9 @@ func (this Database) OptimizeTable(tableName string) bool {
 
 // 修复数据表
 func (this Database) RepairTable(tableName string) bool {
-    err := NewDB().Exec("REPAIR TABLE `" + tableName + "`").Error
+    db := NewDB()
+
+    err := NewDialect(db).RepairTable(db, tableName)
     if err == nil {
         return true
     }
@@ -83,31 +71,12 @@ func (this Database) RepairTable(tableName string) bool {
 
 // 获取数据表的所有字段
 func (this Database) GetFullColumnsFromTable(tableName string) []map[string]string {
-    var maps []map[string]any
-
-    var resultMaps []map[string]string
-    NewDB().Raw("SHOW FULL COLUMNS FROM `" + tableName + "`").Scan(&maps)
-
-    if len(maps) > 0 {
-        for _, item := range maps {
-            resultMaps = append(resultMaps, map[string]string{
-                "name":       this.toString(item["Field"]),
-                "type":       this.toString(item["Type"]),
-                "collation":  this.toString(item["Collation"]),
-                "null":       this.toString(item["Null"]),
-                "key":        this.toString(item["Key"]),
-                "default":    this.toString(item["Default"]),
-                "extra":      this.toString(item["Extra"]),
-                "privileges": this.toString(item["Privileges"]),
-                "comment":    this.toString(item["Comment"]),
-            })
-        }
-    }
+    db := NewDB()
 
-    return resultMaps
+    return NewDialect(db).FullColumns(db, tableName)
 }
 
 // any 转换为 string
-func (this Database) toString(val any) string {
+func toString(val any) string {
     return goch.ToString(val)
 }
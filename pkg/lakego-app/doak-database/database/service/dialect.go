@@ -0,0 +1,78 @@
+package service
+
+import (
+    "gorm.io/gorm"
+)
+
+/**
+ * 数据库方言接口
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Dialect interface {
+    // 数据库版本
+    Version(db *gorm.DB) string
+
+    // 数据表状态
+    TableStatus(db *gorm.DB) []map[string]string
+
+    // 优化数据表
+    OptimizeTable(db *gorm.DB, table string) error
+
+    // 修复数据表
+    RepairTable(db *gorm.DB, table string) error
+
+    // 数据表字段
+    FullColumns(db *gorm.DB, table string) []map[string]string
+}
+
+// 已注册方言
+var dialects = map[string]func() Dialect{
+    "mysql":     func() Dialect { return MysqlDialect{} },
+    "postgres":  func() Dialect { return PostgresDialect{} },
+    "sqlite":    func() Dialect { return SqliteDialect{} },
+    "sqlserver": func() Dialect { return SqlserverDialect{} },
+}
+
+// 注册方言，名称为 gorm 驱动名称
+func RegisterDialect(name string, fn func() Dialect) {
+    dialects[name] = fn
+}
+
+// 根据连接获取方言，未知驱动使用 mysql
+func NewDialect(db *gorm.DB) Dialect {
+    if fn, ok := dialects[db.Dialector.Name()]; ok {
+        return fn()
+    }
+
+    return MysqlDialect{}
+}
+
+// 字段数据统一格式
+func formatColumn(item map[string]any) map[string]string {
+    return map[string]string{
+        "name":       toString(item["name"]),
+        "type":       toString(item["type"]),
+        "collation":  toString(item["collation"]),
+        "null":       toString(item["is_null"]),
+        "key":        toString(item["column_key"]),
+        "default":    toString(item["default_value"]),
+        "extra":      toString(item["extra"]),
+        "privileges": toString(item["privileges"]),
+        "comment":    toString(item["comment"]),
+    }
+}
+
+// 数据表状态统一格式
+func formatTableStatus(item map[string]any) map[string]string {
+    return map[string]string{
+        "name":        toString(item["name"]),
+        "comment":     toString(item["comment"]),
+        "engine":      toString(item["engine"]),
+        "collation":   toString(item["collation"]),
+        "data_length": toString(item["data_length"]),
+        "create_time": toString(item["create_time"]),
+        "update_time": toString(item["update_time"]),
+    }
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+/**
+ * MySQL 方言
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type MysqlDialect struct {}
+
+// 数据库版本
+func (this MysqlDialect) Version(db *gorm.DB) string {
+    var dbVersion DbVersion
+
+    err := db.Raw("SELECT VERSION() AS db_version").Scan(&dbVersion).Error
+    if err != nil {
+        return "not found."
+    }
+
+    return dbVersion.DbVersion
+}
+
+// 数据表状态
+func (this MysqlDialect) TableStatus(db *gorm.DB) []map[string]string {
+    var maps []map[string]any
+
+    var resultMaps []map[string]string
+    db.Raw("SHOW TABLE STATUS").Scan(&maps)
+
+    for _, item := range maps {
+        resultMaps = append(resultMaps, map[string]string{
+            "name":        toString(item["Name"]),
+            "comment":     toString(item["Comment"]),
+            "engine":      toString(item["Engine"]),
+            "collation":   toString(item["Collation"]),
+            "data_length": toString(item["Data_length"]),
+            "create_time": toString(item["Create_time"]),
+            "update_time": toString(item["Update_time"]),
+        })
+    }
+
+    return resultMaps
+}
+
+// 优化数据表
+func (this MysqlDialect) OptimizeTable(db *gorm.DB, table string) error {
+    return db.Exec("OPTIMIZE TABLE ?", clause.Table{Name: table}).Error
+}
+
+// 修复数据表
+func (this MysqlDialect) RepairTable(db *gorm.DB, table string) error {
+    return db.Exec("REPAIR TABLE ?", clause.Table{Name: table}).Error
+}
+
+// 数据表字段
+func (this MysqlDialect) FullColumns(db *gorm.DB, table string) []map[string]string {
+    var maps []map[string]any
+
+    var resultMaps []map[string]string
+    db.Raw("SHOW FULL COLUMNS FROM ?", clause.Table{Name: table}).Scan(&maps)
+
+    for _, item := range maps {
+        resultMaps = append(resultMaps, map[string]string{
+            "name":       toString(item["Field"]),
+            "type":       toString(item["Type"]),
+            "collation":  toString(item["Collation"]),
+            "null":       toString(item["Null"]),
+            "key":        toString(item["Key"]),
+            "default":    toString(item["Default"]),
+            "extra":      toString(item["Extra"]),
+            "privileges": toString(item["Privileges"]),
+            "comment":    toString(item["Comment"]),
+        })
+    }
+
+    return resultMaps
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+/**
+ * PostgreSQL 方言
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type PostgresDialect struct {}
+
+// 数据库版本
+func (this PostgresDialect) Version(db *gorm.DB) string {
+    var dbVersion DbVersion
+
+    err := db.Raw("SELECT version() AS db_version").Scan(&dbVersion).Error
+    if err != nil {
+        return "not found."
+    }
+
+    return dbVersion.DbVersion
+}
+
+// 数据表状态
+func (this PostgresDialect) TableStatus(db *gorm.DB) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw(`SELECT
+            c.relname AS name,
+            obj_description(c.oid, 'pg_class') AS comment,
+            'heap' AS engine,
+            (SELECT datcollate FROM pg_database WHERE datname = current_database()) AS collation,
+            pg_total_relation_size(c.oid) AS data_length
+        FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE c.relkind = 'r' AND n.nspname = current_schema()
+        ORDER BY c.relname`).
+        Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        resultMaps = append(resultMaps, formatTableStatus(item))
+    }
+
+    return resultMaps
+}
+
+// 优化数据表
+func (this PostgresDialect) OptimizeTable(db *gorm.DB, table string) error {
+    return db.Exec("VACUUM ANALYZE ?", clause.Table{Name: table}).Error
+}
+
+// 修复数据表
+func (this PostgresDialect) RepairTable(db *gorm.DB, table string) error {
+    return db.Exec("REINDEX TABLE ?", clause.Table{Name: table}).Error
+}
+
+// 数据表字段
+func (this PostgresDialect) FullColumns(db *gorm.DB, table string) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw(`SELECT
+            c.column_name AS name,
+            c.data_type AS type,
+            c.collation_name AS collation,
+            c.is_nullable AS is_null,
+            c.column_default AS default_value,
+            col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position) AS comment,
+            CASE WHEN EXISTS (
+                SELECT 1 FROM information_schema.key_column_usage k
+                JOIN information_schema.table_constraints t
+                    ON t.constraint_name = k.constraint_name AND t.table_schema = k.table_schema
+                WHERE t.constraint_type = 'PRIMARY KEY'
+                    AND k.table_schema = c.table_schema
+                    AND k.table_name = c.table_name
+                    AND k.column_name = c.column_name
+            ) THEN 'PRI' ELSE '' END AS column_key,
+            CASE WHEN c.is_identity = 'YES' THEN 'identity' ELSE '' END AS extra
+        FROM information_schema.columns c
+        WHERE c.table_schema = current_schema() AND c.table_name = ?
+        ORDER BY c.ordinal_position`, table).
+        Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        resultMaps = append(resultMaps, formatColumn(item))
+    }
+
+    return resultMaps
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+/**
+ * SQLite 方言
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SqliteDialect struct {}
+
+// 数据库版本
+func (this SqliteDialect) Version(db *gorm.DB) string {
+    var dbVersion DbVersion
+
+    err := db.Raw("SELECT sqlite_version() AS db_version").Scan(&dbVersion).Error
+    if err != nil {
+        return "not found."
+    }
+
+    return dbVersion.DbVersion
+}
+
+// 数据表状态
+func (this SqliteDialect) TableStatus(db *gorm.DB) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw(`SELECT name, 'sqlite' AS engine
+        FROM sqlite_master
+        WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+        ORDER BY name`).
+        Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        resultMaps = append(resultMaps, formatTableStatus(item))
+    }
+
+    return resultMaps
+}
+
+// 优化数据表，SQLite 只能整库 VACUUM
+func (this SqliteDialect) OptimizeTable(db *gorm.DB, table string) error {
+    err := db.Exec("ANALYZE ?", clause.Table{Name: table}).Error
+    if err != nil {
+        return err
+    }
+
+    return db.Exec("VACUUM").Error
+}
+
+// 修复数据表
+func (this SqliteDialect) RepairTable(db *gorm.DB, table string) error {
+    return db.Exec("REINDEX ?", clause.Table{Name: table}).Error
+}
+
+// 数据表字段
+func (this SqliteDialect) FullColumns(db *gorm.DB, table string) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw("PRAGMA table_info(?)", clause.Table{Name: table}).Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        isNull := "YES"
+        if toString(item["notnull"]) == "1" {
+            isNull = "NO"
+        }
+
+        columnKey := ""
+        if toString(item["pk"]) != "0" {
+            columnKey = "PRI"
+        }
+
+        resultMaps = append(resultMaps, formatColumn(map[string]any{
+            "name":          item["name"],
+            "type":          item["type"],
+            "is_null":       isNull,
+            "column_key":    columnKey,
+            "default_value": item["dflt_value"],
+        }))
+    }
+
+    return resultMaps
+}
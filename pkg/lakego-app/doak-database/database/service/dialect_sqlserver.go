@@ -0,0 +1,103 @@
+package service
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+/**
+ * SQL Server 方言
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SqlserverDialect struct {}
+
+// 数据库版本
+func (this SqlserverDialect) Version(db *gorm.DB) string {
+    var dbVersion DbVersion
+
+    err := db.Raw("SELECT @@VERSION AS db_version").Scan(&dbVersion).Error
+    if err != nil {
+        return "not found."
+    }
+
+    return dbVersion.DbVersion
+}
+
+// 数据表状态
+func (this SqlserverDialect) TableStatus(db *gorm.DB) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw(`SELECT
+            t.name AS name,
+            CAST(ep.value AS NVARCHAR(4000)) AS comment,
+            'mssql' AS engine,
+            CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS NVARCHAR(128)) AS collation,
+            SUM(a.total_pages) * 8 * 1024 AS data_length,
+            t.create_date AS create_time,
+            t.modify_date AS update_time
+        FROM sys.tables t
+        LEFT JOIN sys.extended_properties ep
+            ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+        LEFT JOIN sys.partitions p ON p.object_id = t.object_id
+        LEFT JOIN sys.allocation_units a ON a.container_id = p.partition_id
+        GROUP BY t.name, CAST(ep.value AS NVARCHAR(4000)), t.create_date, t.modify_date
+        ORDER BY t.name`).
+        Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        resultMaps = append(resultMaps, formatTableStatus(item))
+    }
+
+    return resultMaps
+}
+
+// 优化数据表
+func (this SqlserverDialect) OptimizeTable(db *gorm.DB, table string) error {
+    err := db.Exec("ALTER INDEX ALL ON ? REORGANIZE", clause.Table{Name: table}).Error
+    if err != nil {
+        return err
+    }
+
+    return db.Exec("UPDATE STATISTICS ?", clause.Table{Name: table}).Error
+}
+
+// 修复数据表
+func (this SqlserverDialect) RepairTable(db *gorm.DB, table string) error {
+    return db.Exec("ALTER INDEX ALL ON ? REBUILD", clause.Table{Name: table}).Error
+}
+
+// 数据表字段
+func (this SqlserverDialect) FullColumns(db *gorm.DB, table string) []map[string]string {
+    var maps []map[string]any
+
+    db.Raw(`SELECT
+            c.name AS name,
+            ty.name AS type,
+            c.collation_name AS collation,
+            CASE WHEN c.is_nullable = 1 THEN 'YES' ELSE 'NO' END AS is_null,
+            OBJECT_DEFINITION(c.default_object_id) AS default_value,
+            CASE WHEN c.is_identity = 1 THEN 'identity' ELSE '' END AS extra,
+            CAST(ep.value AS NVARCHAR(4000)) AS comment,
+            CASE WHEN EXISTS (
+                SELECT 1 FROM sys.index_columns ic
+                JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+                WHERE i.is_primary_key = 1 AND ic.object_id = c.object_id AND ic.column_id = c.column_id
+            ) THEN 'PRI' ELSE '' END AS column_key
+        FROM sys.columns c
+        JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+        LEFT JOIN sys.extended_properties ep
+            ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+        WHERE c.object_id = OBJECT_ID(?)
+        ORDER BY c.column_id`, table).
+        Scan(&maps)
+
+    var resultMaps []map[string]string
+    for _, item := range maps {
+        resultMaps = append(resultMaps, formatColumn(item))
+    }
+
+    return resultMaps
+}
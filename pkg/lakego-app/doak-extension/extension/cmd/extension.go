@@ -18,6 +18,9 @@ import (
  *
  * > go run main.go lakego-admin:extension --action=local
  * > go run main.go lakego-admin:extension --action=inatll --name=lakego.demo
+ * > go run main.go lakego-admin:extension --action=install --name=lakego.demo
+ * > go run main.go lakego-admin:extension --action=install --file=/path/lakego.demo.zip
+ * > go run main.go lakego-admin:extension --action=sign --file=/path/lakego.demo.zip
  * > go run main.go lakego-admin:extension --action=uninstall --name=lakego.demo
  * > go run main.go lakego-admin:extension --action=upgrade --name=lakego.demo
  * > go run main.go lakego-admin:extension --action=enable --name=lakego.demo
@@ -42,12 +45,14 @@ var ExtensionCmd = &command.Command{
 
 var action string
 var name string
+var file string
 var sort int
 
 func init() {
     pf := ExtensionCmd.Flags()
     pf.StringVarP(&action, "action", "a", "", "操作类型")
     pf.StringVarP(&name, "name", "n", "", "扩展名称")
+    pf.StringVarP(&file, "file", "f", "", "扩展包文件")
     pf.IntVarP(&sort, "sort", "s", 100, "扩展排序值")
 
     command.MarkFlagRequired(pf, "action")
@@ -60,13 +65,23 @@ func ExtensionCtl() {
         return
     }
 
-    switch name {
+    switch action {
         case "inatll", "uninstall", "upgrade",
             "enable", "disable", "sort":
             if name == "" {
                 fmt.Println("扩展名称不能为空")
                 return
             }
+        case "install":
+            if name == "" && file == "" {
+                fmt.Println("扩展名称和扩展包文件不能都为空")
+                return
+            }
+        case "sign":
+            if file == "" {
+                fmt.Println("扩展包文件不能为空")
+                return
+            }
     }
 
     err := errors.New("操作类型不存在")
@@ -89,6 +104,23 @@ func ExtensionCtl() {
                 fmt.Println("安装扩展成功")
                 return
             }
+        case "install":
+            if file != "" {
+                err = newExtension.InstallPackage(file)
+            } else {
+                err = newExtension.Inatll(name)
+            }
+
+            if err == nil {
+                fmt.Println("安装扩展成功")
+                return
+            }
+        case "sign":
+            err = service.SignPackage(file)
+            if err == nil {
+                fmt.Println("扩展包签名成功")
+                return
+            }
         case "uninstall":
             err = newExtension.Uninstall(name)
             if err == nil {
@@ -1,8 +1,13 @@
 package controller
 
 import (
+    "os"
+    "strings"
+    "path/filepath"
+
     "github.com/deatil/go-goch/goch"
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
 
     admin_controller "github.com/deatil/lakego-doak-admin/admin/controller"
 
@@ -136,6 +141,38 @@ func (this *Extension) Local(ctx *router.Context) {
     })
 }
 
+// 扩展操作记录
+// @Summary 扩展操作记录
+// @Description 扩展安装、更新、卸载、启用和禁用记录
+// @Tags 扩展
+// @Accept  application/json
+// @Produce application/json
+// @Param name  query string true  "扩展名称"
+// @Param limit query string false "数量"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /extension/{name}/logs [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.extension.logs"}
+func (this *Extension) Logs(ctx *router.Context) {
+    name := ctx.Param("name")
+    if name == "" {
+        this.Error(ctx, "扩展不能为空")
+        return
+    }
+
+    limit := goch.ToInt(ctx.DefaultQuery("limit", "20"))
+    if limit <= 0 || limit > 100 {
+        limit = 20
+    }
+
+    list := service.NewExtension().Logs(name, limit)
+
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "total": len(list),
+        "list": list,
+    })
+}
+
 // 安装扩展
 // @Summary 安装扩展
 // @Description 安装扩展
@@ -163,6 +200,63 @@ func (this *Extension) Inatll(ctx *router.Context) {
     this.Success(ctx, "安装扩展成功")
 }
 
+// 上传安装扩展包
+// @Summary 上传安装扩展包
+// @Description 上传 zip 格式的扩展包并安装，扩展已安装时更新
+// @Tags 扩展
+// @Accept  multipart/form-data
+// @Produce application/json
+// @Param file formData file true "扩展包"
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /extension/upload [post]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.extension.upload"}
+func (this *Extension) Upload(ctx *router.Context) {
+    file, err := ctx.FormFile("file")
+    if err != nil {
+        this.Error(ctx, "上传扩展包失败")
+        return
+    }
+
+    if strings.ToLower(filepath.Ext(file.Filename)) != ".zip" {
+        this.Error(ctx, "扩展包格式错误")
+        return
+    }
+
+    // 大小限制，单位 MB
+    maxSize := config.New("admin").GetInt64("extension.package.max-size")
+    if maxSize <= 0 {
+        maxSize = 20
+    }
+
+    if file.Size > maxSize << 20 {
+        this.Error(ctx, "扩展包大小超过限制")
+        return
+    }
+
+    tmp, err := os.CreateTemp("", "lakego-extension-*.zip")
+    if err != nil {
+        this.Error(ctx, "上传扩展包失败")
+        return
+    }
+
+    tmp.Close()
+    defer os.Remove(tmp.Name())
+
+    if err := ctx.SaveUploadedFile(file, tmp.Name()); err != nil {
+        this.Error(ctx, "上传扩展包失败")
+        return
+    }
+
+    err = service.NewExtensionWithCtx(ctx).InstallPackage(tmp.Name())
+    if err != nil {
+        this.Error(ctx, err.Error())
+        return
+    }
+
+    this.Success(ctx, "安装扩展成功")
+}
+
 // 卸载扩展
 // @Summary 卸载扩展
 // @Description 卸载扩展
@@ -0,0 +1,73 @@
+package extension
+
+import (
+    "github.com/deatil/go-events/events"
+)
+
+// 事件前缀，事件名称为前缀加操作状态，比如 lakego-admin:extension.installing
+const EventPrefix = "lakego-admin:extension."
+
+// 操作失败事件
+const EventFailed = EventPrefix + "failed"
+
+/**
+ * 扩展操作事件
+ *
+ * 执行中事件为 filter，可以调用 Veto 阻止操作:
+ * events.AddFilter("lakego-admin:extension.installing", func(e *extension.Event) *extension.Event {
+ *     e.Veto(errors.New("not allowed"))
+ *     return e
+ * }, events.DefaultSort)
+ *
+ * 完成和失败事件为 action
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Event struct {
+    // 扩展名称
+    Name string
+
+    // 操作
+    Action string
+
+    // 操作前版本
+    FromVersion string
+
+    // 版本
+    Version string
+
+    // 错误
+    err error
+}
+
+// 阻止操作
+func (this *Event) Veto(err error) {
+    this.err = err
+}
+
+// 错误信息，被阻止或者操作失败时不为空
+func (this *Event) Err() error {
+    return this.err
+}
+
+// 触发执行中事件，返回阻止操作的错误
+func TriggerRunning(state string, e *Event) error {
+    if res, ok := events.ApplyFilters(EventPrefix + state, e).(*Event); ok && res != nil {
+        return res.Err()
+    }
+
+    return e.Err()
+}
+
+// 触发完成事件
+func TriggerDone(state string, e *Event) {
+    events.DoAction(EventPrefix + state, e)
+}
+
+// 触发失败事件
+func TriggerFailed(e *Event, err error) {
+    e.err = err
+
+    events.DoAction(EventFailed, e)
+}
@@ -3,6 +3,9 @@ package extension
 import (
     "encoding/json"
 
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
     iapp "github.com/deatil/lakego-doak/lakego/app/interfaces"
 )
 
@@ -48,20 +51,26 @@ type Extension struct {
     // map[string]string{'lakego.log-viewer' => '1.0.*'}
     Require map[string]string `json:"require"`
 
+    // 数据库迁移，安装和更新时执行，卸载时回滚
+    Migrations []migration.Migration `json:"-"`
+
+    // 扩展方法在操作事务中执行，数据库操作使用传入的 tx，
+    // 失败时和数据库迁移一起回滚
+
     // 安装后
-    Install func() error `json:"-"`
+    Install func(tx *gorm.DB) error `json:"-"`
 
-    // 卸载后
-    Uninstall func() error `json:"-"`
+    // 卸载，在回滚数据库迁移之前执行，失败时不会卸载
+    Uninstall func(tx *gorm.DB) error `json:"-"`
 
     // 更新后
-    Upgrade func() error `json:"-"`
+    Upgrade func(tx *gorm.DB) error `json:"-"`
 
     // 启用后
-    Enable func() error `json:"-"`
+    Enable func(tx *gorm.DB) error `json:"-"`
 
     // 禁用后
-    Disable func() error `json:"-"`
+    Disable func(tx *gorm.DB) error `json:"-"`
 
     // 安装启用后运行
     Start func(iapp.App) error `json:"-"`
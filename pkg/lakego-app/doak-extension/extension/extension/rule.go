@@ -3,6 +3,8 @@ package extension
 import (
     "strings"
 
+    "gorm.io/gorm"
+
     "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-tree/tree"
     "github.com/deatil/go-datebin/datebin"
@@ -13,15 +15,34 @@ import (
 )
 
 // 规则
-type Rule struct {}
+type Rule struct {
+    // 数据库连接，扩展方法中传入操作事务
+    db *gorm.DB
+}
 
 // 初始化
 func NewRule() *Rule {
-    r := &Rule{}
+    r := &Rule{
+        db: model.NewDB(),
+    }
+
+    return r
+}
+
+// 使用指定数据库连接初始化
+func NewRuleWithDB(db *gorm.DB) *Rule {
+    r := &Rule{
+        db: db,
+    }
 
     return r
 }
 
+// 规则模型
+func (this *Rule) model() *gorm.DB {
+    return this.db.Model(&model.AuthRule{})
+}
+
 // 创建
 func (this *Rule) Create(data map[string]any, parentId string) bool {
     if len(data) == 0 {
@@ -31,7 +52,7 @@ func (this *Rule) Create(data map[string]any, parentId string) bool {
     lastOrder := 0
 
     var info model.AuthRule
-    err := model.NewAuthRule().
+    err := this.model().
         Order("listorder DESC").
         First(&info).
         Error
@@ -54,7 +75,7 @@ func (this *Rule) Create(data map[string]any, parentId string) bool {
         AddIp:       "0.0.0.0",
     }
 
-    err2 := model.NewDB().
+    err2 := this.db.
         Create(&insertData).
         Error
     if err2 == nil {
@@ -76,7 +97,7 @@ func (this *Rule) Delete(slug string) bool {
         return false
     }
 
-    model.NewAuthRule().
+    this.model().
         Where("id IN ?", ids).
         Delete(&model.AuthRule{})
 
@@ -90,7 +111,7 @@ func (this *Rule) Enable(slug string) bool {
         return false
     }
 
-    model.NewAuthRule().
+    this.model().
         Where("id IN ?", ids).
         Updates(map[string]any{
             "status": 1,
@@ -106,7 +127,7 @@ func (this *Rule) Disable(slug string) bool {
         return false
     }
 
-    model.NewAuthRule().
+    this.model().
         Where("id IN ?", ids).
         Updates(map[string]any{
             "status": 0,
@@ -127,14 +148,14 @@ func (this *Rule) Export(slug string) []map[string]any {
     var info model.AuthRule
 
     // 模型
-    err := model.NewAuthRule().
+    err := this.model().
         Where("slug = ?", slug).
         First(&info).
         Error
     if err == nil {
         rules := make([]map[string]any, 0)
 
-        model.NewAuthRule().
+        this.model().
             Where("id IN ?", ids).
             Order("listorder ASC").
             Find(&rules)
@@ -152,12 +173,12 @@ func (this *Rule) GetAuthRuleIdsBySlug(slug string) []string {
     ids := make([]string, 0)
 
     rules := make([]map[string]any, 0)
-    model.NewAuthRule().
+    this.model().
         Where("slug = ?", slug).
         Find(&rules)
 
     ruleList := make([]map[string]any, 0)
-    model.NewAuthRule().
+    this.model().
         Order("listorder ASC").
         Select("id", "parentid", "slug").
         Find(&ruleList)
@@ -0,0 +1,108 @@
+package model
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/facade"
+)
+
+// 操作
+const (
+    ActionInstall   = "install"
+    ActionUpgrade   = "upgrade"
+    ActionUninstall = "uninstall"
+    ActionEnable    = "enable"
+    ActionDisable   = "disable"
+)
+
+// 操作状态
+const (
+    StatePending = "pending"
+    StateFailed  = "failed"
+
+    StateInstalling   = "installing"
+    StateInstalled    = "installed"
+    StateUpgrading    = "upgrading"
+    StateUpgraded     = "upgraded"
+    StateUninstalling = "uninstalling"
+    StateUninstalled  = "uninstalled"
+    StateEnabling     = "enabling"
+    StateEnabled      = "enabled"
+    StateDisabling    = "disabling"
+    StateDisabled     = "disabled"
+)
+
+// 操作的执行中和完成状态
+var actionStates = map[string][2]string{
+    ActionInstall:   {StateInstalling, StateInstalled},
+    ActionUpgrade:   {StateUpgrading, StateUpgraded},
+    ActionUninstall: {StateUninstalling, StateUninstalled},
+    ActionEnable:    {StateEnabling, StateEnabled},
+    ActionDisable:   {StateDisabling, StateDisabled},
+}
+
+// 执行中状态
+func RunningState(action string) string {
+    return actionStates[action][0]
+}
+
+// 完成状态
+func DoneState(action string) string {
+    return actionStates[action][1]
+}
+
+// 状态是否可以变更
+// pending -> [running] -> [done] / failed
+func CanTransition(action string, from string, to string) bool {
+    states, ok := actionStates[action]
+    if !ok {
+        return false
+    }
+
+    switch from {
+        case StatePending:
+            return to == states[0] || to == StateFailed
+        case states[0]:
+            return to == states[1] || to == StateFailed
+    }
+
+    return false
+}
+
+// 扩展操作记录
+type ExtensionLog struct {
+    ID          string `gorm:"column:id;type:char(36);not null;primaryKey;" json:"id"`
+    Name        string `gorm:"column:name;not null;type:varchar(160);index;" json:"name"`
+    Action      string `gorm:"column:action;not null;type:varchar(20);" json:"action"`
+    FromVersion string `gorm:"column:from_version;type:varchar(50);" json:"from_version"`
+    Version     string `gorm:"column:version;type:varchar(50);" json:"version"`
+    State       string `gorm:"column:state;not null;type:varchar(20);" json:"state"`
+    Message     string `gorm:"column:message;type:text;" json:"message"`
+    UpdateTime  int    `gorm:"column:update_time;size:10;" json:"update_time"`
+    AddTime     int    `gorm:"column:add_time;size:10;" json:"add_time"`
+    AddIp       string `gorm:"column:add_ip;size:50;" json:"add_ip"`
+}
+
+func (this *ExtensionLog) BeforeCreate(tx *gorm.DB) error {
+    this.ID = uuid.ToUUIDString()
+
+    return nil
+}
+
+func NewExtensionLog() *gorm.DB {
+    return facade.DB.Model(&ExtensionLog{})
+}
+
+// 获取扩展的操作记录，最新的在前
+func GetExtensionLogs(name string, limit int) []ExtensionLog {
+    list := make([]ExtensionLog, 0)
+
+    NewExtensionLog().
+        Where("name = ?", name).
+        Order("add_time DESC").
+        Limit(limit).
+        Find(&list)
+
+    return list
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+)
+
+// 数据库迁移
+var Migrations = []migration.Migration{
+    {
+        Name: "2026_10_18_000001_create_extension_log_table",
+        Up: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if !m.HasTable(&ExtensionLog{}) {
+                return m.CreateTable(&ExtensionLog{})
+            }
+
+            return nil
+        },
+        Down: func(db *gorm.DB) error {
+            m := db.Migrator()
+
+            if m.HasTable(&ExtensionLog{}) {
+                return m.DropTable(&ExtensionLog{})
+            }
+
+            return nil
+        },
+    },
+}
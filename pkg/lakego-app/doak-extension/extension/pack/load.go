@@ -0,0 +1,260 @@
+package pack
+
+import (
+    "os"
+    "io/fs"
+    "sort"
+    "strings"
+    "encoding/json"
+    "path/filepath"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/migration"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+// 扩展包安装目录
+func Dir() string {
+    dir := config.New("admin").GetString(conf("path"))
+    if dir == "" {
+        dir = "{root}/extension/package"
+    }
+
+    return path.FormatPath(dir)
+}
+
+// 安装中的扩展包目录，导入时忽略
+func StageDir(name string) string {
+    return filepath.Join(Dir(), name + ".tmp")
+}
+
+// 移动目录，已存在的目录会被替换
+func Replace(src string, dst string) error {
+    if err := os.RemoveAll(dst); err != nil {
+        return err
+    }
+
+    return os.Rename(src, dst)
+}
+
+// 静态资源发布目录
+func StaticPath(name string) string {
+    return path.PublicPath("/extension/" + name)
+}
+
+// 导入目录下全部已解压的扩展包
+func LoadDir(dir string) ([]extension.Extension, error) {
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil, nil
+        }
+
+        return nil, err
+    }
+
+    exts := make([]extension.Extension, 0)
+    for _, entry := range entries {
+        if !entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+            continue
+        }
+
+        ext, err := Load(filepath.Join(dir, entry.Name()))
+        if err != nil {
+            return exts, err
+        }
+
+        exts = append(exts, ext)
+    }
+
+    return exts, nil
+}
+
+// 导入已解压的扩展包
+func Load(dir string) (extension.Extension, error) {
+    var ext extension.Extension
+
+    data, err := os.ReadFile(filepath.Join(dir, InfoFile))
+    if err != nil {
+        return ext, err
+    }
+
+    if err := json.Unmarshal(data, &ext); err != nil {
+        return ext, err
+    }
+
+    if !ValidName(ext.Name) {
+        return ext, ErrInvalidName
+    }
+
+    ext.Migrations, err = loadMigrations(ext.Name, filepath.Join(dir, MigrationDir))
+    if err != nil {
+        return ext, err
+    }
+
+    name := ext.Name
+
+    ext.Install = func(tx *gorm.DB) error {
+        if err := publishConfig(dir); err != nil {
+            return err
+        }
+
+        return publishStatic(dir, name)
+    }
+
+    ext.Upgrade = ext.Install
+
+    ext.Uninstall = func(tx *gorm.DB) error {
+        return os.RemoveAll(StaticPath(name))
+    }
+
+    return ext, nil
+}
+
+// 数据库迁移，文件名为 [名称].up.sql 和 [名称].down.sql，按名称排序执行
+func loadMigrations(name string, dir string) ([]migration.Migration, error) {
+    files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+    if err != nil {
+        return nil, err
+    }
+
+    sort.Strings(files)
+
+    migrations := make([]migration.Migration, 0, len(files))
+    for _, file := range files {
+        base := strings.TrimSuffix(filepath.Base(file), ".up.sql")
+
+        up, err := os.ReadFile(file)
+        if err != nil {
+            return nil, err
+        }
+
+        down, err := os.ReadFile(filepath.Join(dir, base + ".down.sql"))
+        if err != nil && !os.IsNotExist(err) {
+            return nil, err
+        }
+
+        migrations = append(migrations, migration.Migration{
+            Name: name + "_" + base,
+            Up:   sqlExec(string(up)),
+            Down: sqlExec(string(down)),
+        })
+    }
+
+    return migrations, nil
+}
+
+// 执行 sql，{prefix} 替换为数据表前缀
+func sqlExec(sql string) func(*gorm.DB) error {
+    return func(db *gorm.DB) error {
+        stmts := SplitSQL(strings.ReplaceAll(sql, "{prefix}", tablePrefix()))
+
+        for _, stmt := range stmts {
+            if err := db.Exec(stmt).Error; err != nil {
+                return err
+            }
+        }
+
+        return nil
+    }
+}
+
+// 当前数据库连接的数据表前缀
+func tablePrefix() string {
+    cfg := config.New("database")
+
+    return cfg.GetString("connections." + cfg.GetString("default") + ".prefix")
+}
+
+// 拆分 sql 语句，忽略引号和 -- 注释内的分号
+func SplitSQL(sql string) []string {
+    stmts := make([]string, 0)
+
+    var quote byte
+    start := 0
+    for i := 0; i < len(sql); i++ {
+        c := sql[i]
+
+        switch {
+            case quote != 0:
+                if c == quote {
+                    quote = 0
+                }
+            case c == '\'' || c == '"' || c == '`':
+                quote = c
+            case c == '-' && strings.HasPrefix(sql[i:], "--"):
+                if n := strings.IndexByte(sql[i:], '\n'); n >= 0 {
+                    i += n
+                } else {
+                    i = len(sql)
+                }
+            case c == ';':
+                if stmt := strings.TrimSpace(sql[start:i]); stmt != "" {
+                    stmts = append(stmts, stmt)
+                }
+
+                start = i + 1
+        }
+    }
+
+    if stmt := strings.TrimSpace(sql[start:]); stmt != "" {
+        stmts = append(stmts, stmt)
+    }
+
+    return stmts
+}
+
+// 复制配置文件，已存在时不覆盖
+func publishConfig(dir string) error {
+    return copyDir(filepath.Join(dir, ConfigDir), path.ConfigPath(""), false)
+}
+
+// 发布静态资源
+func publishStatic(dir string, name string) error {
+    static := StaticPath(name)
+    if err := os.RemoveAll(static); err != nil {
+        return err
+    }
+
+    return copyDir(filepath.Join(dir, StaticDir), static, true)
+}
+
+// 复制目录
+func copyDir(src string, dst string, overwrite bool) error {
+    if _, err := os.Stat(src); os.IsNotExist(err) {
+        return nil
+    }
+
+    return filepath.WalkDir(src, func(file string, d fs.DirEntry, err error) error {
+        if err != nil || d.IsDir() {
+            return err
+        }
+
+        rel, err := filepath.Rel(src, file)
+        if err != nil {
+            return err
+        }
+
+        target := filepath.Join(dst, rel)
+        if !overwrite {
+            if _, err := os.Stat(target); err == nil {
+                return nil
+            }
+        }
+
+        data, err := os.ReadFile(file)
+        if err != nil {
+            return err
+        }
+
+        if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+            return err
+        }
+
+        return os.WriteFile(target, data, 0644)
+    })
+}
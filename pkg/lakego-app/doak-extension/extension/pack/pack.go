@@ -0,0 +1,316 @@
+package pack
+
+import (
+    "io"
+    "os"
+    "path"
+    "sort"
+    "bytes"
+    "errors"
+    "strings"
+    "archive/zip"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "path/filepath"
+
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+// 扩展包内文件
+const (
+    // 扩展信息
+    InfoFile = "extension.json"
+
+    // 签名
+    SignatureFile = "extension.sig"
+
+    // 静态资源目录
+    StaticDir = "static/"
+
+    // 数据库迁移目录
+    MigrationDir = "migrations/"
+
+    // 配置文件目录
+    ConfigDir = "config/"
+)
+
+// 解压后最大大小
+var MaxSize int64 = 100 << 20
+
+var (
+    // 扩展信息不存在
+    ErrInfoNotFound = errors.New("pack: extension.json not found")
+
+    // 文件路径错误
+    ErrInvalidPath = errors.New("pack: invalid file path")
+
+    // 扩展名称错误
+    ErrInvalidName = errors.New("pack: invalid extension name")
+
+    // 扩展包过大
+    ErrTooLarge = errors.New("pack: package too large")
+
+    // 签名不存在
+    ErrSignatureNotFound = errors.New("pack: signature not found")
+
+    // 签名错误
+    ErrSignatureInvalid = errors.New("pack: signature invalid")
+)
+
+/**
+ * 扩展包，zip 格式
+ *
+ * extension.json 扩展信息，和 Extension 结构一致
+ * extension.sig  签名，签名内容为 Manifest
+ * static/        静态资源，安装时复制到 {public}/extension/[name]
+ * migrations/    数据库迁移，[name].up.sql 和 [name].down.sql
+ * config/        配置文件，安装时复制到 {config}，已存在时不覆盖
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Package struct {
+    // 扩展信息
+    Info extension.Extension
+
+    // 文件，不包括签名
+    files map[string][]byte
+
+    // 签名
+    signature string
+}
+
+// 打开扩展包
+func Open(file string) (*Package, error) {
+    data, err := os.ReadFile(file)
+    if err != nil {
+        return nil, err
+    }
+
+    return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+// 读取扩展包
+func Read(r io.ReaderAt, size int64) (*Package, error) {
+    zr, err := zip.NewReader(r, size)
+    if err != nil {
+        return nil, err
+    }
+
+    p := &Package{
+        files: make(map[string][]byte),
+    }
+
+    var total int64
+    for _, f := range zr.File {
+        if f.FileInfo().IsDir() {
+            continue
+        }
+
+        name, err := cleanName(f.Name)
+        if err != nil {
+            return nil, err
+        }
+
+        data, err := readFile(f, MaxSize - total)
+        if err != nil {
+            return nil, err
+        }
+
+        total += int64(len(data))
+
+        if name == SignatureFile {
+            p.signature = strings.TrimSpace(string(data))
+            continue
+        }
+
+        p.files[name] = data
+    }
+
+    info, ok := p.files[InfoFile]
+    if !ok {
+        return nil, ErrInfoNotFound
+    }
+
+    if err := json.Unmarshal(info, &p.Info); err != nil {
+        return nil, err
+    }
+
+    if !ValidName(p.Info.Name) {
+        return nil, ErrInvalidName
+    }
+
+    return p, nil
+}
+
+// 文件列表
+func (this *Package) Files() []string {
+    names := make([]string, 0, len(this.files))
+    for name := range this.files {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+
+    return names
+}
+
+// 签名内容，每行为文件名和 sha256 摘要
+func (this *Package) Manifest() []byte {
+    var buf bytes.Buffer
+    for _, name := range this.Files() {
+        sum := sha256.Sum256(this.files[name])
+
+        buf.WriteString(name + ":" + hex.EncodeToString(sum[:]) + "\n")
+    }
+
+    return buf.Bytes()
+}
+
+// 是否有签名
+func (this *Package) Signed() bool {
+    return this.signature != ""
+}
+
+// 验证签名
+func (this *Package) Verify(signer Signer) error {
+    if this.signature == "" {
+        return ErrSignatureNotFound
+    }
+
+    if !signer.Verify(this.Manifest(), this.signature) {
+        return ErrSignatureInvalid
+    }
+
+    return nil
+}
+
+// 签名
+func (this *Package) Sign(signer Signer) error {
+    signature, err := signer.Sign(this.Manifest())
+    if err != nil {
+        return err
+    }
+
+    this.signature = signature
+
+    return nil
+}
+
+// 写入 zip 格式
+func (this *Package) Write(w io.Writer) error {
+    zw := zip.NewWriter(w)
+
+    for _, name := range this.Files() {
+        f, err := zw.Create(name)
+        if err != nil {
+            return err
+        }
+
+        if _, err := f.Write(this.files[name]); err != nil {
+            return err
+        }
+    }
+
+    if this.signature != "" {
+        f, err := zw.Create(SignatureFile)
+        if err != nil {
+            return err
+        }
+
+        if _, err := f.Write([]byte(this.signature)); err != nil {
+            return err
+        }
+    }
+
+    return zw.Close()
+}
+
+// 解压到目录，已存在的目录会被清除
+func (this *Package) Extract(dir string) error {
+    if err := os.RemoveAll(dir); err != nil {
+        return err
+    }
+
+    for name, data := range this.files {
+        file := filepath.Join(dir, filepath.FromSlash(name))
+        if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+            return err
+        }
+
+        if err := os.WriteFile(file, data, 0644); err != nil {
+            return err
+        }
+    }
+
+    if this.signature != "" {
+        err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte(this.signature), 0644)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 扩展名称只能包含小写字母、数字和 . _ -，
+// 不能以 .tmp 结尾，和安装中的目录区分
+func ValidName(name string) bool {
+    if name == "" || name == "." || name == ".." {
+        return false
+    }
+
+    if strings.HasSuffix(name, ".tmp") {
+        return false
+    }
+
+    for _, c := range name {
+        if !(c >= 'a' && c <= 'z') &&
+            !(c >= '0' && c <= '9') &&
+            c != '.' && c != '_' && c != '-' {
+            return false
+        }
+    }
+
+    return !strings.Contains(name, "..")
+}
+
+// 检测文件路径，不能跳出扩展目录
+func cleanName(name string) (string, error) {
+    name = strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./")
+
+    if name == "" || name == ".." ||
+        strings.HasPrefix(name, "/") ||
+        strings.HasPrefix(name, "../") ||
+        strings.Contains(name, ":") ||
+        path.Clean(name) != name {
+        return "", ErrInvalidPath
+    }
+
+    return name, nil
+}
+
+// 读取文件，限制大小
+func readFile(f *zip.File, limit int64) ([]byte, error) {
+    if limit <= 0 || int64(f.UncompressedSize64) > limit {
+        return nil, ErrTooLarge
+    }
+
+    rc, err := f.Open()
+    if err != nil {
+        return nil, err
+    }
+    defer rc.Close()
+
+    data, err := io.ReadAll(io.LimitReader(rc, limit + 1))
+    if err != nil {
+        return nil, err
+    }
+
+    if int64(len(data)) > limit {
+        return nil, ErrTooLarge
+    }
+
+    return data, nil
+}
@@ -0,0 +1,253 @@
+package pack
+
+import (
+    "os"
+    "bytes"
+    "testing"
+    "archive/zip"
+    "path/filepath"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+const testInfo = `{"name":"lakego.demo","title":"Demo","version":"1.0.0"}`
+
+// 生成 zip 数据，文件按顺序写入
+func makeZip(t *testing.T, files ...string) []byte {
+    var buf bytes.Buffer
+
+    zw := zip.NewWriter(&buf)
+    for i := 0; i + 1 < len(files); i += 2 {
+        f, err := zw.Create(files[i])
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        f.Write([]byte(files[i + 1]))
+    }
+
+    if err := zw.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    return buf.Bytes()
+}
+
+func readZip(data []byte) (*Package, error) {
+    return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+func Test_Read(t *testing.T) {
+    eq := assertT(t)
+
+    p, err := readZip(makeZip(t,
+        InfoFile, testInfo,
+        "static/app.js", "app",
+        "migrations/001_demo.up.sql", "CREATE TABLE demo (id int);",
+    ))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(p.Info.Name, "lakego.demo", "Info")
+    eq(len(p.Files()), 3, "Files")
+    eq(p.Files()[0], InfoFile, "Files sorted")
+    eq(p.Signed(), false, "Signed")
+
+    _, err = readZip(makeZip(t, "static/app.js", "app"))
+    eq(err, ErrInfoNotFound, "Read without info")
+
+    _, err = readZip(makeZip(t, InfoFile, `{"name":"../demo"}`))
+    eq(err, ErrInvalidName, "Read invalid name")
+}
+
+func Test_Read_InvalidPath(t *testing.T) {
+    eq := assertT(t)
+
+    for _, name := range []string{
+        "../evil.sh",
+        "/etc/passwd",
+        "static/../../evil.sh",
+        "static/./app.js",
+        "..\\evil.sh",
+        "static\\..\\..\\evil.sh",
+        "c:/evil.sh",
+        "static//app.js",
+    } {
+        _, err := readZip(makeZip(t, InfoFile, testInfo, name, "evil"))
+        eq(err, ErrInvalidPath, "Read path " + name)
+    }
+
+    // 当前目录前缀
+    p, err := readZip(makeZip(t, "./" + InfoFile, testInfo))
+    eq(err, nil, "Read ./ prefix")
+    eq(p.Files()[0], InfoFile, "Read ./ prefix name")
+}
+
+func Test_Read_TooLarge(t *testing.T) {
+    eq := assertT(t)
+
+    maxSize := MaxSize
+    t.Cleanup(func() {
+        MaxSize = maxSize
+    })
+
+    MaxSize = 100
+
+    _, err := readZip(makeZip(t, InfoFile, testInfo, "static/big.js", string(bytes.Repeat([]byte{'a'}, 101))))
+    eq(err, ErrTooLarge, "Read file too large")
+
+    // 全部文件合计大小
+    _, err = readZip(makeZip(t, InfoFile, testInfo, "static/a.js", string(bytes.Repeat([]byte{'a'}, 50))))
+    eq(err, ErrTooLarge, "Read total too large")
+
+    _, err = readZip(makeZip(t, InfoFile, testInfo, "static/a.js", "a"))
+    eq(err, nil, "Read within size")
+}
+
+func Test_Sign(t *testing.T) {
+    eq := assertT(t)
+
+    p, err := readZip(makeZip(t, InfoFile, testInfo, "static/app.js", "app"))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    signer := newSM2Signer(t, "")
+    eq(p.Verify(signer), ErrSignatureNotFound, "Verify without signature")
+
+    eq(p.Sign(signer), nil, "Sign")
+    eq(p.Verify(signer), nil, "Verify")
+
+    // 写入后读取，签名不在签名内容中
+    var buf bytes.Buffer
+    eq(p.Write(&buf), nil, "Write")
+
+    signed, err := readZip(buf.Bytes())
+    eq(err, nil, "Read signed")
+    eq(signed.Signed(), true, "Signed")
+    eq(bytes.Equal(signed.Manifest(), p.Manifest()), true, "Manifest")
+    eq(signed.Verify(signer), nil, "Verify signed")
+
+    // 修改文件后签名失效
+    signed.files["static/app.js"] = []byte("evil")
+    eq(signed.Verify(signer), ErrSignatureInvalid, "Verify changed file")
+
+    // 添加文件后签名失效
+    signed, _ = readZip(buf.Bytes())
+    signed.files["static/evil.js"] = []byte("evil")
+    eq(signed.Verify(signer), ErrSignatureInvalid, "Verify added file")
+}
+
+func Test_Extract(t *testing.T) {
+    eq := assertT(t)
+
+    p, err := readZip(makeZip(t, InfoFile, testInfo, "static/js/app.js", "app"))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    p.signature = "sig"
+
+    dir := filepath.Join(t.TempDir(), "lakego.demo")
+
+    // 已存在的文件会被清除
+    os.MkdirAll(dir, 0755)
+    os.WriteFile(filepath.Join(dir, "old.js"), []byte("old"), 0644)
+
+    eq(p.Extract(dir), nil, "Extract")
+
+    data, err := os.ReadFile(filepath.Join(dir, "static", "js", "app.js"))
+    eq(err, nil, "Extract file")
+    eq(string(data), "app", "Extract file data")
+
+    data, _ = os.ReadFile(filepath.Join(dir, SignatureFile))
+    eq(string(data), "sig", "Extract signature")
+
+    _, err = os.Stat(filepath.Join(dir, "old.js"))
+    eq(os.IsNotExist(err), true, "Extract clear old file")
+}
+
+func Test_LoadDir(t *testing.T) {
+    eq := assertT(t)
+
+    dir := t.TempDir()
+
+    p, _ := readZip(makeZip(t,
+        InfoFile, testInfo,
+        "migrations/001_demo.up.sql", "CREATE TABLE {prefix}demo (id int);",
+        "migrations/001_demo.down.sql", "DROP TABLE {prefix}demo;",
+    ))
+    p.Extract(filepath.Join(dir, "lakego.demo"))
+
+    // 安装中的目录不导入
+    other, _ := readZip(makeZip(t, InfoFile, `{"name":"lakego.other","version":"1.0.0"}`))
+    other.Extract(filepath.Join(dir, "lakego.other.tmp"))
+
+    exts, err := LoadDir(dir)
+    eq(err, nil, "LoadDir")
+    eq(len(exts), 1, "LoadDir skip staged")
+    eq(exts[0].Name, "lakego.demo", "LoadDir name")
+    eq(len(exts[0].Migrations), 1, "LoadDir migrations")
+    eq(exts[0].Migrations[0].Name, "lakego.demo_001_demo", "LoadDir migration name")
+
+    exts, err = LoadDir(filepath.Join(dir, "none"))
+    eq(err, nil, "LoadDir not exists")
+    eq(len(exts), 0, "LoadDir not exists len")
+}
+
+func Test_Replace(t *testing.T) {
+    eq := assertT(t)
+
+    dir := t.TempDir()
+
+    src := filepath.Join(dir, "lakego.demo.tmp")
+    dst := filepath.Join(dir, "lakego.demo")
+
+    os.MkdirAll(src, 0755)
+    os.WriteFile(filepath.Join(src, "new.js"), []byte("new"), 0644)
+    os.MkdirAll(dst, 0755)
+    os.WriteFile(filepath.Join(dst, "old.js"), []byte("old"), 0644)
+
+    eq(Replace(src, dst), nil, "Replace")
+
+    _, err := os.Stat(src)
+    eq(os.IsNotExist(err), true, "Replace src removed")
+
+    _, err = os.Stat(filepath.Join(dst, "old.js"))
+    eq(os.IsNotExist(err), true, "Replace old removed")
+
+    _, err = os.Stat(filepath.Join(dst, "new.js"))
+    eq(err, nil, "Replace new")
+}
+
+func Test_SplitSQL(t *testing.T) {
+    eq := assertT(t)
+
+    stmts := SplitSQL("-- comment; here\nCREATE TABLE a (v varchar(10) DEFAULT ';');\n\nINSERT INTO a VALUES (\"x;y\");")
+    eq(len(stmts), 2, "SplitSQL len")
+    eq(stmts[0], "-- comment; here\nCREATE TABLE a (v varchar(10) DEFAULT ';')", "SplitSQL quote and comment")
+    eq(stmts[1], "INSERT INTO a VALUES (\"x;y\")", "SplitSQL double quote")
+}
+
+func Test_ValidName(t *testing.T) {
+    eq := assertT(t)
+
+    eq(ValidName("lakego.demo"), true, "ValidName")
+    eq(ValidName("lakego_demo-1"), true, "ValidName chars")
+    eq(ValidName(""), false, "ValidName empty")
+    eq(ValidName(".."), false, "ValidName dot")
+    eq(ValidName("lakego..demo"), false, "ValidName double dot")
+    eq(ValidName("Lakego.Demo"), false, "ValidName upper")
+    eq(ValidName("lakego/demo"), false, "ValidName slash")
+
+    // 和安装中的目录区分
+    eq(ValidName("lakego.demo.tmp"), false, "ValidName tmp")
+    eq(ValidName("lakego.tmpl"), true, "ValidName tmpl")
+}
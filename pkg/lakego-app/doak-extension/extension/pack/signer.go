@@ -0,0 +1,192 @@
+package pack
+
+import (
+    "os"
+    "errors"
+    "encoding/base64"
+
+    "github.com/deatil/go-cryptobin/cryptobin/sm2"
+    "github.com/deatil/go-cryptobin/cryptobin/rsa"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+var (
+    // 签名方式不支持
+    ErrSignerNotSupport = errors.New("pack: signer not support")
+
+    // 密钥没有设置
+    ErrKeyNotConfigured = errors.New("pack: key not configured")
+)
+
+// 配置
+func conf(key string) string {
+    return "extension.package." + key
+}
+
+/**
+ * 扩展包签名
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Signer interface {
+    // 签名
+    Sign(data []byte) (string, error)
+
+    // 验证签名
+    Verify(data []byte, signature string) bool
+}
+
+// 是否需要验证签名
+func NeedVerify() bool {
+    return config.New("admin").GetBool(conf("verify"))
+}
+
+// 根据配置获取签名方式，密钥为 PEM 文件路径
+func GetSigner() (Signer, error) {
+    cfg := config.New("admin")
+
+    privateKey, err := readKey(cfg.GetString(conf("private-key")))
+    if err != nil {
+        return nil, err
+    }
+
+    publicKey, err := readKey(cfg.GetString(conf("public-key")))
+    if err != nil {
+        return nil, err
+    }
+
+    password := ""
+    if pass := cfg.GetString(conf("private-key-password")); pass != "" {
+        data, err := base64.StdEncoding.DecodeString(pass)
+        if err != nil {
+            return nil, err
+        }
+
+        password = string(data)
+    }
+
+    return NewSigner(cfg.GetString(conf("signer")), privateKey, publicKey, password)
+}
+
+// 签名方式
+func NewSigner(name string, privateKey []byte, publicKey []byte, password string) (Signer, error) {
+    if len(privateKey) == 0 && len(publicKey) == 0 {
+        return nil, ErrKeyNotConfigured
+    }
+
+    switch name {
+        case "", "sm2":
+            return &SM2Signer{privateKey, publicKey, password}, nil
+        case "rsa":
+            return &RSASigner{privateKey, publicKey, password}, nil
+    }
+
+    return nil, ErrSignerNotSupport
+}
+
+/**
+ * SM2 签名
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SM2Signer struct {
+    privateKey []byte
+    publicKey  []byte
+    password   string
+}
+
+// 签名
+func (this *SM2Signer) Sign(data []byte) (string, error) {
+    if len(this.privateKey) == 0 {
+        return "", ErrKeyNotConfigured
+    }
+
+    s := sm2.FromBytes(data)
+    if this.password != "" {
+        s = s.FromPrivateKeyWithPassword(this.privateKey, this.password)
+    } else {
+        s = s.FromPrivateKey(this.privateKey)
+    }
+
+    s = s.Sign()
+    if err := s.Error(); err != nil {
+        return "", err
+    }
+
+    return s.ToHexString(), nil
+}
+
+// 验证签名
+func (this *SM2Signer) Verify(data []byte, signature string) bool {
+    if len(this.publicKey) == 0 {
+        return false
+    }
+
+    return sm2.FromHexString(signature).
+        FromPublicKey(this.publicKey).
+        Verify(data).
+        ToVerify()
+}
+
+/**
+ * RSA 签名
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type RSASigner struct {
+    privateKey []byte
+    publicKey  []byte
+    password   string
+}
+
+// 签名
+func (this *RSASigner) Sign(data []byte) (string, error) {
+    if len(this.privateKey) == 0 {
+        return "", ErrKeyNotConfigured
+    }
+
+    r := rsa.FromBytes(data)
+    if this.password != "" {
+        r = r.FromPrivateKeyWithPassword(this.privateKey, this.password)
+    } else {
+        r = r.FromPrivateKey(this.privateKey)
+    }
+
+    r = r.Sign()
+    if err := r.Error(); err != nil {
+        return "", err
+    }
+
+    return r.ToHexString(), nil
+}
+
+// 验证签名
+func (this *RSASigner) Verify(data []byte, signature string) bool {
+    if len(this.publicKey) == 0 {
+        return false
+    }
+
+    return rsa.FromHexString(signature).
+        FromPublicKey(this.publicKey).
+        Verify(data).
+        ToVerify()
+}
+
+// 读取密钥文件，没有设置或者文件不存在时返回空
+func readKey(file string) ([]byte, error) {
+    if file == "" {
+        return nil, nil
+    }
+
+    data, err := os.ReadFile(path.FormatPath(file))
+    if err != nil && os.IsNotExist(err) {
+        return nil, nil
+    }
+
+    return data, err
+}
@@ -0,0 +1,105 @@
+package pack
+
+import (
+    "testing"
+
+    "github.com/deatil/go-cryptobin/cryptobin/sm2"
+    "github.com/deatil/go-cryptobin/cryptobin/rsa"
+)
+
+func newSM2Signer(t *testing.T, password string) Signer {
+    key := sm2.GenerateKey()
+
+    privateKey := key.CreatePrivateKey()
+    if password != "" {
+        privateKey = key.CreatePrivateKeyWithPassword(password)
+    }
+
+    publicKey := key.CreatePublicKey()
+    if err := publicKey.Error(); err != nil {
+        t.Fatal(err)
+    }
+
+    signer, err := NewSigner("sm2", privateKey.ToKeyBytes(), publicKey.ToKeyBytes(), password)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return signer
+}
+
+func newRSASigner(t *testing.T) Signer {
+    key := rsa.GenerateKey(2048)
+
+    publicKey := key.CreatePublicKey()
+    if err := publicKey.Error(); err != nil {
+        t.Fatal(err)
+    }
+
+    signer, err := NewSigner("rsa", key.CreatePrivateKey().ToKeyBytes(), publicKey.ToKeyBytes(), "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return signer
+}
+
+func Test_Signer(t *testing.T) {
+    signers := map[string]func(*testing.T) Signer{
+        "sm2": func(t *testing.T) Signer {
+            return newSM2Signer(t, "")
+        },
+        "sm2 password": func(t *testing.T) Signer {
+            return newSM2Signer(t, "123456")
+        },
+        "rsa": newRSASigner,
+    }
+
+    for name, newSigner := range signers {
+        t.Run(name, func(t *testing.T) {
+            eq := assertT(t)
+
+            signer := newSigner(t)
+            other := newSigner(t)
+
+            data := []byte("extension.json:abc\n")
+
+            signature, err := signer.Sign(data)
+            eq(err, nil, "Sign error")
+            eq(signer.Verify(data, signature), true, "Verify")
+
+            eq(signer.Verify([]byte("extension.json:abd\n"), signature), false, "Verify changed data")
+            eq(signer.Verify(data, "00" + signature[2:]), false, "Verify tampered signature")
+            eq(signer.Verify(data, "zz"), false, "Verify invalid signature")
+            eq(other.Verify(data, signature), false, "Verify other key")
+        })
+    }
+}
+
+func Test_NewSigner(t *testing.T) {
+    eq := assertT(t)
+
+    _, err := NewSigner("sm2", nil, nil, "")
+    eq(err, ErrKeyNotConfigured, "NewSigner without key")
+
+    _, err = NewSigner("dsa", []byte("key"), nil, "")
+    eq(err, ErrSignerNotSupport, "NewSigner not support")
+
+    key := sm2.GenerateKey()
+
+    // 只有公钥时不能签名
+    verifier, _ := NewSigner("", nil, key.CreatePublicKey().ToKeyBytes(), "")
+    _, err = verifier.Sign([]byte("data"))
+    eq(err, ErrKeyNotConfigured, "Sign without private key")
+
+    // 只有私钥时不能验证
+    signer, _ := NewSigner("", key.CreatePrivateKey().ToKeyBytes(), nil, "")
+    signature, err := signer.Sign([]byte("data"))
+    eq(err, nil, "Sign with private key")
+    eq(signer.Verify([]byte("data"), signature), false, "Verify without public key")
+
+    // rsa 公钥不能验证 sm2 签名
+    rsaSigner := newRSASigner(t)
+    rsaSignature, _ := rsaSigner.Sign([]byte("data"))
+    eq(verifier.Verify([]byte("data"), rsaSignature), false, "Verify rsa signature with sm2")
+}
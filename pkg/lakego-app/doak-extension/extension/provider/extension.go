@@ -1,13 +1,17 @@
 package provider
 
 import (
+    "log"
+
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/provider"
 
     admin_route "github.com/deatil/lakego-doak-admin/admin/support/route"
 
+    "github.com/deatil/lakego-doak-extension/extension/model"
     "github.com/deatil/lakego-doak-extension/extension/extension"
     ext_cmd "github.com/deatil/lakego-doak-extension/extension/cmd"
+    ext_service "github.com/deatil/lakego-doak-extension/extension/service"
     ext_router "github.com/deatil/lakego-doak-extension/extension/route"
 )
 
@@ -34,6 +38,9 @@ func (this *Extension) Boot() {
     // 路由
     this.loadRoute()
 
+    // 数据库迁移
+    this.loadMigration()
+
     // 加载扩展
     this.loadExtension()
 }
@@ -52,10 +59,20 @@ func (this *Extension) loadRoute() {
     })
 }
 
+// 数据库迁移
+func (this *Extension) loadMigration() {
+    this.AddMigrations("lakego-extension", model.Migrations...)
+}
+
 // 导入扩展
 func (this *Extension) loadExtension() {
     m := extension.GetManager()
 
+    // 导入已安装的扩展包
+    if err := ext_service.LoadPackages(); err != nil {
+        log.Printf("Error to load extension packages: %v", err)
+    }
+
     m.CallBooting()
 
     // 加载扩展
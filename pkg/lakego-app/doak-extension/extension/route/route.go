@@ -12,6 +12,8 @@ func Route(engine gin.IRouter) {
     extController := new(controller.Extension)
     engine.GET("/extension", extController.Index)
     engine.GET("/extension/local", extController.Local)
+    engine.POST("/extension/upload", extController.Upload)
+    engine.GET("/extension/:name/logs", extController.Logs)
     engine.POST("/extension/:name/install", extController.Inatll)
     engine.DELETE("/extension/:name/uninstall", extController.Uninstall)
     engine.PUT("/extension/:name/upgrade", extController.Upgrade)
@@ -3,11 +3,15 @@ package service
 import (
     "fmt"
     "errors"
+    "strings"
+
+    "gorm.io/gorm"
 
     "github.com/deatil/go-goch/goch"
     "github.com/deatil/go-datebin/datebin"
 
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/facade/config"
 
     admin_model "github.com/deatil/lakego-doak-admin/admin/model"
@@ -82,12 +86,47 @@ func (this *Extension) Local() []map[string]any {
     return newExts
 }
 
-// 安装扩展
+// 安装扩展，未安装的依赖扩展按依赖顺序先安装
 func (this *Extension) Inatll(name string) error {
     if name == "" {
         return errors.New("扩展不能为空")
     }
 
+    if model.IsInstallExtension(name) {
+        return errors.New("扩展已经安装")
+    }
+
+    order, err := NewResolver().InstallOrder(name)
+    if err != nil {
+        return err
+    }
+
+    installed := make([]string, 0, len(order))
+    for _, extName := range order {
+        if err := this.install(extName); err != nil {
+            // 回滚本次已安装的依赖扩展
+            this.uninstallBatch(installed)
+
+            return err
+        }
+
+        installed = append(installed, extName)
+    }
+
+    return nil
+}
+
+// 按安装的相反顺序卸载扩展
+func (this *Extension) uninstallBatch(names []string) {
+    for i := len(names) - 1; i >= 0; i-- {
+        if err := this.Uninstall(names[i]); err != nil {
+            facade.Logger.Error("[extension] rollback install error: " + err.Error())
+        }
+    }
+}
+
+// 安装单个扩展
+func (this *Extension) install(name string) error {
     extManager := extension.GetManager()
 
     info := extManager.GetExtension(name)
@@ -96,7 +135,7 @@ func (this *Extension) Inatll(name string) error {
     }
 
     if !extManager.ValidateInfo(info) {
-        return errors.New("扩展信息不完整")
+        return errors.New(fmt.Sprintf("扩展[%s]信息不完整", name))
     }
 
     adminVersion := config.New("version").GetString("version")
@@ -114,68 +153,86 @@ func (this *Extension) Inatll(name string) error {
         return errors.New("扩展已经安装")
     }
 
-    ip := "0.0.0.0"
-    if this.Ctx != nil {
-        ip = router.GetRequestIp(this.Ctx)
-    }
+    ran := countExtensionMigrations(info)
 
-    insertData := model.Extension{
-        Name: name,
-        Title: info.Title,
-        Version: info.Version,
-        Adaptation: info.Adaptation,
-        Info: string(info.ToJSON()),
-        Listorder: 100,
-        Status: 0,
-        UpdateTime: int(datebin.NowTimestamp()),
-        UpdateIp: ip,
-        AddTime: int(datebin.NowTimestamp()),
-        AddIp: ip,
-    }
+    err = this.newOperation(model.ActionInstall, name, "", info.Version).run(
+        func(tx *gorm.DB) error {
+            // 数据库迁移
+            if err := runExtensionMigrations(tx, info); err != nil {
+                return err
+            }
 
-    err = model.NewDB().
-        Create(&insertData).
-        Error
-    if err != nil {
-        return errors.New("安装扩展失败")
-    }
+            insertData := model.Extension{
+                Name: name,
+                Title: info.Title,
+                Version: info.Version,
+                Adaptation: info.Adaptation,
+                Info: string(info.ToJSON()),
+                Listorder: 100,
+                Status: 0,
+                UpdateTime: int(datebin.NowTimestamp()),
+                UpdateIp: this.ip(),
+                AddTime: int(datebin.NowTimestamp()),
+                AddIp: this.ip(),
+            }
+
+            if err := tx.Create(&insertData).Error; err != nil {
+                return errors.New("安装扩展失败")
+            }
 
-    // 执行方法
-    if info.Install != nil {
-        info.Install()
+            return nil
+        },
+        hookStep(name, info.Install),
+    )
+    if err != nil {
+        revertExtensionMigrations(info, ran)
     }
 
-    return nil
+    return err
 }
 
-// 卸载扩展
+// 卸载扩展，已启用时先禁用
 func (this *Extension) Uninstall(name string) error {
     if name == "" {
         return errors.New("扩展不能为空")
     }
 
-    if model.IsEnableExtension(name) {
-        return errors.New("扩展没有被安装或者请先禁用扩展")
+    installInfo := model.GetExtension(name)
+    if installInfo.ID == "" {
+        return errors.New("扩展没有被安装")
     }
 
-    // 删除
-    err := model.NewExtension().
-        Where("name = ?", name).
-        Delete(&model.Extension{}).
-        Error
-    if err != nil {
-        return errors.New("卸载扩展失败")
+    // 被其他扩展依赖时不能卸载
+    if dependents := NewResolver().Dependents(name); len(dependents) > 0 {
+        return errors.New(fmt.Sprintf("扩展[%s]依赖当前扩展，不能卸载", strings.Join(dependents, ",")))
     }
 
-    info := extension.GetManager().GetExtension(name)
-    if info.Name != "" {
-        // 执行方法
-        if info.Uninstall != nil {
-            info.Uninstall()
+    if installInfo.Status == 1 {
+        if err := this.Disable(name); err != nil {
+            return err
         }
     }
 
-    return nil
+    info := extension.GetManager().GetExtension(name)
+
+    // 先执行扩展方法，失败时不回滚迁移和删除记录
+    return this.newOperation(model.ActionUninstall, name, installInfo.Version, "").run(
+        hookStep(name, info.Uninstall),
+        func(tx *gorm.DB) error {
+            // 回滚数据库迁移
+            if info.Name != "" {
+                if err := rollbackExtensionMigrations(tx, info); err != nil {
+                    return err
+                }
+            }
+
+            if err := deleteExtension(tx, name); err != nil {
+                return errors.New("卸载扩展失败")
+            }
+
+            return nil
+        },
+    )
 }
 
 // 更新扩展
@@ -221,32 +278,41 @@ func (this *Extension) Upgrade(name string) error {
         return err
     }
 
-    ip := "0.0.0.0"
-    if this.Ctx != nil {
-        ip = router.GetRequestIp(this.Ctx)
+    // 更新后的版本需要满足依赖当前扩展的扩展
+    if err = NewResolver().CheckDependents(name, info.Version); err != nil {
+        return err
     }
 
-    err = model.NewExtension().
-        Where("name = ?", name).
-        Updates(map[string]any{
-            "title": info.Title,
-            "version": info.Version,
-            "adaptation": info.Adaptation,
-            "info": string(info.ToJSON()),
-            "update_time": int(datebin.NowTimestamp()),
-            "update_ip": ip,
-        }).
-        Error
-    if err != nil {
-        return errors.New("更新扩展失败")
-    }
+    ran := countExtensionMigrations(info)
+
+    err = this.newOperation(model.ActionUpgrade, name, installInfo.Version, info.Version).run(
+        func(tx *gorm.DB) error {
+            // 数据库迁移
+            if err := runExtensionMigrations(tx, info); err != nil {
+                return err
+            }
 
-    // 执行方法
-    if info.Upgrade != nil {
-        info.Upgrade()
+            err := updateExtension(tx, name, map[string]any{
+                "title": info.Title,
+                "version": info.Version,
+                "adaptation": info.Adaptation,
+                "info": string(info.ToJSON()),
+                "update_time": int(datebin.NowTimestamp()),
+                "update_ip": this.ip(),
+            })
+            if err != nil {
+                return errors.New("更新扩展失败")
+            }
+
+            return nil
+        },
+        hookStep(name, info.Upgrade),
+    )
+    if err != nil {
+        revertExtensionMigrations(info, ran)
     }
 
-    return nil
+    return err
 }
 
 // 启用扩展
@@ -260,26 +326,25 @@ func (this *Extension) Enable(name string) error {
         return errors.New("扩展不存在")
     }
 
-    if model.IsEnableExtension(name) {
-        return errors.New("扩展已经启用")
+    installInfo := model.GetExtension(name)
+    if installInfo.ID == "" {
+        return errors.New("扩展没有被安装")
     }
 
-    err := model.NewExtension().
-        Where("name = ?", name).
-        Updates(map[string]any{
-            "status": 1,
-        }).
-        Error
-    if err != nil {
-        return errors.New("启用扩展失败")
+    if installInfo.Status == 1 {
+        return errors.New("扩展已经启用")
     }
 
-    // 执行方法
-    if info.Enable != nil {
-        info.Enable()
-    }
+    return this.newOperation(model.ActionEnable, name, installInfo.Version, installInfo.Version).run(
+        func(tx *gorm.DB) error {
+            if err := updateExtension(tx, name, map[string]any{"status": 1}); err != nil {
+                return errors.New("启用扩展失败")
+            }
 
-    return nil
+            return nil
+        },
+        hookStep(name, info.Enable),
+    )
 }
 
 // 禁用扩展
@@ -297,22 +362,23 @@ func (this *Extension) Disable(name string) error {
         return errors.New("扩展已经禁用")
     }
 
-    err := model.NewExtension().
-        Where("name = ?", name).
-        Updates(map[string]any{
-            "status": 0,
-        }).
-        Error
-    if err != nil {
-        return errors.New("禁用扩展失败")
-    }
+    installInfo := model.GetExtension(name)
 
-    // 执行方法
-    if info.Disable != nil {
-        info.Disable()
-    }
+    return this.newOperation(model.ActionDisable, name, installInfo.Version, installInfo.Version).run(
+        func(tx *gorm.DB) error {
+            if err := updateExtension(tx, name, map[string]any{"status": 0}); err != nil {
+                return errors.New("禁用扩展失败")
+            }
 
-    return nil
+            return nil
+        },
+        hookStep(name, info.Disable),
+    )
+}
+
+// 扩展操作记录
+func (this *Extension) Logs(name string, limit int) []model.ExtensionLog {
+    return model.GetExtensionLogs(name, limit)
 }
 
 // 更改排序
@@ -343,3 +409,18 @@ func (this *Extension) Listorder(name string, listorder int) error {
 
     return nil
 }
+
+// 更新扩展信息
+func updateExtension(tx *gorm.DB, name string, data map[string]any) error {
+    return tx.Model(&model.Extension{}).
+        Where("name = ?", name).
+        Updates(data).
+        Error
+}
+
+// 删除扩展信息
+func deleteExtension(tx *gorm.DB, name string) error {
+    return tx.Where("name = ?", name).
+        Delete(&model.Extension{}).
+        Error
+}
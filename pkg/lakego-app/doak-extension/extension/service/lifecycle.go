@@ -0,0 +1,229 @@
+package service
+
+import (
+    "fmt"
+    "errors"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/facade"
+    "github.com/deatil/lakego-doak/lakego/database"
+    "github.com/deatil/lakego-doak/lakego/migration"
+
+    "github.com/deatil/lakego-doak-extension/extension/model"
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+/**
+ * 扩展操作
+ *
+ * 状态变更为 pending -> [执行中] -> [完成] / failed
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type operation struct {
+    // 操作记录
+    log model.ExtensionLog
+
+    // 事件
+    event *extension.Event
+}
+
+// 创建操作记录
+func (this *Extension) newOperation(action string, name string, fromVersion string, version string) *operation {
+    now := int(datebin.NowTimestamp())
+
+    op := &operation{
+        log: model.ExtensionLog{
+            Name: name,
+            Action: action,
+            FromVersion: fromVersion,
+            Version: version,
+            State: model.StatePending,
+            UpdateTime: now,
+            AddTime: now,
+            AddIp: this.ip(),
+        },
+        event: &extension.Event{
+            Name: name,
+            Action: action,
+            FromVersion: fromVersion,
+            Version: version,
+        },
+    }
+
+    // 记录失败不影响操作
+    if err := model.NewDB().Create(&op.log).Error; err != nil {
+        facade.Logger.Error("[extension] create log error: " + err.Error())
+    }
+
+    return op
+}
+
+// 变更状态
+func (this *operation) transition(state string, message string) error {
+    if !model.CanTransition(this.log.Action, this.log.State, state) {
+        return errors.New(fmt.Sprintf("扩展操作状态[%s]不能变更为[%s]", this.log.State, state))
+    }
+
+    this.log.State = state
+    this.log.Message = message
+    this.log.UpdateTime = int(datebin.NowTimestamp())
+
+    if this.log.ID == "" {
+        return nil
+    }
+
+    err := model.NewExtensionLog().
+        Where("id = ?", this.log.ID).
+        Updates(map[string]any{
+            "state": this.log.State,
+            "message": this.log.Message,
+            "update_time": this.log.UpdateTime,
+        }).
+        Error
+    if err != nil {
+        facade.Logger.Error("[extension] update log error: " + err.Error())
+    }
+
+    return nil
+}
+
+// 操作失败
+func (this *operation) fail(err error) error {
+    this.transition(model.StateFailed, err.Error())
+
+    extension.TriggerFailed(this.event, err)
+
+    return err
+}
+
+// 执行操作，执行中事件可以阻止操作。
+// 数据库变更和扩展方法在同一事务内按顺序执行，任一步骤失败时回滚事务。
+// 扩展方法需要放在删除数据的步骤之前，扩展方法失败时不会丢失数据
+func (this *operation) run(steps ...func(tx *gorm.DB) error) error {
+    running := model.RunningState(this.log.Action)
+    if err := extension.TriggerRunning(running, this.event); err != nil {
+        return this.fail(errors.New(fmt.Sprintf("扩展操作被阻止: %s", err.Error())))
+    }
+
+    if err := this.transition(running, ""); err != nil {
+        return this.fail(err)
+    }
+
+    // 提交后再执行模型的 AfterCommit 回调，比如清除权限规则缓存
+    err := database.Transaction(model.NewDB(), func(tx *gorm.DB) error {
+        for _, step := range steps {
+            if err := step(tx); err != nil {
+                return err
+            }
+        }
+
+        return nil
+    })
+    if err != nil {
+        return this.fail(err)
+    }
+
+    done := model.DoneState(this.log.Action)
+    if err := this.transition(done, ""); err != nil {
+        return err
+    }
+
+    extension.TriggerDone(done, this.event)
+
+    return nil
+}
+
+// 请求 IP
+func (this *Extension) ip() string {
+    if this.Ctx != nil {
+        return router.GetRequestIp(this.Ctx)
+    }
+
+    return "0.0.0.0"
+}
+
+// 执行扩展方法的操作步骤
+func hookStep(name string, hook func(tx *gorm.DB) error) func(tx *gorm.DB) error {
+    return func(tx *gorm.DB) error {
+        if hook == nil {
+            return nil
+        }
+
+        if err := hook(tx); err != nil {
+            return errors.New(fmt.Sprintf("扩展[%s]执行失败: %s", name, err.Error()))
+        }
+
+        return nil
+    }
+}
+
+// 执行扩展数据库迁移，迁移分组为扩展名称
+func runExtensionMigrations(db *gorm.DB, info extension.Extension) error {
+    if len(info.Migrations) == 0 {
+        return nil
+    }
+
+    migration.Register(info.Name, info.Migrations...)
+
+    _, err := migration.NewMigrator(db).
+        Run(migration.Migrations(info.Name))
+    if err != nil {
+        return errors.New(fmt.Sprintf("扩展数据库迁移失败: %s", err.Error()))
+    }
+
+    return nil
+}
+
+// 回滚扩展数据库迁移
+func rollbackExtensionMigrations(db *gorm.DB, info extension.Extension) error {
+    if len(info.Migrations) == 0 {
+        return nil
+    }
+
+    migration.Register(info.Name, info.Migrations...)
+
+    _, err := migration.NewMigrator(db).
+        Reset(migration.Migrations(info.Name), info.Name)
+    if err != nil {
+        return errors.New(fmt.Sprintf("扩展数据库迁移回滚失败: %s", err.Error()))
+    }
+
+    return nil
+}
+
+// 已执行的扩展数据库迁移数量
+func countExtensionMigrations(info extension.Extension) int {
+    if len(info.Migrations) == 0 {
+        return 0
+    }
+
+    records, err := migration.NewMigrator(model.NewDB()).Ran(info.Name)
+    if err != nil {
+        return 0
+    }
+
+    return len(records)
+}
+
+// 事务回滚后撤销本次执行的迁移，
+// 部分数据库的表结构变更不能被事务回滚
+func revertExtensionMigrations(info extension.Extension, ran int) {
+    count := countExtensionMigrations(info)
+    if count <= ran {
+        return
+    }
+
+    migration.Register(info.Name, info.Migrations...)
+
+    _, err := migration.NewMigrator(model.NewDB()).
+        Rollback(migration.Migrations(info.Name), count - ran, info.Name)
+    if err != nil {
+        facade.Logger.Error("[extension] revert migrations error: " + err.Error())
+    }
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+    "errors"
+    "testing"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/go-events/events"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+
+    "github.com/deatil/lakego-doak-extension/extension/model"
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+// 测试扩展，调用的扩展方法按顺序记录
+type testLifecycle struct {
+    calls []string
+}
+
+func (this *testLifecycle) hook(name string, err error) func(*gorm.DB) error {
+    return func(*gorm.DB) error {
+        this.calls = append(this.calls, name)
+
+        return err
+    }
+}
+
+func migrateLifecycle(t *testing.T) {
+    migrate(t)
+
+    t.Cleanup(func() {
+        db := model.NewDB()
+        db.Migrator().DropTable(migration.NewMigrator(db).GetTable(), "lc_demo")
+    })
+}
+
+// 添加扩展，数据表迁移为 table 时创建对应的数据表
+func extendTestExtension(t *testing.T, name string, table string, require map[string]string) extension.Extension {
+    ext := extension.Extension{
+        Name:        name,
+        Title:       name,
+        Description: name,
+        Keywords:    []string{"demo"},
+        Authors:     []extension.Author{{Name: "deatil"}},
+        Version:     "1.0.0",
+        Adaptation:  "^1.3",
+        Require:     require,
+    }
+
+    if table != "" {
+        ext.Migrations = []migration.Migration{
+            {
+                Name: name + "_create_" + table,
+                Up: func(db *gorm.DB) error {
+                    return db.Exec("CREATE TABLE " + table + " (id int, value varchar(20))").Error
+                },
+                Down: func(db *gorm.DB) error {
+                    return db.Exec("DROP TABLE " + table).Error
+                },
+            },
+        }
+    }
+
+    extension.Extend(ext)
+
+    t.Cleanup(func() {
+        extension.GetManager().Forget(name)
+    })
+
+    return ext
+}
+
+// 是否有对应状态的操作记录
+func hasLogState(name string, action string, state string) bool {
+    var count int64
+    model.NewExtensionLog().
+        Where("name = ? AND action = ? AND state = ?", name, action, state).
+        Count(&count)
+
+    return count > 0
+}
+
+func Test_Uninstall_HookFailed(t *testing.T) {
+    eq := assertT(t)
+
+    migrateLifecycle(t)
+
+    ext := extendTestExtension(t, "lakego.lc-demo", "lc_demo", nil)
+
+    eq(NewExtension().Inatll(ext.Name), nil, "Inatll")
+
+    db := model.NewDB()
+    db.Exec("INSERT INTO lc_demo (id, value) VALUES (1, 'keep')")
+
+    // 卸载方法失败时不回滚迁移和删除记录
+    lc := &testLifecycle{}
+    ext.Uninstall = lc.hook("uninstall", errors.New("uninstall error"))
+    extension.Extend(ext)
+
+    err := NewExtension().Uninstall(ext.Name)
+    eq(err != nil, true, "Uninstall failed")
+    eq(len(lc.calls), 1, "Uninstall hook called")
+    eq(model.IsInstallExtension(ext.Name), true, "Uninstall failed still installed")
+    eq(hasLogState(ext.Name, model.ActionUninstall, model.StateFailed), true, "Uninstall failed log")
+
+    var value string
+    db.Raw("SELECT value FROM lc_demo WHERE id = 1").Scan(&value)
+    eq(value, "keep", "Uninstall failed keep data")
+
+    // 卸载方法成功后回滚迁移
+    ext.Uninstall = lc.hook("uninstall", nil)
+    extension.Extend(ext)
+
+    eq(NewExtension().Uninstall(ext.Name), nil, "Uninstall")
+    eq(model.IsInstallExtension(ext.Name), false, "Uninstall not installed")
+    eq(db.Migrator().HasTable("lc_demo"), false, "Uninstall drop table")
+    eq(hasLogState(ext.Name, model.ActionUninstall, model.StateUninstalled), true, "Uninstall log")
+}
+
+func Test_Install_HookFailed(t *testing.T) {
+    eq := assertT(t)
+
+    migrateLifecycle(t)
+
+    ext := extendTestExtension(t, "lakego.lc-demo", "lc_demo", nil)
+
+    lc := &testLifecycle{}
+    ext.Install = lc.hook("install", errors.New("install error"))
+    extension.Extend(ext)
+
+    // 安装方法失败时回滚迁移和安装记录
+    err := NewExtension().Inatll(ext.Name)
+    eq(err != nil, true, "Inatll failed")
+    eq(len(lc.calls), 1, "Inatll hook called")
+    eq(model.IsInstallExtension(ext.Name), false, "Inatll failed not installed")
+    eq(model.NewDB().Migrator().HasTable("lc_demo"), false, "Inatll failed table")
+    eq(countExtensionMigrations(ext), 0, "Inatll failed migrations")
+    eq(hasLogState(ext.Name, model.ActionInstall, model.StateFailed), true, "Inatll failed log")
+}
+
+func Test_Install_RollbackBatch(t *testing.T) {
+    eq := assertT(t)
+
+    migrateLifecycle(t)
+
+    lc := &testLifecycle{}
+
+    dep := extendTestExtension(t, "lakego.lc-dep", "lc_demo", nil)
+    dep.Install = lc.hook("dep.install", nil)
+    dep.Uninstall = lc.hook("dep.uninstall", nil)
+    extension.Extend(dep)
+
+    ext := extendTestExtension(t, "lakego.lc-app", "", map[string]string{
+        dep.Name: "^1.0",
+    })
+    ext.Install = lc.hook("app.install", errors.New("install error"))
+    extension.Extend(ext)
+
+    // 依赖扩展先安装，当前扩展安装失败时卸载本次安装的依赖扩展
+    err := NewExtension().Inatll(ext.Name)
+    eq(err != nil, true, "Inatll failed")
+    eq(len(lc.calls), 3, "Inatll calls")
+    eq(lc.calls[0], "dep.install", "Inatll dep first")
+    eq(lc.calls[1], "app.install", "Inatll app")
+    eq(lc.calls[2], "dep.uninstall", "Inatll rollback dep")
+
+    eq(model.IsInstallExtension(ext.Name), false, "Inatll app not installed")
+    eq(model.IsInstallExtension(dep.Name), false, "Inatll dep rollback")
+    eq(model.NewDB().Migrator().HasTable("lc_demo"), false, "Inatll dep table rollback")
+
+    // 已安装的依赖扩展不会被卸载
+    ext.Install = nil
+    extension.Extend(ext)
+
+    dep.Install = nil
+    extension.Extend(dep)
+
+    eq(NewExtension().Inatll(dep.Name), nil, "Inatll dep")
+
+    ext.Install = lc.hook("app.install", errors.New("install error"))
+    extension.Extend(ext)
+
+    eq(NewExtension().Inatll(ext.Name) != nil, true, "Inatll app failed")
+    eq(model.IsInstallExtension(dep.Name), true, "Inatll installed dep kept")
+}
+
+func Test_Enable_HookRollback(t *testing.T) {
+    eq := assertT(t)
+
+    migrateLifecycle(t)
+
+    ext := extendTestExtension(t, "lakego.lc-demo", "lc_demo", nil)
+
+    eq(NewExtension().Inatll(ext.Name), nil, "Inatll")
+
+    // 扩展方法在操作事务中写入，失败时一起回滚
+    ext.Enable = func(tx *gorm.DB) error {
+        if err := tx.Exec("INSERT INTO lc_demo (id, value) VALUES (1, 'enable')").Error; err != nil {
+            return err
+        }
+
+        return errors.New("enable error")
+    }
+    extension.Extend(ext)
+
+    err := NewExtension().Enable(ext.Name)
+    eq(err != nil, true, "Enable failed")
+    eq(model.IsEnableExtension(ext.Name), false, "Enable failed status")
+
+    var count int64
+    model.NewDB().Raw("SELECT COUNT(*) FROM lc_demo").Scan(&count)
+    eq(count, int64(0), "Enable failed rollback hook write")
+
+    ext.Enable = func(tx *gorm.DB) error {
+        return tx.Exec("INSERT INTO lc_demo (id, value) VALUES (1, 'enable')").Error
+    }
+    extension.Extend(ext)
+
+    eq(NewExtension().Enable(ext.Name), nil, "Enable")
+    eq(model.IsEnableExtension(ext.Name), true, "Enable status")
+
+    model.NewDB().Raw("SELECT COUNT(*) FROM lc_demo").Scan(&count)
+    eq(count, int64(1), "Enable hook write")
+}
+
+func Test_Veto(t *testing.T) {
+    eq := assertT(t)
+
+    migrateLifecycle(t)
+
+    lc := &testLifecycle{}
+
+    ext := extendTestExtension(t, "lakego.lc-demo", "lc_demo", nil)
+    ext.Install = lc.hook("install", nil)
+    ext.Uninstall = lc.hook("uninstall", nil)
+    extension.Extend(ext)
+
+    veto := func(e *extension.Event) *extension.Event {
+        if e.Name == ext.Name {
+            e.Veto(errors.New("not allowed"))
+        }
+
+        return e
+    }
+
+    installing := extension.EventPrefix + model.StateInstalling
+    uninstalling := extension.EventPrefix + model.StateUninstalling
+
+    t.Cleanup(func() {
+        events.Default().Filter().Remove(installing)
+        events.Default().Filter().Remove(uninstalling)
+    })
+
+    // 阻止安装
+    events.AddFilter(installing, veto, events.DefaultSort)
+
+    err := NewExtension().Inatll(ext.Name)
+    eq(err != nil, true, "Inatll veto")
+    eq(len(lc.calls), 0, "Inatll veto hook")
+    eq(model.IsInstallExtension(ext.Name), false, "Inatll veto not installed")
+    eq(model.NewDB().Migrator().HasTable("lc_demo"), false, "Inatll veto table")
+    eq(hasLogState(ext.Name, model.ActionInstall, model.StateFailed), true, "Inatll veto log")
+
+    events.Default().Filter().Remove(installing)
+
+    eq(NewExtension().Inatll(ext.Name), nil, "Inatll")
+
+    // 阻止卸载
+    events.AddFilter(uninstalling, veto, events.DefaultSort)
+
+    err = NewExtension().Uninstall(ext.Name)
+    eq(err != nil, true, "Uninstall veto")
+    eq(len(lc.calls), 1, "Uninstall veto hook")
+    eq(model.IsInstallExtension(ext.Name), true, "Uninstall veto still installed")
+    eq(model.NewDB().Migrator().HasTable("lc_demo"), true, "Uninstall veto table")
+}
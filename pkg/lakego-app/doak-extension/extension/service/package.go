@@ -0,0 +1,146 @@
+package service
+
+import (
+    "os"
+    "fmt"
+    "bytes"
+    "errors"
+    "path/filepath"
+
+    "github.com/deatil/lakego-doak-extension/extension/pack"
+    "github.com/deatil/lakego-doak-extension/extension/model"
+    "github.com/deatil/lakego-doak-extension/extension/version"
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+// 导入已解压的扩展包
+func LoadPackages() error {
+    exts, err := pack.LoadDir(pack.Dir())
+    for _, ext := range exts {
+        extension.Extend(ext)
+    }
+
+    return err
+}
+
+// 从扩展包安装，已安装时更新
+func (this *Extension) InstallPackage(file string) error {
+    p, err := pack.Open(file)
+    if err != nil {
+        return errors.New("扩展包读取失败: " + err.Error())
+    }
+
+    if pack.NeedVerify() {
+        signer, err := pack.GetSigner()
+        if err != nil {
+            return errors.New("扩展包签名配置错误: " + err.Error())
+        }
+
+        if err := p.Verify(signer); err != nil {
+            return errors.New("扩展包签名验证失败")
+        }
+    }
+
+    info := p.Info
+    if !extension.GetManager().ValidateInfo(info) {
+        return errors.New("扩展信息不完整")
+    }
+
+    // 已安装时需要禁用后更新
+    installInfo := model.GetExtension(info.Name)
+    if installInfo.ID != "" {
+        if installInfo.Status != 0 {
+            return errors.New("更新请先禁用扩展")
+        }
+
+        err := version.VersionCheck(info.Version, fmt.Sprintf("> %s", installInfo.Version))
+        if err != nil {
+            return errors.New(fmt.Sprintf("扩展[%s]升级到版本[%s]错误", installInfo.Version, info.Version))
+        }
+    }
+
+    // 编译在系统内的扩展不能被扩展包替换
+    if current := extension.GetManager().GetExtension(info.Name); current.Name != "" {
+        if _, err := os.Stat(filepath.Join(pack.Dir(), info.Name, pack.InfoFile)); err != nil {
+            return errors.New("扩展已存在")
+        }
+    }
+
+    if err := os.MkdirAll(pack.Dir(), 0755); err != nil {
+        return err
+    }
+
+    // 先解压到临时目录，安装成功后再移动到扩展包目录。
+    // 失败时删除临时目录，启动时不会导入安装失败的扩展包
+    stage := pack.StageDir(info.Name)
+    defer os.RemoveAll(stage)
+
+    if err := p.Extract(stage); err != nil {
+        return errors.New("扩展包解压失败: " + err.Error())
+    }
+
+    ext, err := pack.Load(stage)
+    if err != nil {
+        return errors.New("扩展包导入失败: " + err.Error())
+    }
+
+    previous, previousErr := extension.GetManager().GetExtend(info.Name)
+
+    extension.Extend(ext)
+
+    if installInfo.ID != "" {
+        err = this.Upgrade(info.Name)
+    } else {
+        err = this.Inatll(info.Name)
+    }
+
+    if err != nil {
+        // 恢复之前导入的扩展
+        if previousErr == nil {
+            extension.Extend(previous)
+        } else {
+            extension.GetManager().Forget(info.Name)
+        }
+
+        return err
+    }
+
+    dir := filepath.Join(pack.Dir(), info.Name)
+    if err := pack.Replace(stage, dir); err != nil {
+        return errors.New("扩展包移动失败: " + err.Error())
+    }
+
+    // 扩展方法使用移动后的目录
+    ext, err = pack.Load(dir)
+    if err != nil {
+        return errors.New("扩展包导入失败: " + err.Error())
+    }
+
+    extension.Extend(ext)
+
+    return nil
+}
+
+// 使用配置的私钥签名扩展包
+func SignPackage(file string) error {
+    p, err := pack.Open(file)
+    if err != nil {
+        return err
+    }
+
+    signer, err := pack.GetSigner()
+    if err != nil {
+        return err
+    }
+
+    if err := p.Sign(signer); err != nil {
+        return err
+    }
+
+    var buf bytes.Buffer
+    if err := p.Write(&buf); err != nil {
+        return err
+    }
+
+    return os.WriteFile(file, buf.Bytes(), 0644)
+}
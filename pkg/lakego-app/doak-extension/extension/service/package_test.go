@@ -0,0 +1,148 @@
+package service
+
+import (
+    "os"
+    "testing"
+    "archive/zip"
+    "encoding/json"
+    "path/filepath"
+
+    "github.com/deatil/lakego-doak-extension/extension/pack"
+    "github.com/deatil/lakego-doak-extension/extension/model"
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+const testPackageName = "lakego.package-demo"
+
+func migrate(t *testing.T) {
+    if err := model.NewDB().AutoMigrate(&model.Extension{}, &model.ExtensionLog{}); err != nil {
+        t.Fatal(err)
+    }
+
+    t.Cleanup(func() {
+        model.NewDB().Migrator().DropTable(&model.Extension{}, &model.ExtensionLog{})
+    })
+}
+
+// 生成扩展包文件
+func makePackage(t *testing.T, ver string, adaptation string, require map[string]string) string {
+    info, _ := json.Marshal(map[string]any{
+        "name":        testPackageName,
+        "title":       "Package demo",
+        "description": "Package demo",
+        "keywords":    []string{"demo"},
+        "authors":     []map[string]string{{"name": "deatil"}},
+        "version":     ver,
+        "adaptation":  adaptation,
+        "require":     require,
+    })
+
+    file := filepath.Join(t.TempDir(), "demo.zip")
+
+    f, err := os.Create(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+
+    zw := zip.NewWriter(f)
+
+    w, _ := zw.Create(pack.InfoFile)
+    w.Write(info)
+
+    if err := zw.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    return file
+}
+
+// 扩展包目录中的扩展版本，不存在时为空
+func installedPackageVersion() string {
+    ext, err := pack.Load(filepath.Join(pack.Dir(), testPackageName))
+    if err != nil {
+        return ""
+    }
+
+    return ext.Version
+}
+
+func cleanPackage(t *testing.T) {
+    t.Cleanup(func() {
+        os.RemoveAll(pack.Dir())
+        os.RemoveAll(pack.StaticPath(testPackageName))
+        extension.GetManager().Forget(testPackageName)
+    })
+}
+
+func Test_InstallPackage(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+    cleanPackage(t)
+
+    err := NewExtension().InstallPackage(makePackage(t, "1.0.0", "^1.3", nil))
+    eq(err, nil, "InstallPackage")
+    eq(model.IsInstallExtension(testPackageName), true, "InstallPackage installed")
+    eq(installedPackageVersion(), "1.0.0", "InstallPackage dir")
+
+    _, err = os.Stat(pack.StageDir(testPackageName))
+    eq(os.IsNotExist(err), true, "InstallPackage stage removed")
+
+    // 扩展方法使用移动后的目录
+    eq(extension.GetManager().GetExtension(testPackageName).Version, "1.0.0", "InstallPackage extend")
+
+    // 更新
+    err = NewExtension().InstallPackage(makePackage(t, "1.1.0", "^1.3", nil))
+    eq(err, nil, "InstallPackage upgrade")
+    eq(model.GetExtension(testPackageName).Version, "1.1.0", "InstallPackage upgrade version")
+    eq(installedPackageVersion(), "1.1.0", "InstallPackage upgrade dir")
+}
+
+func Test_InstallPackage_Failed(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+    cleanPackage(t)
+
+    // 适配版本不满足时安装失败
+    err := NewExtension().InstallPackage(makePackage(t, "1.0.0", "^9.0", nil))
+    eq(err != nil, true, "InstallPackage failed")
+    eq(model.IsInstallExtension(testPackageName), false, "InstallPackage failed not installed")
+
+    _, err = os.Stat(filepath.Join(pack.Dir(), testPackageName))
+    eq(os.IsNotExist(err), true, "InstallPackage failed dir")
+
+    _, err = os.Stat(pack.StageDir(testPackageName))
+    eq(os.IsNotExist(err), true, "InstallPackage failed stage removed")
+
+    eq(extension.GetManager().Exists(testPackageName), false, "InstallPackage failed forget")
+
+    // 启动时不会导入
+    eq(LoadPackages(), nil, "LoadPackages")
+    eq(extension.GetManager().Exists(testPackageName), false, "LoadPackages failed package")
+}
+
+func Test_InstallPackage_UpgradeFailed(t *testing.T) {
+    eq := assertT(t)
+
+    migrate(t)
+    cleanPackage(t)
+
+    err := NewExtension().InstallPackage(makePackage(t, "1.0.0", "^1.3", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 依赖扩展没有安装时更新失败，保留之前的扩展包
+    err = NewExtension().InstallPackage(makePackage(t, "1.1.0", "^1.3", map[string]string{
+        "lakego.missing": "*",
+    }))
+    eq(err != nil, true, "InstallPackage upgrade failed")
+    eq(model.GetExtension(testPackageName).Version, "1.0.0", "InstallPackage upgrade failed version")
+    eq(installedPackageVersion(), "1.0.0", "InstallPackage upgrade failed dir")
+    eq(extension.GetManager().GetExtension(testPackageName).Version, "1.0.0", "InstallPackage upgrade failed extend")
+
+    _, err = os.Stat(pack.StageDir(testPackageName))
+    eq(os.IsNotExist(err), true, "InstallPackage upgrade failed stage removed")
+}
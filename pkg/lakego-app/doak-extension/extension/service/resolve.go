@@ -0,0 +1,175 @@
+package service
+
+import (
+    "fmt"
+    "sort"
+    "errors"
+    "encoding/json"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak-extension/extension/model"
+    "github.com/deatil/lakego-doak-extension/extension/version"
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+/**
+ * 依赖检测
+ *
+ * 每个扩展只有本地的一个版本，不在多个候选版本中求解，
+ * 只按依赖拓扑排序得到安装顺序，再检测版本是否满足全部约束
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Resolver struct {
+    // 本地扩展
+    available map[string]extension.Extension
+
+    // 已安装扩展版本
+    installed map[string]string
+
+    // 已安装扩展依赖
+    requires map[string]map[string]string
+}
+
+// 构造函数，使用本地扩展和已安装扩展
+func NewResolver() *Resolver {
+    r := &Resolver{
+        available: make(map[string]extension.Extension),
+        installed: make(map[string]string),
+        requires:  make(map[string]map[string]string),
+    }
+
+    for _, ext := range extension.GetManager().GetExtensions() {
+        r.available[ext.Name] = ext
+    }
+
+    for _, ext := range model.GetAllExtensions() {
+        name := goch.ToString(ext["name"])
+
+        r.installed[name] = goch.ToString(ext["version"])
+
+        // 依赖使用安装时的扩展信息
+        var info extension.Extension
+        json.Unmarshal([]byte(goch.ToString(ext["info"])), &info)
+
+        r.requires[name] = info.Require
+    }
+
+    return r
+}
+
+// 安装顺序，依赖在前，包括需要安装的依赖扩展和当前扩展，
+// 已安装扩展使用安装的版本，未安装的使用本地版本，不满足约束时返回错误
+func (this *Resolver) InstallOrder(name string) ([]string, error) {
+    ext, ok := this.available[name]
+    if !ok {
+        return nil, errors.New(fmt.Sprintf("扩展[%s]不存在", name))
+    }
+
+    // 每个扩展的全部版本约束
+    constraints := make(map[string][]string)
+
+    order := make([]string, 0)
+    visiting := make(map[string]bool)
+    visited := make(map[string]bool)
+
+    var visit func(ext extension.Extension) error
+    visit = func(ext extension.Extension) error {
+        if visited[ext.Name] {
+            return nil
+        }
+
+        if visiting[ext.Name] {
+            return errors.New(fmt.Sprintf("扩展[%s]存在循环依赖", ext.Name))
+        }
+
+        visiting[ext.Name] = true
+
+        for _, require := range sortedKeys(ext.Require) {
+            constraints[require] = append(constraints[require], ext.Require[require])
+
+            // 已安装的依赖不再解析
+            if _, ok := this.installed[require]; ok {
+                continue
+            }
+
+            dep, ok := this.available[require]
+            if !ok {
+                return errors.New(fmt.Sprintf("依赖扩展[%s]不存在", require))
+            }
+
+            if err := visit(dep); err != nil {
+                return err
+            }
+        }
+
+        visiting[ext.Name] = false
+        visited[ext.Name] = true
+
+        if _, ok := this.installed[ext.Name]; !ok {
+            order = append(order, ext.Name)
+        }
+
+        return nil
+    }
+
+    if err := visit(ext); err != nil {
+        return nil, err
+    }
+
+    // 已安装扩展使用安装的版本，未安装的使用本地版本
+    for _, require := range sortedKeys(constraints) {
+        ver, ok := this.installed[require]
+        if !ok {
+            ver = this.available[require].Version
+        }
+
+        for _, constraint := range constraints[require] {
+            if err := version.VersionCheck(ver, constraint); err != nil {
+                return nil, errors.New(fmt.Sprintf("依赖扩展[%s]版本[%s]不满足[%s]", require, ver, constraint))
+            }
+        }
+    }
+
+    return order, nil
+}
+
+// 依赖当前扩展的已安装扩展
+func (this *Resolver) Dependents(name string) []string {
+    dependents := make([]string, 0)
+
+    for _, ext := range sortedKeys(this.requires) {
+        if _, ok := this.requires[ext][name]; ok && ext != name {
+            dependents = append(dependents, ext)
+        }
+    }
+
+    return dependents
+}
+
+// 检测更新后的版本是否满足依赖当前扩展的已安装扩展
+func (this *Resolver) CheckDependents(name string, ver string) error {
+    for _, ext := range this.Dependents(name) {
+        constraint := this.requires[ext][name]
+
+        if err := version.VersionCheck(ver, constraint); err != nil {
+            return errors.New(fmt.Sprintf("扩展[%s]需要依赖扩展[%s]版本[%s]", ext, name, constraint))
+        }
+    }
+
+    return nil
+}
+
+// 排序后的键
+func sortedKeys[T any](data map[string]T) []string {
+    keys := make([]string, 0, len(data))
+    for key := range data {
+        keys = append(keys, key)
+    }
+
+    sort.Strings(keys)
+
+    return keys
+}
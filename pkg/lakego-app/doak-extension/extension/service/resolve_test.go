@@ -0,0 +1,146 @@
+package service
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/deatil/lakego-doak-extension/extension/extension"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if actual != expected {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+// 本地扩展和已安装扩展版本
+func newTestResolver(exts []extension.Extension, installed map[string]string) *Resolver {
+    r := &Resolver{
+        available: make(map[string]extension.Extension),
+        installed: installed,
+        requires:  make(map[string]map[string]string),
+    }
+
+    for _, ext := range exts {
+        r.available[ext.Name] = ext
+
+        if _, ok := installed[ext.Name]; ok {
+            r.requires[ext.Name] = ext.Require
+        }
+    }
+
+    return r
+}
+
+func testExt(name string, ver string, require map[string]string) extension.Extension {
+    return extension.Extension{
+        Name:    name,
+        Version: ver,
+        Require: require,
+    }
+}
+
+func Test_Resolver_InstallOrder(t *testing.T) {
+    eq := assertT(t)
+
+    r := newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"b": "^1.0", "c": "^2.0"}),
+        testExt("b", "1.2.0", map[string]string{"d": ">= 1.0"}),
+        testExt("c", "2.1.0", map[string]string{"d": "< 2.0"}),
+        testExt("d", "1.5.0", nil),
+    }, map[string]string{})
+
+    order, err := r.InstallOrder("a")
+    eq(err, nil, "InstallOrder error")
+    eq(strings.Join(order, ","), "d,b,c,a", "InstallOrder")
+
+    // 已安装的依赖不再安装
+    r.installed["d"] = "1.5.0"
+
+    order, _ = r.InstallOrder("a")
+    eq(strings.Join(order, ","), "b,c,a", "InstallOrder installed")
+
+    _, err = r.InstallOrder("e")
+    eq(err != nil, true, "InstallOrder not exists")
+}
+
+func Test_Resolver_Cycle(t *testing.T) {
+    eq := assertT(t)
+
+    r := newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"b": "*"}),
+        testExt("b", "1.0.0", map[string]string{"c": "*"}),
+        testExt("c", "1.0.0", map[string]string{"a": "*"}),
+        testExt("self", "1.0.0", map[string]string{"self": "*"}),
+    }, map[string]string{})
+
+    _, err := r.InstallOrder("a")
+    eq(err != nil && strings.Contains(err.Error(), "循环依赖"), true, "InstallOrder cycle")
+
+    _, err = r.InstallOrder("self")
+    eq(err != nil && strings.Contains(err.Error(), "循环依赖"), true, "InstallOrder self cycle")
+
+    // 已安装的依赖不再解析，不会形成循环
+    r.installed["c"] = "1.0.0"
+
+    order, err := r.InstallOrder("a")
+    eq(err, nil, "InstallOrder installed cycle")
+    eq(strings.Join(order, ","), "b,a", "InstallOrder installed cycle order")
+}
+
+func Test_Resolver_Constraints(t *testing.T) {
+    eq := assertT(t)
+
+    // 多个扩展对同一依赖的版本约束冲突
+    r := newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"b": "*", "c": "*"}),
+        testExt("b", "1.0.0", map[string]string{"d": "^1.0"}),
+        testExt("c", "1.0.0", map[string]string{"d": "^2.0"}),
+        testExt("d", "1.5.0", nil),
+    }, map[string]string{})
+
+    _, err := r.InstallOrder("a")
+    eq(err != nil && strings.Contains(err.Error(), "依赖扩展[d]版本[1.5.0]不满足[^2.0]"), true, "InstallOrder constraint conflict")
+
+    // 已安装的依赖使用安装的版本
+    r = newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"d": "^2.0"}),
+        testExt("d", "2.1.0", nil),
+    }, map[string]string{"d": "1.5.0"})
+
+    _, err = r.InstallOrder("a")
+    eq(err != nil && strings.Contains(err.Error(), "版本[1.5.0]"), true, "InstallOrder installed version")
+
+    r.installed["d"] = "2.0.1"
+
+    order, err := r.InstallOrder("a")
+    eq(err, nil, "InstallOrder installed version ok")
+    eq(strings.Join(order, ","), "a", "InstallOrder installed version order")
+
+    // 依赖不存在
+    r = newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"x": "*"}),
+    }, map[string]string{})
+
+    _, err = r.InstallOrder("a")
+    eq(err != nil && strings.Contains(err.Error(), "依赖扩展[x]不存在"), true, "InstallOrder require not exists")
+}
+
+func Test_Resolver_Dependents(t *testing.T) {
+    eq := assertT(t)
+
+    r := newTestResolver([]extension.Extension{
+        testExt("a", "1.0.0", map[string]string{"c": "^1.0"}),
+        testExt("b", "1.0.0", map[string]string{"c": "~1.2"}),
+        testExt("c", "1.2.0", nil),
+    }, map[string]string{"a": "1.0.0", "b": "1.0.0", "c": "1.2.0"})
+
+    eq(strings.Join(r.Dependents("c"), ","), "a,b", "Dependents")
+    eq(len(r.Dependents("a")), 0, "Dependents none")
+
+    eq(r.CheckDependents("c", "1.2.5"), nil, "CheckDependents")
+    eq(r.CheckDependents("c", "1.3.0") != nil, true, "CheckDependents break ~1.2")
+    eq(r.CheckDependents("c", "2.0.0") != nil, true, "CheckDependents break ^1.0")
+}
@@ -8,36 +8,38 @@ import (
     "github.com/deatil/lakego-doak-extension/extension/version"
 )
 
-// 检测依赖
+// 检测依赖，依赖扩展需要已安装且版本满足
 func CheckExtensionRequire(requires map[string]string) (bool, error) {
     if len(requires) == 0 {
         return true, nil
     }
 
     exts := make([]string, 0)
-    for _, require := range requires {
-        exts = append(exts, require)
+    for name := range requires {
+        exts = append(exts, name)
     }
 
-    requireExts := make([]map[string]any, 0)
+    requireExts := make([]model.Extension, 0)
 
     model.NewExtension().
-        Where("name IN", exts).
+        Where("name IN ?", exts).
         Order("listorder DESC").
         Find(&requireExts)
-    if len(requireExts) == 0 {
-        return false, errors.New("需要的依赖扩展需要安装")
-    }
 
+    installed := make(map[string]string)
     for _, requireExt := range requireExts {
-        extName := requireExt["name"].(string)
-        extVersion := requireExt["version"].(string)
-
-        if ver, ok := requires[extName]; ok {
-            err := version.VersionCheck(extVersion, ver)
-            if err != nil {
-                return false, errors.New(fmt.Sprintf("依赖扩展[%s]所需安装版本[%s]错误", ver, extVersion))
-            }
+        installed[requireExt.Name] = requireExt.Version
+    }
+
+    for _, name := range sortedKeys(requires) {
+        extVersion, ok := installed[name]
+        if !ok {
+            return false, errors.New(fmt.Sprintf("需要的依赖扩展[%s]需要安装", name))
+        }
+
+        err := version.VersionCheck(extVersion, requires[name])
+        if err != nil {
+            return false, errors.New(fmt.Sprintf("依赖扩展[%s]所需安装版本[%s]错误", requires[name], extVersion))
         }
     }
 
@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/deatil/go-hash v0.0.3
+	github.com/deatil/go-cryptobin v0.0.3
 	github.com/deatil/go-goch v0.0.3
 	github.com/deatil/lakego-doak v0.0.3
 	github.com/deatil/lakego-doak-admin v0.0.3
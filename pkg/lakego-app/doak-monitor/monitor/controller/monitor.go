@@ -17,6 +17,7 @@ import (
     "github.com/deatil/go-datebin/datebin"
 
     "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/metrics"
 
     adminController "github.com/deatil/lakego-doak-admin/admin/controller"
 )
@@ -152,3 +153,19 @@ func (this *Monitor) Index(ctx *router.Context) {
     })
 }
 
+
+// 运行指标
+// @Summary 运行指标
+// @Description 进程内的运行指标，如操作日志写入和丢弃数量
+// @Tags 系统监控
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {string} json "{"success": true, "code": 0, "message": "string", "data": ""}"
+// @Router /monitor/metrics [get]
+// @Security Bearer
+// @x-lakego {"slug": "lakego-admin.monitor.metrics"}
+func (this *Monitor) Metrics(ctx *router.Context) {
+    this.SuccessWithData(ctx, "获取成功", router.H{
+        "list": metrics.Snapshot(),
+    })
+}
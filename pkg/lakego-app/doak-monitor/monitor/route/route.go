@@ -13,4 +13,5 @@ func Route(engine gin.IRouter) {
     // 系统监控
     monitorController := new(controller.Monitor)
     engine.GET("/monitor", monitorController.Index)
+    engine.GET("/monitor/metrics", monitorController.Metrics)
 }
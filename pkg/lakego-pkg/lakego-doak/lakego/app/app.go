@@ -11,6 +11,7 @@ import (
     "errors"
     "context"
     "reflect"
+    "syscall"
 
     "github.com/deatil/go-datebin/datebin"
     "github.com/deatil/lakego-jwt/jwt"
@@ -19,6 +20,7 @@ import (
     "github.com/deatil/lakego-doak/lakego/path"
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/command"
+    "github.com/deatil/lakego-doak/lakego/facade"
     "github.com/deatil/lakego-doak/lakego/schedule"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     "github.com/deatil/lakego-doak/lakego/middleware/recovery"
@@ -73,6 +75,12 @@ type App struct {
     // 启动后
     bootedCallbacks []func()
 
+    // 关闭时
+    terminatingCallbacks []func()
+
+    // 关闭回调只执行一次
+    terminateOnce sync.Once
+
     // 自定义运行监听
     netListener net.Listener
 }
@@ -91,7 +99,9 @@ func New() *App {
     }
 
     // 计划任务
-    scheduler := schedule.New().SetShowLogInfo(dev)
+    scheduler := schedule.New().
+        WithHistory(schedule.NewDatabaseHistory(facade.DB, "")).
+        SetShowLogInfo(dev)
 
     return &App{
         dev:              dev,
@@ -269,6 +279,27 @@ func (this *App) CallBootedCallbacks() {
     }
 }
 
+// 设置关闭时函数
+func (this *App) WithTerminating(f func()) {
+    this.mut.Lock()
+    defer this.mut.Unlock()
+
+    this.terminatingCallbacks = append(this.terminatingCallbacks, f)
+}
+
+// 关闭时回调，多次调用只执行一次
+func (this *App) CallTerminatingCallbacks() {
+    this.terminateOnce.Do(func() {
+        this.mut.RLock()
+        callbacks := this.terminatingCallbacks
+        this.mut.RUnlock()
+
+        for _, callback := range callbacks {
+            callback()
+        }
+    })
+}
+
 // 设置根脚本
 func (this *App) WithRootCmd(cmd *command.Command) {
     this.rootCmd = cmd
@@ -473,11 +504,14 @@ func (this *App) serverRun() {
             if servertype == "grace" {
                 // 优雅地关机
                 this.graceRun(addr)
-            } else {
-                // gin 自带运行
-                err = this.route.Run(addr)
+                return
             }
 
+            // gin 自带运行
+            this.watchSignal()
+
+            err = this.route.Run(addr)
+
         case "tls":
             // 运行端口
             addr := conf.GetString("types.tls.addr")
@@ -489,6 +523,8 @@ func (this *App) serverRun() {
             certFile = this.formatPath(certFile)
             keyFile = this.formatPath(keyFile)
 
+            this.watchSignal()
+
             err = this.route.RunTLS(addr, certFile, keyFile)
 
         case "unix":
@@ -498,15 +534,21 @@ func (this *App) serverRun() {
             // 格式化
             file = this.formatPath(file)
 
+            this.watchSignal()
+
             err = this.route.RunUnix(file)
 
         case "fd":
             // fd
             fd := conf.GetInt("types.fd.fd")
 
+            this.watchSignal()
+
             err = this.route.RunFd(fd)
 
         case "net-listener":
+            this.watchSignal()
+
             if this.netListener != nil {
                 err = this.route.RunListener(this.netListener)
             } else {
@@ -524,10 +566,28 @@ func (this *App) serverRun() {
     }
 
     if err != nil {
+        // 退出前执行关闭回调
+        this.CallTerminatingCallbacks()
+
         log.Fatalf("server err: %s\n", err)
     }
 }
 
+// 监听退出信号，执行关闭回调后退出
+func (this *App) watchSignal() {
+    quit := make(chan os.Signal, 1)
+    signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+    go func() {
+        <-quit
+        log.Println("Server exiting")
+
+        this.CallTerminatingCallbacks()
+
+        os.Exit(0)
+    }()
+}
+
 // 优雅地关机
 func (this *App) graceRun(address string) {
     conf := this.config
@@ -543,19 +603,27 @@ func (this *App) graceRun(address string) {
     go func() {
         // 服务连接
         if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+            this.CallTerminatingCallbacks()
+
             log.Fatalf("listen: %s\n", err)
         }
     }()
 
-    // 等待中断信号以优雅地关闭服务器（设置 5 秒的超时时间）
-    quit := make(chan os.Signal)
-    signal.Notify(quit, os.Interrupt)
+    // 等待中断或者终止信号以优雅地关闭服务器
+    quit := make(chan os.Signal, 1)
+    signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
     <-quit
     log.Println("Shutdown Server ...")
 
     ctx, cancel := context.WithTimeout(context.Background(), conf.GetDuration("types.http.grace-timeout"))
     defer cancel()
-    if err := srv.Shutdown(ctx); err != nil {
+
+    err := srv.Shutdown(ctx)
+
+    // 关闭时，退出前执行
+    this.CallTerminatingCallbacks()
+
+    if err != nil {
         log.Fatal("Server Shutdown:", err)
     }
 
@@ -40,6 +40,9 @@ type App interface {
     // 设置启动后函数
     WithBooted(func())
 
+    // 设置关闭时函数
+    WithTerminating(func())
+
     // 获取脚本
     GetRootCmd() *command.Command
 
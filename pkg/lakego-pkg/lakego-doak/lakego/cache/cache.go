@@ -14,6 +14,7 @@ import (
 func New(driver interfaces.Driver, conf ...Config) *Cache {
     cache := &Cache{
         driver: driver,
+        flight: newFlightGroup(),
     }
 
     if len(conf) > 0{
@@ -43,6 +44,9 @@ type Cache struct {
 
     // 驱动
     driver interfaces.Driver
+
+    // 合并并发回调
+    flight *flightGroup
 }
 
 // 设置驱动
@@ -128,6 +132,98 @@ func (this *Cache) Pull(key string) (any, error) {
     return val, nil
 }
 
+// 不存在时设置，返回是否设置成功
+func (this *Cache) Add(key string, value any, ttl any) (bool, error) {
+    key = this.wrapperKey(key)
+
+    return this.add(key, value, this.formatTime(ttl))
+}
+
+// 获取缓存，不存在时调用回调并缓存结果
+// 同一进程内相同 key 的并发回调只会执行一次
+func (this *Cache) Remember(key string, ttl any, fn func() (any, error)) (any, error) {
+    if val, err := this.Get(key); err == nil {
+        return val, nil
+    }
+
+    return this.flight.Do(this.wrapperKey(key), func() (any, error) {
+        if val, err := this.Get(key); err == nil {
+            return val, nil
+        }
+
+        val, err := fn()
+        if err != nil {
+            return nil, err
+        }
+
+        if err = this.Put(key, val, ttl); err != nil {
+            return nil, err
+        }
+
+        return val, nil
+    })
+}
+
+// 获取缓存，不存在时调用回调并永久缓存结果
+func (this *Cache) RememberForever(key string, fn func() (any, error)) (any, error) {
+    if val, err := this.Get(key); err == nil {
+        return val, nil
+    }
+
+    return this.flight.Do(this.wrapperKey(key), func() (any, error) {
+        if val, err := this.Get(key); err == nil {
+            return val, nil
+        }
+
+        val, err := fn()
+        if err != nil {
+            return nil, err
+        }
+
+        if err = this.Forever(key, val); err != nil {
+            return nil, err
+        }
+
+        return val, nil
+    })
+}
+
+// 批量获取，不存在的 key 不返回
+func (this *Cache) Many(keys []string) map[string]any {
+    data := make(map[string]any)
+
+    for _, key := range keys {
+        val, err := this.Get(key)
+        if err == nil {
+            data[key] = val
+        }
+    }
+
+    return data
+}
+
+// 批量设置
+func (this *Cache) PutMany(values map[string]any, ttl any) error {
+    for key, value := range values {
+        err := this.Put(key, value, ttl)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 标签缓存
+func (this *Cache) Tags(names ...string) *TaggedCache {
+    return NewTaggedCache(this, NewTagSet(this, names...))
+}
+
+// 锁
+func (this *Cache) Lock(name string, ttl any, owner ...string) *Lock {
+    return NewLock(this, name, this.formatTime(ttl), owner...)
+}
+
 // 增加一
 func (this *Cache) Increment(key string, value ...int64) error {
     key = this.wrapperKey(key)
@@ -154,6 +250,25 @@ func (this *Cache) Flush() (bool, error) {
     return this.driver.Flush()
 }
 
+// 不存在时设置
+func (this *Cache) add(key string, value any, ttl time.Duration) (bool, error) {
+    if driver, ok := this.driver.(interfaces.AtomicDriver); ok {
+        return driver.Add(key, value, ttl)
+    }
+
+    // 驱动不支持原子操作时降级处理
+    if this.driver.Exists(key) {
+        return false, nil
+    }
+
+    err := this.driver.Put(key, value, ttl)
+    if err != nil {
+        return false, err
+    }
+
+    return true, nil
+}
+
 // 包装字段
 func (this *Cache) wrapperKey(key string) string {
     if this.prefix == "" {
@@ -163,7 +278,11 @@ func (this *Cache) wrapperKey(key string) string {
     return fmt.Sprintf("%s:%s", this.prefix, key)
 }
 
-// 时间格式化
+// 时间格式化，time.Duration 直接使用，其他类型单位为秒
 func (this *Cache) formatTime(t any) time.Duration {
+    if d, ok := t.(time.Duration); ok {
+        return d
+    }
+
     return time.Second * goch.ToDuration(t)
 }
@@ -0,0 +1,132 @@
+package cache
+
+import (
+    "sync"
+    "time"
+    "testing"
+    "reflect"
+    "sync/atomic"
+
+    "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func newTestCache() *Cache {
+    return New(memory.New(memory.Config{})).WithPrefix("test")
+}
+
+func Test_Remember(t *testing.T) {
+    assert := assertT(t)
+
+    c := newTestCache()
+
+    var calls int32
+    fn := func() (any, error) {
+        atomic.AddInt32(&calls, 1)
+        return "data", nil
+    }
+
+    var wg sync.WaitGroup
+    for i := 0; i < 10; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            c.Remember("key", 60, fn)
+        }()
+    }
+    wg.Wait()
+
+    val, err := c.Remember("key", 60, fn)
+    assert(err, nil, "Remember error")
+    assert(val, "data", "Remember")
+    assert(atomic.LoadInt32(&calls), int32(1), "Remember calls")
+
+    val, _ = c.Pull("key")
+    assert(val, "data", "Pull")
+    assert(c.Has("key"), false, "Pull forget")
+}
+
+func Test_AddAndMany(t *testing.T) {
+    assert := assertT(t)
+
+    c := newTestCache()
+
+    ok, _ := c.Add("key", "1", 60)
+    assert(ok, true, "Add")
+    ok, _ = c.Add("key", "2", 60)
+    assert(ok, false, "Add exists")
+
+    c.PutMany(map[string]any{"a": "1", "b": "2"}, 60)
+    assert(c.Many([]string{"a", "b", "c"}), map[string]any{"a": "1", "b": "2"}, "Many")
+}
+
+func Test_Tags(t *testing.T) {
+    assert := assertT(t)
+
+    c := newTestCache()
+
+    c.Tags("rule", "admin").Put("tree", "data", 60)
+    c.Tags("rule").Put("list", "data", 60)
+
+    val, _ := c.Tags("rule", "admin").Get("tree")
+    assert(val, "data", "Tags Get")
+    assert(c.Has("tree"), false, "Tags key isolated")
+
+    c.Tags("admin").Flush()
+    assert(c.Tags("rule", "admin").Has("tree"), false, "Tags Flush")
+    assert(c.Tags("rule").Has("list"), true, "Tags Flush other")
+}
+
+func Test_Lock(t *testing.T) {
+    assert := assertT(t)
+
+    c := newTestCache()
+
+    lock1 := c.Lock("job", 10)
+    lock2 := c.Lock("job", 10)
+
+    ok, _ := lock1.Get()
+    assert(ok, true, "Lock Get")
+    ok, _ = lock2.Get()
+    assert(ok, false, "Lock Get locked")
+
+    ok, _ = lock2.Release()
+    assert(ok, false, "Lock Release not owner")
+
+    _, err := lock2.Block(0)
+    assert(err, ErrLockTimeout, "Lock Block timeout")
+
+    // time.Duration 不再乘以秒
+    start := time.Now()
+    _, err = lock2.WithSleep(10 * time.Millisecond).Block(50 * time.Millisecond)
+    assert(err, ErrLockTimeout, "Lock Block Duration timeout")
+    assert(time.Since(start) < time.Second, true, "Lock Block Duration wait")
+
+    ok, _ = lock1.Release()
+    assert(ok, true, "Lock Release")
+
+    ran := false
+    ok, _ = lock2.Get(func() {
+        ran = true
+    })
+    assert(ok && ran, true, "Lock Get callback")
+    assert(lock2.CurrentOwner(), "", "Lock callback release")
+}
+
+func Test_FormatTime(t *testing.T) {
+    assert := assertT(t)
+
+    c := newTestCache()
+
+    assert(c.formatTime(60), 60 * time.Second, "formatTime int")
+    assert(c.formatTime("60"), 60 * time.Second, "formatTime string")
+    assert(c.formatTime(time.Hour), time.Hour, "formatTime Duration")
+    assert(c.formatTime(500 * time.Millisecond), 500 * time.Millisecond, "formatTime Millisecond")
+}
@@ -0,0 +1,272 @@
+package file
+
+import (
+    "os"
+    "fmt"
+    "sync"
+    "time"
+    "errors"
+    "strconv"
+    "crypto/sha1"
+    "encoding/hex"
+    "path/filepath"
+
+    "github.com/deatil/go-goch/goch"
+)
+
+// 头部长度，存储过期时间戳
+const headerLen = 10
+
+// 永久缓存的过期时间
+const foreverExpire int64 = 9999999999
+
+// 缓存配置
+type Config struct {
+    // 缓存目录
+    Path string
+
+    // 文件权限
+    FileMode os.FileMode
+
+    // 目录权限
+    DirMode os.FileMode
+}
+
+/**
+ * 文件缓存
+ *
+ * 每个 key 一个文件，文件前 10 位为过期时间戳
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type File struct {
+    // 锁
+    mu sync.Mutex
+
+    // 配置
+    config Config
+}
+
+// 构造函数
+func New(config Config) *File {
+    if config.FileMode == 0 {
+        config.FileMode = 0644
+    }
+
+    if config.DirMode == 0 {
+        config.DirMode = 0755
+    }
+
+    return &File{
+        config: config,
+    }
+}
+
+// 判断是否存在
+func (this *File) Exists(key string) bool {
+    _, _, err := this.read(key)
+
+    return err == nil
+}
+
+// 获取
+func (this *File) Get(key string) (any, error) {
+    data, _, err := this.read(key)
+    if err != nil {
+        return "", err
+    }
+
+    return data, nil
+}
+
+// 设置
+func (this *File) Put(key string, value any, ttl time.Duration) error {
+    val, err := goch.ToStringE(value)
+    if err != nil {
+        return err
+    }
+
+    expire := foreverExpire
+    if ttl > 0 {
+        expire = time.Now().Add(ttl).Unix()
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    return this.write(key, val, expire)
+}
+
+// 存在永久
+func (this *File) Forever(key string, value any) error {
+    return this.Put(key, value, 0)
+}
+
+// 增加
+func (this *File) Increment(key string, value ...int64) error {
+    var step int64 = 1
+    if len(value) > 0 {
+        step = value[0]
+    }
+
+    return this.incr(key, step)
+}
+
+// 减少
+func (this *File) Decrement(key string, value ...int64) error {
+    var step int64 = 1
+    if len(value) > 0 {
+        step = value[0]
+    }
+
+    return this.incr(key, -step)
+}
+
+// 删除
+func (this *File) Forget(key string) (bool, error) {
+    err := os.Remove(this.filename(key))
+    if err != nil && !os.IsNotExist(err) {
+        return false, err
+    }
+
+    return true, nil
+}
+
+// 清空
+func (this *File) Flush() (bool, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    entries, err := os.ReadDir(this.config.Path)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return true, nil
+        }
+
+        return false, err
+    }
+
+    for _, entry := range entries {
+        err = os.RemoveAll(filepath.Join(this.config.Path, entry.Name()))
+        if err != nil {
+            return false, err
+        }
+    }
+
+    return true, nil
+}
+
+// 不存在时设置
+func (this *File) Add(key string, value any, ttl time.Duration) (bool, error) {
+    val, err := goch.ToStringE(value)
+    if err != nil {
+        return false, err
+    }
+
+    expire := foreverExpire
+    if ttl > 0 {
+        expire = time.Now().Add(ttl).Unix()
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if _, _, err := this.read(key); err == nil {
+        return false, nil
+    }
+
+    err = this.write(key, val, expire)
+    if err != nil {
+        return false, err
+    }
+
+    return true, nil
+}
+
+// 值相等时删除
+func (this *File) ForgetIfEquals(key string, value string) (bool, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    data, _, err := this.read(key)
+    if err != nil || data != value {
+        return false, nil
+    }
+
+    return this.Forget(key)
+}
+
+// 自增，保留原有过期时间
+func (this *File) incr(key string, step int64) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    data, expire, err := this.read(key)
+    if err != nil {
+        data, expire = "0", foreverExpire
+    }
+
+    num, err := strconv.ParseInt(data, 10, 64)
+    if err != nil {
+        return errors.New("file cache: value is not an integer")
+    }
+
+    return this.write(key, strconv.FormatInt(num + step, 10), expire)
+}
+
+// 读取数据
+func (this *File) read(key string) (string, int64, error) {
+    filename := this.filename(key)
+
+    content, err := os.ReadFile(filename)
+    if err != nil {
+        return "", 0, errors.New("file cache nil")
+    }
+
+    if len(content) < headerLen {
+        os.Remove(filename)
+        return "", 0, errors.New("file cache data error")
+    }
+
+    expire, err := strconv.ParseInt(string(content[:headerLen]), 10, 64)
+    if err != nil {
+        os.Remove(filename)
+        return "", 0, errors.New("file cache data error")
+    }
+
+    if expire <= time.Now().Unix() {
+        os.Remove(filename)
+        return "", 0, errors.New("file cache nil")
+    }
+
+    return string(content[headerLen:]), expire, nil
+}
+
+// 写入数据，先写临时文件再重命名
+func (this *File) write(key string, value string, expire int64) error {
+    filename := this.filename(key)
+
+    err := os.MkdirAll(filepath.Dir(filename), this.config.DirMode)
+    if err != nil {
+        return err
+    }
+
+    content := fmt.Sprintf("%0*d%s", headerLen, expire, value)
+
+    tmp := filename + ".tmp"
+    err = os.WriteFile(tmp, []byte(content), this.config.FileMode)
+    if err != nil {
+        return err
+    }
+
+    return os.Rename(tmp, filename)
+}
+
+// 缓存文件路径
+func (this *File) filename(key string) string {
+    sum := sha1.Sum([]byte(key))
+    hash := hex.EncodeToString(sum[:])
+
+    return filepath.Join(this.config.Path, hash[0:2], hash[2:4], hash)
+}
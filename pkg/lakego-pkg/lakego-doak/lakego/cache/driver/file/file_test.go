@@ -0,0 +1,45 @@
+package file
+
+import (
+    "time"
+    "testing"
+    "reflect"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_File(t *testing.T) {
+    assert := assertT(t)
+
+    f := New(Config{
+        Path: t.TempDir(),
+    })
+
+    f.Put("key", "value", time.Minute)
+    val, err := f.Get("key")
+    assert(err, nil, "Get error")
+    assert(val, "value", "Get")
+    assert(f.Exists("key"), true, "Exists")
+
+    f.Put("ttl", "data", time.Second)
+    time.Sleep(1100 * time.Millisecond)
+    assert(f.Exists("ttl"), false, "Exists expired")
+
+    f.Forever("num", 5)
+    f.Increment("num", 3)
+    f.Decrement("num")
+    val, _ = f.Get("num")
+    assert(val, "7", "Increment")
+
+    f.Forget("key")
+    assert(f.Exists("key"), false, "Forget")
+
+    f.Flush()
+    assert(f.Exists("num"), false, "Flush")
+}
@@ -0,0 +1,380 @@
+package memory
+
+import (
+    "sync"
+    "time"
+    "errors"
+    "strconv"
+    "hash/fnv"
+
+    "github.com/deatil/go-goch/goch"
+)
+
+// 缓存配置
+type Config struct {
+    // 分片数量
+    Shards int
+
+    // 最大缓存数量，0 为不限制
+    MaxSize int
+
+    // 过期清理间隔，0 为不自动清理
+    CleanupInterval time.Duration
+}
+
+// 缓存数据
+type item struct {
+    // 数据
+    value string
+
+    // 过期时间，0 为永久
+    expire int64
+}
+
+// 是否过期
+func (this item) expired(now int64) bool {
+    return this.expire > 0 && this.expire <= now
+}
+
+// 分片
+type shard struct {
+    // 锁
+    mu sync.RWMutex
+
+    // 数据
+    items map[string]item
+}
+
+/**
+ * 内存缓存
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Memory struct {
+    // 分片
+    shards []*shard
+
+    // 每个分片最大数量
+    shardSize int
+
+    // 停止清理
+    stop chan struct{}
+
+    // 停止一次
+    once sync.Once
+}
+
+// 构造函数
+func New(config Config) *Memory {
+    if config.Shards <= 0 {
+        config.Shards = 32
+    }
+
+    shardSize := 0
+    if config.MaxSize > 0 {
+        shardSize = (config.MaxSize + config.Shards - 1) / config.Shards
+    }
+
+    shards := make([]*shard, config.Shards)
+    for i := range shards {
+        shards[i] = &shard{
+            items: make(map[string]item),
+        }
+    }
+
+    m := &Memory{
+        shards:    shards,
+        shardSize: shardSize,
+        stop:      make(chan struct{}),
+    }
+
+    if config.CleanupInterval > 0 {
+        go m.janitor(config.CleanupInterval)
+    }
+
+    return m
+}
+
+// 判断是否存在
+func (this *Memory) Exists(key string) bool {
+    _, ok := this.get(key)
+
+    return ok
+}
+
+// 获取
+func (this *Memory) Get(key string) (any, error) {
+    val, ok := this.get(key)
+    if !ok {
+        return "", errors.New("memory nil")
+    }
+
+    return val, nil
+}
+
+// 设置
+func (this *Memory) Put(key string, value any, ttl time.Duration) error {
+    val, err := goch.ToStringE(value)
+    if err != nil {
+        return err
+    }
+
+    var expire int64
+    if ttl > 0 {
+        expire = time.Now().Add(ttl).UnixNano()
+    }
+
+    s := this.getShard(key)
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    this.set(s, key, item{
+        value:  val,
+        expire: expire,
+    })
+
+    return nil
+}
+
+// 存在永久
+func (this *Memory) Forever(key string, value any) error {
+    return this.Put(key, value, 0)
+}
+
+// 增加
+func (this *Memory) Increment(key string, value ...int64) error {
+    var step int64 = 1
+    if len(value) > 0 {
+        step = value[0]
+    }
+
+    return this.incr(key, step)
+}
+
+// 减少
+func (this *Memory) Decrement(key string, value ...int64) error {
+    var step int64 = 1
+    if len(value) > 0 {
+        step = value[0]
+    }
+
+    return this.incr(key, -step)
+}
+
+// 删除
+func (this *Memory) Forget(key string) (bool, error) {
+    s := this.getShard(key)
+
+    s.mu.Lock()
+    delete(s.items, key)
+    s.mu.Unlock()
+
+    return true, nil
+}
+
+// 清空
+func (this *Memory) Flush() (bool, error) {
+    for _, s := range this.shards {
+        s.mu.Lock()
+        s.items = make(map[string]item)
+        s.mu.Unlock()
+    }
+
+    return true, nil
+}
+
+// 不存在时设置
+func (this *Memory) Add(key string, value any, ttl time.Duration) (bool, error) {
+    val, err := goch.ToStringE(value)
+    if err != nil {
+        return false, err
+    }
+
+    now := time.Now()
+
+    var expire int64
+    if ttl > 0 {
+        expire = now.Add(ttl).UnixNano()
+    }
+
+    s := this.getShard(key)
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if it, ok := s.items[key]; ok && !it.expired(now.UnixNano()) {
+        return false, nil
+    }
+
+    this.set(s, key, item{
+        value:  val,
+        expire: expire,
+    })
+
+    return true, nil
+}
+
+// 值相等时删除
+func (this *Memory) ForgetIfEquals(key string, value string) (bool, error) {
+    s := this.getShard(key)
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    it, ok := s.items[key]
+    if !ok || it.expired(time.Now().UnixNano()) || it.value != value {
+        return false, nil
+    }
+
+    delete(s.items, key)
+
+    return true, nil
+}
+
+// 缓存数量，包括未清理的过期数据
+func (this *Memory) Len() int {
+    n := 0
+    for _, s := range this.shards {
+        s.mu.RLock()
+        n += len(s.items)
+        s.mu.RUnlock()
+    }
+
+    return n
+}
+
+// 清理过期数据
+func (this *Memory) DeleteExpired() {
+    now := time.Now().UnixNano()
+
+    for _, s := range this.shards {
+        s.mu.Lock()
+        for k, v := range s.items {
+            if v.expired(now) {
+                delete(s.items, k)
+            }
+        }
+        s.mu.Unlock()
+    }
+}
+
+// 关闭
+func (this *Memory) Close() error {
+    this.once.Do(func() {
+        close(this.stop)
+    })
+
+    return nil
+}
+
+// 获取数据
+func (this *Memory) get(key string) (string, bool) {
+    s := this.getShard(key)
+
+    s.mu.RLock()
+    it, ok := s.items[key]
+    s.mu.RUnlock()
+
+    if !ok || it.expired(time.Now().UnixNano()) {
+        return "", false
+    }
+
+    return it.value, true
+}
+
+// 自增，保留原有过期时间
+func (this *Memory) incr(key string, step int64) error {
+    s := this.getShard(key)
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    it, ok := s.items[key]
+    if !ok || it.expired(time.Now().UnixNano()) {
+        it = item{value: "0"}
+    }
+
+    num, err := strconv.ParseInt(it.value, 10, 64)
+    if err != nil {
+        return errors.New("memory: value is not an integer")
+    }
+
+    it.value = strconv.FormatInt(num + step, 10)
+
+    this.set(s, key, it)
+
+    return nil
+}
+
+// 写入数据，超出容量时先清理过期数据，再淘汰最早过期的数据
+func (this *Memory) set(s *shard, key string, it item) {
+    if _, ok := s.items[key]; !ok && this.shardSize > 0 && len(s.items) >= this.shardSize {
+        this.evict(s)
+    }
+
+    s.items[key] = it
+}
+
+// 淘汰数据
+func (this *Memory) evict(s *shard) {
+    now := time.Now().UnixNano()
+
+    for k, v := range s.items {
+        if v.expired(now) {
+            delete(s.items, k)
+        }
+    }
+
+    if len(s.items) < this.shardSize {
+        return
+    }
+
+    var (
+        evictKey string
+        evictAt  int64 = -1
+    )
+
+    for k, v := range s.items {
+        // 永久数据最后淘汰
+        if v.expire == 0 {
+            if evictAt == -1 {
+                evictKey = k
+                evictAt = 0
+            }
+
+            continue
+        }
+
+        if evictAt <= 0 || v.expire < evictAt {
+            evictKey = k
+            evictAt = v.expire
+        }
+    }
+
+    delete(s.items, evictKey)
+}
+
+// 定时清理
+func (this *Memory) janitor(interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+            case <-ticker.C:
+                this.DeleteExpired()
+            case <-this.stop:
+                return
+        }
+    }
+}
+
+// 获取分片
+func (this *Memory) getShard(key string) *shard {
+    h := fnv.New32a()
+    h.Write([]byte(key))
+
+    return this.shards[h.Sum32() % uint32(len(this.shards))]
+}
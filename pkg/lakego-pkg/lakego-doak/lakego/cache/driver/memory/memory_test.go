@@ -0,0 +1,71 @@
+package memory
+
+import (
+    "time"
+    "testing"
+    "reflect"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_Memory(t *testing.T) {
+    assert := assertT(t)
+
+    m := New(Config{})
+    defer m.Close()
+
+    m.Put("key", "value", time.Minute)
+    val, err := m.Get("key")
+    assert(err, nil, "Get error")
+    assert(val, "value", "Get")
+    assert(m.Exists("key"), true, "Exists")
+
+    m.Put("ttl", 123, time.Millisecond)
+    time.Sleep(5 * time.Millisecond)
+    assert(m.Exists("ttl"), false, "Exists expired")
+
+    m.Forever("num", 5)
+    m.Increment("num")
+    m.Increment("num", 3)
+    m.Decrement("num", 2)
+    val, _ = m.Get("num")
+    assert(val, "7", "Increment")
+
+    m.Increment("new-num")
+    val, _ = m.Get("new-num")
+    assert(val, "1", "Increment missing key")
+
+    err = m.Increment("key")
+    assert(err != nil, true, "Increment not integer")
+
+    m.Forget("key")
+    assert(m.Exists("key"), false, "Forget")
+
+    m.Flush()
+    assert(m.Len(), 0, "Flush")
+}
+
+func Test_MemoryMaxSize(t *testing.T) {
+    assert := assertT(t)
+
+    m := New(Config{
+        Shards:  1,
+        MaxSize: 2,
+    })
+    defer m.Close()
+
+    m.Forever("forever", "1")
+    m.Put("short", "2", time.Minute)
+    m.Put("long", "3", time.Hour)
+
+    assert(m.Len(), 2, "Len")
+    assert(m.Exists("short"), false, "evict soonest expiring")
+    assert(m.Exists("forever"), true, "keep forever")
+    assert(m.Exists("long"), true, "keep new")
+}
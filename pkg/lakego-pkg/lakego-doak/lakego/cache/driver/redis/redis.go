@@ -9,6 +9,15 @@ import (
     "github.com/go-redis/redis/extra/redisotel/v8"
 )
 
+// 值相等时删除的脚本
+var forgetIfEqualsScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+    return redis.call("del", KEYS[1])
+else
+    return 0
+end
+`)
+
 // 日志接口
 type iLogger interface {
     Errorf(template string, args ...any)
@@ -184,6 +193,21 @@ func (this *Redis) Flush() (bool, error) {
     return true, nil
 }
 
+// 不存在时设置
+func (this *Redis) Add(key string, value any, ttl time.Duration) (bool, error) {
+    return this.client.SetNX(this.ctx, key, value, ttl).Result()
+}
+
+// 值相等时删除
+func (this *Redis) ForgetIfEquals(key string, value string) (bool, error) {
+    n, err := forgetIfEqualsScript.Run(this.ctx, this.client, []string{key}, value).Int64()
+    if err != nil {
+        return false, err
+    }
+
+    return n > 0, nil
+}
+
 // HashSet
 func (this *Redis) HashSet(key string, field string, value string) error {
     return this.client.HSet(this.ctx, key, field, value).Err()
@@ -0,0 +1,58 @@
+package cache
+
+import (
+    "sync"
+)
+
+// 执行中的调用
+type flightCall struct {
+    wg  sync.WaitGroup
+    val any
+    err error
+}
+
+/**
+ * 合并相同 key 的并发调用
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type flightGroup struct {
+    mu    sync.Mutex
+    calls map[string]*flightCall
+}
+
+// 构造函数
+func newFlightGroup() *flightGroup {
+    return &flightGroup{
+        calls: make(map[string]*flightCall),
+    }
+}
+
+// 执行，相同 key 同时只执行一次，其他调用等待结果
+func (this *flightGroup) Do(key string, fn func() (any, error)) (any, error) {
+    this.mu.Lock()
+    if c, ok := this.calls[key]; ok {
+        this.mu.Unlock()
+        c.wg.Wait()
+
+        return c.val, c.err
+    }
+
+    c := new(flightCall)
+    c.wg.Add(1)
+    this.calls[key] = c
+    this.mu.Unlock()
+
+    defer func() {
+        c.wg.Done()
+
+        this.mu.Lock()
+        delete(this.calls, key)
+        this.mu.Unlock()
+    }()
+
+    c.val, c.err = fn()
+
+    return c.val, c.err
+}
@@ -36,3 +36,17 @@ type Driver interface {
     Flush() (bool, error)
 }
 
+
+/**
+ * 原子操作驱动接口，用于 Add 和锁
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type AtomicDriver interface {
+    // 不存在时存储，返回是否存储成功
+    Add(string, any, time.Duration) (bool, error)
+
+    // 值相等时删除，返回是否删除成功
+    ForgetIfEquals(string, string) (bool, error)
+}
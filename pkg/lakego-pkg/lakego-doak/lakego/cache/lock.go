@@ -0,0 +1,161 @@
+package cache
+
+import (
+    "fmt"
+    "time"
+    "errors"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/cache/interfaces"
+)
+
+// 获取锁超时
+var ErrLockTimeout = errors.New("cache: lock timeout")
+
+/**
+ * 缓存锁
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Lock struct {
+    // 缓存
+    cache *Cache
+
+    // 锁名称
+    name string
+
+    // 持有者
+    owner string
+
+    // 过期时间，0 为不过期
+    ttl time.Duration
+
+    // 阻塞时重试间隔
+    sleep time.Duration
+}
+
+// 构造函数
+func NewLock(cache *Cache, name string, ttl time.Duration, owner ...string) *Lock {
+    lock := &Lock{
+        cache: cache,
+        name:  name,
+        ttl:   ttl,
+        sleep: 250 * time.Millisecond,
+    }
+
+    if len(owner) > 0 && owner[0] != "" {
+        lock.owner = owner[0]
+    } else {
+        lock.owner = uuid.ToUUIDString()
+    }
+
+    return lock
+}
+
+// 设置阻塞时重试间隔
+func (this *Lock) WithSleep(sleep time.Duration) *Lock {
+    this.sleep = sleep
+
+    return this
+}
+
+// 获取持有者
+func (this *Lock) Owner() string {
+    return this.owner
+}
+
+// 尝试获取锁，传入回调时获取成功后执行回调并释放锁
+func (this *Lock) Get(fn ...func()) (bool, error) {
+    ok, err := this.acquire()
+    if err != nil || !ok {
+        return ok, err
+    }
+
+    if len(fn) > 0 {
+        defer this.Release()
+
+        fn[0]()
+    }
+
+    return true, nil
+}
+
+// 阻塞获取锁，wait 为等待的秒数或者 time.Duration
+func (this *Lock) Block(wait any, fn ...func()) (bool, error) {
+    deadline := time.Now().Add(this.cache.formatTime(wait))
+
+    for {
+        ok, err := this.acquire()
+        if err != nil {
+            return false, err
+        }
+
+        if ok {
+            break
+        }
+
+        if time.Now().Add(this.sleep).After(deadline) {
+            return false, ErrLockTimeout
+        }
+
+        time.Sleep(this.sleep)
+    }
+
+    if len(fn) > 0 {
+        defer this.Release()
+
+        fn[0]()
+    }
+
+    return true, nil
+}
+
+// 释放锁，只有持有者可以释放
+func (this *Lock) Release() (bool, error) {
+    key := this.cache.wrapperKey(this.lockKey())
+
+    if driver, ok := this.cache.driver.(interfaces.AtomicDriver); ok {
+        return driver.ForgetIfEquals(key, this.owner)
+    }
+
+    // 驱动不支持原子操作时降级处理
+    val, err := this.cache.driver.Get(key)
+    if err != nil || fmt.Sprintf("%v", val) != this.owner {
+        return false, nil
+    }
+
+    return this.cache.driver.Forget(key)
+}
+
+// 强制释放锁
+func (this *Lock) ForceRelease() (bool, error) {
+    return this.cache.Forget(this.lockKey())
+}
+
+// 当前持有者
+func (this *Lock) CurrentOwner() string {
+    val, err := this.cache.Get(this.lockKey())
+    if err != nil {
+        return ""
+    }
+
+    return fmt.Sprintf("%v", val)
+}
+
+// 是否为当前持有者
+func (this *Lock) IsOwnedByCurrentProcess() bool {
+    return this.CurrentOwner() == this.owner
+}
+
+// 获取锁
+func (this *Lock) acquire() (bool, error) {
+    key := this.cache.wrapperKey(this.lockKey())
+
+    return this.cache.add(key, this.owner, this.ttl)
+}
+
+// 锁 key
+func (this *Lock) lockKey() string {
+    return "lock:" + this.name
+}
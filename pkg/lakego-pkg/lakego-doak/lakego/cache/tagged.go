@@ -0,0 +1,281 @@
+package cache
+
+import (
+    "fmt"
+    "strings"
+    "crypto/sha1"
+    "encoding/hex"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+)
+
+/**
+ * 标签集合
+ *
+ * 每个标签对应一个随机命名空间，清空标签时重置命名空间，
+ * 旧数据不再被访问并等待过期
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type TagSet struct {
+    // 缓存
+    cache *Cache
+
+    // 标签
+    names []string
+}
+
+// 构造函数
+func NewTagSet(cache *Cache, names ...string) *TagSet {
+    return &TagSet{
+        cache: cache,
+        names: names,
+    }
+}
+
+// 标签列表
+func (this *TagSet) GetNames() []string {
+    return this.names
+}
+
+// 重置全部标签
+func (this *TagSet) Reset() error {
+    for _, name := range this.names {
+        if _, err := this.ResetTag(name); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 重置标签
+func (this *TagSet) ResetTag(name string) (string, error) {
+    id := uuid.ToUUIDString()
+
+    err := this.cache.Forever(this.TagKey(name), id)
+    if err != nil {
+        return "", err
+    }
+
+    return id, nil
+}
+
+// 标签 ID
+func (this *TagSet) TagId(name string) (string, error) {
+    val, err := this.cache.Get(this.TagKey(name))
+    if err == nil {
+        if id := fmt.Sprintf("%v", val); id != "" {
+            return id, nil
+        }
+    }
+
+    return this.ResetTag(name)
+}
+
+// 命名空间
+func (this *TagSet) GetNamespace() (string, error) {
+    ids := make([]string, 0, len(this.names))
+
+    for _, name := range this.names {
+        id, err := this.TagId(name)
+        if err != nil {
+            return "", err
+        }
+
+        ids = append(ids, id)
+    }
+
+    return strings.Join(ids, "|"), nil
+}
+
+// 标签存储 key
+func (this *TagSet) TagKey(name string) string {
+    return "tag:" + name + ":key"
+}
+
+/**
+ * 标签缓存
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type TaggedCache struct {
+    // 缓存
+    cache *Cache
+
+    // 标签
+    tags *TagSet
+}
+
+// 构造函数
+func NewTaggedCache(cache *Cache, tags *TagSet) *TaggedCache {
+    return &TaggedCache{
+        cache: cache,
+        tags:  tags,
+    }
+}
+
+// 获取标签
+func (this *TaggedCache) GetTags() *TagSet {
+    return this.tags
+}
+
+// 判断是否存在
+func (this *TaggedCache) Has(key string) bool {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return false
+    }
+
+    return this.cache.Has(key)
+}
+
+// 获取
+func (this *TaggedCache) Get(key string) (any, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return "", err
+    }
+
+    return this.cache.Get(key)
+}
+
+// 设置
+func (this *TaggedCache) Put(key string, value any, ttl any) error {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return err
+    }
+
+    return this.cache.Put(key, value, ttl)
+}
+
+// 永久设置
+func (this *TaggedCache) Forever(key string, value any) error {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return err
+    }
+
+    return this.cache.Forever(key, value)
+}
+
+// 不存在时设置
+func (this *TaggedCache) Add(key string, value any, ttl any) (bool, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return false, err
+    }
+
+    return this.cache.Add(key, value, ttl)
+}
+
+// 获取后删除
+func (this *TaggedCache) Pull(key string) (any, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return "", err
+    }
+
+    return this.cache.Pull(key)
+}
+
+// 获取缓存，不存在时调用回调并缓存结果
+func (this *TaggedCache) Remember(key string, ttl any, fn func() (any, error)) (any, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return nil, err
+    }
+
+    return this.cache.Remember(key, ttl, fn)
+}
+
+// 获取缓存，不存在时调用回调并永久缓存结果
+func (this *TaggedCache) RememberForever(key string, fn func() (any, error)) (any, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return nil, err
+    }
+
+    return this.cache.RememberForever(key, fn)
+}
+
+// 批量获取
+func (this *TaggedCache) Many(keys []string) map[string]any {
+    data := make(map[string]any)
+
+    for _, key := range keys {
+        val, err := this.Get(key)
+        if err == nil {
+            data[key] = val
+        }
+    }
+
+    return data
+}
+
+// 批量设置
+func (this *TaggedCache) PutMany(values map[string]any, ttl any) error {
+    for key, value := range values {
+        err := this.Put(key, value, ttl)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 增加
+func (this *TaggedCache) Increment(key string, value ...int64) error {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return err
+    }
+
+    return this.cache.Increment(key, value...)
+}
+
+// 减少
+func (this *TaggedCache) Decrement(key string, value ...int64) error {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return err
+    }
+
+    return this.cache.Decrement(key, value...)
+}
+
+// 删除
+func (this *TaggedCache) Forget(key string) (bool, error) {
+    key, err := this.taggedKey(key)
+    if err != nil {
+        return false, err
+    }
+
+    return this.cache.Forget(key)
+}
+
+// 清空标签下的全部缓存
+func (this *TaggedCache) Flush() (bool, error) {
+    err := this.tags.Reset()
+    if err != nil {
+        return false, err
+    }
+
+    return true, nil
+}
+
+// 带标签的 key
+func (this *TaggedCache) taggedKey(key string) (string, error) {
+    namespace, err := this.tags.GetNamespace()
+    if err != nil {
+        return "", err
+    }
+
+    sum := sha1.Sum([]byte(namespace))
+
+    return hex.EncodeToString(sum[:]) + ":" + key, nil
+}
@@ -0,0 +1,263 @@
+package migrate
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/deatil/lakego-doak/lakego/color"
+    "github.com/deatil/lakego-doak/lakego/command"
+    "github.com/deatil/lakego-doak/lakego/migration"
+    "github.com/deatil/lakego-doak/lakego/facade/database"
+)
+
+/**
+ * 执行迁移
+ *
+ * > ./main migrate [--group=admin]
+ * > main.exe migrate [--group=admin]
+ * > go run main.go migrate [--group=admin]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var MigrateCmd = &command.Command{
+    Use: "migrate",
+    Short: "执行数据库迁移。",
+    Example: "{execfile} migrate --group=admin",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Migrate()
+    },
+}
+
+/**
+ * 回滚迁移
+ *
+ * > ./main migrate:rollback [--step=1] [--group=admin]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var RollbackCmd = &command.Command{
+    Use: "migrate:rollback",
+    Short: "回滚数据库迁移。",
+    Example: "{execfile} migrate:rollback --step=1",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Rollback()
+    },
+}
+
+/**
+ * 迁移状态
+ *
+ * > ./main migrate:status
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var StatusCmd = &command.Command{
+    Use: "migrate:status",
+    Short: "查看数据库迁移状态。",
+    Example: "{execfile} migrate:status",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Status()
+    },
+}
+
+/**
+ * 重建数据库
+ *
+ * > ./main migrate:fresh --force [--seed]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var FreshCmd = &command.Command{
+    Use: "migrate:fresh",
+    Short: "删除全部数据表后重新执行迁移。",
+    Example: "{execfile} migrate:fresh --force --seed",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Fresh()
+    },
+}
+
+/**
+ * 数据填充
+ *
+ * > ./main db:seed [--group=admin]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var SeedCmd = &command.Command{
+    Use: "db:seed",
+    Short: "执行数据填充。",
+    Example: "{execfile} db:seed --group=admin",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Seed()
+    },
+}
+
+var (
+    // 分组
+    migrateGroup string
+
+    // 回滚分组
+    rollbackGroup string
+
+    // 回滚数量
+    rollbackStep int
+
+    // 强制执行
+    freshForce bool
+
+    // 重建后填充
+    freshSeed bool
+
+    // 填充分组
+    seedGroup string
+)
+
+func init() {
+    MigrateCmd.Flags().StringVarP(&migrateGroup, "group", "g", "", "迁移分组，多个用逗号分隔")
+
+    RollbackCmd.Flags().StringVarP(&rollbackGroup, "group", "g", "", "迁移分组，多个用逗号分隔")
+    RollbackCmd.Flags().IntVarP(&rollbackStep, "step", "s", 0, "回滚数量，默认回滚最后一个批次")
+
+    FreshCmd.Flags().BoolVarP(&freshForce, "force", "f", false, "确认删除全部数据表")
+    FreshCmd.Flags().BoolVarP(&freshSeed, "seed", "", false, "重建后执行数据填充")
+
+    SeedCmd.Flags().StringVarP(&seedGroup, "group", "g", "", "填充分组，多个用逗号分隔")
+}
+
+// 执行迁移
+func Migrate() {
+    groups := splitGroups(migrateGroup)
+
+    done, err := newMigrator().Run(migration.Migrations(groups...))
+    showDone("迁移", done)
+
+    if err != nil {
+        color.Redln("迁移失败：" + err.Error())
+        return
+    }
+
+    color.Greenln("迁移完成")
+}
+
+// 回滚迁移
+func Rollback() {
+    groups := splitGroups(rollbackGroup)
+
+    done, err := newMigrator().Rollback(migration.Migrations(), rollbackStep, groups...)
+    showDone("回滚", done)
+
+    if err != nil {
+        color.Redln("回滚失败：" + err.Error())
+        return
+    }
+
+    color.Greenln("回滚完成")
+}
+
+// 迁移状态
+func Status() {
+    list, err := newMigrator().Status(migration.Migrations())
+    if err != nil {
+        color.Redln("获取状态失败：" + err.Error())
+        return
+    }
+
+    for _, item := range list {
+        if item.Ran {
+            color.Greenln(fmt.Sprintf("[已执行] [%d] %s (%s)", item.Batch, item.Name, item.Group))
+        } else {
+            color.Yellowln(fmt.Sprintf("[未执行] %s (%s)", item.Name, item.Group))
+        }
+    }
+}
+
+// 重建数据库
+func Fresh() {
+    if !freshForce {
+        color.Redln("该操作会删除全部数据表，请使用 --force 确认")
+        return
+    }
+
+    m := newMigrator()
+
+    done, err := m.Fresh(migration.Migrations())
+    showDone("迁移", done)
+
+    if err != nil {
+        color.Redln("重建失败：" + err.Error())
+        return
+    }
+
+    if freshSeed {
+        done, err = m.Seed(migration.Seeders())
+        showDone("填充", done)
+
+        if err != nil {
+            color.Redln("填充失败：" + err.Error())
+            return
+        }
+    }
+
+    color.Greenln("重建完成")
+}
+
+// 数据填充
+func Seed() {
+    groups := splitGroups(seedGroup)
+
+    done, err := newMigrator().Seed(migration.Seeders(groups...))
+    showDone("填充", done)
+
+    if err != nil {
+        color.Redln("填充失败：" + err.Error())
+        return
+    }
+
+    color.Greenln("填充完成")
+}
+
+// 迁移
+func newMigrator() *migration.Migrator {
+    return migration.NewMigrator(database.New())
+}
+
+// 显示已完成
+func showDone(typ string, done []string) {
+    for _, name := range done {
+        color.Cyanln(typ + "：" + name)
+    }
+}
+
+// 分组
+func splitGroups(group string) []string {
+    groups := make([]string, 0)
+
+    for _, g := range strings.Split(group, ",") {
+        g = strings.TrimSpace(g)
+        if g != "" {
+            groups = append(groups, g)
+        }
+    }
+
+    return groups
+}
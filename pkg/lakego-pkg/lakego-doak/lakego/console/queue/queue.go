@@ -0,0 +1,241 @@
+package queue
+
+import (
+    "fmt"
+    "time"
+    "strings"
+    "context"
+    "syscall"
+    "os/signal"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/color"
+    "github.com/deatil/lakego-doak/lakego/queue"
+    "github.com/deatil/lakego-doak/lakego/command"
+    facade_queue "github.com/deatil/lakego-doak/lakego/facade/queue"
+)
+
+/**
+ * 执行队列任务
+ *
+ * > ./main queue:work [--connection=database] [--queue=default,emails] [--once]
+ * > main.exe queue:work
+ * > go run main.go queue:work
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var WorkCmd = &command.Command{
+    Use: "queue:work",
+    Short: "执行队列任务。",
+    Example: "{execfile} queue:work --queue=default,emails",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Work()
+    },
+}
+
+/**
+ * 失败任务列表
+ *
+ * > ./main queue:failed
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var FailedCmd = &command.Command{
+    Use: "queue:failed",
+    Short: "查看失败的队列任务。",
+    Example: "{execfile} queue:failed",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Failed()
+    },
+}
+
+/**
+ * 重试失败任务
+ *
+ * > ./main queue:retry [id...|all]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var RetryCmd = &command.Command{
+    Use: "queue:retry",
+    Short: "重试失败的队列任务。",
+    Example: "{execfile} queue:retry all",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+        Retry(args)
+    },
+}
+
+var (
+    // 连接
+    connection string
+
+    // 队列
+    queues string
+
+    // 执行一次
+    once bool
+
+    // 并发数量
+    concurrency int
+
+    // 最大执行次数
+    tries int
+
+    // 超时时间
+    timeout int
+
+    // 空闲等待
+    sleep int
+)
+
+func init() {
+    pf := WorkCmd.Flags()
+    pf.StringVarP(&connection, "connection", "c", "", "队列连接")
+    pf.StringVarP(&queues, "queue", "q", queue.DefaultQueue, "队列名称，多个用逗号分隔，按顺序获取")
+    pf.BoolVarP(&once, "once", "", false, "只执行一个任务")
+    pf.IntVarP(&concurrency, "concurrency", "", 1, "并发数量")
+    pf.IntVarP(&tries, "tries", "", 1, "默认最大执行次数")
+    pf.IntVarP(&timeout, "timeout", "", 60, "默认超时时间，单位：秒")
+    pf.IntVarP(&sleep, "sleep", "", 3, "队列为空时的等待时间，单位：秒")
+
+    FailedCmd.Flags().StringVarP(&connection, "connection", "c", "", "队列连接")
+    RetryCmd.Flags().StringVarP(&connection, "connection", "c", "", "队列连接")
+}
+
+// 执行队列任务
+func Work() {
+    q := getQueue()
+
+    names := make([]string, 0)
+    for _, name := range strings.Split(queues, ",") {
+        if name = strings.TrimSpace(name); name != "" {
+            names = append(names, name)
+        }
+    }
+
+    worker := q.Worker(queue.WorkerOptions{
+        Queues:      names,
+        Sleep:       time.Duration(sleep) * time.Second,
+        Concurrency: concurrency,
+        Tries:       tries,
+        Timeout:     time.Duration(timeout) * time.Second,
+    })
+
+    worker.WithReporter(func(job *queue.Job, status string, err error) {
+        nowDate := datebin.Now().ToDatetimeString()
+        msg := fmt.Sprintf("[%s] %s %s (%s)", nowDate, job.Name, status, job.ID)
+
+        switch status {
+            case queue.StatusProcessed:
+                color.Greenln(msg)
+            case queue.StatusReleased:
+                color.Yellowln(msg + " " + err.Error())
+            default:
+                color.Redln(msg + " " + err.Error())
+        }
+    })
+
+    if once {
+        if _, err := worker.RunOnce(); err != nil {
+            color.Redln("执行失败：" + err.Error())
+        }
+
+        return
+    }
+
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer stop()
+
+    color.Greenln("队列 [" + q.GetName() + "] 开始执行，队列：" + strings.Join(names, ","))
+
+    worker.Run(ctx)
+
+    color.Greenln("队列已停止")
+}
+
+// 失败任务列表
+func Failed() {
+    failed := getQueue().GetFailed()
+    if failed == nil {
+        color.Redln("没有设置失败任务存储")
+        return
+    }
+
+    list, err := failed.All()
+    if err != nil {
+        color.Redln("获取失败任务错误：" + err.Error())
+        return
+    }
+
+    if len(list) == 0 {
+        color.Greenln("没有失败的任务")
+        return
+    }
+
+    for _, item := range list {
+        failedAt := datebin.FromTimestamp(item.FailedAt).ToDatetimeString()
+
+        color.Yellowln(fmt.Sprintf("[%s] %s %s/%s", failedAt, item.UUID, item.Connection, item.Queue))
+        color.Redln("    " + item.Exception)
+    }
+}
+
+// 重试失败任务
+func Retry(ids []string) {
+    q := getQueue()
+
+    failed := q.GetFailed()
+    if failed == nil {
+        color.Redln("没有设置失败任务存储")
+        return
+    }
+
+    if len(ids) == 0 {
+        color.Redln("请输入要重试的任务 ID 或者 all")
+        return
+    }
+
+    if len(ids) == 1 && ids[0] == "all" {
+        list, err := failed.All()
+        if err != nil {
+            color.Redln("获取失败任务错误：" + err.Error())
+            return
+        }
+
+        ids = make([]string, 0, len(list))
+        for _, item := range list {
+            ids = append(ids, item.UUID)
+        }
+    }
+
+    for _, id := range ids {
+        if err := q.Retry(id); err != nil {
+            color.Redln("任务 [" + id + "] 重试失败：" + err.Error())
+            continue
+        }
+
+        color.Greenln("任务 [" + id + "] 已重新加入队列")
+    }
+}
+
+// 队列连接
+func getQueue() *queue.Queue {
+    if connection == "" {
+        return facade_queue.Default
+    }
+
+    return facade_queue.Connection(connection)
+}
@@ -2,6 +2,8 @@ package schedule
 
 import (
     "fmt"
+    "time"
+    "strings"
 
     "github.com/deatil/go-datebin/datebin"
 
@@ -58,3 +60,121 @@ func NewScheduleCmd(s *schedule.Schedule) *command.Command {
 
     return ScheduleCmd
 }
+
+/**
+ * 计划任务列表
+ *
+ * > ./main schedule:list
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var ScheduleListCmd = &command.Command{
+    Use: "schedule:list",
+    Short: "计划任务列表。",
+    Example: "{execfile} schedule:list",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+
+    },
+}
+
+// 构造函数
+func NewScheduleListCmd(s *schedule.Schedule) *command.Command {
+    ScheduleListCmd.Run = func(cmd *command.Command, args []string) {
+        now := time.Now()
+
+        entries := s.Entries()
+        if len(entries) == 0 {
+            color.Yellowln("没有计划任务")
+            return
+        }
+
+        for _, entry := range entries {
+            next := "-"
+            if t, err := entry.Next(now); err == nil {
+                next = datebin.FromStdTime(t).ToDatetimeString()
+            }
+
+            options := make([]string, 0)
+            if entry.IsWithoutOverlapping() {
+                options = append(options, "without-overlapping")
+            }
+            if entry.IsOnOneServer() {
+                options = append(options, "on-one-server")
+            }
+            if entry.GetTimeout() > 0 {
+                options = append(options, "timeout=" + entry.GetTimeout().String())
+            }
+
+            color.Greenln(fmt.Sprintf("%-30s %-20s next: %s %s", entry.Name, entry.Spec, next, strings.Join(options, " ")))
+        }
+    }
+
+    return ScheduleListCmd
+}
+
+/**
+ * 计划任务执行记录
+ *
+ * > ./main schedule:history [--name=name] [--limit=20]
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+var ScheduleHistoryCmd = &command.Command{
+    Use: "schedule:history",
+    Short: "计划任务执行记录。",
+    Example: "{execfile} schedule:history --name=name --limit=20",
+    SilenceUsage: true,
+    PreRun: func(cmd *command.Command, args []string) {
+    },
+    Run: func(cmd *command.Command, args []string) {
+
+    },
+}
+
+var (
+    // 任务名称
+    historyName string
+
+    // 数量
+    historyLimit int
+)
+
+func init() {
+    pf := ScheduleHistoryCmd.Flags()
+    pf.StringVarP(&historyName, "name", "n", "", "任务名称")
+    pf.IntVarP(&historyLimit, "limit", "l", 20, "显示数量")
+}
+
+// 构造函数
+func NewScheduleHistoryCmd(s *schedule.Schedule) *command.Command {
+    ScheduleHistoryCmd.Run = func(cmd *command.Command, args []string) {
+        list, err := s.History(historyName, historyLimit)
+        if err != nil {
+            color.Redln("获取执行记录失败：" + err.Error())
+            return
+        }
+
+        if len(list) == 0 {
+            color.Yellowln("没有执行记录")
+            return
+        }
+
+        for _, item := range list {
+            startTime := datebin.FromTimestamp(item.StartTime).ToDatetimeString()
+            msg := fmt.Sprintf("[%s] %-30s %-8s %dms %s", startTime, item.Name, item.Status, item.Duration, item.Server)
+
+            if item.Status == schedule.StatusFailed {
+                color.Redln(msg + " " + item.Error)
+            } else {
+                color.Greenln(msg)
+            }
+        }
+    }
+
+    return ScheduleHistoryCmd
+}
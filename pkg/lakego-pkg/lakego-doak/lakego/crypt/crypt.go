@@ -0,0 +1,92 @@
+package crypt
+
+import (
+    "errors"
+    "crypto/aes"
+    "crypto/rand"
+    "crypto/cipher"
+    "crypto/sha256"
+    "encoding/base64"
+)
+
+// 密钥为空
+var ErrEmptyKey = errors.New("crypt: key is empty")
+
+// 密文格式错误
+var ErrInvalidData = errors.New("crypt: invalid data")
+
+// 构造函数
+func New(key []byte) *Crypt {
+    return &Crypt{
+        key: key,
+    }
+}
+
+/**
+ * 对称加密，AES-256-GCM
+ *
+ * 密钥经过 sha256 得到 32 字节，
+ * 密文为 base64(nonce + 加密数据)
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Crypt struct {
+    // 密钥
+    key []byte
+}
+
+// 加密
+func (this *Crypt) Encrypt(data string) (string, error) {
+    aead, err := this.aead()
+    if err != nil {
+        return "", err
+    }
+
+    nonce := make([]byte, aead.NonceSize())
+    if _, err := rand.Read(nonce); err != nil {
+        return "", err
+    }
+
+    sealed := aead.Seal(nonce, nonce, []byte(data), nil)
+
+    return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// 解密
+func (this *Crypt) Decrypt(data string) (string, error) {
+    aead, err := this.aead()
+    if err != nil {
+        return "", err
+    }
+
+    sealed, err := base64.StdEncoding.DecodeString(data)
+    if err != nil || len(sealed) < aead.NonceSize() {
+        return "", ErrInvalidData
+    }
+
+    nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+    plain, err := aead.Open(nil, nonce, sealed, nil)
+    if err != nil {
+        return "", ErrInvalidData
+    }
+
+    return string(plain), nil
+}
+
+// 加密器
+func (this *Crypt) aead() (cipher.AEAD, error) {
+    if len(this.key) == 0 {
+        return nil, ErrEmptyKey
+    }
+
+    key := sha256.Sum256(this.key)
+
+    block, err := aes.NewCipher(key[:])
+    if err != nil {
+        return nil, err
+    }
+
+    return cipher.NewGCM(block)
+}
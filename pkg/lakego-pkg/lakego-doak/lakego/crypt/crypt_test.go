@@ -0,0 +1,42 @@
+package crypt
+
+import (
+    "testing"
+    "reflect"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_Crypt(t *testing.T) {
+    eq := assertT(t)
+
+    c := New([]byte("lakego-app-key"))
+
+    encrypted, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+    eq(err, nil, "Encrypt err")
+    eq(encrypted != "JBSWY3DPEHPK3PXP", true, "Encrypt")
+
+    // 每次加密使用不同的 nonce
+    encrypted2, _ := c.Encrypt("JBSWY3DPEHPK3PXP")
+    eq(encrypted != encrypted2, true, "Encrypt nonce")
+
+    decrypted, err := c.Decrypt(encrypted)
+    eq(err, nil, "Decrypt err")
+    eq(decrypted, "JBSWY3DPEHPK3PXP", "Decrypt")
+
+    // 密钥不同
+    _, err = New([]byte("other-key")).Decrypt(encrypted)
+    eq(err, ErrInvalidData, "Decrypt other key")
+
+    _, err = c.Decrypt("JBSWY3DPEHPK3PXP")
+    eq(err, ErrInvalidData, "Decrypt plain")
+
+    _, err = New(nil).Encrypt("JBSWY3DPEHPK3PXP")
+    eq(err, ErrEmptyKey, "Encrypt empty key")
+}
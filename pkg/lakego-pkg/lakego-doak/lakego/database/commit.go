@@ -0,0 +1,116 @@
+package database
+
+import (
+    "fmt"
+    "sync"
+    "context"
+
+    "gorm.io/gorm"
+)
+
+// 当前语句提交后执行的回调
+const afterCommitKey = "lakego:after_commit"
+
+// 上下文 key
+type afterCommitContextKey struct{}
+
+// 事务提交后执行的回调列表
+type afterCommitQueue struct {
+    mu  sync.Mutex
+    fns []func()
+}
+
+// 添加
+func (this *afterCommitQueue) add(fn func()) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.fns = append(this.fns, fn)
+}
+
+// 执行
+func (this *afterCommitQueue) run() {
+    this.mu.Lock()
+    fns := this.fns
+    this.fns = nil
+    this.mu.Unlock()
+
+    for _, fn := range fns {
+        fn()
+    }
+}
+
+/**
+ * 事务，提交成功后执行事务内 AfterCommit 添加的回调，
+ * 回滚时不执行
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func Transaction(db *gorm.DB, fc func(tx *gorm.DB) error) error {
+    ctx := db.Statement.Context
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    queue := &afterCommitQueue{}
+
+    err := db.WithContext(context.WithValue(ctx, afterCommitContextKey{}, queue)).
+        Transaction(fc)
+    if err != nil {
+        return err
+    }
+
+    queue.run()
+
+    return nil
+}
+
+// 在模型钩子中使用，数据提交后执行回调
+// 在 Transaction 内时等事务提交后执行，在语句的默认事务内时等语句提交后执行，
+// 否则直接执行
+func AfterCommit(tx *gorm.DB, fn func()) {
+    if queue, ok := tx.Statement.Context.Value(afterCommitContextKey{}).(*afterCommitQueue); ok {
+        queue.add(fn)
+        return
+    }
+
+    if _, ok := tx.InstanceGet("gorm:started_transaction"); ok {
+        // 钩子中的 tx 与语句共用 Statement，直接存储避免 InstanceSet 生成新的 Statement
+        key := fmt.Sprintf("%p", tx.Statement) + afterCommitKey
+
+        queue, _ := tx.Statement.Settings.LoadOrStore(key, &afterCommitQueue{})
+        queue.(*afterCommitQueue).add(fn)
+        return
+    }
+
+    fn()
+}
+
+// 注册提交后执行回调，在默认事务提交之后执行
+func RegisterAfterCommitCallbacks(db *gorm.DB) error {
+    var err error
+
+    runQueue := func(tx *gorm.DB) {
+        queue, ok := tx.InstanceGet(afterCommitKey)
+        if !ok || tx.Error != nil {
+            return
+        }
+
+        queue.(*afterCommitQueue).run()
+    }
+
+    callback := db.Callback()
+
+    err = callback.Create().After("gorm:commit_or_rollback_transaction").Register("lakego:after_commit", runQueue)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Update().After("gorm:commit_or_rollback_transaction").Register("lakego:after_commit", runQueue)
+    if err != nil {
+        return err
+    }
+
+    return callback.Delete().After("gorm:commit_or_rollback_transaction").Register("lakego:after_commit", runQueue)
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+    "errors"
+    "testing"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+)
+
+// 提交时的记录
+var commitEvents []string
+
+type commitUser struct {
+    ID   uint
+    Name string
+}
+
+func (this *commitUser) AfterSave(tx *gorm.DB) error {
+    commitEvents = append(commitEvents, "save:" + this.Name)
+
+    AfterCommit(tx, func() {
+        commitEvents = append(commitEvents, "commit:" + this.Name)
+    })
+
+    if this.Name == "fail" {
+        return errors.New("fail")
+    }
+
+    return nil
+}
+
+func newCommitDB(t *testing.T) *gorm.DB {
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/commit.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = RegisterAfterCommitCallbacks(db); err != nil {
+        t.Fatal(err)
+    }
+
+    db.AutoMigrate(&commitUser{})
+
+    commitEvents = nil
+
+    return db
+}
+
+func Test_AfterCommit(t *testing.T) {
+    db := newCommitDB(t)
+
+    db.Create(&commitUser{Name: "a"})
+    db.Create(&commitUser{Name: "fail"})
+
+    expected := []string{"save:a", "commit:a", "save:fail"}
+    if !reflect.DeepEqual(commitEvents, expected) {
+        t.Errorf("Failed AfterCommit: actual: %v, expected: %v", commitEvents, expected)
+    }
+}
+
+func Test_AfterCommit_Transaction(t *testing.T) {
+    db := newCommitDB(t)
+
+    err := Transaction(db, func(tx *gorm.DB) error {
+        tx.Create(&commitUser{Name: "a"})
+        tx.Create(&commitUser{Name: "b"})
+
+        commitEvents = append(commitEvents, "end")
+
+        return nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    expected := []string{"save:a", "save:b", "end", "commit:a", "commit:b"}
+    if !reflect.DeepEqual(commitEvents, expected) {
+        t.Errorf("Failed Transaction: actual: %v, expected: %v", commitEvents, expected)
+    }
+
+    commitEvents = nil
+
+    // 回滚时不执行
+    Transaction(db, func(tx *gorm.DB) error {
+        tx.Create(&commitUser{Name: "c"})
+
+        return errors.New("rollback")
+    })
+
+    expected = []string{"save:c"}
+    if !reflect.DeepEqual(commitEvents, expected) {
+        t.Errorf("Failed Transaction rollback: actual: %v, expected: %v", commitEvents, expected)
+    }
+}
@@ -8,8 +8,10 @@ import (
     "gorm.io/gorm"
     "gorm.io/gorm/schema"
     "gorm.io/gorm/logger"
+    "gorm.io/plugin/dbresolver"
 
     "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/database"
     "github.com/deatil/lakego-doak/lakego/database/interfaces"
 )
 
@@ -138,9 +140,60 @@ func (this *Driver) CreateOpenConnection(dia gorm.Dialector) {
             db.Statement.RaiseErrorOnNotFound = false
         })
 
+    // 提交后执行回调
+    if err := database.RegisterAfterCommitCallbacks(db); err != nil {
+        log.Printf("Error to register database after commit callbacks: %v", err)
+    }
+
     this.db = db
 }
 
+/**
+ * 读写分离，配置 sources 和 replicas 后生效
+ */
+func (this *Driver) UseResolver(dialector func(dsn string) gorm.Dialector) {
+    if this.db == nil {
+        return
+    }
+
+    // 配置
+    cfg := array.ArrayFrom(this.Config)
+
+    sources := cfg.Value("sources").ToStringSlice()
+    replicas := cfg.Value("replicas").ToStringSlice()
+    if len(sources) == 0 && len(replicas) == 0 {
+        return
+    }
+
+    resolverConfig := dbresolver.Config{
+        Policy: database.NewPolicy(cfg.Value("policy").ToString()),
+    }
+
+    for _, dsn := range sources {
+        resolverConfig.Sources = append(resolverConfig.Sources, dialector(dsn))
+    }
+
+    for _, dsn := range replicas {
+        resolverConfig.Replicas = append(resolverConfig.Replicas, dialector(dsn))
+    }
+
+    resolver := dbresolver.Register(resolverConfig).
+        SetConnMaxIdleTime(cfg.Value("conn-max-idle-time").ToDuration()).
+        SetConnMaxLifetime(cfg.Value("conn-max-lifetime").ToDuration()).
+        SetMaxIdleConns(cfg.Value("max-idle-conns").ToInt()).
+        SetMaxOpenConns(cfg.Value("max-open-conns").ToInt())
+
+    if err := this.db.Use(resolver); err != nil {
+        log.Printf("Error to register database resolver: %v", err)
+        return
+    }
+
+    // 写后读主库
+    if err := database.RegisterStickyCallbacks(this.db); err != nil {
+        log.Printf("Error to register database sticky callbacks: %v", err)
+    }
+}
+
 /**
  * 初始化
  */
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+    "gorm.io/gorm"
     "gorm.io/driver/mysql"
 
     "github.com/deatil/lakego-doak/lakego/database/driver"
@@ -40,6 +41,15 @@ func (this *Mysql) CreateConnection() {
     // 连接配置
     dsn = conf["dsn"].(string)
 
+    // 创建链接
+    this.CreateOpenConnection(this.Dialector(dsn))
+
+    // 读写分离
+    this.UseResolver(this.Dialector)
+}
+
+// 连接方言
+func (this *Mysql) Dialector(dsn string) gorm.Dialector {
     mc := mysql.Config{
         DSN:                       dsn,
         DefaultStringSize:         191,   // default length of string type field
@@ -49,8 +59,5 @@ func (this *Mysql) CreateConnection() {
         DontSupportRenameColumn:   true,
     }
 
-    // 创建链接
-    dialector := mysql.New(mc)
-
-    this.CreateOpenConnection(dialector)
+    return mysql.New(mc)
 }
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/driver/postgres"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/database/driver"
+)
+
+/**
+ * PostgreSQL 驱动
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Postgres struct {
+    // 继承默认
+    driver.Driver
+}
+
+// 构造函数
+func New(conf ...map[string]any) *Postgres {
+    p := &Postgres{}
+
+    if len(conf) > 0 {
+        p.Config = conf[0]
+    }
+
+    p.CreateConnection()
+
+    return p
+}
+
+// 创建连接
+func (this *Postgres) CreateConnection() {
+    // 配置
+    cfg := array.ArrayFrom(this.Config)
+
+    // 创建链接
+    this.CreateOpenConnection(this.Dialector(cfg.Value("dsn").ToString()))
+
+    // 读写分离
+    this.UseResolver(this.Dialector)
+}
+
+// 连接方言
+func (this *Postgres) Dialector(dsn string) gorm.Dialector {
+    // 配置
+    cfg := array.ArrayFrom(this.Config)
+
+    pc := postgres.Config{
+        DSN: dsn,
+        // 关闭预编译语句缓存，兼容 pgbouncer 等连接池
+        PreferSimpleProtocol: cfg.Value("prefer-simple-protocol").ToBool(),
+    }
+
+    return postgres.New(pc)
+}
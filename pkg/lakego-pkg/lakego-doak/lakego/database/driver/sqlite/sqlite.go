@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+    "os"
+    "strings"
+    "path/filepath"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/database/driver"
+)
+
+/**
+ * SQLite 驱动
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Sqlite struct {
+    // 继承默认
+    driver.Driver
+}
+
+// 构造函数
+func New(conf ...map[string]any) *Sqlite {
+    s := &Sqlite{}
+
+    if len(conf) > 0 {
+        s.Config = conf[0]
+    }
+
+    s.CreateConnection()
+
+    return s
+}
+
+// 创建连接
+func (this *Sqlite) CreateConnection() {
+    // 配置
+    cfg := array.ArrayFrom(this.Config)
+
+    // 创建链接
+    this.CreateOpenConnection(this.Dialector(cfg.Value("dsn").ToString()))
+
+    // 读写分离
+    this.UseResolver(this.Dialector)
+}
+
+// 连接方言
+func (this *Sqlite) Dialector(dsn string) gorm.Dialector {
+    // 数据库文件，支持 {runtime} 等路径前缀
+    dsn = path.FormatPath(dsn)
+
+    // 确保数据库文件目录存在
+    if dsn != ":memory:" && !strings.HasPrefix(dsn, "file:") {
+        dbFile, _, _ := strings.Cut(dsn, "?")
+        os.MkdirAll(filepath.Dir(dbFile), 0755)
+    }
+
+    return sqlite.Open(dsn)
+}
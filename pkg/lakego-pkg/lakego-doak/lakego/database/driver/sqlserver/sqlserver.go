@@ -0,0 +1,55 @@
+package sqlserver
+
+import (
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlserver"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/database/driver"
+)
+
+/**
+ * SQL Server 驱动
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Sqlserver struct {
+    // 继承默认
+    driver.Driver
+}
+
+// 构造函数
+func New(conf ...map[string]any) *Sqlserver {
+    s := &Sqlserver{}
+
+    if len(conf) > 0 {
+        s.Config = conf[0]
+    }
+
+    s.CreateConnection()
+
+    return s
+}
+
+// 创建连接
+func (this *Sqlserver) CreateConnection() {
+    // 配置
+    cfg := array.ArrayFrom(this.Config)
+
+    // 创建链接
+    this.CreateOpenConnection(this.Dialector(cfg.Value("dsn").ToString()))
+
+    // 读写分离
+    this.UseResolver(this.Dialector)
+}
+
+// 连接方言
+func (this *Sqlserver) Dialector(dsn string) gorm.Dialector {
+    sc := sqlserver.Config{
+        DSN:               dsn,
+        DefaultStringSize: 256,
+    }
+
+    return sqlserver.New(sc)
+}
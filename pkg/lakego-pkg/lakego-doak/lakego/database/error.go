@@ -0,0 +1,42 @@
+package database
+
+import (
+    "errors"
+    "strings"
+)
+
+// 唯一索引冲突时各数据库的错误信息
+var duplicateKeyMessages = []string{
+    // mysql
+    "Error 1062",
+    "Duplicate entry",
+    // postgres
+    "SQLSTATE 23505",
+    "duplicate key value violates unique constraint",
+    // sqlite
+    "UNIQUE constraint failed",
+    // sqlserver
+    "Cannot insert duplicate key",
+}
+
+// 是否为唯一索引冲突
+func IsDuplicateKey(err error) bool {
+    if err == nil {
+        return false
+    }
+
+    // 驱动错误提供 SQLSTATE 时优先使用，比如 postgres
+    var state interface{ SQLState() string }
+    if errors.As(err, &state) {
+        return state.SQLState() == "23505"
+    }
+
+    msg := err.Error()
+    for _, m := range duplicateKeyMessages {
+        if strings.Contains(msg, m) {
+            return true
+        }
+    }
+
+    return false
+}
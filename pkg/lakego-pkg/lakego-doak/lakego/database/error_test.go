@@ -0,0 +1,69 @@
+package database
+
+import (
+    "errors"
+    "testing"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+)
+
+type duplicateUser struct {
+    ID   uint
+    Name string `gorm:"uniqueIndex"`
+}
+
+type testStateError struct {
+    state string
+}
+
+func (this testStateError) Error() string {
+    return "state error"
+}
+
+func (this testStateError) SQLState() string {
+    return this.state
+}
+
+func Test_IsDuplicateKey(t *testing.T) {
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/test.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    db.AutoMigrate(&duplicateUser{})
+
+    if err := db.Create(&duplicateUser{Name: "lakego"}).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    err = db.Create(&duplicateUser{Name: "lakego"}).Error
+    if !IsDuplicateKey(err) {
+        t.Errorf("Failed IsDuplicateKey sqlite: %v", err)
+    }
+
+    tests := map[string]bool{
+        "Error 1062 (23000): Duplicate entry '1' for key 'seq'": true,
+        "ERROR: duplicate key value violates unique constraint \"idx_seq\" (SQLSTATE 23505)": true,
+        "mssql: Cannot insert duplicate key row in object 'dbo.log'": true,
+        "database is locked": false,
+    }
+
+    for msg, expected := range tests {
+        if IsDuplicateKey(errors.New(msg)) != expected {
+            t.Errorf("Failed IsDuplicateKey: %s", msg)
+        }
+    }
+
+    if !IsDuplicateKey(testStateError{"23505"}) {
+        t.Error("Failed IsDuplicateKey SQLState")
+    }
+
+    if IsDuplicateKey(testStateError{"40001"}) {
+        t.Error("Failed IsDuplicateKey other SQLState")
+    }
+
+    if IsDuplicateKey(nil) {
+        t.Error("Failed IsDuplicateKey nil")
+    }
+}
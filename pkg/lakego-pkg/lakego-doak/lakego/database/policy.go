@@ -0,0 +1,39 @@
+package database
+
+import (
+    "sync/atomic"
+
+    "gorm.io/gorm"
+    "gorm.io/plugin/dbresolver"
+)
+
+// 随机选择
+type RandomPolicy = dbresolver.RandomPolicy
+
+/**
+ * 轮询选择
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type RoundRobinPolicy struct {
+    // 计数
+    counter uint64
+}
+
+// 选择连接
+func (this *RoundRobinPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+    n := atomic.AddUint64(&this.counter, 1)
+
+    return connPools[(n - 1) % uint64(len(connPools))]
+}
+
+// 根据名称获取选择策略
+func NewPolicy(name string) dbresolver.Policy {
+    switch name {
+        case "round-robin", "roundrobin":
+            return &RoundRobinPolicy{}
+        default:
+            return RandomPolicy{}
+    }
+}
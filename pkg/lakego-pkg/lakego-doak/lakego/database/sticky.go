@@ -0,0 +1,137 @@
+package database
+
+import (
+    "context"
+    "strings"
+    "sync/atomic"
+
+    "gorm.io/gorm"
+    "gorm.io/plugin/dbresolver"
+)
+
+// 框架上下文中存储的 key，比如 gin 的 ctx.Set
+const StickyKey = "lakego-database.sticky"
+
+// 上下文 key
+type stickyContextKey struct{}
+
+/**
+ * 写后读主库
+ *
+ * 同一个请求上下文内写入数据后，后续查询都使用主库，
+ * 避免从库同步延迟读不到刚写入的数据
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Sticky struct {
+    // 是否已写入
+    written int32
+}
+
+// 构造函数
+func NewSticky() *Sticky {
+    return &Sticky{}
+}
+
+// 标记已写入
+func (this *Sticky) MarkWritten() {
+    atomic.StoreInt32(&this.written, 1)
+}
+
+// 是否已写入
+func (this *Sticky) Written() bool {
+    return atomic.LoadInt32(&this.written) == 1
+}
+
+// 上下文添加写后读主库
+func WithSticky(ctx context.Context, sticky ...*Sticky) context.Context {
+    if len(sticky) > 0 && sticky[0] != nil {
+        return context.WithValue(ctx, stickyContextKey{}, sticky[0])
+    }
+
+    return context.WithValue(ctx, stickyContextKey{}, NewSticky())
+}
+
+// 从上下文获取
+func StickyFromContext(ctx context.Context) *Sticky {
+    if ctx == nil {
+        return nil
+    }
+
+    if sticky, ok := ctx.Value(stickyContextKey{}).(*Sticky); ok {
+        return sticky
+    }
+
+    if sticky, ok := ctx.Value(StickyKey).(*Sticky); ok {
+        return sticky
+    }
+
+    return nil
+}
+
+// 注册写后读主库回调，需在注册 dbresolver 之后调用
+func RegisterStickyCallbacks(db *gorm.DB) error {
+    var err error
+
+    markWritten := func(tx *gorm.DB) {
+        if tx.Error != nil {
+            return
+        }
+
+        if sticky := StickyFromContext(tx.Statement.Context); sticky != nil {
+            sticky.MarkWritten()
+        }
+    }
+
+    // 原生语句只有非查询语句算写入
+    markRawWritten := func(tx *gorm.DB) {
+        sql := strings.TrimSpace(tx.Statement.SQL.String())
+        if len(sql) >= 6 && strings.EqualFold(sql[:6], "select") {
+            return
+        }
+
+        markWritten(tx)
+    }
+
+    // 已写入时查询使用主库，会重新触发 dbresolver 选择连接
+    useSource := func(tx *gorm.DB) {
+        if sticky := StickyFromContext(tx.Statement.Context); sticky != nil && sticky.Written() {
+            dbresolver.Write.ModifyStatement(tx.Statement)
+        }
+    }
+
+    callback := db.Callback()
+
+    err = callback.Create().After("*").Register("lakego:sticky_written", markWritten)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Update().After("*").Register("lakego:sticky_written", markWritten)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Delete().After("*").Register("lakego:sticky_written", markWritten)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Raw().After("*").Register("lakego:sticky_written", markRawWritten)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Query().Before("gorm:query").Register("lakego:sticky_source", useSource)
+    if err != nil {
+        return err
+    }
+
+    err = callback.Row().Before("gorm:row").Register("lakego:sticky_source", useSource)
+    if err != nil {
+        return err
+    }
+
+    return callback.Raw().Before("gorm:raw").Register("lakego:sticky_source", useSource)
+}
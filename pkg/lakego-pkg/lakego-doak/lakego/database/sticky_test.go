@@ -0,0 +1,66 @@
+package database
+
+import (
+    "context"
+    "testing"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+    "gorm.io/plugin/dbresolver"
+)
+
+type stickyUser struct {
+    ID   uint
+    Name string
+}
+
+func Test_Sticky(t *testing.T) {
+    dir := t.TempDir()
+
+    db, err := gorm.Open(sqlite.Open(dir + "/source.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    replica, _ := gorm.Open(sqlite.Open(dir + "/replica.db"), &gorm.Config{})
+    replica.AutoMigrate(&stickyUser{})
+    db.AutoMigrate(&stickyUser{})
+
+    err = db.Use(dbresolver.Register(dbresolver.Config{
+        Replicas: []gorm.Dialector{sqlite.Open(dir + "/replica.db")},
+        Policy:   NewPolicy("round-robin"),
+    }))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = RegisterStickyCallbacks(db); err != nil {
+        t.Fatal(err)
+    }
+
+    var count int64
+
+    // 无上下文，写入后读从库
+    db.Create(&stickyUser{Name: "a"})
+    db.Model(&stickyUser{}).Count(&count)
+    if count != 0 {
+        t.Errorf("Failed read replica: actual: %d, expected: 0", count)
+    }
+
+    // 写入后读主库
+    ctx := WithSticky(context.Background())
+    db.WithContext(ctx).Model(&stickyUser{}).Count(&count)
+    if count != 0 {
+        t.Errorf("Failed read before write: actual: %d, expected: 0", count)
+    }
+
+    db.WithContext(ctx).Create(&stickyUser{Name: "b"})
+    db.WithContext(ctx).Model(&stickyUser{}).Count(&count)
+    if count != 2 {
+        t.Errorf("Failed sticky read: actual: %d, expected: 2", count)
+    }
+
+    if !StickyFromContext(ctx).Written() {
+        t.Error("Failed sticky written")
+    }
+}
@@ -3,13 +3,16 @@ package cache
 import (
     "strings"
 
+    "github.com/deatil/lakego-doak/lakego/path"
     "github.com/deatil/lakego-doak/lakego/array"
     "github.com/deatil/lakego-doak/lakego/register"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     "github.com/deatil/lakego-doak/lakego/facade/logger"
     "github.com/deatil/lakego-doak/lakego/cache"
     "github.com/deatil/lakego-doak/lakego/cache/interfaces"
+    fileDriver "github.com/deatil/lakego-doak/lakego/cache/driver/file"
     redisDriver "github.com/deatil/lakego-doak/lakego/cache/driver/redis"
+    memoryDriver "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
 )
 
 /**
@@ -89,28 +92,55 @@ func registerDriver() {
     // 注册缓存驱动
     register.
         NewManagerWithPrefix("cache").
-        Register("redis", func(conf map[string]any) any {
-            cfg := array.ArrayFrom(conf)
-
-            driver := redisDriver.New(redisDriver.Config{
-                DB:       cfg.Value("db").ToInt(),
-                Addr:     cfg.Value("addr").ToString(),
-                Password: cfg.Value("password").ToString(),
-
-                MinIdleConn:  cfg.Value("minidle-conn").ToInt(),
-                DialTimeout:  cfg.Value("dial-timeout").ToDuration(),
-                ReadTimeout:  cfg.Value("read-timeout").ToDuration(),
-                WriteTimeout: cfg.Value("write-timeout").ToDuration(),
-
-                PoolSize:     cfg.Value("pool-size").ToInt(),
-                PoolTimeout:  cfg.Value("pool-timeout").ToDuration(),
-
-                EnableTrace:  cfg.Value("enabletrace").ToBool(),
-
-                Logger: logger.New(),
-            })
-
-            return driver
+        RegisterMany(map[string]func(map[string]any) any {
+            "redis": func(conf map[string]any) any {
+                cfg := array.ArrayFrom(conf)
+
+                driver := redisDriver.New(redisDriver.Config{
+                    DB:       cfg.Value("db").ToInt(),
+                    Addr:     cfg.Value("addr").ToString(),
+                    Password: cfg.Value("password").ToString(),
+
+                    MinIdleConn:  cfg.Value("minidle-conn").ToInt(),
+                    DialTimeout:  cfg.Value("dial-timeout").ToDuration(),
+                    ReadTimeout:  cfg.Value("read-timeout").ToDuration(),
+                    WriteTimeout: cfg.Value("write-timeout").ToDuration(),
+
+                    PoolSize:     cfg.Value("pool-size").ToInt(),
+                    PoolTimeout:  cfg.Value("pool-timeout").ToDuration(),
+
+                    EnableTrace:  cfg.Value("enabletrace").ToBool(),
+
+                    Logger: logger.New(),
+                })
+
+                return driver
+            },
+            "memory": func(conf map[string]any) any {
+                cfg := array.ArrayFrom(conf)
+
+                driver := memoryDriver.New(memoryDriver.Config{
+                    Shards:          cfg.Value("shards").ToInt(),
+                    MaxSize:         cfg.Value("max-size").ToInt(),
+                    CleanupInterval: cfg.Value("cleanup-interval").ToDuration(),
+                })
+
+                return driver
+            },
+            "file": func(conf map[string]any) any {
+                cfg := array.ArrayFrom(conf)
+
+                cachePath := cfg.Value("path").ToString()
+                if cachePath == "" {
+                    cachePath = "{runtime}/cache"
+                }
+
+                driver := fileDriver.New(fileDriver.Config{
+                    Path: path.FormatPath(cachePath),
+                })
+
+                return driver
+            },
         })
 }
 
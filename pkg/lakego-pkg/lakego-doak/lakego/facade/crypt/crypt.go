@@ -0,0 +1,43 @@
+package crypt
+
+import (
+    "encoding/base64"
+
+    "github.com/deatil/lakego-doak/lakego/crypt"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+)
+
+/**
+ * 加密，使用应用密钥
+ *
+ * encrypted, err := crypt.Encrypt("data")
+ * data, err := crypt.Decrypt(encrypted)
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+// 实例化
+func New() *crypt.Crypt {
+    return crypt.New(AppKey())
+}
+
+// 应用密钥，配置为 base64 编码
+func AppKey() []byte {
+    key, err := base64.StdEncoding.DecodeString(config.New("server").GetString("app-key"))
+    if err != nil {
+        return nil
+    }
+
+    return key
+}
+
+// 加密
+func Encrypt(data string) (string, error) {
+    return New().Encrypt(data)
+}
+
+// 解密
+func Decrypt(data string) (string, error) {
+    return New().Decrypt(data)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+    "sync"
     "strings"
     "gorm.io/gorm"
 
@@ -9,11 +10,20 @@ import (
     "github.com/deatil/lakego-doak/lakego/database"
     "github.com/deatil/lakego-doak/lakego/database/interfaces"
     mysqlDriver "github.com/deatil/lakego-doak/lakego/database/driver/mysql"
+    sqliteDriver "github.com/deatil/lakego-doak/lakego/database/driver/sqlite"
+    postgresDriver "github.com/deatil/lakego-doak/lakego/database/driver/postgres"
+    sqlserverDriver "github.com/deatil/lakego-doak/lakego/database/driver/sqlserver"
 )
 
 // 默认
 var Default *gorm.DB
 
+// 已创建的连接
+var connections = make(map[string]*gorm.DB)
+
+// 连接锁
+var connectionsMu sync.Mutex
+
 // 初始化
 func init() {
     // 注册默认
@@ -47,8 +57,34 @@ func NewWithType(database string, once ...bool) *gorm.DB {
     return Database(database, once...)
 }
 
+// 获取命名连接，同名连接只创建一次
+func Connection(name string) *gorm.DB {
+    return Database(name, true)
+}
+
 // 选择数据库
 func Database(name string, once ...bool) *gorm.DB {
+    if len(once) == 0 || !once[0] {
+        return newConnection(name)
+    }
+
+    name = strings.ToLower(name)
+
+    connectionsMu.Lock()
+    defer connectionsMu.Unlock()
+
+    if db, ok := connections[name]; ok {
+        return db
+    }
+
+    db := newConnection(name)
+    connections[name] = db
+
+    return db
+}
+
+// 创建连接
+func newConnection(name string) *gorm.DB {
     // 使用驱动类型
     driverType, driverConf := GetConfig("type", name)
 
@@ -57,7 +93,7 @@ func Database(name string, once ...bool) *gorm.DB {
     // 驱动
     driver := register.
         NewManagerWithPrefix("database").
-        GetRegister(newDriverType, driverConf, once...)
+        GetRegister(newDriverType, driverConf)
     if driver == nil {
         panic("数据库驱动[" + newDriverType + "]没有被注册")
     }
@@ -117,6 +153,21 @@ func registerDB() {
             "mysql": func(conf map[string]any) any {
                 driver := mysqlDriver.New(conf)
 
+                return driver
+            },
+            "postgres": func(conf map[string]any) any {
+                driver := postgresDriver.New(conf)
+
+                return driver
+            },
+            "sqlite": func(conf map[string]any) any {
+                driver := sqliteDriver.New(conf)
+
+                return driver
+            },
+            "sqlserver": func(conf map[string]any) any {
+                driver := sqlserverDriver.New(conf)
+
                 return driver
             },
         })
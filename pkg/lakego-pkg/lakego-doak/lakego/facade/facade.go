@@ -3,6 +3,7 @@ package facade
 import (
     "gorm.io/gorm"
     "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/queue"
     "github.com/deatil/lakego-doak/lakego/config"
     "github.com/deatil/lakego-doak/lakego/logger"
     "github.com/deatil/lakego-doak/lakego/upload"
@@ -11,6 +12,7 @@ import (
     "github.com/deatil/lakego-doak/lakego/permission"
 
     facade_cache "github.com/deatil/lakego-doak/lakego/facade/cache"
+    facade_queue "github.com/deatil/lakego-doak/lakego/facade/queue"
     facade_logger "github.com/deatil/lakego-doak/lakego/facade/logger"
     facade_upload "github.com/deatil/lakego-doak/lakego/facade/upload"
     facade_storage "github.com/deatil/lakego-doak/lakego/facade/storage"
@@ -23,12 +25,18 @@ import (
 // 数据库
 var DB *gorm.DB
 
+// 命名数据库连接
+var DBConnection func(string) *gorm.DB
+
 // 缓存
 var Cache *cache.Cache
 
 // 日志
 var Logger *logger.Logger
 
+// 队列
+var Queue *queue.Queue
+
 // 上传
 var Upload *upload.Upload
 
@@ -49,12 +57,18 @@ func init() {
     // 数据库
     DB = facade_database.Default
 
+    // 命名数据库连接
+    DBConnection = facade_database.Connection
+
     // 缓存
     Cache = facade_cache.Default
 
     // 日志
     Logger = facade_logger.Default
 
+    // 队列
+    Queue = facade_queue.Default
+
     // 上传
     Upload = facade_upload.Default
 
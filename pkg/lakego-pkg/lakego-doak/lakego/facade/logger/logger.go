@@ -2,8 +2,11 @@ package logger
 
 import (
     "log"
+    "sync"
+    "context"
     "strings"
 
+    "github.com/deatil/lakego-doak/lakego/array"
     "github.com/deatil/lakego-doak/lakego/register"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     "github.com/deatil/lakego-doak/lakego/logger"
@@ -14,13 +17,19 @@ import (
 // 默认
 var Default *logger.Logger
 
+// 通道缓存
+var (
+    channels   = make(map[string]*logger.Logger)
+    channelsMu sync.Mutex
+)
+
 // 初始化
 func init() {
     // 注册默认
     registerDriver()
 
     // 默认
-    Default = New()
+    Default = Channel(GetDefaultDriver())
 }
 
 /**
@@ -36,8 +45,44 @@ func New(once ...bool) *logger.Logger {
     return NewLogger(driver, once...)
 }
 
-// 验证码
+// 获取通道，同名通道只创建一次
+func Channel(name string) *logger.Logger {
+    return NewLogger(name, true)
+}
+
+// 日志通道
 func NewLogger(driverName string, once ...bool) *logger.Logger {
+    if len(once) == 0 || !once[0] {
+        return newLogger(driverName)
+    }
+
+    driverName = strings.ToLower(driverName)
+
+    channelsMu.Lock()
+    l, ok := channels[driverName]
+    channelsMu.Unlock()
+
+    if ok {
+        return l
+    }
+
+    // 多通道日志创建时会获取其他通道，创建时不加锁
+    l = newLogger(driverName)
+
+    channelsMu.Lock()
+    defer channelsMu.Unlock()
+
+    if exists, ok := channels[driverName]; ok {
+        return exists
+    }
+
+    channels[driverName] = l
+
+    return l
+}
+
+// 创建日志
+func newLogger(driverName string) *logger.Logger {
     // 配置
     conf := config.New("logger")
 
@@ -59,7 +104,7 @@ func NewLogger(driverName string, once ...bool) *logger.Logger {
     driverType := driverConf["type"].(string)
     driver := register.
         NewManagerWithPrefix("logger").
-        GetRegister(driverType, driverConf, once...)
+        GetRegister(driverType, driverConf)
     if driver == nil {
         log.Print("日志驱动[" + driverType + "]没有被注册")
     }
@@ -79,6 +124,17 @@ func LogrusWithFields(log *logger.Logger, fields map[string]any) *logrusDriver.E
     return log.WithFields(fields).(*logrusDriver.Entry)
 }
 
+// 带上下文数据，包括请求 ID、管理员 ID 和路由别名
+// logger.WithContext(ctx).Info("logger test")
+func WithContext(ctx context.Context) *logrusDriver.Entry {
+    return LogrusWithContext(Default, ctx)
+}
+
+// 带上下文数据
+func LogrusWithContext(log *logger.Logger, ctx context.Context) *logrusDriver.Entry {
+    return log.WithContext(ctx).(*logrusDriver.Entry)
+}
+
 // 默认驱动
 func GetDefaultDriver() string {
     return config.New("logger").GetString("default")
@@ -98,5 +154,41 @@ func registerDriver() {
 
                 return driver
             },
+
+            // 多通道日志
+            "stack": func(conf map[string]any) any {
+                names := array.ArrayFrom(conf).Value("channels").ToStringSlice()
+
+                loggers := make([]*logrusDriver.Logger, 0, len(names))
+                for _, name := range names {
+                    // 不支持嵌套
+                    if getChannelType(name) == "stack" {
+                        log.Print("日志通道[" + name + "]不能嵌套多通道日志")
+                        continue
+                    }
+
+                    if channel, ok := Channel(name).GetDriver().(*logrusDriver.Logrus); ok {
+                        loggers = append(loggers, channel.GetLogger())
+                    }
+                }
+
+                driver := logrusDriver.New()
+
+                driver.WithConfig(conf)
+                driver.WithLogger(logrusDriver.NewStackLogger(loggers...))
+
+                return driver
+            },
         })
 }
+
+// 通道类型
+func getChannelType(name string) string {
+    drivers := config.New("logger").GetStringMap("drivers")
+
+    if conf, ok := drivers[strings.ToLower(name)].(map[string]any); ok {
+        return array.ArrayFrom(conf).Value("type").ToString()
+    }
+
+    return ""
+}
@@ -0,0 +1,239 @@
+package queue
+
+import (
+    "sync"
+    "strings"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/queue"
+    "github.com/deatil/lakego-doak/lakego/register"
+    "github.com/deatil/lakego-doak/lakego/redis"
+    "github.com/deatil/lakego-doak/lakego/migration"
+    "github.com/deatil/lakego-doak/lakego/facade/config"
+    "github.com/deatil/lakego-doak/lakego/facade/database"
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+    redisDriver "github.com/deatil/lakego-doak/lakego/queue/driver/redis"
+    memoryDriver "github.com/deatil/lakego-doak/lakego/queue/driver/memory"
+    databaseDriver "github.com/deatil/lakego-doak/lakego/queue/driver/database"
+)
+
+/**
+ * 队列
+ *
+ * queue.Default.Dispatch("send-mail", data, queue.OnQueue("emails"))
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+// 默认
+var Default *queue.Queue
+
+// 没有队列配置时使用的默认连接
+const fallbackConnection = "memory"
+
+// 连接缓存
+var (
+    connections   = make(map[string]*queue.Queue)
+    connectionsMu sync.Mutex
+)
+
+// 初始化
+func init() {
+    // 注册默认
+    registerDriver()
+
+    // 默认
+    Default = New()
+}
+
+// 默认连接
+func New() *queue.Queue {
+    return Connection(GetDefaultConnection())
+}
+
+// 获取连接，同名连接只创建一次
+func Connection(name string) *queue.Queue {
+    name = strings.ToLower(name)
+
+    connectionsMu.Lock()
+    defer connectionsMu.Unlock()
+
+    if q, ok := connections[name]; ok {
+        return q
+    }
+
+    q := newConnection(name)
+    connections[name] = q
+
+    return q
+}
+
+// 创建连接
+func newConnection(name string) *queue.Queue {
+    conf := config.New("queue")
+
+    connections := conf.GetStringMap("connections")
+
+    // 没有队列配置文件时使用内存队列，避免只引入门面时 panic
+    if len(connections) == 0 && name == fallbackConnection {
+        return queue.New(name, memoryDriver.New())
+    }
+
+    cfg := array.ArrayFrom(connections)
+    if !cfg.Has(name) {
+        panic("队列连接[" + name + "]配置不存在")
+    }
+
+    driverConf := cfg.Value(name).ToStringMap()
+    driverType := cfg.Value(name + ".type").ToString()
+
+    driver := register.
+        NewManagerWithPrefix("queue").
+        GetRegister(driverType, driverConf)
+    if driver == nil {
+        panic("队列驱动[" + driverType + "]没有被注册")
+    }
+
+    q := queue.New(name, driver.(interfaces.Driver))
+
+    // 失败任务
+    if failed := conf.GetStringMap("failed"); len(failed) > 0 {
+        failedConf := array.ArrayFrom(failed)
+        q.WithFailed(queue.NewDatabaseFailedProvider(
+            getDB(failedConf.Value("connection").ToString()),
+            failedConf.Value("table").ToString(),
+        ))
+    }
+
+    return q
+}
+
+// 默认连接，没有配置时使用内存队列
+func GetDefaultConnection() string {
+    name := config.New("queue").GetString("default")
+    if name == "" {
+        name = fallbackConnection
+    }
+
+    return name
+}
+
+// 数据库队列和失败任务的建表迁移
+func Migrations() []migration.Migration {
+    conf := config.New("queue")
+
+    migrations := make([]migration.Migration, 0)
+
+    cfg := array.ArrayFrom(conf.GetStringMap("connections"))
+    for name := range conf.GetStringMap("connections") {
+        if cfg.Value(name + ".type").ToString() != "database" {
+            continue
+        }
+
+        driver := databaseDriver.New(
+            getDB(cfg.Value(name + ".connection").ToString()),
+            cfg.Value(name + ".table").ToString(),
+            0,
+        )
+
+        migrations = append(migrations, driver.Migration())
+    }
+
+    if failed := conf.GetStringMap("failed"); len(failed) > 0 {
+        failedConf := array.ArrayFrom(failed)
+
+        provider := queue.NewDatabaseFailedProvider(
+            getDB(failedConf.Value("connection").ToString()),
+            failedConf.Value("table").ToString(),
+        )
+
+        migrations = append(migrations, provider.Migration())
+    }
+
+    return migrations
+}
+
+// 数据库连接
+func getDB(name string) *gorm.DB {
+    if name == "" {
+        return database.New()
+    }
+
+    return database.Connection(name)
+}
+
+// redis 连接，为空时使用默认连接
+// 不使用 redis 门面，避免没有使用 redis 队列时也连接 redis
+func newRedis(connect string) redis.Redis {
+    conf := config.New("redis")
+
+    if connect == "" {
+        connect = conf.GetString("default")
+    }
+
+    connects := conf.GetStringMap("connects")
+
+    connectConf, ok := connects[connect]
+    if !ok {
+        panic("redis连接配置 [" + connect + "] 不存在")
+    }
+
+    cfg := array.ArrayFrom(connectConf)
+
+    return redis.New(redis.Config{
+        DB:       cfg.Value("db").ToInt(),
+        Addr:     cfg.Value("addr").ToString(),
+        Password: cfg.Value("password").ToString(),
+
+        MinIdleConn:  cfg.Value("minidle-conn").ToInt(),
+        DialTimeout:  cfg.Value("dial-timeout").ToDuration(),
+        ReadTimeout:  cfg.Value("read-timeout").ToDuration(),
+        WriteTimeout: cfg.Value("write-timeout").ToDuration(),
+
+        PoolSize:     cfg.Value("pool-size").ToInt(),
+        PoolTimeout:  cfg.Value("pool-timeout").ToDuration(),
+
+        EnableTrace:  cfg.Value("enabletrace").ToBool(),
+
+        KeyPrefix:    cfg.Value("key-prefix").ToString(),
+    })
+}
+
+// 注册
+func registerDriver() {
+    register.
+        NewManagerWithPrefix("queue").
+        RegisterMany(map[string]func(map[string]any) any {
+            // 内存队列
+            "memory": func(conf map[string]any) any {
+                return memoryDriver.New()
+            },
+
+            // 数据库队列
+            "database": func(conf map[string]any) any {
+                cfg := array.ArrayFrom(conf)
+
+                return databaseDriver.New(
+                    getDB(cfg.Value("connection").ToString()),
+                    cfg.Value("table").ToString(),
+                    cfg.Value("retry-after").ToDuration(),
+                )
+            },
+
+            // redis 队列
+            "redis": func(conf map[string]any) any {
+                cfg := array.ArrayFrom(conf)
+
+                client := newRedis(cfg.Value("connection").ToString()).GetClient()
+
+                return redisDriver.New(
+                    client,
+                    cfg.Value("prefix").ToString(),
+                    cfg.Value("retry-after").ToDuration(),
+                )
+            },
+        })
+}
@@ -57,8 +57,71 @@ func New() *upload.Upload {
         WithFileinfo(fileinfo).
         WithOpenFileinfo(openFileinfo).
         WithRename(rename).
+        WithValidators(NewValidators()).
         WithDir(uploadDir)
 
     return up
 }
 
+/**
+ * 上传校验链
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func NewValidators() *upload.Validators {
+    conf := config.New("admin")
+
+    validators := upload.NewValidators()
+
+    // 文件大小
+    if maxSize := conf.GetInt64("Upload.Validate.max-size"); maxSize > 0 {
+        validators.Add(upload.MaxSizeValidator(maxSize))
+    }
+
+    // 后缀和文件头一致
+    if conf.GetBool("Upload.Validate.match-mime") {
+        validators.Add(upload.MatchMimeValidator())
+    }
+
+    // 文件格式
+    if mimes := conf.GetStringSlice("Upload.Validate.Mimes"); len(mimes) > 0 {
+        validators.Add(upload.MimeValidator(mimes...))
+    }
+
+    // 图片尺寸
+    validators.Add(upload.ImageSizeValidator(
+        conf.GetInt("Upload.Validate.Image.min-width"),
+        conf.GetInt("Upload.Validate.Image.min-height"),
+        conf.GetInt("Upload.Validate.Image.max-width"),
+        conf.GetInt("Upload.Validate.Image.max-height"),
+    ))
+
+    return validators
+}
+
+
+/**
+ * 分片上传
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func NewChunk() *upload.Chunk {
+    conf := config.New("admin")
+
+    // 文件系统，分片临时文件不放在公开磁盘
+    chunkDisk := conf.GetString("Upload.Chunk.Disk")
+    if chunkDisk == "" {
+        chunkDisk = "local"
+    }
+
+    useStorage := storage.NewWithDisk(chunkDisk)
+
+    // 临时目录
+    chunkDir := conf.GetString("Upload.Chunk.Directory")
+
+    return upload.NewChunk(useStorage, chunkDir).
+        WithMaxSize(conf.GetInt64("Upload.Validate.max-size")).
+        WithMaxChunkSize(conf.GetInt64("Upload.Chunk.max-chunk-size"))
+}
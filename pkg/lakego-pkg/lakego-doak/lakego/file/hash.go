@@ -7,7 +7,9 @@ import (
     "bufio"
     "errors"
     "crypto/md5"
+    "hash"
     "crypto/sha1"
+    "crypto/sha256"
 )
 
 // stream 使用接口
@@ -138,3 +140,83 @@ func Sha1ForBigWithStream(openfile File) (string, error) {
     checksum := fmt.Sprintf("%x", hash.Sum(nil))
     return checksum, nil
 }
+
+// 文件 Sha256
+func Sha256(filename string) (string, error) {
+    openfile, err := os.Open(filename)
+    if err != nil {
+        return "", err
+    }
+    defer openfile.Close()
+
+    return Sha256WithStream(openfile)
+}
+
+// 文件 Sha256
+func Sha256WithStream(openfile File) (string, error) {
+    hash := sha256.New()
+    _, err := io.Copy(hash, openfile)
+    if nil != err {
+        return "", err
+    }
+
+    sum := hash.Sum(nil)
+
+    return fmt.Sprintf("%x", sum), nil
+}
+
+// 读取时计算摘要
+func NewHashReader(reader File) *HashReader {
+    hr := &HashReader{
+        md5:    md5.New(),
+        sha1:   sha1.New(),
+        sha256: sha256.New(),
+    }
+
+    hr.reader = io.TeeReader(reader, io.MultiWriter(hr.md5, hr.sha1, hr.sha256))
+
+    return hr
+}
+
+/**
+ * 读取时计算摘要，只需读取一次文件
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type HashReader struct {
+    reader io.Reader
+    size   int64
+
+    md5    hash.Hash
+    sha1   hash.Hash
+    sha256 hash.Hash
+}
+
+// 读取
+func (this *HashReader) Read(p []byte) (int, error) {
+    n, err := this.reader.Read(p)
+    this.size += int64(n)
+
+    return n, err
+}
+
+// 已读取大小
+func (this *HashReader) Size() int64 {
+    return this.size
+}
+
+// Md5
+func (this *HashReader) Md5() string {
+    return fmt.Sprintf("%x", this.md5.Sum(nil))
+}
+
+// Sha1
+func (this *HashReader) Sha1() string {
+    return fmt.Sprintf("%x", this.sha1.Sum(nil))
+}
+
+// Sha256
+func (this *HashReader) Sha256() string {
+    return fmt.Sprintf("%x", this.sha256.Sum(nil))
+}
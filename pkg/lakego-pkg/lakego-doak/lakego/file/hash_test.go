@@ -0,0 +1,36 @@
+package file
+
+import (
+    "io"
+    "testing"
+    "reflect"
+    "strings"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_HashReader(t *testing.T) {
+    eq := assertT(t)
+
+    hr := NewHashReader(strings.NewReader("lakego"))
+
+    data, err := io.ReadAll(hr)
+    eq(err, nil, "ReadAll")
+    eq(string(data), "lakego", "ReadAll data")
+    eq(hr.Size(), int64(6), "Size")
+
+    md5, _ := Md5WithStream(strings.NewReader("lakego"))
+    sha1, _ := Sha1WithStream(strings.NewReader("lakego"))
+    sha256, _ := Sha256WithStream(strings.NewReader("lakego"))
+
+    eq(hr.Md5(), md5, "Md5")
+    eq(hr.Sha1(), sha1, "Sha1")
+    eq(hr.Sha256(), sha256, "Sha256")
+    eq(len(sha256), 64, "Sha256 len")
+}
@@ -2,6 +2,7 @@ package file
 
 import (
     "reflect"
+    "runtime"
 )
 
 // 结构体路径
@@ -20,10 +21,10 @@ func PackageName(v any) string {
 
 // 获取结构体名称
 func StructName(name any) string {
-    t := reflect.ValueOf(f).Type()
+    t := reflect.ValueOf(name).Type()
 
     if t.Kind() == reflect.Func {
-        return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+        return runtime.FuncForPC(reflect.ValueOf(name).Pointer()).Name()
     }
 
     return t.String()
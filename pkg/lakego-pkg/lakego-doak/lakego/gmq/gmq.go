@@ -1,9 +1,21 @@
 package gmq
 
 import (
+    "log"
+    "sync"
     "errors"
+    "runtime/debug"
 )
 
+// 默认缓冲数量
+const DefaultBufferSize = 1024
+
+// 已关闭
+var ErrClosed = errors.New("GMQ is closed")
+
+// 未运行且缓冲已满
+var ErrNotRunning = errors.New("GMQ is not running yet")
+
 // 内容
 type Payload struct {
     // 主题
@@ -17,86 +29,174 @@ type Payload struct {
 type Handler func(value any)
 
 // 消息中间件
+// 进程内消息，需要持久化和重试时使用 lakego/queue
 type GMQ struct {
+    // 锁
+    mu sync.RWMutex
+
     // 载荷
     payload chan Payload
 
     // 退出
-    quit chan bool
+    quit chan struct{}
+
+    // 退出完成
+    done chan struct{}
 
     // 列表
     handles map[string][]Handler
 
+    // 执行中
+    wg sync.WaitGroup
+
     // 是否运行
     running bool
+
+    // 是否关闭
+    closed bool
+
+    // 关闭一次
+    closeOnce sync.Once
 }
 
-// 发布
+// 发布，未运行时先写入缓冲，缓冲已满时返回错误
 func (this *GMQ) Publish(topic string, data any) error {
-    if !this.running {
-        return errors.New("GMQ is not running yet")
+    this.mu.RLock()
+    closed, running := this.closed, this.running
+    this.mu.RUnlock()
+
+    if closed {
+        return ErrClosed
     }
 
-    this.payload <- Payload{topic, data}
+    v := Payload{topic, data}
 
-    return nil
+    select {
+        case this.payload <- v:
+            return nil
+        default:
+    }
+
+    if !running {
+        return ErrNotRunning
+    }
+
+    // 缓冲已满时等待处理，关闭时退出
+    select {
+        case this.payload <- v:
+            return nil
+        case <-this.quit:
+            return ErrClosed
+    }
 }
 
 // 订阅
 func (this *GMQ) Subscribe(topic string, handler Handler) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
     if nil == this.handles {
         this.handles = make(map[string][]Handler)
     }
 
-    if nil == this.handles[topic] {
-        this.handles[topic] = []Handler{handler}
-    } else {
-        this.handles[topic] = append(this.handles[topic], handler)
-    }
+    this.handles[topic] = append(this.handles[topic], handler)
 }
 
 // 处理业务
-func (this *GMQ) handle(value any, handlers []Handler) {
+func (this *GMQ) handle(v Payload, handlers []Handler) {
+    defer this.wg.Done()
+
     for _, handler := range handlers {
-        handler(value)
+        this.call(handler, v)
+    }
+}
+
+// 执行，防止单个处理异常导致退出
+func (this *GMQ) call(handler Handler, v Payload) {
+    defer func() {
+        if r := recover(); r != nil {
+            log.Printf("gmq: topic [%s] handler panic: %v\n%s", v.Topic, r, debug.Stack())
+        }
+    }()
+
+    handler(v.Value)
+}
+
+// 分发
+func (this *GMQ) dispatch(v Payload) {
+    this.mu.RLock()
+    handlers := this.handles[v.Topic]
+    this.mu.RUnlock()
+
+    if len(handlers) > 0 {
+        this.wg.Add(1)
+        go this.handle(v, handlers)
     }
 }
 
 // 运行
 func (this *GMQ) Run() {
+    this.mu.Lock()
     if this.running {
+        this.mu.Unlock()
         return
     }
 
     // 设置为运行
     this.running = true
+    this.mu.Unlock()
+
+    defer close(this.done)
 
     for {
         select {
             case v := <-this.payload:
-                if nil != this.handles[v.Topic] {
-                    go this.handle(v.Value, this.handles[v.Topic])
-                }
-            case v := <-this.quit:
-                if v {
-                    close(this.payload)
-                    close(this.quit)
-                    break
+                this.dispatch(v)
+            case <-this.quit:
+                // 处理剩余消息
+                for {
+                    select {
+                        case v := <-this.payload:
+                            this.dispatch(v)
+                        default:
+                            this.wg.Wait()
+                            return
+                    }
                 }
         }
     }
 }
 
-// 关闭
-func (this *GMQ) Close()  {
-    this.quit <- true
+// 关闭，等待已发布的消息处理完成
+func (this *GMQ) Close() {
+    this.closeOnce.Do(func() {
+        this.mu.Lock()
+        this.closed = true
+        running := this.running
+        this.mu.Unlock()
+
+        close(this.quit)
+
+        // 未运行时直接处理剩余消息
+        if !running {
+            this.Run()
+        }
+
+        <-this.done
+    })
 }
 
 // 新建GMQ
-func NewGMQ() *GMQ {
+func NewGMQ(size ...int) *GMQ {
+    bufferSize := DefaultBufferSize
+    if len(size) > 0 && size[0] >= 0 {
+        bufferSize = size[0]
+    }
+
     return &GMQ{
-        payload: make(chan Payload),
-        quit:    make(chan bool),
+        payload: make(chan Payload, bufferSize),
+        quit:    make(chan struct{}),
+        done:    make(chan struct{}),
         handles: make(map[string][]Handler),
     }
 }
@@ -0,0 +1,93 @@
+package gmq
+
+import (
+    "sync/atomic"
+    "testing"
+)
+
+func Test_GMQ(t *testing.T) {
+    mq := NewGMQ()
+
+    var count int32
+    mq.Subscribe("test", func(value any) {
+        atomic.AddInt32(&count, int32(value.(int)))
+    })
+    mq.Subscribe("panic", func(value any) {
+        panic("panic")
+    })
+
+    // 运行前发布
+    if err := mq.Publish("test", 1); err != nil {
+        t.Fatal(err)
+    }
+
+    go mq.Run()
+
+    mq.Publish("panic", 1)
+    mq.Publish("test", 2)
+
+    mq.Close()
+
+    if atomic.LoadInt32(&count) != 3 {
+        t.Errorf("Failed GMQ: actual: %v, expected: %v", count, 3)
+    }
+
+    if err := mq.Publish("test", 1); err != ErrClosed {
+        t.Errorf("Failed Publish: actual: %v, expected: %v", err, ErrClosed)
+    }
+}
+
+func Test_Publish_NotRunning(t *testing.T) {
+    mq := NewGMQ(1)
+
+    if err := mq.Publish("test", 1); err != nil {
+        t.Fatal(err)
+    }
+
+    // 未运行时缓冲已满
+    if err := mq.Publish("test", 2); err != ErrNotRunning {
+        t.Errorf("Failed Publish: actual: %v, expected: %v", err, ErrNotRunning)
+    }
+
+    mq.Close()
+}
+
+func Test_Publish_Blocked(t *testing.T) {
+    mq := NewGMQ(1)
+
+    release := make(chan struct{})
+    started := make(chan struct{}, 1)
+
+    var count int32
+    mq.Subscribe("test", func(value any) {
+        started <- struct{}{}
+        <-release
+        atomic.AddInt32(&count, 1)
+    })
+
+    go mq.Run()
+
+    // 订阅和关闭不会被阻塞中的发布卡住
+    mq.Publish("test", 1)
+    <-started
+
+    mq.Publish("test", 2)
+
+    errs := make(chan error, 1)
+    go func() {
+        errs <- mq.Publish("test", 3)
+    }()
+
+    mq.Subscribe("other", func(value any) {})
+
+    close(release)
+    mq.Close()
+
+    if err := <-errs; err != nil && err != ErrClosed {
+        t.Errorf("Failed Publish blocked: %v", err)
+    }
+
+    if atomic.LoadInt32(&count) < 2 {
+        t.Errorf("Failed Publish blocked count: actual: %v, expected: >= %v", count, 2)
+    }
+}
@@ -0,0 +1,142 @@
+package limiter
+
+import (
+    "time"
+    "strconv"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+)
+
+// 构造函数
+func New(c *cache.Cache) *Limiter {
+    return &Limiter{
+        cache: c,
+        now:   time.Now,
+    }
+}
+
+/**
+ * 滑动窗口限流
+ *
+ * 按窗口计数，当前次数为当前窗口次数加上
+ * 上一窗口次数按剩余时间比例折算
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Limiter struct {
+    // 缓存
+    cache *cache.Cache
+
+    // 当前时间
+    now func() time.Time
+}
+
+// 设置当前时间
+func (this *Limiter) WithNow(now func() time.Time) *Limiter {
+    this.now = now
+
+    return this
+}
+
+// 记录一次，返回当前次数
+func (this *Limiter) Hit(key string, window time.Duration) int64 {
+    window = formatWindow(window)
+
+    current := this.windowIndex(window)
+    windowKey := this.windowKey(key, current)
+
+    // 保留两个窗口用于折算
+    ttl := int64(window / time.Second) * 2
+
+    this.cache.Add(windowKey, 0, ttl)
+    this.cache.Increment(windowKey)
+
+    return this.Attempts(key, window)
+}
+
+// 当前次数
+func (this *Limiter) Attempts(key string, window time.Duration) int64 {
+    window = formatWindow(window)
+
+    now := this.now()
+    current := this.windowIndex(window)
+
+    currentCount := this.count(this.windowKey(key, current))
+    previousCount := this.count(this.windowKey(key, current - 1))
+
+    // 上一窗口剩余比例
+    elapsed := now.UnixNano() % int64(window)
+    weight := float64(int64(window) - elapsed) / float64(window)
+
+    return currentCount + int64(float64(previousCount) * weight)
+}
+
+// 是否超出限制
+func (this *Limiter) TooManyAttempts(key string, max int64, window time.Duration) bool {
+    return this.Attempts(key, window) >= max
+}
+
+// 剩余次数
+func (this *Limiter) Remaining(key string, max int64, window time.Duration) int64 {
+    remaining := max - this.Attempts(key, window)
+    if remaining < 0 {
+        remaining = 0
+    }
+
+    return remaining
+}
+
+// 距离当前窗口结束的时间
+func (this *Limiter) AvailableIn(window time.Duration) time.Duration {
+    window = formatWindow(window)
+
+    elapsed := this.now().UnixNano() % int64(window)
+
+    return window - time.Duration(elapsed)
+}
+
+// 清除记录
+func (this *Limiter) Clear(key string, window time.Duration) {
+    window = formatWindow(window)
+
+    current := this.windowIndex(window)
+
+    this.cache.Forget(this.windowKey(key, current))
+    this.cache.Forget(this.windowKey(key, current - 1))
+}
+
+// 窗口序号
+func (this *Limiter) windowIndex(window time.Duration) int64 {
+    return this.now().UnixNano() / int64(window)
+}
+
+// 窗口缓存 key
+func (this *Limiter) windowKey(key string, index int64) string {
+    return "limiter:" + key + ":" + strconv.FormatInt(index, 10)
+}
+
+// 窗口次数
+func (this *Limiter) count(key string) int64 {
+    if !this.cache.Has(key) {
+        return 0
+    }
+
+    data, err := this.cache.Get(key)
+    if err != nil {
+        return 0
+    }
+
+    return goch.ToInt64(data)
+}
+
+// 窗口最小为 1 秒
+func formatWindow(window time.Duration) time.Duration {
+    if window < time.Second {
+        window = time.Second
+    }
+
+    return window.Truncate(time.Second)
+}
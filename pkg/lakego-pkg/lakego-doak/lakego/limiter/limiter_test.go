@@ -0,0 +1,91 @@
+package limiter
+
+import (
+    "time"
+    "testing"
+    "reflect"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_Limiter(t *testing.T) {
+    eq := assertT(t)
+
+    // 窗口开始时间 1699999980
+    now := time.Unix(1700000000, 0)
+
+    l := New(cache.New(memory.New(memory.Config{}))).
+        WithNow(func() time.Time {
+            return now
+        })
+
+    window := time.Minute
+
+    for i := 1; i <= 3; i++ {
+        eq(l.Hit("login", window), int64(i), "Hit")
+    }
+
+    eq(l.TooManyAttempts("login", 3, window), true, "TooManyAttempts")
+    eq(l.TooManyAttempts("login", 4, window), false, "TooManyAttempts 4")
+    eq(l.Remaining("login", 5, window), int64(2), "Remaining")
+    eq(l.Attempts("other", window), int64(0), "Attempts other")
+
+    // 下一窗口过去一半，上一窗口按一半折算
+    now = time.Unix(1699999980 + 90, 0)
+
+    eq(l.Attempts("login", window), int64(1), "Attempts sliding")
+    eq(l.Hit("login", window), int64(2), "Hit sliding")
+
+    eq(l.AvailableIn(window), 30 * time.Second, "AvailableIn")
+
+    l.Clear("login", window)
+    eq(l.Attempts("login", window), int64(0), "Clear")
+}
+
+func Test_Log(t *testing.T) {
+    eq := assertT(t)
+
+    now := time.Unix(1700000000, 0)
+
+    l := NewLog(cache.New(memory.New(memory.Config{}))).
+        WithNow(func() time.Time {
+            return now
+        })
+
+    window := time.Minute
+
+    eq(l.Hit("login", window), int64(1), "Hit 1")
+
+    now = now.Add(10 * time.Second)
+    eq(l.Hit("login", window), int64(2), "Hit 2")
+
+    now = now.Add(10 * time.Second)
+    eq(l.Hit("login", window), int64(3), "Hit 3")
+
+    eq(l.TooManyAttempts("login", 3, window), true, "TooManyAttempts")
+    eq(l.TooManyAttempts("login", 4, window), false, "TooManyAttempts 4")
+    eq(l.Attempts("other", window), int64(0), "Attempts other")
+
+    // 最早一次计数过期后可以继续
+    eq(l.AvailableIn("login", 3, window), 40 * time.Second, "AvailableIn")
+    eq(l.AvailableIn("login", 2, window), 50 * time.Second, "AvailableIn max 2")
+    eq(l.AvailableIn("login", 4, window), time.Duration(0), "AvailableIn not limited")
+
+    // 第一次过期
+    now = now.Add(40 * time.Second)
+    eq(l.Attempts("login", window), int64(2), "Attempts expired")
+    eq(l.AvailableIn("login", 3, window), time.Duration(0), "AvailableIn expired")
+    eq(l.Hit("login", window), int64(3), "Hit after expired")
+
+    l.Clear("login")
+    eq(l.Attempts("login", window), int64(0), "Clear")
+}
@@ -0,0 +1,138 @@
+package limiter
+
+import (
+    "time"
+    "strconv"
+    "strings"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+)
+
+// 滑动日志
+func NewLog(c *cache.Cache) *Log {
+    return &Log{
+        cache: c,
+        now:   time.Now,
+        wait:  3,
+    }
+}
+
+/**
+ * 滑动日志限流
+ *
+ * 记录窗口内每次的时间，次数准确，
+ * 可以算出最早一次计数过期的时间
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Log struct {
+    // 缓存
+    cache *cache.Cache
+
+    // 当前时间
+    now func() time.Time
+
+    // 获取锁等待秒数
+    wait int
+}
+
+// 设置当前时间
+func (this *Log) WithNow(now func() time.Time) *Log {
+    this.now = now
+
+    return this
+}
+
+// 记录一次，返回当前次数
+func (this *Log) Hit(key string, window time.Duration) int64 {
+    window = formatWindow(window)
+
+    var attempts int64
+
+    // 读取后写入，使用缓存锁保证原子性
+    ok, _ := this.cache.Lock(this.logKey(key), 5).Block(this.wait, func() {
+        hits := append(this.hits(key, window), this.now().UnixNano())
+
+        this.cache.Put(this.logKey(key), encodeHits(hits), int64(window / time.Second))
+
+        attempts = int64(len(hits))
+    })
+    if !ok {
+        return this.Attempts(key, window)
+    }
+
+    return attempts
+}
+
+// 当前次数
+func (this *Log) Attempts(key string, window time.Duration) int64 {
+    return int64(len(this.hits(key, formatWindow(window))))
+}
+
+// 是否超出限制
+func (this *Log) TooManyAttempts(key string, max int64, window time.Duration) bool {
+    return this.Attempts(key, window) >= max
+}
+
+// 次数降到 max 以下需要等待的时间，
+// 即最早一次仍在计数的记录过期的时间
+func (this *Log) AvailableIn(key string, max int64, window time.Duration) time.Duration {
+    window = formatWindow(window)
+
+    hits := this.hits(key, window)
+    if max <= 0 || int64(len(hits)) < max {
+        return 0
+    }
+
+    oldest := hits[int64(len(hits)) - max]
+
+    return time.Duration(oldest + int64(window) - this.now().UnixNano())
+}
+
+// 清除记录
+func (this *Log) Clear(key string) {
+    this.cache.Forget(this.logKey(key))
+}
+
+// 窗口内的记录，按时间排序
+func (this *Log) hits(key string, window time.Duration) []int64 {
+    logKey := this.logKey(key)
+    if !this.cache.Has(logKey) {
+        return nil
+    }
+
+    data, err := this.cache.Get(logKey)
+    if err != nil {
+        return nil
+    }
+
+    start := this.now().UnixNano() - int64(window)
+
+    hits := make([]int64, 0)
+    for _, item := range strings.Split(goch.ToString(data), ",") {
+        hit, err := strconv.ParseInt(item, 10, 64)
+        if err == nil && hit > start {
+            hits = append(hits, hit)
+        }
+    }
+
+    return hits
+}
+
+// 缓存 key
+func (this *Log) logKey(key string) string {
+    return "limiter:log:" + key
+}
+
+// 记录编码
+func encodeHits(hits []int64) string {
+    items := make([]string, 0, len(hits))
+    for _, hit := range hits {
+        items = append(items, strconv.FormatInt(hit, 10))
+    }
+
+    return strings.Join(items, ",")
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+    "context"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+)
+
+// 上下文数据名称
+const (
+    // 请求 ID
+    RequestIdKey = "request_id"
+
+    // 管理员 ID
+    AdminIdKey = "admin_id"
+
+    // 路由别名
+    RouteKey = "route"
+)
+
+// 获取上下文日志数据，包括请求 ID、管理员 ID 和路由别名
+func ContextFields(ctx context.Context) map[string]any {
+    fields := make(map[string]any)
+
+    if ctx == nil {
+        return fields
+    }
+
+    if c, ok := ctx.(*router.Context); ok {
+        if requestId := c.GetString(RequestIdKey); requestId != "" {
+            fields[RequestIdKey] = requestId
+        }
+
+        if adminId, ok := c.Get(AdminIdKey); ok {
+            fields[AdminIdKey] = adminId
+        }
+
+        if fullPath := c.FullPath(); fullPath != "" && c.Request != nil {
+            route := router.NewName().GetNameByPath(c.Request.Method, fullPath)
+            if route == "" {
+                route = fullPath
+            }
+
+            fields[RouteKey] = route
+        }
+
+        return fields
+    }
+
+    for _, key := range []string{RequestIdKey, AdminIdKey, RouteKey} {
+        if val := ctx.Value(key); val != nil {
+            fields[key] = val
+        }
+    }
+
+    return fields
+}
@@ -2,13 +2,12 @@ package logrus
 
 import (
     "fmt"
-    "time"
+    "sync"
     logger "log"
 
     "github.com/sirupsen/logrus"
-    "github.com/lestrrat/go-file-rotatelogs"
 
-    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/array"
     "github.com/deatil/lakego-doak/lakego/logger/driver/logrus/formatter"
 )
 
@@ -24,6 +23,9 @@ type (
     // Entry 别名
     Entry = logrus.Entry
 
+    // Logger 别名
+    Logger = logrus.Logger
+
     // 日志方法
     LogFunction = logrus.LogFunction
 )
@@ -37,11 +39,21 @@ type (
 type Logrus struct {
     // 配置
     Config map[string]any
+
+    // 锁
+    mu sync.RWMutex
+
+    // 日志
+    logger *logrus.Logger
 }
 
 // 设置配置
 func (this *Logrus) WithConfig(config map[string]any) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
     this.Config = config
+    this.logger = nil
 }
 
 // 批量设置自定义变量
@@ -215,10 +227,42 @@ func (this *Logrus) GetLevel() logrus.Level {
     return this.getLogger().GetLevel()
 }
 
-// 设置
+// 设置日志
+func (this *Logrus) WithLogger(log *logrus.Logger) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.logger = log
+}
+
+// 获取日志
+func (this *Logrus) GetLogger() *logrus.Logger {
+    return this.getLogger()
+}
+
+// 获取日志，同一配置只创建一次
 func (this *Logrus) getLogger() *logrus.Logger {
-    // 配置
-    conf := this.Config
+    this.mu.RLock()
+    log := this.logger
+    this.mu.RUnlock()
+
+    if log != nil {
+        return log
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.logger == nil {
+        this.logger = NewLogger(this.Config)
+    }
+
+    return this.logger
+}
+
+// 根据配置创建日志
+func NewLogger(conf map[string]any) *logrus.Logger {
+    cfg := array.ArrayFrom(conf)
 
     log := logrus.New()
 
@@ -226,7 +270,7 @@ func (this *Logrus) getLogger() *logrus.Logger {
 
     var useFormatter logrus.Formatter
 
-    formatterType := conf["formatter"].(string)
+    formatterType := cfg.Value("formatter").ToString()
     switch formatterType {
         case "json":
             // json 格式
@@ -244,65 +288,48 @@ func (this *Logrus) getLogger() *logrus.Logger {
     // 设置输出样式
     log.SetFormatter(useFormatter)
 
-    // 日志目录
-    filepath := conf["filepath"].(string)
-
-    // 日志文件
-    // log_%Y%m%d.log
-    logPath := path.FormatPath(filepath)
-
-    maxAge := time.Duration(int64(conf["max-age"].(int)))
-    rotationTime := time.Duration(int64(conf["rotation-time"].(int)))
-
-    writer, err := rotatelogs.New(
-        logPath,
-        // rotatelogs.WithLinkName(baseLogPath), // 生成软链，指向最新日志文件
-        rotatelogs.WithMaxAge(maxAge * time.Hour), // 文件最大保存时间
-        rotatelogs.WithRotationTime(rotationTime * time.Hour), // 日志切割时间间隔
-    )
+    // 设置输出
+    writer, err := NewWriter(conf)
     if err != nil {
         logger.Print(fmt.Sprintf("日志配置错误：%v", err))
+    } else {
+        log.SetOutput(writer)
     }
 
-    // os.Stdout || os.Stderr
-    // 设置output,默认为stderr,可以为任何io.Writer，比如文件*os.File
-    // file, _ := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-    log.SetOutput(writer)
+    // 设置最低 loglevel
+    log.SetLevel(ParseLevel(cfg.Value("level").ToString()))
 
-    // 日志等级
-    level := conf["level"].(string)
+    return log
+}
 
-    // 设置最低 loglevel
+// 日志等级，默认为 trace
+func ParseLevel(level string) logrus.Level {
     switch level {
         case "panic":
             // panic 等级
-            log.SetLevel(logrus.PanicLevel)
+            return logrus.PanicLevel
 
         case "fatal":
             // fatal 等级
-            log.SetLevel(logrus.FatalLevel)
+            return logrus.FatalLevel
 
         case "error":
             // error 等级
-            log.SetLevel(logrus.ErrorLevel)
+            return logrus.ErrorLevel
 
-        case "warning":
+        case "warning", "warn":
             // warning 等级
-            log.SetLevel(logrus.WarnLevel)
+            return logrus.WarnLevel
 
         case "info":
             // info 等级
-            log.SetLevel(logrus.InfoLevel)
+            return logrus.InfoLevel
 
         case "debug":
             // debug 等级
-            log.SetLevel(logrus.DebugLevel)
-
-        case "trace":
-            // trace 等级
-            log.SetLevel(logrus.TraceLevel)
+            return logrus.DebugLevel
     }
 
-    return log
+    // trace 等级
+    return logrus.TraceLevel
 }
-
@@ -0,0 +1,76 @@
+package logrus
+
+import (
+    "io"
+    "sync"
+
+    "github.com/sirupsen/logrus"
+)
+
+// 创建多通道日志，日志会按各通道的等级、格式和输出分别写入
+func NewStackLogger(loggers ...*logrus.Logger) *logrus.Logger {
+    log := logrus.New()
+
+    log.SetReportCaller(true)
+    log.SetOutput(io.Discard)
+
+    level := logrus.PanicLevel
+    for _, l := range loggers {
+        if l == nil {
+            continue
+        }
+
+        if l.GetLevel() > level {
+            level = l.GetLevel()
+        }
+
+        log.AddHook(&stackHook{
+            logger: l,
+        })
+    }
+
+    log.SetLevel(level)
+
+    return log
+}
+
+/**
+ * 多通道日志钩子
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type stackHook struct {
+    // 锁
+    mu sync.Mutex
+
+    // 通道日志
+    logger *logrus.Logger
+}
+
+// 触发等级
+func (this *stackHook) Levels() []logrus.Level {
+    levels := make([]logrus.Level, 0)
+    for _, level := range logrus.AllLevels {
+        if this.logger.IsLevelEnabled(level) {
+            levels = append(levels, level)
+        }
+    }
+
+    return levels
+}
+
+// 写入通道
+func (this *stackHook) Fire(entry *logrus.Entry) error {
+    data, err := this.logger.Formatter.Format(entry)
+    if err != nil {
+        return err
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    _, err = this.logger.Out.Write(data)
+
+    return err
+}
@@ -0,0 +1,54 @@
+package logrus
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+
+    "github.com/sirupsen/logrus"
+)
+
+func Test_StackLogger(t *testing.T) {
+    var infoBuf, errorBuf bytes.Buffer
+
+    info := logrus.New()
+    info.SetOutput(&infoBuf)
+    info.SetLevel(logrus.InfoLevel)
+    info.SetFormatter(&logrus.JSONFormatter{})
+
+    errLog := logrus.New()
+    errLog.SetOutput(&errorBuf)
+    errLog.SetLevel(logrus.ErrorLevel)
+
+    stack := NewStackLogger(info, errLog)
+
+    stack.WithField("request_id", "abc").Info("info message")
+    stack.Error("error message")
+    stack.Debug("debug message")
+
+    if !strings.Contains(infoBuf.String(), `"request_id":"abc"`) {
+        t.Errorf("Failed info channel: %s", infoBuf.String())
+    }
+
+    if strings.Contains(infoBuf.String(), "debug message") {
+        t.Errorf("Failed info channel level: %s", infoBuf.String())
+    }
+
+    if strings.Contains(errorBuf.String(), "info message") || !strings.Contains(errorBuf.String(), "error message") {
+        t.Errorf("Failed error channel: %s", errorBuf.String())
+    }
+}
+
+func Test_ParseLevel(t *testing.T) {
+    for level, expected := range map[string]logrus.Level{
+        "error":   logrus.ErrorLevel,
+        "warning": logrus.WarnLevel,
+        "warn":    logrus.WarnLevel,
+        "info":    logrus.InfoLevel,
+        "":        logrus.TraceLevel,
+    } {
+        if actual := ParseLevel(level); actual != expected {
+            t.Errorf("Failed ParseLevel %s: actual: %v, expected: %v", level, actual, expected)
+        }
+    }
+}
@@ -0,0 +1,13 @@
+//go:build !windows && !plan9
+
+package logrus
+
+import (
+    "io"
+    "log/syslog"
+)
+
+// syslog 输出，network 和 address 为空时连接本地 syslog
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+    return syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
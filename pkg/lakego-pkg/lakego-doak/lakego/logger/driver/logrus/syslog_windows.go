@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logrus
+
+import (
+    "io"
+    "errors"
+)
+
+// syslog 输出
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+    return nil, errors.New("logger syslog output not support on this platform")
+}
@@ -0,0 +1,56 @@
+package logrus
+
+import (
+    "io"
+    "os"
+    "fmt"
+    "time"
+
+    "github.com/deatil/lakego-doak/lakego/path"
+    "github.com/deatil/lakego-doak/lakego/array"
+    "github.com/deatil/lakego-doak/lakego/logger/rotate"
+)
+
+// 根据配置创建输出
+// output 支持 file, stdout, stderr, syslog，默认为 file
+func NewWriter(conf map[string]any) (io.Writer, error) {
+    cfg := array.ArrayFrom(conf)
+
+    output := cfg.Value("output").ToString()
+    switch output {
+        case "stdout":
+            return os.Stdout, nil
+
+        case "stderr":
+            return os.Stderr, nil
+
+        case "syslog":
+            return newSyslogWriter(
+                cfg.Value("network").ToString(),
+                cfg.Value("address").ToString(),
+                cfg.Value("tag").ToString(),
+            )
+
+        case "", "file":
+            // 日志文件
+            // log_%Y%m%d.log
+            logPath := path.FormatPath(cfg.Value("filepath").ToString())
+            if logPath == "" {
+                return nil, fmt.Errorf("logger filepath is empty")
+            }
+
+            // 单位：小时
+            maxAge := cfg.Value("max-age").ToInt()
+
+            // 单位：MB
+            maxSize := cfg.Value("max-size").ToInt()
+
+            return rotate.New(rotate.Config{
+                Pattern: logPath,
+                MaxAge:  time.Duration(maxAge) * time.Hour,
+                MaxSize: int64(maxSize) * 1024 * 1024,
+            }), nil
+    }
+
+    return nil, fmt.Errorf("logger output [%s] not support", output)
+}
@@ -1,6 +1,8 @@
 package logger
 
 import (
+    "context"
+
     "github.com/deatil/lakego-doak/lakego/logger/interfaces"
 )
 
@@ -49,6 +51,11 @@ func (this *Logger) WithField(key string, value any) any {
     return this.Driver.WithField(key, value)
 }
 
+// 设置上下文数据，包括请求 ID、管理员 ID 和路由别名
+func (this *Logger) WithContext(ctx context.Context) any {
+    return this.Driver.WithFields(ContextFields(ctx))
+}
+
 // ========
 
 func (this *Logger) Trace(args ...any) {
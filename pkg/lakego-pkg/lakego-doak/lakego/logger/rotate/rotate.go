@@ -0,0 +1,236 @@
+package rotate
+
+import (
+    "os"
+    "fmt"
+    "sync"
+    "time"
+    "strings"
+    "path/filepath"
+)
+
+// 时间格式
+var patternLayouts = map[byte]string{
+    'Y': "2006",
+    'm': "01",
+    'd': "02",
+    'H': "15",
+    'M': "04",
+    'S': "05",
+}
+
+// 清理用通配
+var globReplacer = strings.NewReplacer(
+    "%Y", "*",
+    "%m", "*",
+    "%d", "*",
+    "%H", "*",
+    "%M", "*",
+    "%S", "*",
+)
+
+// 配置
+type Config struct {
+    // 文件路径，支持 %Y %m %d %H %M %S
+    // 比如 runtime/log/log_%Y%m%d.log
+    Pattern string
+
+    // 文件最大保存时间，0 为不清理
+    MaxAge time.Duration
+
+    // 单个文件最大字节数，0 为不限制
+    MaxSize int64
+
+    // 文件权限
+    FileMode os.FileMode
+}
+
+/**
+ * 按时间和大小切割的日志文件
+ *
+ * 超出大小时文件名追加序号，比如 log_20261018.1.log
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Writer struct {
+    // 锁
+    mu sync.Mutex
+
+    // 配置
+    config Config
+
+    // 当前时间文件名
+    base string
+
+    // 当前序号
+    index int
+
+    // 当前文件
+    file *os.File
+
+    // 当前文件大小
+    size int64
+
+    // 当前时间
+    now func() time.Time
+}
+
+// 构造函数
+func New(config Config) *Writer {
+    if config.FileMode == 0 {
+        config.FileMode = 0644
+    }
+
+    return &Writer{
+        config: config,
+        now:    time.Now,
+    }
+}
+
+// 写入
+func (this *Writer) Write(p []byte) (n int, err error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    base := formatPattern(this.config.Pattern, this.now())
+
+    if this.file == nil || base != this.base {
+        if err = this.open(base, 0); err != nil {
+            return 0, err
+        }
+    } else if this.config.MaxSize > 0 && this.size + int64(len(p)) > this.config.MaxSize {
+        if err = this.open(base, this.index + 1); err != nil {
+            return 0, err
+        }
+    }
+
+    n, err = this.file.Write(p)
+    this.size += int64(n)
+
+    return n, err
+}
+
+// 关闭
+func (this *Writer) Close() error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.file == nil {
+        return nil
+    }
+
+    err := this.file.Close()
+    this.file = nil
+
+    return err
+}
+
+// 当前文件名
+func (this *Writer) Filename() string {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    return this.filename(this.base, this.index)
+}
+
+// 打开文件，跳过已满的文件
+func (this *Writer) open(base string, index int) error {
+    if this.file != nil {
+        this.file.Close()
+        this.file = nil
+    }
+
+    err := os.MkdirAll(filepath.Dir(base), 0755)
+    if err != nil {
+        return err
+    }
+
+    for {
+        name := this.filename(base, index)
+
+        info, err := os.Stat(name)
+        if err == nil && this.config.MaxSize > 0 && info.Size() >= this.config.MaxSize {
+            index++
+            continue
+        }
+
+        file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, this.config.FileMode)
+        if err != nil {
+            return err
+        }
+
+        var size int64
+        if info != nil {
+            size = info.Size()
+        }
+
+        this.file = file
+        this.base = base
+        this.index = index
+        this.size = size
+
+        break
+    }
+
+    if this.config.MaxAge > 0 {
+        go this.cleanup()
+    }
+
+    return nil
+}
+
+// 清理过期文件
+func (this *Writer) cleanup() {
+    matches, err := filepath.Glob(globReplacer.Replace(this.config.Pattern))
+    if err != nil {
+        return
+    }
+
+    // 序号文件
+    ext := filepath.Ext(this.config.Pattern)
+    indexed, _ := filepath.Glob(globReplacer.Replace(strings.TrimSuffix(this.config.Pattern, ext)) + ".*" + ext)
+    matches = append(matches, indexed...)
+
+    cutoff := this.now().Add(-this.config.MaxAge)
+    for _, match := range matches {
+        info, err := os.Stat(match)
+        if err != nil || info.IsDir() {
+            continue
+        }
+
+        if info.ModTime().Before(cutoff) {
+            os.Remove(match)
+        }
+    }
+}
+
+// 文件名
+func (this *Writer) filename(base string, index int) string {
+    if index == 0 {
+        return base
+    }
+
+    ext := filepath.Ext(base)
+
+    return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(base, ext), index, ext)
+}
+
+// 格式化文件路径
+func formatPattern(pattern string, t time.Time) string {
+    var b strings.Builder
+
+    for i := 0; i < len(pattern); i++ {
+        if pattern[i] == '%' && i + 1 < len(pattern) {
+            if layout, ok := patternLayouts[pattern[i+1]]; ok {
+                b.WriteString(t.Format(layout))
+                i++
+                continue
+            }
+        }
+
+        b.WriteByte(pattern[i])
+    }
+
+    return b.String()
+}
@@ -0,0 +1,69 @@
+package rotate
+
+import (
+    "os"
+    "time"
+    "testing"
+    "reflect"
+    "path/filepath"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_Writer(t *testing.T) {
+    assert := assertT(t)
+
+    dir := t.TempDir()
+
+    w := New(Config{
+        Pattern: filepath.Join(dir, "log_%Y%m%d.log"),
+        MaxSize: 10,
+    })
+    defer w.Close()
+
+    now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local)
+    w.now = func() time.Time {
+        return now
+    }
+
+    w.Write([]byte("12345678"))
+    assert(w.Filename(), filepath.Join(dir, "log_20261018.log"), "Filename")
+
+    w.Write([]byte("12345678"))
+    assert(w.Filename(), filepath.Join(dir, "log_20261018.1.log"), "Filename size")
+
+    now = now.Add(24 * time.Hour)
+    w.Write([]byte("123"))
+    assert(w.Filename(), filepath.Join(dir, "log_20261019.log"), "Filename day")
+
+    data, _ := os.ReadFile(filepath.Join(dir, "log_20261018.1.log"))
+    assert(string(data), "12345678", "ReadFile")
+}
+
+func Test_Cleanup(t *testing.T) {
+    assert := assertT(t)
+
+    dir := t.TempDir()
+
+    old := filepath.Join(dir, "log_20261001.1.log")
+    os.WriteFile(old, []byte("old"), 0644)
+    os.Chtimes(old, time.Now().Add(-48 * time.Hour), time.Now().Add(-48 * time.Hour))
+
+    w := New(Config{
+        Pattern: filepath.Join(dir, "log_%Y%m%d.log"),
+        MaxAge:  24 * time.Hour,
+    })
+    defer w.Close()
+
+    w.Write([]byte("new"))
+    w.cleanup()
+
+    _, err := os.Stat(old)
+    assert(os.IsNotExist(err), true, "cleanup")
+}
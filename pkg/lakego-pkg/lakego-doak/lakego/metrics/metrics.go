@@ -0,0 +1,134 @@
+package metrics
+
+import (
+    "sort"
+    "sync"
+    "sync/atomic"
+)
+
+// 默认指标
+var defaultRegistry = New()
+
+// 计数器
+type Counter struct {
+    value int64
+}
+
+// 增加
+func (this *Counter) Add(delta int64) {
+    atomic.AddInt64(&this.value, delta)
+}
+
+// 加一
+func (this *Counter) Inc() {
+    this.Add(1)
+}
+
+// 当前值
+func (this *Counter) Value() int64 {
+    return atomic.LoadInt64(&this.value)
+}
+
+// 指标
+// 进程内计数，用于监控显示，不做持久化
+type Registry struct {
+    // 锁
+    mu sync.RWMutex
+
+    // 计数器
+    counters map[string]*Counter
+
+    // 实时数值
+    gauges map[string]func() int64
+}
+
+// 构造函数
+func New() *Registry {
+    return &Registry{
+        counters: make(map[string]*Counter),
+        gauges:   make(map[string]func() int64),
+    }
+}
+
+// 获取计数器，不存在时创建
+func (this *Registry) Counter(name string) *Counter {
+    this.mu.RLock()
+    counter, ok := this.counters[name]
+    this.mu.RUnlock()
+
+    if ok {
+        return counter
+    }
+
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if counter, ok = this.counters[name]; !ok {
+        counter = &Counter{}
+        this.counters[name] = counter
+    }
+
+    return counter
+}
+
+// 设置实时数值，读取时调用
+func (this *Registry) Gauge(name string, fn func() int64) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.gauges[name] = fn
+}
+
+// 全部指标名称
+func (this *Registry) Names() []string {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    names := make([]string, 0, len(this.counters) + len(this.gauges))
+    for name := range this.counters {
+        names = append(names, name)
+    }
+    for name := range this.gauges {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+
+    return names
+}
+
+// 全部指标当前值
+func (this *Registry) Snapshot() map[string]int64 {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    data := make(map[string]int64, len(this.counters) + len(this.gauges))
+    for name, counter := range this.counters {
+        data[name] = counter.Value()
+    }
+    for name, fn := range this.gauges {
+        data[name] = fn()
+    }
+
+    return data
+}
+
+// 默认指标的计数器
+func GetCounter(name string) *Counter {
+    return defaultRegistry.Counter(name)
+}
+
+// 设置默认指标的实时数值
+func SetGauge(name string, fn func() int64) {
+    defaultRegistry.Gauge(name, fn)
+}
+
+// 默认指标当前值
+func Snapshot() map[string]int64 {
+    return defaultRegistry.Snapshot()
+}
+
+// 默认指标
+func Default() *Registry {
+    return defaultRegistry
+}
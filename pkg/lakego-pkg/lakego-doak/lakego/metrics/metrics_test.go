@@ -0,0 +1,34 @@
+package metrics
+
+import (
+    "testing"
+)
+
+func Test_Registry(t *testing.T) {
+    r := New()
+
+    r.Counter("test.flushed").Add(3)
+    r.Counter("test.flushed").Inc()
+    r.Counter("test.dropped")
+    r.Gauge("test.buffered", func() int64 {
+        return 5
+    })
+
+    data := r.Snapshot()
+
+    check := map[string]int64{
+        "test.flushed":  4,
+        "test.dropped":  0,
+        "test.buffered": 5,
+    }
+    for name, expected := range check {
+        if data[name] != expected {
+            t.Errorf("Failed Snapshot %s: actual: %v, expected: %v", name, data[name], expected)
+        }
+    }
+
+    names := r.Names()
+    if len(names) != 3 || names[0] != "test.buffered" {
+        t.Errorf("Failed Names: actual: %v", names)
+    }
+}
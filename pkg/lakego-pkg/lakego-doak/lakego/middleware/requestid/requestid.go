@@ -0,0 +1,48 @@
+package requestid
+
+import (
+    "context"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/router"
+)
+
+// 请求头
+const HeaderName = "X-Request-Id"
+
+// gin 上下文名称
+const ContextKey = "request_id"
+
+// 请求上下文 key
+type contextKey struct{}
+
+// 从请求上下文获取请求 ID
+func FromContext(ctx context.Context) string {
+    requestId, _ := ctx.Value(contextKey{}).(string)
+
+    return requestId
+}
+
+/**
+ * 请求 ID
+ *
+ * 优先使用请求头里的 X-Request-Id，没有时生成新的
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func Handler() router.HandlerFunc {
+    return func(ctx *router.Context) {
+        requestId := ctx.GetHeader(HeaderName)
+        if requestId == "" || len(requestId) > 64 {
+            requestId = uuid.ToUUIDString()
+        }
+
+        ctx.Set(ContextKey, requestId)
+        ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), contextKey{}, requestId))
+
+        ctx.Header(HeaderName, requestId)
+
+        ctx.Next()
+    }
+}
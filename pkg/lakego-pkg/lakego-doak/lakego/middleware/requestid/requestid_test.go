@@ -0,0 +1,44 @@
+package requestid
+
+import (
+    "testing"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+)
+
+func Test_Handler(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    var ginId, requestId string
+    var stringKey any
+
+    r := gin.New()
+    r.Use(Handler())
+    r.GET("/", func(ctx *router.Context) {
+        ginId = ctx.GetString(ContextKey)
+        requestId = FromContext(ctx.Request.Context())
+        stringKey = ctx.Request.Context().Value(ContextKey)
+    })
+
+    req := httptest.NewRequest("GET", "/", nil)
+    req.Header.Set(HeaderName, "request-1")
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    if ginId != "request-1" || requestId != "request-1" {
+        t.Errorf("Failed Handler: gin: %s, request: %s", ginId, requestId)
+    }
+
+    if w.Header().Get(HeaderName) != "request-1" {
+        t.Errorf("Failed Handler header: %s", w.Header().Get(HeaderName))
+    }
+
+    // 请求上下文不使用字符串 key
+    if stringKey != nil {
+        t.Errorf("Failed Handler string key: %v", stringKey)
+    }
+}
@@ -0,0 +1,26 @@
+package sticky
+
+import (
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/database"
+)
+
+/**
+ * 请求内写后读主库
+ *
+ * 使用 facade.DB.WithContext(ctx) 查询时，
+ * 同一请求写入数据后的查询会使用主库
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+func Handler() router.HandlerFunc {
+    return func(ctx *router.Context) {
+        sticky := database.NewSticky()
+
+        ctx.Set(database.StickyKey, sticky)
+        ctx.Request = ctx.Request.WithContext(database.WithSticky(ctx.Request.Context(), sticky))
+
+        ctx.Next()
+    }
+}
@@ -0,0 +1,144 @@
+package throttle
+
+import (
+    "fmt"
+    "math"
+    "time"
+
+    "github.com/deatil/lakego-doak/lakego/limiter"
+)
+
+// 限流结果
+type Result struct {
+    // 是否允许
+    Allowed bool
+
+    // 限制次数
+    Limit int64
+
+    // 剩余次数
+    Remaining int64
+
+    // 需要等待的时间
+    RetryAfter time.Duration
+
+    // 完全恢复的时间
+    ResetAfter time.Duration
+}
+
+// 需要等待的秒数
+func (this Result) RetryAfterSeconds() int64 {
+    return ceilSeconds(this.RetryAfter)
+}
+
+// 限流算法
+type Limiter interface {
+    Allow(key string) (Result, error)
+}
+
+// 滑动窗口
+func NewSlidingWindow(store Store, limit int64, period time.Duration) *SlidingWindow {
+    return &SlidingWindow{
+        store:  store,
+        limit:  limit,
+        period: period,
+        now:    time.Now,
+    }
+}
+
+/**
+ * 滑动窗口，使用 limiter 包的滑动窗口计数，
+ * 计数使用缓存的原子操作，不需要加锁
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SlidingWindow struct {
+    store  Store
+    limit  int64
+    period time.Duration
+    now    func() time.Time
+}
+
+// 检测
+func (this *SlidingWindow) Allow(key string) (Result, error) {
+    l := limiter.New(this.store.Cache()).WithNow(this.now)
+
+    result := Result{
+        Limit:      this.limit,
+        ResetAfter: l.AvailableIn(this.period),
+    }
+
+    // 先计数再判断，计数为原子递增，并发请求不会同时通过
+    attempts := l.Hit(key, this.period)
+    if attempts > this.limit {
+        result.RetryAfter = result.ResetAfter
+        return result, nil
+    }
+
+    result.Allowed = true
+    result.Remaining = this.limit - attempts
+
+    return result, nil
+}
+
+// 令牌桶
+func NewTokenBucket(store Store, limit int64, period time.Duration) *TokenBucket {
+    return &TokenBucket{
+        store:  store,
+        limit:  limit,
+        period: period,
+        now:    time.Now,
+    }
+}
+
+/**
+ * 令牌桶，容量为 limit，每个周期补满
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type TokenBucket struct {
+    store  Store
+    limit  int64
+    period time.Duration
+    now    func() time.Time
+}
+
+// 检测
+func (this *TokenBucket) Allow(key string) (Result, error) {
+    result := Result{
+        Limit: this.limit,
+    }
+
+    now := this.now().UnixNano()
+
+    // 每纳秒补充的令牌
+    rate := float64(this.limit) / float64(this.period)
+
+    err := this.store.Update(key, this.period, func(value string) string {
+        tokens := float64(this.limit)
+        last := now
+
+        if value != "" {
+            fmt.Sscanf(value, "%g:%d", &tokens, &last)
+
+            tokens = math.Min(float64(this.limit), tokens + float64(now - last) * rate)
+        }
+
+        if tokens >= 1 {
+            tokens--
+
+            result.Allowed = true
+        } else {
+            result.RetryAfter = time.Duration(math.Ceil((1 - tokens) / rate))
+        }
+
+        result.Remaining = int64(math.Floor(tokens))
+        result.ResetAfter = time.Duration(math.Ceil((float64(this.limit) - tokens) / rate))
+
+        return fmt.Sprintf("%g:%d", tokens, now)
+    })
+
+    return result, err
+}
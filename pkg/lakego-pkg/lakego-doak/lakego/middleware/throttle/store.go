@@ -0,0 +1,145 @@
+package throttle
+
+import (
+    "sync"
+    "time"
+
+    "github.com/deatil/go-goch/goch"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
+)
+
+// 存储接口
+type Store interface {
+    // 计数使用的缓存，滑动窗口使用缓存的原子操作计数
+    Cache() *cache.Cache
+
+    // 原子更新数据，value 不存在时为空，令牌桶使用
+    Update(key string, ttl time.Duration, fn func(value string) string) error
+}
+
+// 内存存储
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{
+        cache: cache.New(memory.New(memory.Config{})),
+        items: make(map[string]memoryItem),
+    }
+}
+
+// 内存数据
+type memoryItem struct {
+    value  string
+    expire int64
+}
+
+/**
+ * 内存存储，只在单个实例内有效
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type MemoryStore struct {
+    mu sync.Mutex
+
+    // 计数缓存
+    cache *cache.Cache
+
+    items map[string]memoryItem
+
+    // 下次清理过期数据时间
+    nextGC int64
+}
+
+// 计数使用的缓存
+func (this *MemoryStore) Cache() *cache.Cache {
+    return this.cache
+}
+
+// 原子更新数据
+func (this *MemoryStore) Update(key string, ttl time.Duration, fn func(value string) string) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    now := time.Now().UnixNano()
+    this.gc(now)
+
+    value := ""
+    if item, ok := this.items[key]; ok && item.expire > now {
+        value = item.value
+    }
+
+    this.items[key] = memoryItem{
+        value:  fn(value),
+        expire: now + int64(ttl),
+    }
+
+    return nil
+}
+
+// 清理过期数据，每分钟最多一次
+func (this *MemoryStore) gc(now int64) {
+    if now < this.nextGC {
+        return
+    }
+
+    for key, item := range this.items {
+        if item.expire <= now {
+            delete(this.items, key)
+        }
+    }
+
+    this.nextGC = now + int64(time.Minute)
+}
+
+// 缓存存储
+func NewCacheStore(c *cache.Cache) *CacheStore {
+    return &CacheStore{
+        cache: c,
+        wait:  3,
+    }
+}
+
+/**
+ * 缓存存储，多实例共享。滑动窗口使用缓存的原子计数，
+ * 令牌桶需要读取后写入，使用缓存锁保证原子性
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type CacheStore struct {
+    cache *cache.Cache
+
+    // 获取锁等待秒数
+    wait int
+}
+
+// 计数使用的缓存
+func (this *CacheStore) Cache() *cache.Cache {
+    return this.cache
+}
+
+// 原子更新数据
+func (this *CacheStore) Update(key string, ttl time.Duration, fn func(value string) string) error {
+    var err error
+
+    _, lockErr := this.cache.Lock("throttle:" + key, 5).Block(this.wait, func() {
+        value := ""
+        if this.cache.Has(key) {
+            data, _ := this.cache.Get(key)
+            value = goch.ToString(data)
+        }
+
+        seconds := int64(ttl / time.Second)
+        if seconds < 1 {
+            seconds = 1
+        }
+
+        err = this.cache.Put(key, fn(value), seconds)
+    })
+    if lockErr != nil {
+        return lockErr
+    }
+
+    return err
+}
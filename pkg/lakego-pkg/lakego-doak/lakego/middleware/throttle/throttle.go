@@ -0,0 +1,268 @@
+package throttle
+
+import (
+    "fmt"
+    "sync"
+    "time"
+    "errors"
+    "strconv"
+    "net/http"
+
+    "github.com/deatil/lakego-doak/lakego/router"
+)
+
+// 算法
+const (
+    SlidingWindowAlgorithm = "sliding-window"
+    TokenBucketAlgorithm   = "token-bucket"
+)
+
+var (
+    // key 生成方式不存在
+    ErrUnknownKey = errors.New("throttle: unknown key name")
+
+    // 算法不存在
+    ErrUnknownAlgorithm = errors.New("throttle: unknown algorithm")
+)
+
+// 默认错误码，与后台响应的 code.StatusError 一致
+const StatusError = 1
+
+// 根据请求生成限流 key
+type KeyFunc func(*router.Context) string
+
+// 限流后的响应
+type LimitFunc func(*router.Context, Result)
+
+var (
+    // 默认存储
+    defaultStore Store = NewMemoryStore()
+
+    // 限流后的响应
+    defaultLimitFunc LimitFunc = Response
+
+    // key 生成方式
+    keyFuncs = map[string]KeyFunc{
+        "ip":    KeyByIP,
+        "admin": KeyByAdmin,
+    }
+
+    mu sync.RWMutex
+)
+
+// 设置默认存储，比如 NewCacheStore(facade.Cache)
+func SetDefaultStore(store Store) {
+    mu.Lock()
+    defer mu.Unlock()
+
+    defaultStore = store
+}
+
+// 设置限流后的响应
+func SetLimitFunc(fn LimitFunc) {
+    mu.Lock()
+    defer mu.Unlock()
+
+    defaultLimitFunc = fn
+}
+
+// 注册 key 生成方式
+func RegisterKeyFunc(name string, fn KeyFunc) {
+    mu.Lock()
+    defer mu.Unlock()
+
+    keyFuncs[name] = fn
+}
+
+// 根据 IP 限流
+func KeyByIP(ctx *router.Context) string {
+    return "ip:" + router.GetRequestIp(ctx)
+}
+
+// 根据登录账号限流，未登录时根据 IP
+func KeyByAdmin(ctx *router.Context) string {
+    if adminId, ok := ctx.Get("admin_id"); ok {
+        if id, ok := adminId.(string); ok && id != "" {
+            return "admin:" + id
+        }
+    }
+
+    return KeyByIP(ctx)
+}
+
+/**
+ * 限流配置
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Config struct {
+    // 周期内最大请求数
+    Limit int64
+
+    // 周期
+    Period time.Duration
+
+    // 算法，默认为滑动窗口
+    Algorithm string
+
+    // 存储，默认为内存存储
+    Store Store
+
+    // key 生成方式，默认根据 IP
+    KeyFunc KeyFunc
+
+    // 限流后的响应
+    LimitFunc LimitFunc
+
+    // key 前缀，区分不同路由的限流
+    Prefix string
+}
+
+// 中间件，别名使用时格式为 throttle:最大请求数,分钟[,key 方式[,算法]]
+// 比如 throttle:60,1 或者 throttle:10,1,admin,token-bucket
+// 参数错误时在注册路由时 panic
+func Handler(params ...string) router.HandlerFunc {
+    conf, err := ParseParams(params...)
+    if err != nil {
+        panic(err)
+    }
+
+    return New(conf)
+}
+
+// 解析别名参数
+func ParseParams(params ...string) (Config, error) {
+    conf := Config{
+        Limit:  60,
+        Period: time.Minute,
+    }
+
+    if len(params) > 0 {
+        if limit, err := strconv.ParseInt(params[0], 10, 64); err == nil && limit > 0 {
+            conf.Limit = limit
+        }
+    }
+
+    if len(params) > 1 {
+        if minutes, err := strconv.ParseFloat(params[1], 64); err == nil && minutes > 0 {
+            conf.Period = time.Duration(minutes * float64(time.Minute))
+        }
+    }
+
+    if len(params) > 2 && params[2] != "" {
+        mu.RLock()
+        keyFunc, ok := keyFuncs[params[2]]
+        mu.RUnlock()
+
+        if !ok {
+            return conf, fmt.Errorf("%w: %s", ErrUnknownKey, params[2])
+        }
+
+        conf.KeyFunc = keyFunc
+        conf.Prefix = params[2]
+    }
+
+    if len(params) > 3 && params[3] != "" {
+        switch params[3] {
+            case SlidingWindowAlgorithm, TokenBucketAlgorithm:
+                conf.Algorithm = params[3]
+            default:
+                return conf, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, params[3])
+        }
+    }
+
+    return conf, nil
+}
+
+// 根据配置生成中间件
+func New(conf Config) router.HandlerFunc {
+    if conf.Limit <= 0 {
+        conf.Limit = 60
+    }
+
+    if conf.Period < time.Second {
+        conf.Period = time.Minute
+    }
+
+    if conf.KeyFunc == nil {
+        conf.KeyFunc = KeyByIP
+    }
+
+    return func(ctx *router.Context) {
+        store := conf.Store
+        limitFunc := conf.LimitFunc
+
+        mu.RLock()
+        if store == nil {
+            store = defaultStore
+        }
+        if limitFunc == nil {
+            limitFunc = defaultLimitFunc
+        }
+        mu.RUnlock()
+
+        l := newLimiter(conf, store)
+
+        key := "throttle:" + conf.Prefix + ":" +
+            strconv.FormatInt(conf.Limit, 10) + ":" +
+            strconv.FormatInt(int64(conf.Period / time.Second), 10) + ":" +
+            conf.KeyFunc(ctx)
+
+        result, err := l.Allow(key)
+        if err != nil {
+            // 存储异常时不限流
+            ctx.Next()
+            return
+        }
+
+        setHeaders(ctx, result)
+
+        if !result.Allowed {
+            limitFunc(ctx, result)
+            return
+        }
+
+        ctx.Next()
+    }
+}
+
+// 默认响应
+func Response(ctx *router.Context, result Result) {
+    ctx.AbortWithStatusJSON(http.StatusTooManyRequests, router.H{
+        "success": false,
+        "code":    StatusError,
+        "message": "请求过于频繁，请稍后再试",
+        "data":    router.H{},
+    })
+}
+
+// 生成限流算法
+func newLimiter(conf Config, store Store) Limiter {
+    if conf.Algorithm == TokenBucketAlgorithm {
+        return NewTokenBucket(store, conf.Limit, conf.Period)
+    }
+
+    return NewSlidingWindow(store, conf.Limit, conf.Period)
+}
+
+// 设置响应头
+func setHeaders(ctx *router.Context, result Result) {
+    ctx.Header("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+    ctx.Header("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+    ctx.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
+
+    if !result.Allowed {
+        ctx.Header("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+    }
+}
+
+// 向上取整的秒数
+func ceilSeconds(d time.Duration) int64 {
+    seconds := int64(d / time.Second)
+    if d % time.Second > 0 {
+        seconds++
+    }
+
+    return seconds
+}
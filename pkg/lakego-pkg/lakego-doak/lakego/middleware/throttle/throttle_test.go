@@ -0,0 +1,200 @@
+package throttle
+
+import (
+    "sync"
+    "time"
+    "errors"
+    "testing"
+    "reflect"
+    "net/http"
+    "net/http/httptest"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/router"
+    "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func Test_SlidingWindow(t *testing.T) {
+    eq := assertT(t)
+
+    // 窗口开始时间 1699999980
+    now := time.Unix(1700000000, 0)
+
+    l := NewSlidingWindow(NewMemoryStore(), 3, time.Minute)
+    l.now = func() time.Time {
+        return now
+    }
+
+    for i := 2; i >= 0; i-- {
+        res, _ := l.Allow("key")
+        eq(res.Allowed, true, "Allow")
+        eq(res.Remaining, int64(i), "Remaining")
+    }
+
+    res, _ := l.Allow("key")
+    eq(res.Allowed, false, "Allow limited")
+    eq(res.RetryAfter, 40 * time.Second, "RetryAfter")
+
+    // 下一窗口过去一半，上一窗口 4 次（含被拒绝的 1 次）折算 2 次
+    now = time.Unix(1699999980 + 90, 0)
+
+    res, _ = l.Allow("key")
+    eq(res.Allowed, true, "Allow sliding")
+    eq(res.Remaining, int64(0), "Remaining sliding")
+
+    res, _ = l.Allow("key")
+    eq(res.Allowed, false, "Allow sliding limited")
+    eq(res.RetryAfter, 30 * time.Second, "RetryAfter sliding")
+}
+
+func Test_SlidingWindow_CacheStore(t *testing.T) {
+    eq := assertT(t)
+
+    c := cache.New(memory.New(memory.Config{}))
+
+    // 锁被占用时滑动窗口不受影响
+    lock := c.Lock("throttle:key", 60)
+    ok, _ := lock.Get()
+    eq(ok, true, "lock Get")
+    defer lock.Release()
+
+    // 多个实例共享计数
+    l1 := NewSlidingWindow(NewCacheStore(c), 3, time.Minute)
+    l2 := NewSlidingWindow(NewCacheStore(c), 3, time.Minute)
+
+    start := time.Now()
+
+    res, err := l1.Allow("key")
+    eq(err, nil, "Allow error")
+    eq(res.Allowed, true, "Allow 1")
+    res, _ = l2.Allow("key")
+    eq(res.Allowed, true, "Allow 2")
+    eq(res.Remaining, int64(1), "Remaining 2")
+    res, _ = l1.Allow("key")
+    eq(res.Allowed, true, "Allow 3")
+
+    res, _ = l2.Allow("key")
+    eq(res.Allowed, false, "Allow limited")
+
+    eq(time.Since(start) < time.Second, true, "Allow without lock")
+}
+
+func Test_TokenBucket(t *testing.T) {
+    eq := assertT(t)
+
+    now := time.Unix(1700000000, 0)
+
+    l := NewTokenBucket(NewMemoryStore(), 2, 10 * time.Second)
+    l.now = func() time.Time {
+        return now
+    }
+
+    res, _ := l.Allow("key")
+    eq(res.Allowed, true, "Allow")
+    res, _ = l.Allow("key")
+    eq(res.Allowed, true, "Allow 2")
+
+    res, _ = l.Allow("key")
+    eq(res.Allowed, false, "Allow limited")
+    eq(res.RetryAfter, 5 * time.Second, "RetryAfter")
+
+    now = now.Add(5 * time.Second)
+
+    res, _ = l.Allow("key")
+    eq(res.Allowed, true, "Allow refill")
+    eq(res.Remaining, int64(0), "Remaining refill")
+}
+
+func Test_Handler(t *testing.T) {
+    eq := assertT(t)
+
+    gin.SetMode(gin.TestMode)
+
+    r := gin.New()
+    r.Use(New(Config{
+        Limit:  2,
+        Period: time.Minute,
+        Store:  NewMemoryStore(),
+    }))
+    r.GET("/", func(ctx *router.Context) {
+        ctx.String(http.StatusOK, "ok")
+    })
+
+    codes := make([]int, 0)
+    for i := 0; i < 3; i++ {
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+        codes = append(codes, w.Code)
+
+        if i == 2 {
+            eq(w.Header().Get("X-RateLimit-Limit"), "2", "X-RateLimit-Limit")
+            eq(w.Header().Get("X-RateLimit-Remaining"), "0", "X-RateLimit-Remaining")
+            eq(w.Header().Get("Retry-After") != "", true, "Retry-After")
+        }
+    }
+
+    eq(codes, []int{200, 200, 429}, "Handler codes")
+}
+
+func Test_SlidingWindow_Concurrent(t *testing.T) {
+    eq := assertT(t)
+
+    l := NewSlidingWindow(NewMemoryStore(), 10, time.Minute)
+
+    var wg sync.WaitGroup
+    var mu sync.Mutex
+
+    allowed := 0
+    for i := 0; i < 50; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+
+            res, _ := l.Allow("key")
+            if res.Allowed {
+                mu.Lock()
+                allowed++
+                mu.Unlock()
+            }
+        }()
+    }
+
+    wg.Wait()
+
+    eq(allowed, 10, "Allow concurrent")
+}
+
+func Test_ParseParams(t *testing.T) {
+    eq := assertT(t)
+
+    conf, err := ParseParams("10", "2", "admin", "token-bucket")
+    eq(err, nil, "ParseParams")
+    eq(conf.Limit, int64(10), "ParseParams Limit")
+    eq(conf.Period, 2 * time.Minute, "ParseParams Period")
+    eq(conf.Prefix, "admin", "ParseParams Prefix")
+    eq(conf.KeyFunc != nil, true, "ParseParams KeyFunc")
+    eq(conf.Algorithm, TokenBucketAlgorithm, "ParseParams Algorithm")
+
+    _, err = ParseParams("10", "1", "adimn")
+    eq(errors.Is(err, ErrUnknownKey), true, "ParseParams unknown key")
+
+    _, err = ParseParams("10", "1", "ip", "leaky")
+    eq(errors.Is(err, ErrUnknownAlgorithm), true, "ParseParams unknown algorithm")
+
+    defer func() {
+        eq(recover() != nil, true, "Handler panic")
+    }()
+
+    Handler("10", "1", "adimn")
+}
@@ -0,0 +1,73 @@
+package migration
+
+import (
+    "gorm.io/gorm"
+)
+
+/**
+ * 迁移
+ *
+ * 名称需唯一，按名称排序执行，
+ * 建议使用 2026_10_18_000000_create_admin_table 格式
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Migration struct {
+    // 名称
+    Name string
+
+    // 执行
+    Up func(*gorm.DB) error
+
+    // 回滚
+    Down func(*gorm.DB) error
+}
+
+/**
+ * 数据填充
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Seeder struct {
+    // 名称
+    Name string
+
+    // 执行
+    Run func(*gorm.DB) error
+}
+
+/**
+ * 迁移记录
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Record struct {
+    ID        uint   `gorm:"column:id;primaryKey;autoIncrement;" json:"id"`
+    Migration string `gorm:"column:migration;size:191;not null;uniqueIndex;" json:"migration"`
+    Group     string `gorm:"column:group_name;size:100;not null;index;" json:"group"`
+    Batch     int    `gorm:"column:batch;not null;" json:"batch"`
+    AddTime   int64  `gorm:"column:add_time;" json:"add_time"`
+}
+
+/**
+ * 迁移状态
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Status struct {
+    // 名称
+    Name string
+
+    // 分组
+    Group string
+
+    // 是否已执行
+    Ran bool
+
+    // 批次
+    Batch int
+}
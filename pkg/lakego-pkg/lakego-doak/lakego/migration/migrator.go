@@ -0,0 +1,269 @@
+package migration
+
+import (
+    "fmt"
+    "time"
+    "strings"
+
+    "gorm.io/gorm"
+)
+
+/**
+ * 迁移执行
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Migrator struct {
+    // 数据库
+    db *gorm.DB
+
+    // 记录表
+    table string
+}
+
+// 构造函数
+func NewMigrator(db *gorm.DB) *Migrator {
+    return &Migrator{
+        db:    db,
+        table: db.NamingStrategy.TableName("migrations"),
+    }
+}
+
+// 设置记录表
+func (this *Migrator) WithTable(table string) *Migrator {
+    this.table = table
+
+    return this
+}
+
+// 获取记录表
+func (this *Migrator) GetTable() string {
+    return this.table
+}
+
+// 创建记录表
+func (this *Migrator) EnsureTable() error {
+    return this.db.Table(this.table).AutoMigrate(&Record{})
+}
+
+// 已执行的迁移
+func (this *Migrator) Ran(groups ...string) ([]Record, error) {
+    if err := this.EnsureTable(); err != nil {
+        return nil, err
+    }
+
+    records := make([]Record, 0)
+
+    query := this.db.Table(this.table)
+    if len(groups) > 0 {
+        query = query.Where("group_name IN ?", groups)
+    }
+
+    err := query.
+        Order("batch ASC").
+        Order("id ASC").
+        Find(&records).
+        Error
+
+    return records, err
+}
+
+// 执行未执行的迁移，返回执行的迁移名称
+func (this *Migrator) Run(migrations []Group) ([]string, error) {
+    records, err := this.Ran()
+    if err != nil {
+        return nil, err
+    }
+
+    ran := make(map[string]bool)
+    batch := 0
+    for _, record := range records {
+        ran[record.Migration] = true
+
+        if record.Batch > batch {
+            batch = record.Batch
+        }
+    }
+
+    // 新批次
+    batch++
+
+    done := make([]string, 0)
+    for _, m := range migrations {
+        if ran[m.Migration.Name] {
+            continue
+        }
+
+        err = this.db.Transaction(func(tx *gorm.DB) error {
+            if m.Migration.Up != nil {
+                if err := m.Migration.Up(tx); err != nil {
+                    return err
+                }
+            }
+
+            return tx.Table(this.table).Create(&Record{
+                Migration: m.Migration.Name,
+                Group:     m.Group,
+                Batch:     batch,
+                AddTime:   time.Now().Unix(),
+            }).Error
+        })
+        if err != nil {
+            return done, fmt.Errorf("migration [%s] error: %w", m.Migration.Name, err)
+        }
+
+        done = append(done, m.Migration.Name)
+    }
+
+    return done, nil
+}
+
+// 回滚，step 为 0 时回滚最后一个批次，否则回滚最后 step 个迁移
+func (this *Migrator) Rollback(migrations []Group, step int, groups ...string) ([]string, error) {
+    records, err := this.Ran(groups...)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(records) == 0 {
+        return []string{}, nil
+    }
+
+    rollbacks := make([]Record, 0)
+    if step > 0 {
+        for i := len(records) - 1; i >= 0 && len(rollbacks) < step; i-- {
+            rollbacks = append(rollbacks, records[i])
+        }
+    } else {
+        lastBatch := records[len(records) - 1].Batch
+        for i := len(records) - 1; i >= 0; i-- {
+            if records[i].Batch == lastBatch {
+                rollbacks = append(rollbacks, records[i])
+            }
+        }
+    }
+
+    return this.rollbackRecords(migrations, rollbacks)
+}
+
+// 回滚全部
+func (this *Migrator) Reset(migrations []Group, groups ...string) ([]string, error) {
+    records, err := this.Ran(groups...)
+    if err != nil {
+        return nil, err
+    }
+
+    rollbacks := make([]Record, 0, len(records))
+    for i := len(records) - 1; i >= 0; i-- {
+        rollbacks = append(rollbacks, records[i])
+    }
+
+    return this.rollbackRecords(migrations, rollbacks)
+}
+
+// 删除全部带前缀的数据表后重新执行迁移
+func (this *Migrator) Fresh(migrations []Group) ([]string, error) {
+    tables, err := this.db.Migrator().GetTables()
+    if err != nil {
+        return nil, err
+    }
+
+    prefix := this.db.NamingStrategy.TableName("")
+    for _, table := range tables {
+        if prefix != "" && !strings.HasPrefix(table, prefix) {
+            continue
+        }
+
+        if err = this.db.Migrator().DropTable(table); err != nil {
+            return nil, fmt.Errorf("drop table [%s] error: %w", table, err)
+        }
+    }
+
+    return this.Run(migrations)
+}
+
+// 迁移状态
+func (this *Migrator) Status(migrations []Group) ([]Status, error) {
+    records, err := this.Ran()
+    if err != nil {
+        return nil, err
+    }
+
+    ran := make(map[string]Record)
+    for _, record := range records {
+        ran[record.Migration] = record
+    }
+
+    list := make([]Status, 0, len(migrations))
+    for _, m := range migrations {
+        record, ok := ran[m.Migration.Name]
+
+        list = append(list, Status{
+            Name:  m.Migration.Name,
+            Group: m.Group,
+            Ran:   ok,
+            Batch: record.Batch,
+        })
+    }
+
+    return list, nil
+}
+
+// 数据填充
+func (this *Migrator) Seed(seeders []Seeder) ([]string, error) {
+    done := make([]string, 0)
+
+    for _, seeder := range seeders {
+        if seeder.Run == nil {
+            continue
+        }
+
+        err := this.db.Transaction(func(tx *gorm.DB) error {
+            return seeder.Run(tx)
+        })
+        if err != nil {
+            return done, fmt.Errorf("seeder [%s] error: %w", seeder.Name, err)
+        }
+
+        done = append(done, seeder.Name)
+    }
+
+    return done, nil
+}
+
+// 回滚记录
+func (this *Migrator) rollbackRecords(migrations []Group, records []Record) ([]string, error) {
+    defined := make(map[string]Migration)
+    for _, m := range migrations {
+        defined[m.Migration.Name] = m.Migration
+    }
+
+    done := make([]string, 0)
+    for _, record := range records {
+        m, ok := defined[record.Migration]
+        if !ok {
+            return done, fmt.Errorf("migration [%s] not found", record.Migration)
+        }
+
+        err := this.db.Transaction(func(tx *gorm.DB) error {
+            if m.Down != nil {
+                if err := m.Down(tx); err != nil {
+                    return err
+                }
+            }
+
+            return tx.Table(this.table).
+                Where("id = ?", record.ID).
+                Delete(&Record{}).
+                Error
+        })
+        if err != nil {
+            return done, fmt.Errorf("migration [%s] rollback error: %w", record.Migration, err)
+        }
+
+        done = append(done, record.Migration)
+    }
+
+    return done, nil
+}
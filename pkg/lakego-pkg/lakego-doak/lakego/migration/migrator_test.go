@@ -0,0 +1,92 @@
+package migration
+
+import (
+    "testing"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/schema"
+    "gorm.io/driver/sqlite"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+type testUser struct {
+    ID   uint
+    Name string
+}
+
+type testPost struct {
+    ID    uint
+    Title string
+}
+
+func Test_Migrator(t *testing.T) {
+    assert := assertT(t)
+
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/test.db"), &gorm.Config{
+        NamingStrategy: schema.NamingStrategy{
+            SingularTable: true,
+            TablePrefix:   "lakego_",
+        },
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    registry := NewRegistry()
+    registry.Register("admin", Migration{
+        Name: "2026_10_18_000002_create_post_table",
+        Up: func(tx *gorm.DB) error {
+            return tx.Migrator().CreateTable(&testPost{})
+        },
+        Down: func(tx *gorm.DB) error {
+            return tx.Migrator().DropTable(&testPost{})
+        },
+    })
+    registry.Register("user", Migration{
+        Name: "2026_10_18_000001_create_user_table",
+        Up: func(tx *gorm.DB) error {
+            return tx.Migrator().CreateTable(&testUser{})
+        },
+        Down: func(tx *gorm.DB) error {
+            return tx.Migrator().DropTable(&testUser{})
+        },
+    })
+
+    m := NewMigrator(db)
+    assert(m.GetTable(), "lakego_migrations", "GetTable")
+
+    done, err := m.Run(registry.Migrations())
+    assert(err, nil, "Run error")
+    assert(done, []string{"2026_10_18_000001_create_user_table", "2026_10_18_000002_create_post_table"}, "Run")
+    assert(db.Migrator().HasTable(&testPost{}), true, "Run HasTable")
+
+    done, _ = m.Run(registry.Migrations())
+    assert(done, []string{}, "Run again")
+
+    status, _ := m.Status(registry.Migrations())
+    assert(status[0].Ran && status[1].Ran, true, "Status")
+    assert(status[0].Batch, 1, "Status batch")
+
+    done, err = m.Rollback(registry.Migrations(), 1)
+    assert(err, nil, "Rollback error")
+    assert(done, []string{"2026_10_18_000002_create_post_table"}, "Rollback")
+    assert(db.Migrator().HasTable(&testPost{}), false, "Rollback HasTable")
+
+    done, _ = m.Run(registry.Migrations("admin"))
+    assert(done, []string{"2026_10_18_000002_create_post_table"}, "Run group")
+
+    done, _ = m.Reset(registry.Migrations(), "user")
+    assert(done, []string{"2026_10_18_000001_create_user_table"}, "Reset group")
+
+    done, err = m.Fresh(registry.Migrations())
+    assert(err, nil, "Fresh error")
+    assert(len(done), 2, "Fresh")
+}
@@ -0,0 +1,144 @@
+package migration
+
+import (
+    "sort"
+    "sync"
+)
+
+// 默认
+var defaultRegistry = NewRegistry()
+
+/**
+ * 迁移注册
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Registry struct {
+    // 锁
+    mu sync.RWMutex
+
+    // 迁移，分组 => 列表
+    migrations map[string][]Migration
+
+    // 数据填充，分组 => 列表
+    seeders map[string][]Seeder
+}
+
+// 构造函数
+func NewRegistry() *Registry {
+    return &Registry{
+        migrations: make(map[string][]Migration),
+        seeders:    make(map[string][]Seeder),
+    }
+}
+
+// 注册迁移，同名迁移会被覆盖
+func (this *Registry) Register(group string, migrations ...Migration) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    for _, m := range migrations {
+        list := this.migrations[group]
+
+        replaced := false
+        for i, old := range list {
+            if old.Name == m.Name {
+                list[i] = m
+                replaced = true
+            }
+        }
+
+        if !replaced {
+            list = append(list, m)
+        }
+
+        this.migrations[group] = list
+    }
+}
+
+// 注册数据填充
+func (this *Registry) RegisterSeeder(group string, seeders ...Seeder) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.seeders[group] = append(this.seeders[group], seeders...)
+}
+
+// 获取迁移，不传分组时获取全部，按名称排序
+func (this *Registry) Migrations(groups ...string) []Group {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    if len(groups) == 0 {
+        for group := range this.migrations {
+            groups = append(groups, group)
+        }
+    }
+
+    list := make([]Group, 0)
+    for _, group := range groups {
+        for _, m := range this.migrations[group] {
+            list = append(list, Group{
+                Group:     group,
+                Migration: m,
+            })
+        }
+    }
+
+    sort.SliceStable(list, func(i, j int) bool {
+        return list[i].Migration.Name < list[j].Migration.Name
+    })
+
+    return list
+}
+
+// 获取数据填充，不传分组时获取全部
+func (this *Registry) Seeders(groups ...string) []Seeder {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    if len(groups) == 0 {
+        for group := range this.seeders {
+            groups = append(groups, group)
+        }
+
+        sort.Strings(groups)
+    }
+
+    list := make([]Seeder, 0)
+    for _, group := range groups {
+        list = append(list, this.seeders[group]...)
+    }
+
+    return list
+}
+
+// 分组迁移
+type Group struct {
+    // 分组
+    Group string
+
+    // 迁移
+    Migration Migration
+}
+
+// 注册迁移
+func Register(group string, migrations ...Migration) {
+    defaultRegistry.Register(group, migrations...)
+}
+
+// 注册数据填充
+func RegisterSeeder(group string, seeders ...Seeder) {
+    defaultRegistry.RegisterSeeder(group, seeders...)
+}
+
+// 获取迁移
+func Migrations(groups ...string) []Group {
+    return defaultRegistry.Migrations(groups...)
+}
+
+// 获取数据填充
+func Seeders(groups ...string) []Seeder {
+    return defaultRegistry.Seeders(groups...)
+}
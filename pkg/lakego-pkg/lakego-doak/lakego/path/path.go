@@ -6,10 +6,19 @@ import (
     "path/filepath"
 )
 
+// 设置程序根目录的环境变量，单元测试时用来指定配置所在目录
+const BasePathEnv = "LAKEGO_BASE_PATH"
+
 // 程序根目录
 func BasePath() string {
     var basePath string
 
+    if path := os.Getenv(BasePathEnv); path != "" {
+        basePath, _ = filepath.Abs(path)
+
+        return basePath
+    }
+
     if path, err := os.Getwd(); err == nil {
         // 路径进行处理，兼容单元测试程序程序启动时的奇怪路径
         if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "-test") {
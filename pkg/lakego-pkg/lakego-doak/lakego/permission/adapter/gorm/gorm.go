@@ -54,6 +54,9 @@ type Filter struct {
     V3    []string
     V4    []string
     V5    []string
+
+    // 域，规则需使用 p = sub, dom, obj, act 和 g = _, _, dom 格式
+    Domain []string
 }
 
 /**
@@ -164,6 +167,13 @@ func (this *Adapter) filterQuery(db *gorm.DB, filter Filter) func(db *gorm.DB) *
         if len(filter.V5) > 0 {
             db = db.Where("v5 in (?)", filter.V5)
         }
+        if len(filter.Domain) > 0 {
+            db = db.Where(
+                "((ptype LIKE 'p%' AND v1 in (?)) OR (ptype LIKE 'g%' AND v2 in (?)))",
+                filter.Domain,
+                filter.Domain,
+            )
+        }
         return db
     }
 }
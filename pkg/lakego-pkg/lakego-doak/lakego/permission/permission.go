@@ -6,6 +6,9 @@ import (
     "github.com/deatil/lakego-doak/lakego/permission/interfaces"
 )
 
+// 默认域，没有指定域的规则和验证使用
+const DefaultDomain = "default"
+
 // 构造函数
 func New(adapter interfaces.Adapter, modelConf string) *Permission {
     perm := &Permission{}
@@ -105,52 +108,52 @@ func (this *Permission) GetEnforcer() *casbin.Enforcer {
 // this.GetEnforcer().AddFunction(name string, function govaluate.ExpressionFunction)
 
 /**
- * 添加用户角色
+ * 添加用户角色，不传域时使用默认域
  */
 func (this *Permission) AddRoleForUser(user string, role string, domain ...string) (bool, error) {
-    return this.GetEnforcer().AddRoleForUser(user, role, domain...)
+    return this.GetEnforcer().AddRoleForUser(user, role, withDomain(domain)...)
 }
 
 /**
  * 批量添加用户角色
  */
 func (this *Permission) AddRolesForUser(user string, roles []string, domain ...string) (bool, error) {
-    return this.GetEnforcer().AddRolesForUser(user, roles, domain...)
+    return this.GetEnforcer().AddRolesForUser(user, roles, withDomain(domain)...)
 }
 
 /**
- * 用户角色是否拥有某角色
+ * 用户角色是否拥有某角色，不传域时使用默认域
  */
-func (this *Permission) HasRoleForUser(user string, role string) (bool, error) {
-    return this.GetEnforcer().HasRoleForUser(user, role)
+func (this *Permission) HasRoleForUser(user string, role string, domain ...string) (bool, error) {
+    return this.GetEnforcer().HasRoleForUser(user, role, withDomain(domain)...)
 }
 
 /**
  * 用户的全部角色
  */
 func (this *Permission) GetRolesForUser(name string, domain ...string) ([]string, error) {
-    return this.GetEnforcer().GetRolesForUser(name, domain...)
+    return this.GetEnforcer().GetRolesForUser(name, withDomain(domain)...)
 }
 
 /**
  * 角色的全部用户
  */
 func (this *Permission) GetUsersForRole(name string, domain ...string) ([]string, error) {
-    return this.GetEnforcer().GetUsersForRole(name, domain...)
+    return this.GetEnforcer().GetUsersForRole(name, withDomain(domain)...)
 }
 
 /**
- * 删除用户角色
+ * 删除用户角色，不传域时使用默认域
  */
-func (this *Permission) DeleteRoleForUser(user string, role string) (bool, error) {
-    return this.GetEnforcer().DeleteRoleForUser(user, role)
+func (this *Permission) DeleteRoleForUser(user string, role string, domain ...string) (bool, error) {
+    return this.GetEnforcer().DeleteRoleForUser(user, role, withDomain(domain)...)
 }
 
 /**
- * 删除用户所有角色
+ * 删除用户所有角色，不传域时使用默认域
  */
-func (this *Permission) DeleteRolesForUser(user string) (bool, error) {
-    return this.GetEnforcer().DeleteRolesForUser(user)
+func (this *Permission) DeleteRolesForUser(user string, domain ...string) (bool, error) {
+    return this.GetEnforcer().DeleteRolesForUser(user, withDomain(domain)...)
 }
 
 /**
@@ -161,31 +164,31 @@ func (this *Permission) DeleteUser(user string) (bool, error) {
 }
 
 /**
- * 添加权限
+ * 添加权限，使用默认域
  */
 func (this *Permission) AddPolicy(user string, ptype string, rule string) (bool, error) {
-    return this.GetEnforcer().AddPolicy(user, ptype, rule)
+    return this.AddPolicyInDomain(user, DefaultDomain, ptype, rule)
 }
 
 /**
- * 删除权限
+ * 删除权限，使用默认域
  */
 func (this *Permission) DeletePolicy(user string, ptype string, rule string) (bool, error) {
-    return this.GetEnforcer().DeletePermissionForUser(user, ptype, rule)
+    return this.DeletePolicyInDomain(user, DefaultDomain, ptype, rule)
 }
 
 /**
- * 删除标识所有权限
+ * 删除标识所有权限，不传域时使用默认域
  */
-func (this *Permission) DeletePolicys(user string) (bool, error) {
-    return this.GetEnforcer().DeletePermissionForUser(user)
+func (this *Permission) DeletePolicys(user string, domain ...string) (bool, error) {
+    return this.GetEnforcer().RemoveFilteredPolicy(0, user, withDomain(domain)[0])
 }
 
 /**
- * 判断是否有权限
+ * 判断是否有权限，使用默认域
  */
 func (this *Permission) HasPermissionForUser(user string, ptype string, rule string) bool {
-    return this.GetEnforcer().HasPermissionForUser(user, ptype, rule)
+    return this.GetEnforcer().HasPolicy(user, DefaultDomain, ptype, rule)
 }
 
 /**
@@ -234,21 +237,21 @@ func (this *Permission) GetPermissionsForUser(user string) [][]string {
  * 全部角色
  */
 func (this *Permission) GetImplicitRolesForUser(user string, domain ...string) ([]string, error) {
-    return this.GetEnforcer().GetImplicitRolesForUser(user, domain...)
+    return this.GetEnforcer().GetImplicitRolesForUser(user, withDomain(domain)...)
 }
 
 /**
  * 角色的用户
  */
 func (this *Permission) GetImplicitUsersForRole(user string, domain ...string) ([]string, error) {
-    return this.GetEnforcer().GetImplicitUsersForRole(user, domain...)
+    return this.GetEnforcer().GetImplicitUsersForRole(user, withDomain(domain)...)
 }
 
 /**
  * 用户的全部权限
  */
 func (this *Permission) GetImplicitPermissionsForUser(user string, domain ...string) ([][]string, error) {
-    return this.GetEnforcer().GetImplicitPermissionsForUser(user, domain...)
+    return this.GetEnforcer().GetImplicitPermissionsForUser(user, withDomain(domain)...)
 }
 
 /**
@@ -262,7 +265,7 @@ func (this *Permission) GetImplicitUsersForPermission(permission ...string) ([]s
  * 用户的全部决策器
  */
 func (this *Permission) GetImplicitResourcesForUser(user string, domain ...string) ([][]string, error) {
-    return this.GetEnforcer().GetImplicitResourcesForUser(user, domain...)
+    return this.GetEnforcer().GetImplicitResourcesForUser(user, withDomain(domain)...)
 }
 
 /**
@@ -273,8 +276,102 @@ func (this *Permission) GetDomainsForUser(user string) ([]string, error) {
 }
 
 /**
- * 验证用户权限
+ * 验证用户权限，使用默认域
  */
 func (this *Permission) Enforce(user string, ptype string, rule string) (bool, error) {
-    return this.GetEnforcer().Enforce(user, ptype, rule)
+    return this.EnforceInDomain(user, DefaultDomain, ptype, rule)
+}
+
+/**
+ * 添加域内用户角色
+ */
+func (this *Permission) AddRoleForUserInDomain(user string, role string, domain string) (bool, error) {
+    return this.GetEnforcer().AddRoleForUserInDomain(user, role, domain)
+}
+
+/**
+ * 删除域内用户角色
+ */
+func (this *Permission) DeleteRoleForUserInDomain(user string, role string, domain string) (bool, error) {
+    return this.GetEnforcer().DeleteRoleForUserInDomain(user, role, domain)
+}
+
+/**
+ * 删除域内用户所有角色
+ */
+func (this *Permission) DeleteRolesForUserInDomain(user string, domain string) (bool, error) {
+    return this.GetEnforcer().DeleteRolesForUserInDomain(user, domain)
+}
+
+/**
+ * 域内用户的全部角色
+ */
+func (this *Permission) GetRolesForUserInDomain(user string, domain string) []string {
+    return this.GetEnforcer().GetRolesForUserInDomain(user, domain)
+}
+
+/**
+ * 域内角色的全部用户
+ */
+func (this *Permission) GetUsersForRoleInDomain(role string, domain string) []string {
+    return this.GetEnforcer().GetUsersForRoleInDomain(role, domain)
+}
+
+/**
+ * 添加域内权限
+ */
+func (this *Permission) AddPolicyInDomain(user string, domain string, ptype string, rule string) (bool, error) {
+    return this.GetEnforcer().AddPolicy(user, domain, ptype, rule)
+}
+
+/**
+ * 删除域内权限
+ */
+func (this *Permission) DeletePolicyInDomain(user string, domain string, ptype string, rule string) (bool, error) {
+    return this.GetEnforcer().RemovePolicy(user, domain, ptype, rule)
+}
+
+/**
+ * 域内用户的全部权限
+ */
+func (this *Permission) GetPermissionsForUserInDomain(user string, domain string) [][]string {
+    return this.GetEnforcer().GetPermissionsForUserInDomain(user, domain)
+}
+
+/**
+ * 全部域
+ */
+func (this *Permission) GetAllDomains() ([]string, error) {
+    return this.GetEnforcer().GetAllDomains()
+}
+
+/**
+ * 删除域的全部角色和权限
+ * 不传域时只清空已加载的规则，不会删除存储的数据
+ */
+func (this *Permission) DeleteDomains(domain ...string) (bool, error) {
+    return this.GetEnforcer().DeleteDomains(domain...)
+}
+
+/**
+ * 验证域内用户权限
+ */
+func (this *Permission) EnforceInDomain(user string, domain string, ptype string, rule string) (bool, error) {
+    return this.GetEnforcer().Enforce(user, domain, ptype, rule)
+}
+
+/**
+ * 只加载符合条件的规则，条件格式由适配器决定
+ */
+func (this *Permission) LoadFilteredPolicy(filter any) error {
+    return this.GetEnforcer().LoadFilteredPolicy(filter)
+}
+
+// 没有指定域时使用默认域
+func withDomain(domain []string) []string {
+    if len(domain) > 0 && domain[0] != "" {
+        return domain[:1]
+    }
+
+    return []string{DefaultDomain}
 }
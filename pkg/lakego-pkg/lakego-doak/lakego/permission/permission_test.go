@@ -0,0 +1,169 @@
+package permission
+
+import (
+    "testing"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/driver/sqlite"
+
+    gorm_adapter "github.com/deatil/lakego-doak/lakego/permission/adapter/gorm"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func newTestPermission(t *testing.T) (*Permission, *gorm.DB) {
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/test.db"), &gorm.Config{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err := db.AutoMigrate(&gorm_adapter.Rules{}); err != nil {
+        t.Fatal(err)
+    }
+
+    adapter, err := gorm_adapter.New(db)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    perm := New(adapter, "../../../../testdata/config/rbac_model.conf")
+    if perm.GetEnforcer() == nil {
+        t.Fatal("enforcer is nil")
+    }
+
+    return perm, db
+}
+
+func Test_EnforceInDomain_Isolation(t *testing.T) {
+    eq := assertT(t)
+
+    perm, _ := newTestPermission(t)
+
+    // 两个租户使用相同的分组 ID 和账号
+    perm.AddPolicyInDomain("group-1", "tenant-a", "/admin/user", "GET")
+    perm.AddPolicyInDomain("group-1", "tenant-b", "/admin/log", "GET")
+    perm.AddRoleForUserInDomain("user-1", "group-1", "tenant-a")
+    perm.AddRoleForUserInDomain("user-2", "group-1", "tenant-b")
+
+    ok, _ := perm.EnforceInDomain("user-1", "tenant-a", "/admin/user", "GET")
+    eq(ok, true, "tenant-a own rule")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-a", "/admin/log", "GET")
+    eq(ok, false, "tenant-a other tenant rule")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-b", "/admin/log", "GET")
+    eq(ok, false, "tenant-a user in tenant-b")
+
+    ok, _ = perm.EnforceInDomain("user-2", "tenant-b", "/admin/log", "GET")
+    eq(ok, true, "tenant-b own rule")
+
+    ok, _ = perm.EnforceInDomain("user-2", "tenant-b", "/admin/user", "GET")
+    eq(ok, false, "tenant-b other tenant rule")
+
+    ok, _ = perm.EnforceInDomain("user-2", "tenant-a", "/admin/user", "GET")
+    eq(ok, false, "tenant-b user in tenant-a")
+
+    // 没有指定域的验证不能使用租户的规则
+    ok, _ = perm.Enforce("user-1", "/admin/user", "GET")
+    eq(ok, false, "Enforce tenant rule")
+
+    // 删除域只影响当前域
+    perm.DeleteDomains("tenant-a")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-a", "/admin/user", "GET")
+    eq(ok, false, "tenant-a after DeleteDomains")
+
+    ok, _ = perm.EnforceInDomain("user-2", "tenant-b", "/admin/log", "GET")
+    eq(ok, true, "tenant-b after DeleteDomains")
+}
+
+func Test_Enforce_DefaultDomain(t *testing.T) {
+    eq := assertT(t)
+
+    perm, db := newTestPermission(t)
+
+    perm.AddPolicy("group-1", "/admin/user", "GET")
+    perm.AddRoleForUserInDomain("user-1", "group-1", DefaultDomain)
+
+    ok, _ := perm.Enforce("user-1", "/admin/user", "GET")
+    eq(ok, true, "Enforce")
+
+    ok, _ = perm.EnforceInDomain("user-1", DefaultDomain, "/admin/user", "GET")
+    eq(ok, true, "EnforceInDomain default")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-a", "/admin/user", "GET")
+    eq(ok, false, "EnforceInDomain tenant-a")
+
+    eq(perm.HasPermissionForUser("group-1", "/admin/user", "GET"), true, "HasPermissionForUser")
+
+    // 存储的规则带有默认域
+    var rule gorm_adapter.Rules
+    db.Where("ptype = ?", "p").First(&rule)
+    eq([]string{rule.V0, rule.V1, rule.V2, rule.V3}, []string{"group-1", DefaultDomain, "/admin/user", "GET"}, "stored rule")
+
+    perm.DeletePolicy("group-1", "/admin/user", "GET")
+
+    ok, _ = perm.Enforce("user-1", "/admin/user", "GET")
+    eq(ok, false, "Enforce after DeletePolicy")
+    eq(perm.HasPermissionForUser("group-1", "/admin/user", "GET"), false, "HasPermissionForUser after DeletePolicy")
+}
+
+func Test_Roles_DefaultDomain(t *testing.T) {
+    eq := assertT(t)
+
+    perm, db := newTestPermission(t)
+
+    perm.AddPolicy("group-1", "/admin/user", "GET")
+    perm.AddPolicyInDomain("group-1", "tenant-a", "/admin/user", "GET")
+
+    perm.AddRoleForUser("user-1", "group-1")
+    perm.AddRoleForUser("user-1", "group-1", "tenant-a")
+
+    // 存储的角色带有默认域
+    var count int64
+    db.Model(&gorm_adapter.Rules{}).
+        Where("ptype = ? AND v0 = ? AND v1 = ? AND v2 = ?", "g", "user-1", "group-1", DefaultDomain).
+        Count(&count)
+    eq(count, int64(1), "stored role")
+
+    ok, _ := perm.Enforce("user-1", "/admin/user", "GET")
+    eq(ok, true, "Enforce")
+
+    ok, _ = perm.HasRoleForUser("user-1", "group-1")
+    eq(ok, true, "HasRoleForUser")
+
+    roles, _ := perm.GetRolesForUser("user-1")
+    eq(roles, []string{"group-1"}, "GetRolesForUser")
+
+    perm.DeleteRoleForUser("user-1", "group-1")
+
+    ok, _ = perm.HasRoleForUser("user-1", "group-1")
+    eq(ok, false, "HasRoleForUser after DeleteRoleForUser")
+
+    ok, _ = perm.HasRoleForUser("user-1", "group-1", "tenant-a")
+    eq(ok, true, "HasRoleForUser tenant-a")
+
+    perm.AddRoleForUser("user-1", "group-1")
+    perm.DeleteRolesForUser("user-1")
+
+    ok, _ = perm.Enforce("user-1", "/admin/user", "GET")
+    eq(ok, false, "Enforce after DeleteRolesForUser")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-a", "/admin/user", "GET")
+    eq(ok, true, "EnforceInDomain tenant-a")
+
+    // 只删除默认域的权限
+    perm.DeletePolicys("group-1")
+
+    eq(perm.HasPermissionForUser("group-1", "/admin/user", "GET"), false, "DeletePolicys")
+
+    ok, _ = perm.EnforceInDomain("user-1", "tenant-a", "/admin/user", "GET")
+    eq(ok, true, "DeletePolicys tenant-a")
+}
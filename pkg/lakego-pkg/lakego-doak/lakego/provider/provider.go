@@ -8,6 +8,7 @@ import (
     "github.com/deatil/lakego-doak/lakego/router"
     "github.com/deatil/lakego-doak/lakego/publish"
     "github.com/deatil/lakego-doak/lakego/command"
+    "github.com/deatil/lakego-doak/lakego/migration"
     "github.com/deatil/lakego-doak/lakego/facade/config"
     "github.com/deatil/lakego-doak/lakego/config/adapter"
     path_tool "github.com/deatil/lakego-doak/lakego/path"
@@ -173,6 +174,16 @@ func (this *ServiceProvider) Publishes(obj any, paths map[string]string, group s
     publish.Instance().Publish(obj, paths, group)
 }
 
+// 添加数据库迁移
+func (this *ServiceProvider) AddMigrations(group string, migrations ...migration.Migration) {
+    migration.Register(group, migrations...)
+}
+
+// 添加数据填充
+func (this *ServiceProvider) AddSeeders(group string, seeders ...migration.Seeder) {
+    migration.RegisterSeeder(group, seeders...)
+}
+
 // 注册
 func (this *ServiceProvider) Register() {
     // 注册
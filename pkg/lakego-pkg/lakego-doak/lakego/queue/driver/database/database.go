@@ -0,0 +1,180 @@
+package database
+
+import (
+    "time"
+    "strconv"
+
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+)
+
+/**
+ * 队列任务表
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Job struct {
+    ID          uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+    Queue       string `gorm:"column:queue;size:100;index"`
+    Payload     string `gorm:"column:payload;type:text"`
+    Attempts    int    `gorm:"column:attempts"`
+    ReservedAt  int64  `gorm:"column:reserved_at"`
+    AvailableAt int64  `gorm:"column:available_at"`
+    CreatedAt   int64  `gorm:"column:created_at"`
+}
+
+/**
+ * 数据库队列
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Database struct {
+    // 数据库
+    db *gorm.DB
+
+    // 表名
+    table string
+
+    // 保留时间，超时未确认的任务重新执行
+    retryAfter time.Duration
+}
+
+// 构造函数
+func New(db *gorm.DB, table string, retryAfter time.Duration) *Database {
+    if table == "" {
+        table = "jobs"
+    }
+
+    if retryAfter <= 0 {
+        retryAfter = 90 * time.Second
+    }
+
+    return &Database{
+        db:         db,
+        table:      db.NamingStrategy.TableName(table),
+        retryAfter: retryAfter,
+    }
+}
+
+// 推送
+func (this *Database) Push(queue string, body []byte, delay time.Duration) error {
+    now := time.Now()
+
+    return this.query().Create(&Job{
+        Queue:       queue,
+        Payload:     string(body),
+        AvailableAt: now.Add(delay).Unix(),
+        CreatedAt:   now.Unix(),
+    }).Error
+}
+
+// 取出，使用条件更新保证同一任务只被一个执行者取出，同时递增取出次数
+func (this *Database) Pop(queue string) (*interfaces.Message, error) {
+    for i := 0; i < 3; i++ {
+        now := time.Now().Unix()
+        expired := time.Now().Add(-this.retryAfter).Unix()
+
+        jobs := make([]Job, 0, 1)
+        err := this.query().
+            Where("queue = ?", queue).
+            Where(
+                this.db.Where("reserved_at = 0 AND available_at <= ?", now).
+                    Or("reserved_at > 0 AND reserved_at <= ?", expired),
+            ).
+            Order("id ASC").
+            Limit(1).
+            Find(&jobs).
+            Error
+        if err != nil {
+            return nil, err
+        }
+
+        if len(jobs) == 0 {
+            return nil, nil
+        }
+
+        job := jobs[0]
+
+        res := this.query().
+            Where("id = ? AND reserved_at = ?", job.ID, job.ReservedAt).
+            Updates(map[string]any{
+                "reserved_at": now,
+                "attempts":    gorm.Expr("attempts + 1"),
+            })
+        if res.Error != nil {
+            return nil, res.Error
+        }
+
+        // 已被其他执行者取出
+        if res.RowsAffected == 0 {
+            continue
+        }
+
+        return &interfaces.Message{
+            ID:       strconv.FormatUint(job.ID, 10),
+            Queue:    job.Queue,
+            Body:     []byte(job.Payload),
+            Attempts: job.Attempts + 1,
+        }, nil
+    }
+
+    return nil, nil
+}
+
+// 确认删除
+func (this *Database) Delete(msg *interfaces.Message) error {
+    return this.query().
+        Where("id = ?", msg.ID).
+        Delete(&Job{}).
+        Error
+}
+
+// 放回队列
+func (this *Database) Release(msg *interfaces.Message, body []byte, delay time.Duration) error {
+    return this.query().
+        Where("id = ?", msg.ID).
+        Updates(map[string]any{
+            "payload":      string(body),
+            "reserved_at":  0,
+            "available_at": time.Now().Add(delay).Unix(),
+        }).
+        Error
+}
+
+// 队列数量
+func (this *Database) Size(queue string) (int64, error) {
+    var count int64
+
+    err := this.query().
+        Where("queue = ?", queue).
+        Count(&count).
+        Error
+
+    return count, err
+}
+
+// 查询
+func (this *Database) query() *gorm.DB {
+    return this.db.Table(this.table)
+}
+
+// 建表迁移，使用队列的数据库连接
+func (this *Database) Migration() migration.Migration {
+    return migration.Migration{
+        Name: "2026_10_18_000001_create_" + this.table + "_table",
+        Up: func(*gorm.DB) error {
+            if this.db.Migrator().HasTable(this.table) {
+                return nil
+            }
+
+            return this.db.Table(this.table).Migrator().CreateTable(&Job{})
+        },
+        Down: func(*gorm.DB) error {
+            return this.db.Migrator().DropTable(this.table)
+        },
+    }
+}
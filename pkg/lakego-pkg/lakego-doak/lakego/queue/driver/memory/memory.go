@@ -0,0 +1,142 @@
+package memory
+
+import (
+    "sync"
+    "time"
+    "errors"
+    "strconv"
+
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+)
+
+// 队列数据
+type item struct {
+    // ID
+    id string
+
+    // 队列
+    queue string
+
+    // 内容
+    body []byte
+
+    // 可执行时间
+    availableAt time.Time
+
+    // 取出次数
+    attempts int
+}
+
+/**
+ * 内存队列
+ *
+ * 数据只保存在当前进程，适合开发和测试
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Memory struct {
+    // 锁
+    mu sync.Mutex
+
+    // 队列
+    queues map[string][]*item
+
+    // 执行中
+    reserved map[string]*item
+
+    // 自增 ID
+    seq int64
+}
+
+// 构造函数
+func New() *Memory {
+    return &Memory{
+        queues:   make(map[string][]*item),
+        reserved: make(map[string]*item),
+    }
+}
+
+// 推送
+func (this *Memory) Push(queue string, body []byte, delay time.Duration) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.seq++
+
+    this.queues[queue] = append(this.queues[queue], &item{
+        id:          strconv.FormatInt(this.seq, 10),
+        queue:       queue,
+        body:        body,
+        availableAt: time.Now().Add(delay),
+    })
+
+    return nil
+}
+
+// 取出
+func (this *Memory) Pop(queue string) (*interfaces.Message, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    now := time.Now()
+
+    items := this.queues[queue]
+    for i, it := range items {
+        if it.availableAt.After(now) {
+            continue
+        }
+
+        this.queues[queue] = append(items[:i:i], items[i+1:]...)
+        this.reserved[it.id] = it
+
+        it.attempts++
+
+        return &interfaces.Message{
+            ID:       it.id,
+            Queue:    it.queue,
+            Body:     it.body,
+            Attempts: it.attempts,
+        }, nil
+    }
+
+    return nil, nil
+}
+
+// 确认删除
+func (this *Memory) Delete(msg *interfaces.Message) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    delete(this.reserved, msg.ID)
+
+    return nil
+}
+
+// 放回队列
+func (this *Memory) Release(msg *interfaces.Message, body []byte, delay time.Duration) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    it, ok := this.reserved[msg.ID]
+    if !ok {
+        return errors.New("memory queue: message not reserved")
+    }
+
+    delete(this.reserved, msg.ID)
+
+    it.body = body
+    it.availableAt = time.Now().Add(delay)
+
+    this.queues[it.queue] = append(this.queues[it.queue], it)
+
+    return nil
+}
+
+// 队列数量
+func (this *Memory) Size(queue string) (int64, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    return int64(len(this.queues[queue])), nil
+}
@@ -0,0 +1,209 @@
+package redis
+
+import (
+    "time"
+    "context"
+    "strconv"
+    "crypto/sha1"
+    "encoding/hex"
+
+    "github.com/go-redis/redis/v8"
+
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+)
+
+// 取出脚本，先把到期的延迟任务和超时的执行中任务放回队列，
+// 取出时在同一脚本内递增取出次数，次数按任务内容的 sha1 保存
+var popScript = redis.NewScript(`
+local function migrate(from, to, now)
+    local val = redis.call('zrangebyscore', from, '-inf', now)
+    if next(val) ~= nil then
+        redis.call('zremrangebyrank', from, 0, #val - 1)
+        for i = 1, #val, 100 do
+            redis.call('rpush', to, unpack(val, i, math.min(i + 99, #val)))
+        end
+    end
+end
+
+migrate(KEYS[2], KEYS[1], ARGV[1])
+migrate(KEYS[3], KEYS[1], ARGV[1])
+
+local job = redis.call('lpop', KEYS[1])
+if not job then
+    return false
+end
+
+redis.call('zadd', KEYS[3], ARGV[2], job)
+
+local attempts = redis.call('hincrby', KEYS[4], redis.sha1hex(job), 1)
+
+return {job, attempts}
+`)
+
+/**
+ * redis 队列
+ *
+ * 使用列表保存待执行任务，有序集合保存延迟和执行中任务
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Redis struct {
+    // 上下文
+    ctx context.Context
+
+    // 客户端
+    client *redis.Client
+
+    // 前缀
+    prefix string
+
+    // 保留时间，超时未确认的任务重新执行
+    retryAfter time.Duration
+}
+
+// 构造函数
+func New(client *redis.Client, prefix string, retryAfter time.Duration) *Redis {
+    if prefix == "" {
+        prefix = "queues"
+    }
+
+    if retryAfter <= 0 {
+        retryAfter = 90 * time.Second
+    }
+
+    return &Redis{
+        ctx:        context.Background(),
+        client:     client,
+        prefix:     prefix,
+        retryAfter: retryAfter,
+    }
+}
+
+// 推送
+func (this *Redis) Push(queue string, body []byte, delay time.Duration) error {
+    if delay > 0 {
+        return this.client.ZAdd(this.ctx, this.delayedKey(queue), &redis.Z{
+            Score:  float64(time.Now().Add(delay).Unix()),
+            Member: string(body),
+        }).Err()
+    }
+
+    return this.client.RPush(this.ctx, this.queueKey(queue), string(body)).Err()
+}
+
+// 取出
+func (this *Redis) Pop(queue string) (*interfaces.Message, error) {
+    now := time.Now()
+
+    res, err := popScript.Run(this.ctx, this.client,
+        []string{
+            this.queueKey(queue),
+            this.delayedKey(queue),
+            this.reservedKey(queue),
+            this.attemptsKey(queue),
+        },
+        strconv.FormatInt(now.Unix(), 10),
+        strconv.FormatInt(now.Add(this.retryAfter).Unix(), 10),
+    ).Result()
+    if err == redis.Nil {
+        return nil, nil
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    data, ok := res.([]any)
+    if !ok || len(data) != 2 {
+        return nil, nil
+    }
+
+    body, _ := data[0].(string)
+    attempts, _ := data[1].(int64)
+
+    return &interfaces.Message{
+        ID:       body,
+        Queue:    queue,
+        Body:     []byte(body),
+        Attempts: int(attempts),
+    }, nil
+}
+
+// 确认删除
+func (this *Redis) Delete(msg *interfaces.Message) error {
+    _, err := this.client.TxPipelined(this.ctx, func(pipe redis.Pipeliner) error {
+        pipe.ZRem(this.ctx, this.reservedKey(msg.Queue), msg.ID)
+        pipe.HDel(this.ctx, this.attemptsKey(msg.Queue), bodyHash(msg.ID))
+
+        return nil
+    })
+
+    return err
+}
+
+// 放回队列
+func (this *Redis) Release(msg *interfaces.Message, body []byte, delay time.Duration) error {
+    _, err := this.client.TxPipelined(this.ctx, func(pipe redis.Pipeliner) error {
+        pipe.ZRem(this.ctx, this.reservedKey(msg.Queue), msg.ID)
+
+        // 放回的任务内容会变化，取出次数跟随新的内容
+        pipe.HDel(this.ctx, this.attemptsKey(msg.Queue), bodyHash(msg.ID))
+        pipe.HSet(this.ctx, this.attemptsKey(msg.Queue), bodyHash(string(body)), msg.Attempts)
+
+        if delay > 0 {
+            pipe.ZAdd(this.ctx, this.delayedKey(msg.Queue), &redis.Z{
+                Score:  float64(time.Now().Add(delay).Unix()),
+                Member: string(body),
+            })
+        } else {
+            pipe.RPush(this.ctx, this.queueKey(msg.Queue), string(body))
+        }
+
+        return nil
+    })
+
+    return err
+}
+
+// 队列数量，包括延迟和执行中任务
+func (this *Redis) Size(queue string) (int64, error) {
+    pipe := this.client.Pipeline()
+
+    llen := pipe.LLen(this.ctx, this.queueKey(queue))
+    delayed := pipe.ZCard(this.ctx, this.delayedKey(queue))
+    reserved := pipe.ZCard(this.ctx, this.reservedKey(queue))
+
+    if _, err := pipe.Exec(this.ctx); err != nil {
+        return 0, err
+    }
+
+    return llen.Val() + delayed.Val() + reserved.Val(), nil
+}
+
+// 队列
+func (this *Redis) queueKey(queue string) string {
+    return this.prefix + ":" + queue
+}
+
+// 延迟队列
+func (this *Redis) delayedKey(queue string) string {
+    return this.queueKey(queue) + ":delayed"
+}
+
+// 执行中队列
+func (this *Redis) reservedKey(queue string) string {
+    return this.queueKey(queue) + ":reserved"
+}
+
+// 取出次数
+func (this *Redis) attemptsKey(queue string) string {
+    return this.queueKey(queue) + ":attempts"
+}
+
+// 任务内容的 sha1，与脚本中的 redis.sha1hex 一致
+func bodyHash(body string) string {
+    sum := sha1.Sum([]byte(body))
+
+    return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,157 @@
+package queue
+
+import (
+    "errors"
+
+    "gorm.io/gorm"
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+)
+
+/**
+ * 失败任务
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type FailedJob struct {
+    ID         uint   `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+    UUID       string `gorm:"column:uuid;size:64;uniqueIndex" json:"uuid"`
+    Connection string `gorm:"column:connection;size:100" json:"connection"`
+    Queue      string `gorm:"column:queue;size:100" json:"queue"`
+    Payload    string `gorm:"column:payload;type:text" json:"payload"`
+    Exception  string `gorm:"column:exception;type:text" json:"exception"`
+    FailedAt   int64  `gorm:"column:failed_at" json:"failed_at"`
+}
+
+// 失败任务存储接口
+type FailedProvider interface {
+    // 记录
+    Log(connection string, queue string, job *Job, err error) error
+
+    // 全部
+    All() ([]FailedJob, error)
+
+    // 获取
+    Find(uuid string) (FailedJob, error)
+
+    // 删除
+    Forget(uuid string) error
+
+    // 清空
+    Flush() error
+}
+
+/**
+ * 数据库失败任务存储
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type DatabaseFailedProvider struct {
+    // 数据库
+    db *gorm.DB
+
+    // 表名
+    table string
+}
+
+// 构造函数
+func NewDatabaseFailedProvider(db *gorm.DB, table string) *DatabaseFailedProvider {
+    if table == "" {
+        table = "failed_jobs"
+    }
+
+    return &DatabaseFailedProvider{
+        db:    db,
+        table: db.NamingStrategy.TableName(table),
+    }
+}
+
+// 记录
+func (this *DatabaseFailedProvider) Log(connection string, queue string, job *Job, err error) error {
+    payload, e := job.Marshal()
+    if e != nil {
+        return e
+    }
+
+    exception := ""
+    if err != nil {
+        exception = err.Error()
+    }
+
+    return this.query().Create(&FailedJob{
+        UUID:       job.ID,
+        Connection: connection,
+        Queue:      queue,
+        Payload:    string(payload),
+        Exception:  exception,
+        FailedAt:   datebin.NowTimestamp(),
+    }).Error
+}
+
+// 全部
+func (this *DatabaseFailedProvider) All() ([]FailedJob, error) {
+    list := make([]FailedJob, 0)
+
+    err := this.query().
+        Order("id DESC").
+        Find(&list).
+        Error
+
+    return list, err
+}
+
+// 获取
+func (this *DatabaseFailedProvider) Find(uuid string) (FailedJob, error) {
+    var job FailedJob
+
+    err := this.query().
+        Where("uuid = ?", uuid).
+        First(&job).
+        Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return job, ErrFailedJobNotFound
+    }
+
+    return job, err
+}
+
+// 删除
+func (this *DatabaseFailedProvider) Forget(uuid string) error {
+    return this.query().
+        Where("uuid = ?", uuid).
+        Delete(&FailedJob{}).
+        Error
+}
+
+// 清空
+func (this *DatabaseFailedProvider) Flush() error {
+    return this.query().
+        Where("1 = 1").
+        Delete(&FailedJob{}).
+        Error
+}
+
+// 查询
+func (this *DatabaseFailedProvider) query() *gorm.DB {
+    return this.db.Table(this.table)
+}
+
+// 建表迁移，使用失败任务的数据库连接
+func (this *DatabaseFailedProvider) Migration() migration.Migration {
+    return migration.Migration{
+        Name: "2026_10_18_000002_create_" + this.table + "_table",
+        Up: func(*gorm.DB) error {
+            if this.db.Migrator().HasTable(this.table) {
+                return nil
+            }
+
+            return this.db.Table(this.table).Migrator().CreateTable(&FailedJob{})
+        },
+        Down: func(*gorm.DB) error {
+            return this.db.Migrator().DropTable(this.table)
+        },
+    }
+}
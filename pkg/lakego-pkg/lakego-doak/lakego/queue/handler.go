@@ -0,0 +1,63 @@
+package queue
+
+import (
+    "sync"
+    "context"
+)
+
+// 默认
+var defaultHandlers = NewHandlers()
+
+// 任务处理
+type Handler func(ctx context.Context, job *Job) error
+
+/**
+ * 任务处理列表
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Handlers struct {
+    // 锁
+    mu sync.RWMutex
+
+    // 列表
+    handlers map[string]Handler
+}
+
+// 构造函数
+func NewHandlers() *Handlers {
+    return &Handlers{
+        handlers: make(map[string]Handler),
+    }
+}
+
+// 注册
+func (this *Handlers) Handle(name string, handler Handler) *Handlers {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    this.handlers[name] = handler
+
+    return this
+}
+
+// 获取
+func (this *Handlers) Get(name string) (Handler, bool) {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    handler, ok := this.handlers[name]
+
+    return handler, ok
+}
+
+// 注册任务处理
+func Handle(name string, handler Handler) *Handlers {
+    return defaultHandlers.Handle(name, handler)
+}
+
+// 默认任务处理列表
+func DefaultHandlers() *Handlers {
+    return defaultHandlers
+}
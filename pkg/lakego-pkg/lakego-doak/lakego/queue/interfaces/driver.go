@@ -0,0 +1,47 @@
+package interfaces
+
+import (
+    "time"
+)
+
+// 消息
+type Message struct {
+    // 驱动内的标识，确认和重新放回时使用
+    ID string
+
+    // 队列名称
+    Queue string
+
+    // 内容
+    Body []byte
+
+    // 取出次数，包括本次，驱动在取出时原子递增，
+    // 执行者异常退出后重新取出时同样计数
+    Attempts int
+}
+
+/**
+ * 队列驱动接口
+ *
+ * 取出的消息需要调用 Delete 确认或者 Release 放回，
+ * 超过保留时间没有确认的消息会重新进入队列
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Driver interface {
+    // 推送，delay 大于 0 时延迟执行
+    Push(queue string, body []byte, delay time.Duration) error
+
+    // 取出，队列为空时返回 nil，同时递增取出次数
+    Pop(queue string) (*Message, error)
+
+    // 确认删除
+    Delete(msg *Message) error
+
+    // 放回队列
+    Release(msg *Message, body []byte, delay time.Duration) error
+
+    // 队列数量
+    Size(queue string) (int64, error)
+}
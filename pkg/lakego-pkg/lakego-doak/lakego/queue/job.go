@@ -0,0 +1,123 @@
+package queue
+
+import (
+    "time"
+    "encoding/json"
+)
+
+// 默认队列
+const DefaultQueue = "default"
+
+/**
+ * 任务
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Job struct {
+    // 任务 ID
+    ID string `json:"id"`
+
+    // 处理名称
+    Name string `json:"name"`
+
+    // 队列名称
+    Queue string `json:"queue"`
+
+    // 任务数据
+    Payload json.RawMessage `json:"payload"`
+
+    // 已执行次数
+    Attempts int `json:"attempts"`
+
+    // 最大执行次数，0 为使用执行者配置
+    Tries int `json:"tries"`
+
+    // 重试间隔，单位：秒，按执行次数取值，超出时取最后一个
+    Backoff []int `json:"backoff"`
+
+    // 超时时间，单位：秒，0 为使用执行者配置
+    Timeout int `json:"timeout"`
+
+    // 添加时间
+    CreatedAt int64 `json:"created_at"`
+
+    // 延迟执行
+    Delay time.Duration `json:"-"`
+}
+
+// 解析任务数据
+func (this *Job) Unmarshal(v any) error {
+    return json.Unmarshal(this.Payload, v)
+}
+
+// 重试间隔
+func (this *Job) BackoffDelay() time.Duration {
+    if len(this.Backoff) == 0 {
+        return 0
+    }
+
+    index := this.Attempts - 1
+    if index < 0 {
+        index = 0
+    }
+
+    if index >= len(this.Backoff) {
+        index = len(this.Backoff) - 1
+    }
+
+    return time.Duration(this.Backoff[index]) * time.Second
+}
+
+// 编码
+func (this *Job) Marshal() ([]byte, error) {
+    return json.Marshal(this)
+}
+
+// 解码任务
+func DecodeJob(body []byte) (*Job, error) {
+    job := &Job{}
+    if err := json.Unmarshal(body, job); err != nil {
+        return nil, err
+    }
+
+    return job, nil
+}
+
+// 任务选项
+type Option func(*Job)
+
+// 队列名称
+func OnQueue(queue string) Option {
+    return func(job *Job) {
+        job.Queue = queue
+    }
+}
+
+// 延迟执行
+func Delay(delay time.Duration) Option {
+    return func(job *Job) {
+        job.Delay = delay
+    }
+}
+
+// 最大执行次数
+func Tries(tries int) Option {
+    return func(job *Job) {
+        job.Tries = tries
+    }
+}
+
+// 重试间隔，单位：秒
+func Backoff(seconds ...int) Option {
+    return func(job *Job) {
+        job.Backoff = seconds
+    }
+}
+
+// 超时时间
+func Timeout(timeout time.Duration) Option {
+    return func(job *Job) {
+        job.Timeout = int(timeout / time.Second)
+    }
+}
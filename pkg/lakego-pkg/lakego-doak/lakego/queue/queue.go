@@ -0,0 +1,146 @@
+package queue
+
+import (
+    "errors"
+    "encoding/json"
+
+    "github.com/deatil/go-datebin/datebin"
+
+    "github.com/deatil/lakego-doak/lakego/uuid"
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+)
+
+var (
+    // 没有设置失败任务存储
+    ErrNoFailedProvider = errors.New("queue: failed provider not set")
+
+    // 失败任务不存在
+    ErrFailedJobNotFound = errors.New("queue: failed job not found")
+)
+
+/**
+ * 队列
+ *
+ * q.Dispatch("send-mail", data, queue.OnQueue("emails"), queue.Tries(3))
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Queue struct {
+    // 连接名称
+    name string
+
+    // 驱动
+    driver interfaces.Driver
+
+    // 失败任务存储
+    failed FailedProvider
+}
+
+// 构造函数
+func New(name string, driver interfaces.Driver) *Queue {
+    return &Queue{
+        name:   name,
+        driver: driver,
+    }
+}
+
+// 设置失败任务存储
+func (this *Queue) WithFailed(failed FailedProvider) *Queue {
+    this.failed = failed
+
+    return this
+}
+
+// 获取失败任务存储
+func (this *Queue) GetFailed() FailedProvider {
+    return this.failed
+}
+
+// 获取驱动
+func (this *Queue) GetDriver() interfaces.Driver {
+    return this.driver
+}
+
+// 连接名称
+func (this *Queue) GetName() string {
+    return this.name
+}
+
+// 分发任务
+func (this *Queue) Dispatch(name string, payload any, opts ...Option) (*Job, error) {
+    data, err := json.Marshal(payload)
+    if err != nil {
+        return nil, err
+    }
+
+    job := &Job{
+        Name:    name,
+        Queue:   DefaultQueue,
+        Payload: data,
+    }
+
+    for _, opt := range opts {
+        opt(job)
+    }
+
+    return job, this.Push(job)
+}
+
+// 推送任务
+func (this *Queue) Push(job *Job) error {
+    if job.ID == "" {
+        job.ID = uuid.ToUUIDString()
+    }
+
+    if job.Queue == "" {
+        job.Queue = DefaultQueue
+    }
+
+    if job.CreatedAt == 0 {
+        job.CreatedAt = datebin.NowTimestamp()
+    }
+
+    body, err := job.Marshal()
+    if err != nil {
+        return err
+    }
+
+    return this.driver.Push(job.Queue, body, job.Delay)
+}
+
+// 队列数量
+func (this *Queue) Size(queue string) (int64, error) {
+    return this.driver.Size(queue)
+}
+
+// 重试失败任务
+func (this *Queue) Retry(id string) error {
+    if this.failed == nil {
+        return ErrNoFailedProvider
+    }
+
+    failed, err := this.failed.Find(id)
+    if err != nil {
+        return err
+    }
+
+    job, err := DecodeJob([]byte(failed.Payload))
+    if err != nil {
+        return err
+    }
+
+    job.Attempts = 0
+    job.Delay = 0
+
+    if err = this.Push(job); err != nil {
+        return err
+    }
+
+    return this.failed.Forget(id)
+}
+
+// 执行者
+func (this *Queue) Worker(opts WorkerOptions) *Worker {
+    return NewWorker(this, opts)
+}
@@ -0,0 +1,262 @@
+package queue
+
+import (
+    "time"
+    "errors"
+    "context"
+    "testing"
+    "reflect"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/schema"
+    "gorm.io/driver/sqlite"
+
+    "github.com/deatil/lakego-doak/lakego/queue/driver/memory"
+    "github.com/deatil/lakego-doak/lakego/queue/driver/database"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+    db, err := gorm.Open(sqlite.Open(t.TempDir() + "/queue.db"), &gorm.Config{
+        NamingStrategy: schema.NamingStrategy{
+            SingularTable: true,
+            TablePrefix:   "lakego_",
+        },
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return db
+}
+
+// 失败任务存储，先执行建表迁移
+func newTestFailed(t *testing.T, db *gorm.DB) *DatabaseFailedProvider {
+    failed := NewDatabaseFailedProvider(db, "")
+    if err := failed.Migration().Up(db); err != nil {
+        t.Fatal(err)
+    }
+
+    return failed
+}
+
+// 数据库队列，先执行建表迁移
+func newTestDriver(t *testing.T, db *gorm.DB, retryAfter time.Duration) *database.Database {
+    driver := database.New(db, "", retryAfter)
+    if err := driver.Migration().Up(db); err != nil {
+        t.Fatal(err)
+    }
+
+    return driver
+}
+
+func Test_Worker(t *testing.T) {
+    assert := assertT(t)
+
+    db := newTestDB(t)
+
+    q := New("memory", memory.New()).
+        WithFailed(newTestFailed(t, db))
+
+    calls := 0
+    handlers := NewHandlers().
+        Handle("ok", func(ctx context.Context, job *Job) error {
+            var data map[string]string
+            job.Unmarshal(&data)
+
+            assert(data["name"], "lakego", "Unmarshal")
+
+            return nil
+        }).
+        Handle("fail", func(ctx context.Context, job *Job) error {
+            calls++
+            return errors.New("fail job")
+        }).
+        Handle("panic", func(ctx context.Context, job *Job) error {
+            panic("panic job")
+        })
+
+    w := q.Worker(WorkerOptions{
+        Handlers: handlers,
+    })
+
+    q.Dispatch("ok", map[string]string{"name": "lakego"})
+
+    ok, err := w.RunOnce()
+    assert(ok, true, "RunOnce")
+    assert(err, nil, "RunOnce error")
+
+    size, _ := q.Size(DefaultQueue)
+    assert(size, int64(0), "Size")
+
+    job, _ := q.Dispatch("fail", nil, Tries(2))
+
+    w.RunOnce()
+    size, _ = q.Size(DefaultQueue)
+    assert(size, int64(1), "Released")
+
+    w.RunOnce()
+    assert(calls, 2, "Tries")
+
+    failed, err := q.GetFailed().Find(job.ID)
+    assert(err, nil, "Find error")
+    assert(failed.Exception, "fail job", "Exception")
+
+    err = q.Retry(job.ID)
+    assert(err, nil, "Retry")
+
+    size, _ = q.Size(DefaultQueue)
+    assert(size, int64(1), "Retry size")
+
+    _, err = q.GetFailed().Find(job.ID)
+    assert(err, ErrFailedJobNotFound, "Retry forget")
+
+    w.RunOnce()
+    w.RunOnce()
+
+    q.Dispatch("panic", nil)
+    w.RunOnce()
+
+    list, _ := q.GetFailed().All()
+    assert(len(list), 2, "All")
+}
+
+func Test_WorkerTimeout(t *testing.T) {
+    assert := assertT(t)
+
+    q := New("memory", memory.New())
+
+    handlers := NewHandlers().
+        Handle("slow", func(ctx context.Context, job *Job) error {
+            <-ctx.Done()
+            return nil
+        })
+
+    w := q.Worker(WorkerOptions{
+        Handlers: handlers,
+        Timeout:  50 * time.Millisecond,
+    })
+
+    var status string
+    var jobErr error
+    w.WithReporter(func(job *Job, s string, err error) {
+        status, jobErr = s, err
+    })
+
+    q.Dispatch("slow", nil)
+    w.RunOnce()
+
+    assert(status, StatusFailed, "status")
+    assert(jobErr, ErrJobTimeout, "timeout")
+}
+
+func Test_WorkerRun(t *testing.T) {
+    assert := assertT(t)
+
+    q := New("database", newTestDriver(t, newTestDB(t), 0))
+
+    done := make(chan string, 3)
+    handlers := NewHandlers().
+        Handle("run", func(ctx context.Context, job *Job) error {
+            var name string
+            job.Unmarshal(&name)
+
+            done <- name
+            return nil
+        })
+
+    q.Dispatch("run", "a")
+    q.Dispatch("run", "b", OnQueue("emails"))
+    q.Dispatch("run", "c", Delay(time.Hour))
+
+    ctx, cancel := context.WithCancel(context.Background())
+
+    w := q.Worker(WorkerOptions{
+        Queues:      []string{DefaultQueue, "emails"},
+        Sleep:       10 * time.Millisecond,
+        Concurrency: 2,
+        Handlers:    handlers,
+    })
+
+    go func() {
+        <-done
+        <-done
+        cancel()
+    }()
+
+    err := w.Run(ctx)
+    assert(err, nil, "Run")
+
+    size, _ := q.Size(DefaultQueue)
+    assert(size, int64(1), "Delayed size")
+
+    size, _ = q.Size("emails")
+    assert(size, int64(0), "emails size")
+}
+
+func Test_BackoffDelay(t *testing.T) {
+    assert := assertT(t)
+
+    job := &Job{Backoff: []int{1, 5}}
+
+    job.Attempts = 1
+    assert(job.BackoffDelay(), time.Second, "BackoffDelay 1")
+
+    job.Attempts = 3
+    assert(job.BackoffDelay(), 5 * time.Second, "BackoffDelay 3")
+}
+
+func Test_WorkerAttempts(t *testing.T) {
+    assert := assertT(t)
+
+    db := newTestDB(t)
+
+    driver := newTestDriver(t, db, time.Second)
+
+    q := New("database", driver).
+        WithFailed(newTestFailed(t, db))
+
+    calls := 0
+    handlers := NewHandlers().
+        Handle("crash", func(ctx context.Context, job *Job) error {
+            calls++
+            return nil
+        })
+
+    w := q.Worker(WorkerOptions{
+        Handlers: handlers,
+    })
+
+    job, _ := q.Dispatch("crash", nil, Tries(2))
+
+    // 执行者取出后异常退出，没有确认也没有放回
+    for i := 1; i <= 2; i++ {
+        msg, err := driver.Pop(DefaultQueue)
+        assert(err, nil, "Pop error")
+        assert(msg.Attempts, i, "Pop Attempts")
+
+        db.Table("lakego_jobs").
+            Where("id = ?", msg.ID).
+            Update("reserved_at", time.Now().Add(-time.Minute).Unix())
+    }
+
+    // 超时后重新取出，超出次数时不再执行
+    ok, err := w.RunOnce()
+    assert(ok, true, "RunOnce")
+    assert(err, nil, "RunOnce error")
+    assert(calls, 0, "RunOnce not called")
+
+    failed, err := q.GetFailed().Find(job.ID)
+    assert(err, nil, "Find error")
+    assert(failed.Exception, ErrMaxAttempts.Error(), "Exception")
+
+    size, _ := q.Size(DefaultQueue)
+    assert(size, int64(0), "Size")
+}
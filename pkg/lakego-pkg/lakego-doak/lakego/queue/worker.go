@@ -0,0 +1,279 @@
+package queue
+
+import (
+    "fmt"
+    "sync"
+    "time"
+    "errors"
+    "context"
+    "runtime/debug"
+
+    "github.com/deatil/lakego-doak/lakego/queue/interfaces"
+)
+
+var (
+    // 任务超时
+    ErrJobTimeout = errors.New("queue: job timeout")
+
+    // 任务处理不存在
+    ErrHandlerNotFound = errors.New("queue: job handler not found")
+
+    // 超出最大执行次数，比如执行者多次异常退出
+    ErrMaxAttempts = errors.New("queue: job attempted too many times")
+)
+
+// 执行状态
+const (
+    // 执行成功
+    StatusProcessed = "processed"
+
+    // 执行失败，等待重试
+    StatusReleased = "released"
+
+    // 执行失败
+    StatusFailed = "failed"
+)
+
+// 执行配置
+type WorkerOptions struct {
+    // 队列，按顺序获取
+    Queues []string
+
+    // 队列为空时的等待时间
+    Sleep time.Duration
+
+    // 并发数量
+    Concurrency int
+
+    // 默认最大执行次数
+    Tries int
+
+    // 默认超时时间
+    Timeout time.Duration
+
+    // 任务处理，为空时使用默认
+    Handlers *Handlers
+}
+
+// 执行结果回调
+type Reporter func(job *Job, status string, err error)
+
+/**
+ * 队列执行者
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Worker struct {
+    // 队列
+    queue *Queue
+
+    // 配置
+    options WorkerOptions
+
+    // 结果回调
+    reporter Reporter
+}
+
+// 构造函数
+func NewWorker(queue *Queue, opts WorkerOptions) *Worker {
+    if len(opts.Queues) == 0 {
+        opts.Queues = []string{DefaultQueue}
+    }
+
+    if opts.Sleep <= 0 {
+        opts.Sleep = 3 * time.Second
+    }
+
+    if opts.Concurrency <= 0 {
+        opts.Concurrency = 1
+    }
+
+    if opts.Tries <= 0 {
+        opts.Tries = 1
+    }
+
+    if opts.Timeout <= 0 {
+        opts.Timeout = 60 * time.Second
+    }
+
+    if opts.Handlers == nil {
+        opts.Handlers = defaultHandlers
+    }
+
+    return &Worker{
+        queue:   queue,
+        options: opts,
+    }
+}
+
+// 设置结果回调
+func (this *Worker) WithReporter(reporter Reporter) *Worker {
+    this.reporter = reporter
+
+    return this
+}
+
+// 执行，ctx 结束后不再获取新任务，等待执行中的任务完成后返回
+func (this *Worker) Run(ctx context.Context) error {
+    var wg sync.WaitGroup
+
+    sem := make(chan struct{}, this.options.Concurrency)
+
+    defer wg.Wait()
+
+    for {
+        select {
+            case <-ctx.Done():
+                return nil
+            case sem <- struct{}{}:
+        }
+
+        msg, err := this.next()
+        if err != nil || msg == nil {
+            <-sem
+
+            select {
+                case <-ctx.Done():
+                    return nil
+                case <-time.After(this.options.Sleep):
+            }
+
+            continue
+        }
+
+        wg.Add(1)
+        go func() {
+            defer func() {
+                <-sem
+                wg.Done()
+            }()
+
+            this.Process(msg)
+        }()
+    }
+}
+
+// 执行一个任务，没有任务时返回 false
+func (this *Worker) RunOnce() (bool, error) {
+    msg, err := this.next()
+    if err != nil || msg == nil {
+        return false, err
+    }
+
+    return true, this.Process(msg)
+}
+
+// 处理消息
+func (this *Worker) Process(msg *interfaces.Message) error {
+    driver := this.queue.GetDriver()
+
+    job, err := DecodeJob(msg.Body)
+    if err != nil {
+        // 无法解析的消息直接删除
+        driver.Delete(msg)
+        return err
+    }
+
+    // 使用驱动记录的取出次数，执行者异常退出没有放回的任务同样计数
+    if msg.Attempts > 0 {
+        job.Attempts = msg.Attempts
+    } else {
+        job.Attempts++
+    }
+
+    tries := job.Tries
+    if tries <= 0 {
+        tries = this.options.Tries
+    }
+
+    if tries > 0 && job.Attempts > tries {
+        err = ErrMaxAttempts
+    } else {
+        err = this.call(job)
+        if err == nil {
+            this.report(job, StatusProcessed, nil)
+
+            return driver.Delete(msg)
+        }
+    }
+
+    if job.Attempts < tries && !errors.Is(err, ErrHandlerNotFound) {
+        body, e := job.Marshal()
+        if e != nil {
+            return e
+        }
+
+        this.report(job, StatusReleased, err)
+
+        return driver.Release(msg, body, job.BackoffDelay())
+    }
+
+    this.report(job, StatusFailed, err)
+
+    if failed := this.queue.GetFailed(); failed != nil {
+        if e := failed.Log(this.queue.GetName(), msg.Queue, job, err); e != nil {
+            return e
+        }
+    }
+
+    return driver.Delete(msg)
+}
+
+// 获取下一个任务
+func (this *Worker) next() (*interfaces.Message, error) {
+    for _, queue := range this.options.Queues {
+        msg, err := this.queue.GetDriver().Pop(queue)
+        if err != nil {
+            return nil, err
+        }
+
+        if msg != nil {
+            return msg, nil
+        }
+    }
+
+    return nil, nil
+}
+
+// 执行任务
+func (this *Worker) call(job *Job) error {
+    handler, ok := this.options.Handlers.Get(job.Name)
+    if !ok {
+        return fmt.Errorf("%w: %s", ErrHandlerNotFound, job.Name)
+    }
+
+    timeout := this.options.Timeout
+    if job.Timeout > 0 {
+        timeout = time.Duration(job.Timeout) * time.Second
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+
+    done := make(chan error, 1)
+
+    go func() {
+        defer func() {
+            if r := recover(); r != nil {
+                done <- fmt.Errorf("queue: job panic: %v\n%s", r, debug.Stack())
+            }
+        }()
+
+        done <- handler(ctx, job)
+    }()
+
+    select {
+        case err := <-done:
+            return err
+        case <-ctx.Done():
+            return ErrJobTimeout
+    }
+}
+
+// 结果回调
+func (this *Worker) report(job *Job, status string, err error) {
+    if this.reporter != nil {
+        this.reporter(job, status, err)
+    }
+}
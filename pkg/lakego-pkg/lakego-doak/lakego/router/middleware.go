@@ -2,8 +2,12 @@ package router
 
 import (
     "sync"
+    "strings"
 )
 
+// 带参数的中间件，别名使用 name:param1,param2 格式传入参数
+type MiddlewareFactory = func(params ...string) HandlerFunc
+
 var instance *Middleware
 var once sync.Once
 
@@ -179,10 +183,18 @@ func (this *Middleware) PushMiddleware(middleware any) *Middleware {
  */
 func (this *Middleware) GetMiddlewareList(name string) (middleware []any) {
     if nameMiddleware := this.alias.Get(name); nameMiddleware != nil {
-        middleware = append(middleware, nameMiddleware)
+        middleware = append(middleware, makeMiddleware(nameMiddleware))
         return
     }
 
+    // 带参数的别名，比如 throttle:60,1
+    if aliasName, params, ok := parseMiddlewareName(name); ok {
+        if nameMiddleware := this.alias.Get(aliasName); nameMiddleware != nil {
+            middleware = append(middleware, makeMiddleware(nameMiddleware, params...))
+            return
+        }
+    }
+
     if ok := this.group.Exists(name); ok {
         nameGroupList := this.group.Get(name).All()
 
@@ -210,3 +222,26 @@ func (this *Middleware) GetGlobalMiddlewareList() (middleware []any) {
     return this.GetMiddlewareList(this.globalName)
 }
 
+// 解析带参数的中间件名称
+func parseMiddlewareName(name string) (string, []string, bool) {
+    names := strings.SplitN(name, ":", 2)
+    if len(names) != 2 || names[0] == "" {
+        return "", nil, false
+    }
+
+    params := strings.Split(names[1], ",")
+    for i, param := range params {
+        params[i] = strings.TrimSpace(param)
+    }
+
+    return names[0], params, true
+}
+
+// 生成中间件
+func makeMiddleware(middleware any, params ...string) any {
+    if factory, ok := middleware.(MiddlewareFactory); ok {
+        return factory(params...)
+    }
+
+    return middleware
+}
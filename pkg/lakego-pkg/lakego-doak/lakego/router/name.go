@@ -98,3 +98,17 @@ func (this *RouteName) GetRoute(name string) RouterInfo {
 
     return RouterInfo{}
 }
+
+// 根据请求方式和路由获取别名
+func (this *RouteName) GetNameByPath(method string, path string) string {
+    this.mu.RLock()
+    defer this.mu.RUnlock()
+
+    for name, route := range this.routes {
+        if route.Method == method && route.Path == path {
+            return name
+        }
+    }
+
+    return ""
+}
@@ -1,7 +1,18 @@
 package schedule
 
 import (
+    "time"
+    "context"
     "strings"
+    "crypto/sha1"
+    "encoding/hex"
+
+    "github.com/robfig/cron/v3"
+)
+
+// 计划时间解析，最低为秒
+var specParser = cron.NewParser(
+    cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
 )
 
 // 构造函数
@@ -29,6 +40,115 @@ type Entry struct {
 
     // 当前任务名称
     Name string
+
+    // 防止重复执行
+    withoutOverlapping bool
+
+    // 防止重复执行的锁过期时间
+    overlappingExpires time.Duration
+
+    // 多个服务器只执行一次
+    onOneServer bool
+
+    // 超时时间
+    timeout time.Duration
+
+    // 执行前
+    beforeCallbacks []func()
+
+    // 执行后
+    afterCallbacks []func()
+
+    // 执行失败
+    failureCallbacks []func(error)
+}
+
+// 上一次执行没有结束时跳过，expires 为锁过期时间，默认为 24 小时
+func (this *Entry) WithoutOverlapping(expires ...time.Duration) *Entry {
+    this.withoutOverlapping = true
+    this.overlappingExpires = 24 * time.Hour
+
+    if len(expires) > 0 && expires[0] > 0 {
+        this.overlappingExpires = expires[0]
+    }
+
+    return this
+}
+
+// 多个服务器同一分钟只执行一次，需要使用共享的缓存
+func (this *Entry) OnOneServer() *Entry {
+    this.onOneServer = true
+
+    return this
+}
+
+// 超时时间，cmd 为 func(context.Context) error 时会收到取消信号，
+// 其他类型的 cmd 超时后会继续执行，执行结束前不会释放防止重复执行的锁
+func (this *Entry) Timeout(timeout time.Duration) *Entry {
+    this.timeout = timeout
+
+    return this
+}
+
+// 执行前
+func (this *Entry) Before(fn func()) *Entry {
+    this.beforeCallbacks = append(this.beforeCallbacks, fn)
+
+    return this
+}
+
+// 执行后
+func (this *Entry) After(fn func()) *Entry {
+    this.afterCallbacks = append(this.afterCallbacks, fn)
+
+    return this
+}
+
+// 执行失败
+func (this *Entry) OnFailure(fn func(error)) *Entry {
+    this.failureCallbacks = append(this.failureCallbacks, fn)
+
+    return this
+}
+
+// 是否防止重复执行
+func (this *Entry) IsWithoutOverlapping() bool {
+    return this.withoutOverlapping
+}
+
+// 是否只在一个服务器执行
+func (this *Entry) IsOnOneServer() bool {
+    return this.onOneServer
+}
+
+// 获取超时时间
+func (this *Entry) GetTimeout() time.Duration {
+    return this.timeout
+}
+
+// 下一次执行时间
+func (this *Entry) Next(t time.Time) (time.Time, error) {
+    if this.Schedule != nil {
+        return this.Schedule.Next(t), nil
+    }
+
+    schedule, err := specParser.Parse(this.Spec)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    return schedule.Next(t), nil
+}
+
+// 锁名称，没有设置名称时使用计划时间生成
+func (this *Entry) MutexName() string {
+    if this.Name != "" {
+        return "schedule:" + this.Name
+    }
+
+    sum := sha1.Sum([]byte(this.Spec))
+
+    return "schedule:" + hex.EncodeToString(sum[:])
 }
 
 // 设置计划时间
@@ -64,6 +184,16 @@ func (this *Entry) AddFunc(cmd func()) *Entry {
     return this.WithCmd(cmd)
 }
 
+// 返回错误的函数，错误会记录到执行记录
+func (this *Entry) AddErrorFunc(cmd func() error) *Entry {
+    return this.WithCmd(cmd)
+}
+
+// 带上下文的函数，超时后上下文会被取消
+func (this *Entry) AddContextFunc(cmd func(context.Context) error) *Entry {
+    return this.WithCmd(cmd)
+}
+
 // Job 接口类
 func (this *Entry) AddJob(cmd IJob) *Entry {
     return this.WithCmd(cmd)
@@ -0,0 +1,116 @@
+package schedule
+
+import (
+    "gorm.io/gorm"
+
+    "github.com/deatil/lakego-doak/lakego/migration"
+)
+
+// 执行状态
+const (
+    // 成功
+    StatusSuccess = "success"
+
+    // 失败
+    StatusFailed = "failed"
+)
+
+/**
+ * 执行记录
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type History struct {
+    ID        uint   `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+    Name      string `gorm:"column:name;size:150;index" json:"name"`
+    Spec      string `gorm:"column:spec;size:100" json:"spec"`
+    Server    string `gorm:"column:server;size:150" json:"server"`
+    Status    string `gorm:"column:status;size:20" json:"status"`
+    Error     string `gorm:"column:error;type:text" json:"error"`
+    StartTime int64  `gorm:"column:start_time;index" json:"start_time"`
+    Duration  int64  `gorm:"column:duration" json:"duration"`
+}
+
+// 执行记录存储接口
+type HistoryStore interface {
+    // 记录
+    Record(history *History) error
+
+    // 列表，name 为空时获取全部
+    List(name string, limit int) ([]History, error)
+}
+
+/**
+ * 数据库执行记录
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type DatabaseHistory struct {
+    // 数据库
+    db *gorm.DB
+
+    // 表名
+    table string
+}
+
+// 构造函数
+func NewDatabaseHistory(db *gorm.DB, table string) *DatabaseHistory {
+    if table == "" {
+        table = "schedule_history"
+    }
+
+    return &DatabaseHistory{
+        db:    db,
+        table: db.NamingStrategy.TableName(table),
+    }
+}
+
+// 记录
+func (this *DatabaseHistory) Record(history *History) error {
+    return this.query().Create(history).Error
+}
+
+// 列表
+func (this *DatabaseHistory) List(name string, limit int) ([]History, error) {
+    list := make([]History, 0)
+
+    query := this.query()
+    if name != "" {
+        query = query.Where("name = ?", name)
+    }
+
+    if limit > 0 {
+        query = query.Limit(limit)
+    }
+
+    err := query.
+        Order("id DESC").
+        Find(&list).
+        Error
+
+    return list, err
+}
+
+// 查询
+func (this *DatabaseHistory) query() *gorm.DB {
+    return this.db.Table(this.table)
+}
+
+// 建表迁移，使用执行记录的数据库连接
+func (this *DatabaseHistory) Migration() migration.Migration {
+    return migration.Migration{
+        Name: "2026_10_18_000003_create_" + this.table + "_table",
+        Up: func(*gorm.DB) error {
+            if this.db.Migrator().HasTable(this.table) {
+                return nil
+            }
+
+            return this.db.Table(this.table).Migrator().CreateTable(&History{})
+        },
+        Down: func(*gorm.DB) error {
+            return this.db.Migrator().DropTable(this.table)
+        },
+    }
+}
@@ -0,0 +1,186 @@
+package schedule
+
+import (
+    "os"
+    "fmt"
+    "time"
+    "errors"
+    "context"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+)
+
+// 执行超时
+var ErrTimeout = errors.New("schedule: entry timeout")
+
+// 服务器名称
+var hostname, _ = os.Hostname()
+
+// 计划任务执行
+type entryJob struct {
+    // 计划任务
+    schedule *Schedule
+
+    // 任务数据
+    entry *Entry
+
+    // 计划任务 id
+    id CronEntryID
+}
+
+// 执行
+func (this *entryJob) Run() {
+    this.schedule.runEntryAt(this.entry, this.schedule.scheduledTime(this))
+}
+
+// 本次计划的执行时间，计划任务在执行任务后才更新 Prev，
+// 获取 Entry 需要等待计划任务处理完本次执行的任务
+func (this *Schedule) scheduledTime(job *entryJob) time.Time {
+    this.mu.RLock()
+    id := job.id
+    this.mu.RUnlock()
+
+    prev := this.Cron.Entry(id).Prev
+    if prev.IsZero() {
+        return time.Now().Truncate(time.Second)
+    }
+
+    return prev
+}
+
+// 执行任务数据
+func (this *Schedule) runEntry(entry *Entry) {
+    this.runEntryAt(entry, time.Now().Truncate(time.Second))
+}
+
+// 执行任务数据，scheduled 为本次计划的执行时间
+func (this *Schedule) runEntryAt(entry *Entry, scheduled time.Time) {
+    c := this.cache
+    start := time.Now()
+
+    // 多个服务器只执行一次
+    if entry.onOneServer && c != nil {
+        ok, err := c.Add(serverMutexName(entry, scheduled), hostname, time.Hour)
+        if err != nil || !ok {
+            return
+        }
+    }
+
+    // 防止重复执行
+    var lock *cache.Lock
+    if entry.withoutOverlapping && c != nil {
+        lock = c.Lock(entry.MutexName(), entry.overlappingExpires)
+
+        ok, err := lock.Get()
+        if err != nil || !ok {
+            return
+        }
+    }
+
+    for _, fn := range entry.beforeCallbacks {
+        fn()
+    }
+
+    err := callEntry(entry, func() {
+        if lock != nil {
+            lock.Release()
+        }
+    })
+
+    for _, fn := range entry.afterCallbacks {
+        fn()
+    }
+
+    if err != nil {
+        for _, fn := range entry.failureCallbacks {
+            fn(err)
+        }
+    }
+
+    this.record(entry, start, err)
+}
+
+// 保存执行记录
+func (this *Schedule) record(entry *Entry, start time.Time, err error) {
+    if this.history == nil {
+        return
+    }
+
+    history := &History{
+        Name:      entry.Name,
+        Spec:      entry.Spec,
+        Server:    hostname,
+        Status:    StatusSuccess,
+        StartTime: start.Unix(),
+        Duration:  time.Since(start).Milliseconds(),
+    }
+
+    if err != nil {
+        history.Status = StatusFailed
+        history.Error = err.Error()
+    }
+
+    if e := this.history.Record(history); e != nil {
+        NewLogger().Printf("record history error: %s", e.Error())
+    }
+}
+
+// 多服务器执行锁名称，使用本次计划的执行时间，
+// 各服务器同一次执行的计划时间相同，与实际开始时间无关
+func serverMutexName(entry *Entry, scheduled time.Time) string {
+    return fmt.Sprintf("%s:server:%d", entry.MutexName(), scheduled.Unix())
+}
+
+// 执行脚本，done 在脚本实际结束后调用。
+// 超时后直接返回 ErrTimeout，只有 func(context.Context) error
+// 类型的脚本会收到取消信号，其他类型的脚本会继续执行到结束，
+// 防止重复执行的锁在脚本实际结束后才释放
+func callEntry(entry *Entry, done func()) error {
+    ctx := context.Background()
+
+    cancel := func() {}
+    if entry.timeout > 0 {
+        ctx, cancel = context.WithTimeout(ctx, entry.timeout)
+    }
+
+    defer cancel()
+
+    result := make(chan error, 1)
+
+    go func() {
+        defer done()
+
+        defer func() {
+            if r := recover(); r != nil {
+                result <- fmt.Errorf("schedule: entry panic: %v", r)
+            }
+        }()
+
+        result <- runCmd(ctx, entry.Cmd)
+    }()
+
+    select {
+        case err := <-result:
+            return err
+        case <-ctx.Done():
+            return ErrTimeout
+    }
+}
+
+// 执行脚本
+func runCmd(ctx context.Context, cmd any) error {
+    switch fn := cmd.(type) {
+        case func():
+            fn()
+        case func() error:
+            return fn()
+        case func(context.Context) error:
+            return fn(ctx)
+        case IJob:
+            fn.Run()
+        default:
+            return fmt.Errorf("schedule: cmd type %T not support", cmd)
+    }
+
+    return nil
+}
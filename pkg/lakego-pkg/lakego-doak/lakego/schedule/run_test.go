@@ -0,0 +1,240 @@
+package schedule
+
+import (
+    "time"
+    "errors"
+    "context"
+    "testing"
+    "reflect"
+    "sync/atomic"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/cache/driver/memory"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func newTestSchedule(c *cache.Cache) *Schedule {
+    return &Schedule{
+        cache: c,
+    }
+}
+
+// 执行记录
+type testHistory struct {
+    list []History
+}
+
+func (this *testHistory) Record(history *History) error {
+    this.list = append(this.list, *history)
+    return nil
+}
+
+func (this *testHistory) List(name string, limit int) ([]History, error) {
+    return this.list, nil
+}
+
+func Test_OnOneServer(t *testing.T) {
+    assert := assertT(t)
+
+    // 共享缓存
+    c := cache.New(memory.New(memory.Config{}))
+
+    var calls int32
+    entry := NewEntry().
+        WithName("one-server").
+        AddFunc(func() {
+            atomic.AddInt32(&calls, 1)
+        }).
+        OnOneServer()
+
+    start := time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC)
+
+    // 两台服务器同一次计划的执行
+    newTestSchedule(c).runEntryAt(entry, start)
+    newTestSchedule(c).runEntryAt(entry, start)
+    assert(atomic.LoadInt32(&calls), int32(1), "OnOneServer same scheduled")
+
+    // 同一分钟内的下一次执行
+    newTestSchedule(c).runEntryAt(entry, start.Add(10 * time.Second))
+    assert(atomic.LoadInt32(&calls), int32(2), "OnOneServer next scheduled")
+
+    assert(
+        serverMutexName(entry, start.Add(10 * time.Second)),
+        "schedule:one-server:server:1792319410",
+        "serverMutexName",
+    )
+}
+
+// 记录过期时间
+type ttlDriver struct {
+    *memory.Memory
+
+    ttls map[string]time.Duration
+}
+
+func (this *ttlDriver) Add(key string, value any, ttl time.Duration) (bool, error) {
+    this.ttls[key] = ttl
+
+    return this.Memory.Add(key, value, ttl)
+}
+
+func Test_LockExpires(t *testing.T) {
+    assert := assertT(t)
+
+    driver := &ttlDriver{
+        Memory: memory.New(memory.Config{}),
+        ttls:   make(map[string]time.Duration),
+    }
+    c := cache.New(driver)
+
+    entry := NewEntry().
+        WithName("expires").
+        AddFunc(func() {}).
+        OnOneServer().
+        WithoutOverlapping(10 * time.Minute)
+
+    start := time.Now()
+    newTestSchedule(c).runEntryAt(entry, start)
+
+    assert(driver.ttls[serverMutexName(entry, start)], time.Hour, "OnOneServer ttl")
+    assert(driver.ttls["lock:" + entry.MutexName()], 10 * time.Minute, "WithoutOverlapping ttl")
+}
+
+func Test_WithoutOverlapping(t *testing.T) {
+    assert := assertT(t)
+
+    c := cache.New(memory.New(memory.Config{}))
+
+    var calls int32
+    entry := NewEntry().
+        WithName("overlapping").
+        AddFunc(func() {
+            atomic.AddInt32(&calls, 1)
+        }).
+        WithoutOverlapping(time.Minute)
+
+    // 其他进程持有锁时跳过
+    lock := c.Lock(entry.MutexName(), time.Minute)
+    ok, _ := lock.Get()
+    assert(ok, true, "lock Get")
+
+    newTestSchedule(c).runEntry(entry)
+    assert(atomic.LoadInt32(&calls), int32(0), "WithoutOverlapping locked")
+
+    lock.Release()
+
+    newTestSchedule(c).runEntry(entry)
+    assert(atomic.LoadInt32(&calls), int32(1), "WithoutOverlapping released")
+
+    // 执行结束后释放锁
+    newTestSchedule(c).runEntry(entry)
+    assert(atomic.LoadInt32(&calls), int32(2), "WithoutOverlapping release after run")
+}
+
+func Test_Timeout(t *testing.T) {
+    assert := assertT(t)
+
+    c := cache.New(memory.New(memory.Config{}))
+    history := &testHistory{}
+
+    release := make(chan struct{})
+    finished := make(chan struct{})
+
+    var failed error
+    entry := NewEntry().
+        WithName("timeout").
+        AddFunc(func() {
+            <-release
+        }).
+        WithoutOverlapping(time.Minute).
+        Timeout(20 * time.Millisecond).
+        OnFailure(func(err error) {
+            failed = err
+        })
+
+    s := newTestSchedule(c).WithHistory(history)
+    s.runEntry(entry)
+
+    assert(failed, ErrTimeout, "Timeout error")
+    assert(len(history.list), 1, "Timeout history")
+    if len(history.list) == 1 {
+        assert(history.list[0].Status, StatusFailed, "Timeout history status")
+    }
+
+    // 超时后脚本仍在执行，锁不释放
+    lock := c.Lock(entry.MutexName(), time.Minute)
+    ok, _ := lock.Get()
+    assert(ok, false, "Timeout lock held")
+
+    go func() {
+        close(release)
+
+        for {
+            if ok, _ := lock.Get(); ok {
+                close(finished)
+                return
+            }
+            time.Sleep(5 * time.Millisecond)
+        }
+    }()
+
+    select {
+        case <-finished:
+        case <-time.After(time.Second):
+            t.Error("Failed Timeout lock release")
+    }
+}
+
+func Test_CallEntry_Context(t *testing.T) {
+    assert := assertT(t)
+
+    canceled := make(chan error, 1)
+
+    entry := NewEntry().
+        AddContextFunc(func(ctx context.Context) error {
+            <-ctx.Done()
+            canceled <- ctx.Err()
+            return ctx.Err()
+        }).
+        Timeout(10 * time.Millisecond)
+
+    err := callEntry(entry, func() {})
+    assert(err, ErrTimeout, "callEntry timeout")
+
+    select {
+        case err := <-canceled:
+            assert(err, context.DeadlineExceeded, "callEntry context canceled")
+        case <-time.After(time.Second):
+            t.Error("Failed callEntry context cancel")
+    }
+}
+
+func Test_CallEntry(t *testing.T) {
+    assert := assertT(t)
+
+    testErr := errors.New("test error")
+
+    entry := NewEntry().
+        AddErrorFunc(func() error {
+            return testErr
+        })
+    assert(callEntry(entry, func() {}), testErr, "callEntry error")
+
+    entry = NewEntry().
+        AddFunc(func() {
+            panic("test panic")
+        })
+    err := callEntry(entry, func() {})
+    assert(err != nil, true, "callEntry panic")
+
+    entry = NewEntry().WithCmd("cmd")
+    err = callEntry(entry, func() {})
+    assert(err != nil, true, "callEntry not support")
+}
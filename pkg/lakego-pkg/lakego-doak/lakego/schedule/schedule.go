@@ -5,6 +5,9 @@ import (
     "time"
     "sync"
     "context"
+
+    "github.com/deatil/lakego-doak/lakego/cache"
+    "github.com/deatil/lakego-doak/lakego/facade"
 )
 
 // 常量
@@ -32,6 +35,7 @@ func New() *Schedule {
         entries: make([]*Entry, 0),
         cronIDs: make(map[string]CronEntryID),
         stoped:  make(map[string]CronEntry),
+        cache:   facade.Cache,
     }
 
     return schedule
@@ -58,6 +62,40 @@ type Schedule struct {
 
     // 已停止的计划任务
     stoped map[string]CronEntry
+
+    // 锁使用的缓存，多服务器时需要使用共享缓存
+    cache *cache.Cache
+
+    // 执行记录
+    history HistoryStore
+}
+
+// 设置锁使用的缓存
+func (this *Schedule) WithCache(c *cache.Cache) *Schedule {
+    this.cache = c
+
+    return this
+}
+
+// 设置执行记录存储，为 nil 时不记录
+func (this *Schedule) WithHistory(history HistoryStore) *Schedule {
+    this.history = history
+
+    return this
+}
+
+// 获取执行记录存储
+func (this *Schedule) GetHistory() HistoryStore {
+    return this.history
+}
+
+// 执行记录
+func (this *Schedule) History(name string, limit int) ([]History, error) {
+    if this.history == nil {
+        return []History{}, nil
+    }
+
+    return this.history.List(name, limit)
 }
 
 // 添加计划任务
@@ -197,25 +235,27 @@ func (this *Schedule) addEntry(entry *Entry) {
     var entryID CronEntryID
     var err error
 
-    switch cmd := entry.Cmd.(type) {
-        // 方法
-        case func():
-            entryID, err = this.Cron.AddFunc(entry.Spec, cmd)
-
-        // job 结构体
-        case IJob:
-            if entry.Spec != "" {
-                // 字符
-                entryID, err = this.Cron.AddJob(entry.Spec, cmd)
-            } else if entry.Schedule != nil {
-                // Schedule 结构体
-                entryID = this.Cron.Schedule(entry.Schedule, cmd)
-            }
+    switch entry.Cmd.(type) {
+        case func(), func() error, func(context.Context) error, IJob:
+        default:
+            return
+    }
+
+    job := &entryJob{schedule: this, entry: entry}
+
+    if entry.Schedule != nil {
+        // Schedule 结构体
+        entryID = this.Cron.Schedule(entry.Schedule, job)
+    } else {
+        // 字符
+        entryID, err = this.Cron.AddJob(entry.Spec, job)
     }
 
     if err == nil {
         this.mu.Lock()
 
+        job.id = entryID
+
         if entry.Name != "" {
             this.cronIDs[entry.Name] = entryID
         } else {
@@ -6,11 +6,14 @@ import (
 
     // 脚本
     publishCmd "github.com/deatil/lakego-doak/lakego/console/publish"
+    queueCmd "github.com/deatil/lakego-doak/lakego/console/queue"
+    migrateCmd "github.com/deatil/lakego-doak/lakego/console/migrate"
     storageCmd "github.com/deatil/lakego-doak/lakego/console/storage"
     scheduleCmd "github.com/deatil/lakego-doak/lakego/console/schedule"
 
     // 视图
     "github.com/deatil/lakego-doak/lakego/facade/view"
+    "github.com/deatil/lakego-doak/lakego/facade/queue"
 )
 
 /**
@@ -33,6 +36,9 @@ func (this *Lakego) Boot() {
     // 脚本
     this.loadCommand()
 
+    // 数据库迁移
+    this.AddMigrations("lakego", queue.Migrations()...)
+
     // 模板渲染
     this.loadHtmlRender()
 }
@@ -46,12 +52,31 @@ func (this *Lakego) loadCommand() {
 
     // 创建软连接
     this.AddCommand(storageCmd.StorageLinkCmd)
+
+    // 数据库迁移
+    this.AddCommand(migrateCmd.MigrateCmd)
+    this.AddCommand(migrateCmd.RollbackCmd)
+    this.AddCommand(migrateCmd.StatusCmd)
+    this.AddCommand(migrateCmd.FreshCmd)
+    this.AddCommand(migrateCmd.SeedCmd)
+
+    // 队列
+    this.AddCommand(queueCmd.WorkCmd)
+    this.AddCommand(queueCmd.FailedCmd)
+    this.AddCommand(queueCmd.RetryCmd)
 }
 
 // 计划任务
 func (this *Lakego) Schedule(s *schedule.Schedule) {
     // 计划任务命令
     this.AddCommand(scheduleCmd.NewScheduleCmd(s))
+    this.AddCommand(scheduleCmd.NewScheduleListCmd(s))
+    this.AddCommand(scheduleCmd.NewScheduleHistoryCmd(s))
+
+    // 执行记录表
+    if history, ok := s.GetHistory().(*schedule.DatabaseHistory); ok {
+        this.AddMigrations("lakego", history.Migration())
+    }
 }
 
 /**
@@ -0,0 +1,79 @@
+package totp
+
+import (
+    "time"
+    "testing"
+    "reflect"
+    "strings"
+    "encoding/base32"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+// RFC 6238 附录 B 的 SHA1 测试数据
+func Test_Generate(t *testing.T) {
+    eq := assertT(t)
+
+    secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+    tests := map[int64]string{
+        59:          "94287082",
+        1111111109:  "07081804",
+        1111111111:  "14050471",
+        1234567890:  "89005924",
+        2000000000:  "69279037",
+        20000000000: "65353130",
+    }
+
+    otp := New(secret).WithDigits(8)
+    for ts, code := range tests {
+        got, err := otp.Generate(time.Unix(ts, 0))
+        eq(err, nil, "Generate err")
+        eq(got, code, "Generate")
+    }
+}
+
+func Test_Validate(t *testing.T) {
+    eq := assertT(t)
+
+    secret, err := GenerateSecret()
+    eq(err, nil, "GenerateSecret")
+    eq(len(secret), 32, "GenerateSecret len")
+
+    otp := New(secret)
+
+    now := time.Unix(1700000000, 0)
+    code, _ := otp.Generate(now.Add(-30 * time.Second))
+
+    counter, ok := otp.Validate(code, now)
+    eq(ok, true, "Validate skew")
+    eq(counter, otp.Counter(now) - 1, "Validate counter")
+
+    _, ok = otp.Validate(code, now.Add(60 * time.Second))
+    eq(ok, false, "Validate expired")
+
+    _, ok = otp.Validate("12345", now)
+    eq(ok, false, "Validate digits")
+
+    _, err = New("not base32!").Generate(now)
+    eq(err, ErrInvalidSecret, "Invalid secret")
+}
+
+func Test_URI(t *testing.T) {
+    eq := assertT(t)
+
+    uri := New("JBSWY3DPEHPK3PXP").URI("Lakego Admin", "admin")
+
+    eq(strings.HasPrefix(uri, "otpauth://totp/Lakego%20Admin:admin?"), true, "URI label")
+    eq(strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP"), true, "URI secret")
+
+    png, err := QRCode(uri, 256)
+    eq(err, nil, "QRCode")
+    eq(string(png[1:4]), "PNG", "QRCode png")
+}
@@ -0,0 +1,502 @@
+package upload
+
+import (
+    "io"
+    "fmt"
+    "path"
+    "time"
+    "errors"
+    "regexp"
+    "strconv"
+    "strings"
+    "crypto/md5"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+
+    "github.com/deatil/lakego-doak/lakego/file"
+    "github.com/deatil/lakego-doak/lakego/storage"
+)
+
+var (
+    // 分片会话不存在
+    ErrChunkNotFound = errors.New("upload: chunk session not found")
+
+    // 分片序号错误
+    ErrChunkIndex = errors.New("upload: chunk index out of range")
+
+    // 分片偏移错误
+    ErrChunkOffset = errors.New("upload: chunk offset mismatch")
+
+    // 分片大小错误
+    ErrChunkSize = errors.New("upload: chunk size mismatch")
+
+    // 分片校验失败
+    ErrChunkChecksum = errors.New("upload: chunk checksum mismatch")
+
+    // 分片未上传完成
+    ErrChunkIncomplete = errors.New("upload: chunks incomplete")
+
+    // 文件校验失败
+    ErrFileChecksum = errors.New("upload: file checksum mismatch")
+
+    // 文件过大
+    ErrFileTooLarge = errors.New("upload: file too large")
+
+    // 分片过大
+    ErrChunkTooLarge = errors.New("upload: chunk too large")
+)
+
+// 会话 id 格式
+var chunkIdRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// 最大分片数量
+const maxChunkTotal = 10000
+
+/**
+ * 分片上传会话
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type ChunkSession struct {
+    // 会话 id
+    ID string `json:"id"`
+
+    // 上传账号
+    Owner string `json:"owner"`
+
+    // 原始文件名
+    Filename string `json:"filename"`
+
+    // 文件大小
+    Size int64 `json:"size"`
+
+    // 分片大小
+    ChunkSize int64 `json:"chunk_size"`
+
+    // 分片数量
+    Total int `json:"total"`
+
+    // 整个文件的 md5
+    Md5 string `json:"md5"`
+
+    // 整个文件的 sha1，合并时计算
+    Sha1 string `json:"sha1,omitempty"`
+
+    // 整个文件的 sha256，合并时计算
+    Sha256 string `json:"sha256,omitempty"`
+
+    // 创建时间
+    CreatedAt int64 `json:"created_at"`
+}
+
+// 分片实际大小
+func (this *ChunkSession) ChunkLength(index int) int64 {
+    if index == this.Total - 1 {
+        return this.Size - int64(index) * this.ChunkSize
+    }
+
+    return this.ChunkSize
+}
+
+// 分片偏移
+func (this *ChunkSession) ChunkOffset(index int) int64 {
+    return int64(index) * this.ChunkSize
+}
+
+// 文件后缀
+func (this *ChunkSession) Extension() string {
+    return strings.TrimPrefix(path.Ext(this.Filename), ".")
+}
+
+// 分片上传
+func NewChunk(storager *storage.Storage, directory string) *Chunk {
+    if directory == "" {
+        directory = "chunks"
+    }
+
+    return &Chunk{
+        storage:   storager,
+        directory: strings.Trim(directory, "/"),
+    }
+}
+
+/**
+ * 分片上传
+ *
+ * 流程: Init -> Put 分片 -> Complete
+ * 分片临时数据保存在 storage 的临时目录，storage 需使用不对外访问的磁盘
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Chunk struct {
+    // 驱动
+    storage *storage.Storage
+
+    // 临时目录
+    directory string
+
+    // 上传账号，设置后只能操作该账号创建的会话
+    owner string
+
+    // 文件最大大小，0 为不限制
+    maxSize int64
+
+    // 分片最大大小，0 为不限制
+    maxChunkSize int64
+}
+
+// 设置上传账号
+func (this *Chunk) WithOwner(owner string) *Chunk {
+    this.owner = owner
+
+    return this
+}
+
+// 设置文件最大大小
+func (this *Chunk) WithMaxSize(size int64) *Chunk {
+    this.maxSize = size
+
+    return this
+}
+
+// 设置分片最大大小
+func (this *Chunk) WithMaxChunkSize(size int64) *Chunk {
+    this.maxChunkSize = size
+
+    return this
+}
+
+// 获取文件系统
+func (this *Chunk) GetStorage() *storage.Storage {
+    return this.storage
+}
+
+// 获取临时目录
+func (this *Chunk) GetDirectory() string {
+    return this.directory
+}
+
+// 开始分片上传
+func (this *Chunk) Init(filename string, size int64, chunkSize int64, checksum string) (*ChunkSession, error) {
+    filename = path.Base(strings.Replace(filename, "\\", "/", -1))
+    if filename == "" || filename == "." || filename == "/" {
+        return nil, errors.New("upload: filename is empty")
+    }
+
+    if size <= 0 || chunkSize <= 0 {
+        return nil, ErrChunkSize
+    }
+
+    if this.maxSize > 0 && size > this.maxSize {
+        return nil, ErrFileTooLarge
+    }
+
+    if this.maxChunkSize > 0 && chunkSize > this.maxChunkSize {
+        return nil, ErrChunkTooLarge
+    }
+
+    total := int((size + chunkSize - 1) / chunkSize)
+    if total > maxChunkTotal {
+        return nil, fmt.Errorf("upload: too many chunks, max %d", maxChunkTotal)
+    }
+
+    checksum = strings.ToLower(strings.TrimSpace(checksum))
+    if !isHexHash(checksum, md5.Size) {
+        return nil, errors.New("upload: invalid file checksum")
+    }
+
+    session := &ChunkSession{
+        ID:        newChunkId(),
+        Owner:     this.owner,
+        Filename:  filename,
+        Size:      size,
+        ChunkSize: chunkSize,
+        Total:     total,
+        Md5:       checksum,
+        CreatedAt: time.Now().Unix(),
+    }
+
+    if err := this.saveSession(session); err != nil {
+        return nil, err
+    }
+
+    return session, nil
+}
+
+// 获取会话
+func (this *Chunk) Session(id string) (*ChunkSession, error) {
+    if !chunkIdRegexp.MatchString(id) {
+        return nil, ErrChunkNotFound
+    }
+
+    metaPath := this.metaPath(id)
+    if !this.storage.Has(metaPath) {
+        return nil, ErrChunkNotFound
+    }
+
+    data, err := this.storage.Read(metaPath)
+    if err != nil {
+        return nil, err
+    }
+
+    session := &ChunkSession{}
+    if err := json.Unmarshal([]byte(data), session); err != nil {
+        return nil, err
+    }
+
+    // 其他账号的会话
+    if this.owner != "" && session.Owner != this.owner {
+        return nil, ErrChunkNotFound
+    }
+
+    return session, nil
+}
+
+// 上传分片
+func (this *Chunk) Put(id string, index int, offset int64, data io.Reader, checksum string) (*ChunkSession, error) {
+    session, err := this.Session(id)
+    if err != nil {
+        return nil, err
+    }
+
+    if index < 0 || index >= session.Total {
+        return nil, ErrChunkIndex
+    }
+
+    if offset != session.ChunkOffset(index) {
+        return nil, ErrChunkOffset
+    }
+
+    length := session.ChunkLength(index)
+
+    partPath := this.partPath(id, index)
+    tmpPath := partPath + ".tmp"
+
+    // 写入临时文件时计算大小和摘要，多读一个字节用来判断分片是否过大
+    hash := md5.New()
+    counter := &countWriter{}
+
+    reader := io.TeeReader(io.LimitReader(data, length + 1), io.MultiWriter(hash, counter))
+    if _, err := this.storage.PutStream(tmpPath, reader); err != nil {
+        this.storage.Delete(tmpPath)
+        return nil, err
+    }
+
+    if counter.n != length {
+        this.storage.Delete(tmpPath)
+        return nil, ErrChunkSize
+    }
+
+    if hex.EncodeToString(hash.Sum(nil)) != strings.ToLower(strings.TrimSpace(checksum)) {
+        this.storage.Delete(tmpPath)
+        return nil, ErrChunkChecksum
+    }
+
+    // 重新上传的分片覆盖之前的分片
+    if this.storage.Has(partPath) {
+        this.storage.Delete(partPath)
+    }
+
+    if _, err := this.storage.Rename(tmpPath, partPath); err != nil {
+        this.storage.Delete(tmpPath)
+        return nil, err
+    }
+
+    return session, nil
+}
+
+// 已上传的分片
+func (this *Chunk) Uploaded(id string) ([]int, error) {
+    session, err := this.Session(id)
+    if err != nil {
+        return nil, err
+    }
+
+    uploaded := make([]int, 0, session.Total)
+    for i := 0; i < session.Total; i++ {
+        if this.storage.Has(this.partPath(id, i)) {
+            uploaded = append(uploaded, i)
+        }
+    }
+
+    return uploaded, nil
+}
+
+// 合并分片，返回合并后文件在 storage 中的路径
+func (this *Chunk) Complete(id string) (*ChunkSession, string, error) {
+    session, err := this.Session(id)
+    if err != nil {
+        return nil, "", err
+    }
+
+    for i := 0; i < session.Total; i++ {
+        if !this.storage.Has(this.partPath(id, i)) {
+            return nil, "", ErrChunkIncomplete
+        }
+    }
+
+    reader, writer := io.Pipe()
+    go func() {
+        writer.CloseWithError(this.writeParts(session, writer))
+    }()
+
+    // 写入时计算摘要
+    hashReader := file.NewHashReader(reader)
+
+    filePath := this.filePath(id)
+
+    _, err = this.storage.PutStream(filePath, hashReader)
+    reader.Close()
+    if err != nil {
+        this.storage.Delete(filePath)
+        return nil, "", err
+    }
+
+    if hashReader.Md5() != session.Md5 {
+        this.storage.Delete(filePath)
+        return nil, "", ErrFileChecksum
+    }
+
+    session.Sha1 = hashReader.Sha1()
+    session.Sha256 = hashReader.Sha256()
+
+    return session, filePath, nil
+}
+
+// 取消上传
+func (this *Chunk) Abort(id string) error {
+    if _, err := this.Session(id); err != nil {
+        return err
+    }
+
+    dir := this.sessionDir(id)
+
+    _, err := this.storage.DeleteDir(dir)
+    return err
+}
+
+// 清除过期未完成的会话
+func (this *Chunk) Clean(expire time.Duration) (int, error) {
+    list, err := this.storage.ListContents(this.directory)
+    if err != nil {
+        return 0, err
+    }
+
+    deadline := time.Now().Add(-expire).Unix()
+
+    count := 0
+    for _, item := range list {
+        if item["type"] != "dir" {
+            continue
+        }
+
+        itemPath, _ := item["path"].(string)
+        id := path.Base(itemPath)
+        if !chunkIdRegexp.MatchString(id) {
+            continue
+        }
+
+        createdAt, _ := item["timestamp"].(int64)
+        if session, err := this.Session(id); err == nil {
+            createdAt = session.CreatedAt
+        }
+
+        if createdAt > deadline {
+            continue
+        }
+
+        if _, err := this.storage.DeleteDir(this.sessionDir(id)); err != nil {
+            return count, err
+        }
+
+        count++
+    }
+
+    return count, nil
+}
+
+// 按顺序写入分片数据
+func (this *Chunk) writeParts(session *ChunkSession, out io.Writer) error {
+    for i := 0; i < session.Total; i++ {
+        if err := this.writePart(session.ID, i, out); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 写入单个分片数据
+func (this *Chunk) writePart(id string, index int, out io.Writer) error {
+    f, err := this.storage.ReadStream(this.partPath(id, index))
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    _, err = io.Copy(out, f)
+    return err
+}
+
+// 保存会话信息
+func (this *Chunk) saveSession(session *ChunkSession) error {
+    data, err := json.Marshal(session)
+    if err != nil {
+        return err
+    }
+
+    _, err = this.storage.Put(this.metaPath(session.ID), string(data))
+    return err
+}
+
+// 会话目录
+func (this *Chunk) sessionDir(id string) string {
+    return this.directory + "/" + id
+}
+
+// 会话信息文件
+func (this *Chunk) metaPath(id string) string {
+    return this.sessionDir(id) + "/meta.json"
+}
+
+// 分片文件
+func (this *Chunk) partPath(id string, index int) string {
+    return this.sessionDir(id) + "/" + strconv.Itoa(index) + ".part"
+}
+
+// 合并后的文件
+func (this *Chunk) filePath(id string) string {
+    return this.sessionDir(id) + "/file"
+}
+
+// 统计写入的字节数
+type countWriter struct {
+    n int64
+}
+
+func (this *countWriter) Write(p []byte) (int, error) {
+    this.n += int64(len(p))
+
+    return len(p), nil
+}
+
+// 生成会话 id
+func newChunkId() string {
+    buf := make([]byte, 16)
+    rand.Read(buf)
+
+    return hex.EncodeToString(buf)
+}
+
+// 检测哈希格式
+func isHexHash(s string, size int) bool {
+    if len(s) != size * 2 {
+        return false
+    }
+
+    _, err := hex.DecodeString(s)
+    return err == nil
+}
@@ -0,0 +1,226 @@
+package upload
+
+import (
+    "time"
+    "bytes"
+    "testing"
+    "reflect"
+    "crypto/md5"
+    "encoding/hex"
+
+    "github.com/deatil/go-filesystem/filesystem/adapter/local"
+
+    "github.com/deatil/lakego-doak/lakego/storage"
+)
+
+func assertT(t *testing.T) func(any, any, string) {
+    return func(actual any, expected any, msg string) {
+        if !reflect.DeepEqual(actual, expected) {
+            t.Errorf("Failed %s: actual: %v, expected: %v", msg, actual, expected)
+        }
+    }
+}
+
+func md5Hex(data []byte) string {
+    sum := md5.Sum(data)
+    return hex.EncodeToString(sum[:])
+}
+
+func newTestChunk(t *testing.T) *Chunk {
+    s := storage.New(local.New(t.TempDir()))
+
+    return NewChunk(s, "chunks")
+}
+
+func Test_Chunk(t *testing.T) {
+    eq := assertT(t)
+
+    chunk := newTestChunk(t)
+
+    data := []byte("0123456789abcdefghij-lakego")
+
+    session, err := chunk.Init("../test.txt", int64(len(data)), 10, md5Hex(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(session.Filename, "test.txt", "Init Filename")
+    eq(session.Total, 3, "Init Total")
+    eq(session.Extension(), "txt", "Extension")
+
+    parts := [][]byte{data[:10], data[10:20], data[20:]}
+
+    _, err = chunk.Put(session.ID, 1, 0, bytes.NewReader(parts[1]), md5Hex(parts[1]))
+    eq(err, ErrChunkOffset, "Put offset")
+
+    _, err = chunk.Put(session.ID, 0, 0, bytes.NewReader(parts[1]), md5Hex(parts[0]))
+    eq(err, ErrChunkChecksum, "Put checksum")
+
+    _, err = chunk.Put(session.ID, 2, 20, bytes.NewReader(data[19:]), md5Hex(data[19:]))
+    eq(err, ErrChunkSize, "Put size")
+
+    _, err = chunk.Put(session.ID, 3, 30, bytes.NewReader(parts[0]), md5Hex(parts[0]))
+    eq(err, ErrChunkIndex, "Put index")
+
+    for i := 2; i >= 1; i-- {
+        _, err = chunk.Put(session.ID, i, int64(i * 10), bytes.NewReader(parts[i]), md5Hex(parts[i]))
+        eq(err, nil, "Put")
+    }
+
+    uploaded, _ := chunk.Uploaded(session.ID)
+    eq(uploaded, []int{1, 2}, "Uploaded")
+
+    _, _, err = chunk.Complete(session.ID)
+    eq(err, ErrChunkIncomplete, "Complete incomplete")
+
+    _, err = chunk.Put(session.ID, 0, 0, bytes.NewReader(parts[0]), md5Hex(parts[0]))
+    eq(err, nil, "Put 0")
+
+    done, filePath, err := chunk.Complete(session.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    contents, _ := chunk.GetStorage().Read(filePath)
+    eq(contents, string(data), "Complete contents")
+    eq(len(done.Sha1), 40, "Complete Sha1")
+    eq(len(done.Sha256), 64, "Complete Sha256")
+
+    eq(chunk.Abort(session.ID), nil, "Abort")
+
+    _, err = chunk.Session(session.ID)
+    eq(err, ErrChunkNotFound, "Session after abort")
+}
+
+func Test_ChunkFileChecksum(t *testing.T) {
+    eq := assertT(t)
+
+    chunk := newTestChunk(t)
+
+    data := []byte("lakego")
+
+    session, err := chunk.Init("test.txt", int64(len(data)), 10, md5Hex([]byte("other")))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, err = chunk.Put(session.ID, 0, 0, bytes.NewReader(data), md5Hex(data))
+    eq(err, nil, "Put")
+
+    _, _, err = chunk.Complete(session.ID)
+    eq(err, ErrFileChecksum, "Complete checksum")
+}
+
+func Test_ChunkClean(t *testing.T) {
+    eq := assertT(t)
+
+    chunk := newTestChunk(t)
+
+    data := []byte("lakego")
+
+    session, err := chunk.Init("test.txt", int64(len(data)), 10, md5Hex(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    count, err := chunk.Clean(time.Hour)
+    eq(err, nil, "Clean")
+    eq(count, 0, "Clean fresh")
+
+    count, err = chunk.Clean(-1)
+    eq(err, nil, "Clean")
+    eq(count, 1, "Clean expired")
+
+    _, err = chunk.Session(session.ID)
+    eq(err, ErrChunkNotFound, "Session after clean")
+}
+
+func Test_ChunkLimit(t *testing.T) {
+    eq := assertT(t)
+
+    chunk := newTestChunk(t).
+        WithMaxSize(100).
+        WithMaxChunkSize(10)
+
+    _, err := chunk.Init("test.txt", 101, 10, md5Hex([]byte("lakego")))
+    eq(err, ErrFileTooLarge, "Init max size")
+
+    _, err = chunk.Init("test.txt", 100, 11, md5Hex([]byte("lakego")))
+    eq(err, ErrChunkTooLarge, "Init max chunk size")
+
+    _, err = chunk.Init("test.txt", 100, 10, md5Hex([]byte("lakego")))
+    eq(err, nil, "Init")
+}
+
+func Test_ChunkPutStream(t *testing.T) {
+    eq := assertT(t)
+
+    chunk := newTestChunk(t)
+
+    data := []byte("0123456789")
+
+    session, err := chunk.Init("test.txt", int64(len(data)), 10, md5Hex(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 超出分片大小的数据只读取到分片大小加一个字节
+    big := bytes.NewReader(bytes.Repeat([]byte("a"), 1 << 20))
+
+    _, err = chunk.Put(session.ID, 0, 0, big, md5Hex(data))
+    eq(err, ErrChunkSize, "Put too large")
+    eq(big.Len(), 1 << 20 - 11, "Put read limit")
+
+    partPath := chunk.partPath(session.ID, 0)
+    eq(chunk.GetStorage().Has(partPath + ".tmp"), false, "Put tmp removed")
+
+    uploaded, _ := chunk.Uploaded(session.ID)
+    eq(uploaded, []int{}, "Uploaded after failed put")
+
+    _, err = chunk.Put(session.ID, 0, 0, bytes.NewReader(data), md5Hex(data))
+    eq(err, nil, "Put")
+
+    // 重新上传覆盖已有分片
+    _, err = chunk.Put(session.ID, 0, 0, bytes.NewReader(data), md5Hex(data))
+    eq(err, nil, "Put again")
+
+    contents, _ := chunk.GetStorage().Read(partPath)
+    eq(contents, string(data), "Put contents")
+}
+
+func Test_ChunkOwner(t *testing.T) {
+    eq := assertT(t)
+
+    s := storage.New(local.New(t.TempDir()))
+
+    owner := NewChunk(s, "chunks").WithOwner("admin-1")
+    other := NewChunk(s, "chunks").WithOwner("admin-2")
+
+    data := []byte("lakego")
+
+    session, err := owner.Init("test.txt", int64(len(data)), 10, md5Hex(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    eq(session.Owner, "admin-1", "Init Owner")
+
+    _, err = other.Session(session.ID)
+    eq(err, ErrChunkNotFound, "Session other owner")
+
+    _, err = other.Put(session.ID, 0, 0, bytes.NewReader(data), md5Hex(data))
+    eq(err, ErrChunkNotFound, "Put other owner")
+
+    _, _, err = other.Complete(session.ID)
+    eq(err, ErrChunkNotFound, "Complete other owner")
+
+    eq(other.Abort(session.ID), ErrChunkNotFound, "Abort other owner")
+
+    _, err = owner.Put(session.ID, 0, 0, bytes.NewReader(data), md5Hex(data))
+    eq(err, nil, "Put owner")
+
+    _, _, err = owner.Complete(session.ID)
+    eq(err, nil, "Complete owner")
+
+    eq(owner.Abort(session.ID), nil, "Abort owner")
+}
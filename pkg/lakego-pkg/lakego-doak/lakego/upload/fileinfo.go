@@ -7,10 +7,13 @@ import (
     "bufio"
     "regexp"
     "strings"
-    "net/http"
     "crypto/md5"
     "crypto/sha1"
     "mime/multipart"
+
+    "github.com/deatil/lakego-filesystem/filesystem"
+
+    "github.com/deatil/lakego-doak/lakego/file"
 )
 
 /**
@@ -78,17 +81,18 @@ func (this *Fileinfo) GetFiletypes() map[string]string {
     return this.filetypes
 }
 
-// mime
+// mime，根据文件头检测
 func (this *Fileinfo) GetMimeType() string {
     // 头部字节
-    buffer := make([]byte, 32)
-    if _, err := this.file.Read(buffer); err != nil {
+    buffer := make([]byte, 512)
+    n, err := io.ReadFull(this.file, buffer)
+    if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
         return ""
     }
 
-    mimetype := http.DetectContentType(buffer)
+    this.file.Seek(0, io.SeekStart)
 
-    return mimetype
+    return filesystem.GetMimeTypeByContent(buffer[:n])
 }
 
 // 后缀
@@ -119,6 +123,8 @@ func (this *Fileinfo) GetOriginalFilename() string {
 func (this *Fileinfo) GetMd5() string {
     const bufferSize = 65536
 
+    this.file.Seek(0, io.SeekStart)
+
     hash := md5.New()
     for buf, reader := make([]byte, bufferSize), bufio.NewReader(this.file); ; {
         n, err := reader.Read(buf)
@@ -141,6 +147,8 @@ func (this *Fileinfo) GetMd5() string {
 func (this *Fileinfo) GetSha1() string {
     const bufferSize = 65536
 
+    this.file.Seek(0, io.SeekStart)
+
     hash := sha1.New()
     for buf, reader := make([]byte, bufferSize), bufio.NewReader(this.file); ; {
         n, err := reader.Read(buf)
@@ -159,6 +167,29 @@ func (this *Fileinfo) GetSha1() string {
     return checksum
 }
 
+// 摘要，读取一次文件同时计算 md5, sha1 和 sha256
+func (this *Fileinfo) GetHashes() (*file.HashReader, error) {
+    if _, err := this.file.Seek(0, io.SeekStart); err != nil {
+        return nil, err
+    }
+
+    hr := file.NewHashReader(this.file)
+    if _, err := io.Copy(io.Discard, hr); err != nil {
+        return nil, err
+    }
+
+    if _, err := this.file.Seek(0, io.SeekStart); err != nil {
+        return nil, err
+    }
+
+    return hr, nil
+}
+
+// 待校验的文件
+func (this *Fileinfo) GetValidateFile() (*ValidateFile, error) {
+    return NewValidateFile(this.fileHeader.Filename, this.fileHeader.Size, this.file)
+}
+
 // 文件大类
 func (this *Fileinfo) GetFileType() string {
     filetypes := this.filetypes
@@ -43,6 +43,9 @@ type Upload struct {
 
     // 权限，'private' or 'public'
     storagePermission string
+
+    // 校验链
+    validators *Validators
 }
 
 // 设置文件信息
@@ -81,6 +84,27 @@ func (this *Upload) GetRename() *Rename {
     return this.rename
 }
 
+// 设置校验链
+func (this *Upload) WithValidators(validators *Validators) *Upload {
+    this.validators = validators
+
+    return this
+}
+
+// 获取校验链
+func (this *Upload) GetValidators() *Validators {
+    return this.validators
+}
+
+// 校验文件，在保存前调用
+func (this *Upload) Validate(file *ValidateFile) error {
+    if this.validators == nil {
+        return nil
+    }
+
+    return this.validators.Validate(file)
+}
+
 // 设置文件系统
 func (this *Upload) WithStorage(storager *storage.Storage) *Upload {
     this.storage = storager
@@ -290,3 +314,18 @@ func (this *Upload) EnsureDir(path string) bool {
 
     return true
 }
+
+// 保存数据流，比如其他磁盘中的文件
+func (this *Upload) SaveStream(stream io.Reader, name string) string {
+    realname := this.GetRealname(name)
+
+    if this.storagePermission != "" {
+        path, _ := this.storage.PutFileAs(this.GetDirectory(), stream, realname, map[string]any{
+            "visibility": this.storagePermission,
+        })
+        return path
+    }
+
+    path, _ := this.storage.PutFileAs(this.GetDirectory(), stream, realname)
+    return path
+}
@@ -0,0 +1,209 @@
+package upload
+
+import (
+    "io"
+    "fmt"
+    "path"
+    "image"
+    "errors"
+    "regexp"
+    "strings"
+
+    _ "image/gif"
+    _ "image/png"
+    _ "image/jpeg"
+
+    "github.com/deatil/lakego-filesystem/filesystem"
+)
+
+// 文件头读取大小
+const validateHeaderSize = 512
+
+/**
+ * 待校验的文件
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type ValidateFile struct {
+    // 文件名
+    Name string
+
+    // 后缀
+    Extension string
+
+    // 大小
+    Size int64
+
+    // 根据文件头检测的格式
+    Mime string
+
+    // 文件头
+    Header []byte
+
+    // 文件流
+    Reader io.ReadSeeker
+}
+
+// 待校验的文件，读取文件头后重置到开头
+func NewValidateFile(name string, size int64, reader io.ReadSeeker) (*ValidateFile, error) {
+    header := make([]byte, validateHeaderSize)
+
+    n, err := io.ReadFull(reader, header)
+    if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+        return nil, err
+    }
+
+    header = header[:n]
+
+    if _, err := reader.Seek(0, io.SeekStart); err != nil {
+        return nil, err
+    }
+
+    return &ValidateFile{
+        Name:      name,
+        Extension: strings.ToLower(strings.TrimPrefix(path.Ext(name), ".")),
+        Size:      size,
+        Mime:      filesystem.GetMimeTypeByContent(header),
+        Header:    header,
+        Reader:    reader,
+    }, nil
+}
+
+// 校验接口
+type Validator interface {
+    Validate(*ValidateFile) error
+}
+
+// 校验函数
+type ValidatorFunc func(*ValidateFile) error
+
+// 校验
+func (this ValidatorFunc) Validate(file *ValidateFile) error {
+    return this(file)
+}
+
+// 校验链
+func NewValidators(validators ...Validator) *Validators {
+    return &Validators{
+        validators: validators,
+    }
+}
+
+/**
+ * 校验链，依次校验，遇到错误停止
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Validators struct {
+    validators []Validator
+}
+
+// 添加校验
+func (this *Validators) Add(validators ...Validator) *Validators {
+    this.validators = append(this.validators, validators...)
+
+    return this
+}
+
+// 校验数量
+func (this *Validators) Len() int {
+    return len(this.validators)
+}
+
+// 校验
+func (this *Validators) Validate(file *ValidateFile) error {
+    for _, validator := range this.validators {
+        if err := validator.Validate(file); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 文件大小
+func MaxSizeValidator(size int64) Validator {
+    return ValidatorFunc(func(file *ValidateFile) error {
+        if size > 0 && file.Size > size {
+            return fmt.Errorf("upload: file size %d exceeds %d", file.Size, size)
+        }
+
+        return nil
+    })
+}
+
+// 后缀，pattern 为正则
+func ExtensionValidator(pattern string) Validator {
+    re := regexp.MustCompile(pattern)
+
+    return ValidatorFunc(func(file *ValidateFile) error {
+        if !re.MatchString(file.Extension) {
+            return fmt.Errorf("upload: extension '%s' not allowed", file.Extension)
+        }
+
+        return nil
+    })
+}
+
+// 文件格式白名单，根据文件头检测，支持 image/* 格式
+func MimeValidator(mimes ...string) Validator {
+    return ValidatorFunc(func(file *ValidateFile) error {
+        for _, mime := range mimes {
+            if mime == file.Mime {
+                return nil
+            }
+
+            if strings.HasSuffix(mime, "/*") &&
+                strings.HasPrefix(file.Mime, strings.TrimSuffix(mime, "*")) {
+                return nil
+            }
+        }
+
+        return fmt.Errorf("upload: mime '%s' not allowed", file.Mime)
+    })
+}
+
+// 后缀和文件内容一致
+func MatchMimeValidator() Validator {
+    return ValidatorFunc(func(file *ValidateFile) error {
+        if !filesystem.CheckMimeType(file.Extension, file.Header) {
+            return fmt.Errorf("upload: extension '%s' does not match content '%s'", file.Extension, file.Mime)
+        }
+
+        return nil
+    })
+}
+
+// 图片尺寸，为 0 时不限制
+// 只校验能够解析的图片格式
+func ImageSizeValidator(minWidth, minHeight, maxWidth, maxHeight int) Validator {
+    return ValidatorFunc(func(file *ValidateFile) error {
+        if !strings.HasPrefix(file.Mime, "image/") || file.Reader == nil {
+            return nil
+        }
+
+        config, _, err := image.DecodeConfig(file.Reader)
+        if _, seekErr := file.Reader.Seek(0, io.SeekStart); seekErr != nil {
+            return seekErr
+        }
+
+        if err != nil {
+            if errors.Is(err, image.ErrFormat) {
+                return nil
+            }
+
+            return fmt.Errorf("upload: decode image failed: %w", err)
+        }
+
+        if (minWidth > 0 && config.Width < minWidth) ||
+            (minHeight > 0 && config.Height < minHeight) ||
+            (maxWidth > 0 && config.Width > maxWidth) ||
+            (maxHeight > 0 && config.Height > maxHeight) {
+            return fmt.Errorf("upload: image size %dx%d not allowed", config.Width, config.Height)
+        }
+
+        return nil
+    })
+}
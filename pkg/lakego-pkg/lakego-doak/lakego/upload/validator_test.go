@@ -0,0 +1,76 @@
+package upload
+
+import (
+    "bytes"
+    "image"
+    "testing"
+    "image/png"
+)
+
+func newTestPng(t *testing.T, width, height int) []byte {
+    buf := &bytes.Buffer{}
+    if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+        t.Fatal(err)
+    }
+
+    return buf.Bytes()
+}
+
+func newTestValidateFile(t *testing.T, name string, data []byte) *ValidateFile {
+    file, err := NewValidateFile(name, int64(len(data)), bytes.NewReader(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return file
+}
+
+func Test_NewValidateFile(t *testing.T) {
+    eq := assertT(t)
+
+    file := newTestValidateFile(t, "test.PNG", newTestPng(t, 10, 10))
+    eq(file.Extension, "png", "Extension")
+    eq(file.Mime, "image/png", "Mime")
+
+    file = newTestValidateFile(t, "test.txt", []byte("lakego"))
+    eq(file.Mime, "text/plain", "Mime text")
+}
+
+func Test_Validators(t *testing.T) {
+    eq := assertT(t)
+
+    data := newTestPng(t, 20, 10)
+
+    validators := NewValidators(
+        MaxSizeValidator(int64(len(data))),
+        ExtensionValidator("(?i)^(png|jpe?g)$"),
+        MimeValidator("image/*"),
+        MatchMimeValidator(),
+        ImageSizeValidator(0, 0, 20, 10),
+    )
+    eq(validators.Len(), 5, "Len")
+
+    eq(validators.Validate(newTestValidateFile(t, "test.png", data)), nil, "Validate png")
+
+    // 后缀和内容不一致
+    err := validators.Validate(newTestValidateFile(t, "test.jpg", data))
+    eq(err != nil, true, "Validate jpg")
+
+    // 后缀不允许
+    err = validators.Validate(newTestValidateFile(t, "test.gif", data))
+    eq(err != nil, true, "Validate gif")
+
+    // 格式不允许
+    err = validators.Validate(newTestValidateFile(t, "test.png", []byte("lakego")))
+    eq(err != nil, true, "Validate text")
+
+    // 尺寸过大
+    err = NewValidators(ImageSizeValidator(0, 0, 10, 10)).
+        Validate(newTestValidateFile(t, "test.png", data))
+    eq(err != nil, true, "Validate image size")
+
+    // 文件过大
+    err = NewValidators(MaxSizeValidator(10)).
+        Validate(newTestValidateFile(t, "test.png", data))
+    eq(err != nil, true, "Validate max size")
+}
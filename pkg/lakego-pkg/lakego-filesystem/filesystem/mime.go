@@ -1,5 +1,12 @@
 package filesystem
 
+import (
+    "strings"
+    "net/http"
+
+    "github.com/h2non/filetype"
+)
+
 // 后缀对应的格式
 var mimeTypes = map[string]string{
     "323":                    "text/h323",
@@ -638,3 +645,41 @@ func GetMimeExtension(mime string) string {
 
     return "Unknown"
 }
+
+// 根据文件头匹配格式，返回后缀和格式
+func MatchMimeType(buf []byte) (string, string, bool) {
+    kind, _ := filetype.Match(buf)
+    if kind == filetype.Unknown {
+        return "", "", false
+    }
+
+    return kind.Extension, kind.MIME.Value, true
+}
+
+// 根据文件内容获取格式，匹配不到文件头时使用 http 检测
+func GetMimeTypeByContent(buf []byte) string {
+    if _, mime, ok := MatchMimeType(buf); ok {
+        return mime
+    }
+
+    mime := http.DetectContentType(buf)
+    mime, _, _ = strings.Cut(mime, ";")
+
+    return strings.TrimSpace(mime)
+}
+
+// 检测后缀和文件内容是否一致
+// 文件头无法识别时只能信任后缀
+func CheckMimeType(extension string, buf []byte) bool {
+    ext, mime, ok := MatchMimeType(buf)
+    if !ok {
+        return true
+    }
+
+    extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+    if extension == ext {
+        return true
+    }
+
+    return GetMimeType(extension) == mime
+}